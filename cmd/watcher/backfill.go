@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/logging"
+	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/watcher"
+)
+
+func backfillCmd(cfgPath *string) *cobra.Command {
+	var watchPath, since, stateFile string
+	var only []string
+	var dryRun, restart bool
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Run a watch's actions over the files already in it, as create events",
+		Example: `  watcher backfill --watch inbox --since 30d
+  watcher backfill --watch photos --action thumbnail --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ResolvePaths(); err != nil {
+				return err
+			}
+			// A config in dry_run only reports what would run.
+			dryRun = dryRun || cfg.Global.DryRun
+			w := pickWatch(cfg.Watches, watchPath)
+			if w == nil {
+				return fmt.Errorf("watch not found: %s", watchPath)
+			}
+			if w.IsRemote() {
+				return errors.New("backfill walks local watches only")
+			}
+			for _, name := range only {
+				if !slices.ContainsFunc(w.Actions, func(a config.Action) bool { return a.Name == name }) {
+					return fmt.Errorf("watch %s has no action %q", w.Key(), name)
+				}
+			}
+			now := time.Now()
+			var after time.Time
+			if since != "" {
+				d, err := parseSince(since)
+				if err != nil {
+					return err
+				}
+				after = now.Add(-d)
+			}
+			if stateFile == "" && cfg.Global.StateDir != "" && !dryRun {
+				stateFile = backfillStateFile(cfg.Global.StateDir, w.Key(), only)
+			}
+			st := backfillState{Watch: w.Key(), Actions: only, Window: since, Since: after, Started: now}
+			if stateFile != "" && !restart {
+				prev, err := readBackfillState(stateFile)
+				if err != nil {
+					return err
+				}
+				if resumed, ok := st.resume(prev); ok {
+					st = resumed
+					fmt.Fprintf(os.Stderr, "resuming after %s (%d files done); --restart starts over\n", st.Cursor, st.Done)
+				}
+			}
+
+			scn := scanner.New(w.Path, w.Recursive)
+			scn.SetNormalization(w.NormalizeUnicode)
+			scn.SetXattrs(w.NeedsXattrs())
+			snap, err := scn.Scan()
+			if err != nil {
+				return err
+			}
+			keys := st.pending(snap)
+
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+			hist, err := watcher.OpenHistory(cfg.Global)
+			if err != nil {
+				return err
+			}
+			exec := actions.NewExecutor(cfg.Global, dryRun)
+			defer exec.Close()
+			logger := logging.New(slog.LevelWarn, cfg.Global.LogFormat)
+			b := &backfill{
+				dispatch: watcher.NewDispatcher(*w, exec, hist, logger),
+				root:     w.Path,
+				only:     only,
+				dryRun:   dryRun,
+			}
+			base, total, saved := st.Done, st.Done+len(keys), time.Now()
+			for i, k := range keys {
+				if ctx.Err() != nil {
+					break
+				}
+				b.run(ctx, k, snap[k])
+				if ctx.Err() != nil {
+					// The file's actions were cut short; resume with it.
+					break
+				}
+				st.Cursor, st.Done = k, st.Done+1
+				st.Ran, st.Failed = st.Ran+b.ran, st.Failed+b.failed
+				b.ran, b.failed = 0, 0
+				if time.Since(saved) >= time.Second || i == len(keys)-1 {
+					saved = time.Now()
+					fmt.Fprintf(os.Stderr, "backfill: %d/%d files, %d actions run, %d failed\n", base+i+1, total, st.Ran, st.Failed)
+					if stateFile != "" {
+						if err := writeBackfillState(stateFile, st); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			if ctx.Err() != nil {
+				if stateFile != "" {
+					if err := writeBackfillState(stateFile, st); err != nil {
+						return err
+					}
+					fmt.Fprintf(os.Stderr, "interrupted after %s; run again to resume\n", st.Cursor)
+				}
+				return nil
+			}
+			if stateFile != "" {
+				if err := os.Remove(stateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+			}
+			fmt.Printf("backfilled %d files: %d actions run, %d failed\n", st.Done, st.Ran, st.Failed)
+			if st.Failed > 0 {
+				return fmt.Errorf("%d actions failed", st.Failed)
+			}
+			return nil
+		},
+	}
+	f := cmd.Flags()
+	f.StringVar(&watchPath, "watch", "", "watch name or path (defaults to first)")
+	f.StringSliceVar(&only, "action", nil, "run only these actions (repeatable)")
+	f.StringVar(&since, "since", "", "only files modified within this long, e.g. 30d or 12h")
+	f.BoolVar(&dryRun, "dry-run", false, "list the actions that would run without running them")
+	f.StringVar(&stateFile, "state", "", "progress file for resuming (default in state_dir)")
+	f.BoolVar(&restart, "restart", false, "ignore saved progress and start from the first file")
+	return cmd
+}
+
+// backfill runs the matching actions for one file at a time.
+type backfill struct {
+	dispatch    *watcher.Dispatcher
+	root        string
+	only        []string
+	dryRun      bool
+	ran, failed int
+}
+
+func (b *backfill) run(ctx context.Context, key string, info scanner.FileInfo) {
+	ev := scanner.Event{Path: key, RelPath: relPath(b.root, key), Type: string(config.EventCreate), Info: info, Age: time.Since(info.ModTime)}
+	ev, matched := b.dispatch.Select(ctx, ev)
+	var selected []config.Action
+	for _, a := range matched {
+		if len(b.only) == 0 || slices.Contains(b.only, a.Name) {
+			selected = append(selected, a)
+		}
+	}
+	if len(selected) == 0 {
+		return
+	}
+	if b.dryRun {
+		for _, a := range selected {
+			fmt.Printf("%s %s: would run\n", a.Name, ev.RelPath)
+			b.ran++
+		}
+		return
+	}
+	evCtx := b.dispatch.Context(ev, actions.NewEventID())
+	b.dispatch.RunChain(ctx, evCtx, selected, func(a config.Action, err error) {
+		b.ran++
+		if err != nil && !errors.Is(err, actions.ErrSkip) {
+			b.failed++
+			fmt.Printf("%s %s: error: %v\n", a.Name, ev.RelPath, err)
+		}
+	})
+}
+
+// backfillState is the progress of a backfill, saved so an interrupted
+// one resumes after the last file it finished. Files are visited in path
+// order.
+type backfillState struct {
+	Watch   string    `json:"watch"`
+	Actions []string  `json:"actions,omitempty"`
+	Window  string    `json:"window,omitempty"`
+	Since   time.Time `json:"since"`
+	Started time.Time `json:"started"`
+	Cursor  string    `json:"cursor"`
+	Done    int       `json:"done"`
+	Ran     int       `json:"ran"`
+	Failed  int       `json:"failed"`
+}
+
+// resume returns prev if it is the saved progress of the same backfill as
+// st. The cutoff of the first run holds, so --since 30d covers the same
+// files however late the resume.
+func (st backfillState) resume(prev *backfillState) (backfillState, bool) {
+	if prev == nil || prev.Watch != st.Watch || prev.Window != st.Window || !slices.Equal(prev.Actions, st.Actions) {
+		return st, false
+	}
+	return *prev, true
+}
+
+// pending lists the regular files of snap still to visit, in path order:
+// those after the cursor and modified since the cutoff.
+func (st backfillState) pending(snap scanner.Snapshot) []string {
+	var keys []string
+	for k, info := range snap {
+		if info.IsDir || info.Special() != "" || (!st.Since.IsZero() && info.ModTime.Before(st.Since)) {
+			continue
+		}
+		if st.Cursor != "" && k <= st.Cursor {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// backfillStateFile names the progress file of a backfill of watch
+// limited to actions.
+func backfillStateFile(stateDir, watch string, actions []string) string {
+	sum := sha256.Sum256([]byte(watch + "\x00" + strings.Join(actions, ",")))
+	return filepath.Join(stateDir, "backfill", hex.EncodeToString(sum[:8])+".json")
+}
+
+func readBackfillState(path string) (*backfillState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st backfillState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return &st, nil
+}
+
+func writeBackfillState(path string, st backfillState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// parseSince reads a Go duration, or a whole number of days such as 30d.
+func parseSince(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --since %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --since %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/scanner"
+)
+
+func TestParseSince(t *testing.T) {
+	for in, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "0d": 0, "12h": 12 * time.Hour, "90m": 90 * time.Minute} {
+		if got, err := parseSince(in); err != nil || got != want {
+			t.Fatalf("parseSince(%q) = %s, %v; want %s", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "-1d", "1.5d", "-2h", "soon"} {
+		if _, err := parseSince(in); err == nil {
+			t.Fatalf("parseSince(%q): expected an error", in)
+		}
+	}
+}
+
+func TestBackfillResume(t *testing.T) {
+	cutoff := time.Now().Add(-time.Hour)
+	st := backfillState{Watch: "inbox", Actions: []string{"thumb"}, Window: "1h", Since: cutoff}
+	saved := st
+	saved.Since, saved.Cursor, saved.Done = cutoff.Add(-time.Minute), "/in/b", 2
+	got, ok := st.resume(&saved)
+	if !ok || got.Cursor != "/in/b" || got.Done != 2 || !got.Since.Equal(saved.Since) {
+		t.Fatalf("expected to resume the saved run with its cutoff, got %+v (%v)", got, ok)
+	}
+	for _, other := range []backfillState{
+		{Watch: "photos", Actions: []string{"thumb"}, Window: "1h"},
+		{Watch: "inbox", Actions: []string{"thumb", "tag"}, Window: "1h"},
+		{Watch: "inbox", Actions: []string{"thumb"}, Window: "30d"},
+	} {
+		other.Cursor = "/in/b"
+		if got, ok := st.resume(&other); ok || got.Cursor != "" {
+			t.Fatalf("resumed a different backfill %+v", other)
+		}
+	}
+	if _, ok := st.resume(nil); ok {
+		t.Fatalf("resumed without saved progress")
+	}
+}
+
+func TestBackfillPending(t *testing.T) {
+	now := time.Now()
+	in := func(name string) string { return filepath.Join("/in", name) }
+	snap := scanner.Snapshot{
+		in("a"):     {ModTime: now},
+		in("b"):     {ModTime: now},
+		in("c"):     {ModTime: now},
+		in("old"):   {ModTime: now.Add(-48 * time.Hour)},
+		in("sub"):   {IsDir: true, ModTime: now},
+		in("sub/d"): {ModTime: now},
+		in("pipe"):  {Mode: fs.ModeNamedPipe, ModTime: now},
+	}
+	st := backfillState{Since: now.Add(-24 * time.Hour)}
+	if got, want := st.pending(snap), []string{in("a"), in("b"), in("c"), in("sub/d")}; !slices.Equal(got, want) {
+		t.Fatalf("pending = %v, want %v", got, want)
+	}
+	st.Cursor = in("b")
+	if got, want := st.pending(snap), []string{in("c"), in("sub/d")}; !slices.Equal(got, want) {
+		t.Fatalf("pending after cursor = %v, want %v", got, want)
+	}
+}
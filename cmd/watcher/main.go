@@ -1,24 +1,41 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // timezones work on hosts without a zoneinfo database
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"watcher-cli/internal/actions"
+	"watcher-cli/internal/admin"
+	"watcher-cli/internal/bench"
+	"watcher-cli/internal/chaos"
 	"watcher-cli/internal/config"
+	"watcher-cli/internal/control"
+	"watcher-cli/internal/history"
+	"watcher-cli/internal/lint"
 	"watcher-cli/internal/logging"
 	"watcher-cli/internal/match"
+	"watcher-cli/internal/metrics"
 	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/status"
+	"watcher-cli/internal/summary"
 	"watcher-cli/internal/version"
 	"watcher-cli/internal/watcher"
+	"watcher-cli/internal/xattr"
 )
 
 func main() {
@@ -32,10 +49,21 @@ func main() {
 	root.PersistentFlags().StringVar(&cfgPath, "config", "watcher.yaml", "path to config file")
 
 	root.AddCommand(runCmd(&cfgPath))
+	root.AddCommand(watchCmd())
+	root.AddCommand(installCmd(&cfgPath))
+	root.AddCommand(uninstallCmd())
+	root.AddCommand(serviceCmd(&cfgPath))
 	root.AddCommand(validateCmd(&cfgPath))
+	root.AddCommand(lintCmd(&cfgPath))
+	root.AddCommand(configCmd(&cfgPath))
 	root.AddCommand(initCmd())
-	root.AddCommand(statusCmd())
+	root.AddCommand(statusCmd(&cfgPath))
+	root.AddCommand(historyCmd(&cfgPath))
 	root.AddCommand(simulateCmd(&cfgPath))
+	root.AddCommand(replayCmd(&cfgPath))
+	root.AddCommand(backfillCmd(&cfgPath))
+	root.AddCommand(snapshotCmd(&cfgPath))
+	root.AddCommand(benchCmd(&cfgPath))
 
 	if err := root.Execute(); err != nil {
 		fmt.Println("error:", err)
@@ -43,10 +71,125 @@ func main() {
 	}
 }
 
+// runOptions holds the flags of the run command.
+type runOptions struct {
+	printSummary bool
+	summaryFile  string
+	faultSpec    string
+	logFormat    string
+	httpAddr     string
+}
+
 func runCmd(cfgPath *string) *cobra.Command {
-	return &cobra.Command{
+	var o runOptions
+	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "Start watcher",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+			return runWatcher(ctx, *cfgPath, o)
+		},
+	}
+	cmd.Flags().BoolVar(&o.printSummary, "summary", false, "print a summary report when run exits")
+	cmd.Flags().StringVar(&o.summaryFile, "summary-file", "", "write a JSON summary report to this file when run exits")
+	cmd.Flags().StringVar(&o.logFormat, "log-format", "", "log format: text or json (default from global.log_format, else text)")
+	cmd.Flags().StringVar(&o.httpAddr, "http-addr", "", "serve the HTTP admin API on this address, e.g. 127.0.0.1:8080")
+	cmd.Flags().StringVar(&o.faultSpec, "fault-injection", "", "inject faults, e.g. fail=0.2,delay=500ms,truncate=0.1,seed=42")
+	cmd.Flags().MarkHidden("fault-injection")
+	return cmd
+}
+
+// runWatcher runs the config at cfgPath until ctx is done.
+func runWatcher(ctx context.Context, cfgPath string, o runOptions) error {
+	logFormat := o.logFormat
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ResolvePaths(); err != nil {
+		return err
+	}
+	if logFormat == "" {
+		logFormat = string(cfg.Global.LogFormat)
+	}
+	switch config.LogFormat(logFormat) {
+	case "", config.LogText, config.LogJSON:
+	default:
+		return fmt.Errorf("unknown --log-format %q (want text or json)", logFormat)
+	}
+	logger := logging.New(slog.LevelInfo, config.LogFormat(logFormat))
+	slog.SetDefault(logger)
+	for _, w := range cfg.Warnings {
+		logger.Warn(w)
+	}
+	super := watcher.NewSupervisor(cfg, logger, cfg.Global.DryRun)
+	if faultSpec := o.faultSpec; faultSpec != "" {
+		if os.Getenv(chaos.EnvVar) != "1" {
+			return fmt.Errorf("--fault-injection requires %s=1", chaos.EnvVar)
+		}
+		faults, err := chaos.Parse(faultSpec)
+		if err != nil {
+			return err
+		}
+		logger.Warn("fault injection enabled", "spec", faultSpec)
+		super.SetFaults(chaos.New(faults))
+	}
+	if o.httpAddr != "" {
+		ln, err := admin.Listen(o.httpAddr, cfg.Global.Admin)
+		if err != nil {
+			return err
+		}
+		h := admin.Handler(super, admin.NewAuth(cfg.Global.Admin), func() error {
+			return reloadConfig(cfgPath, super, logger)
+		})
+		logger.Info("admin api listening", "addr", ln.Addr().String())
+		go func() {
+			if err := admin.Serve(ctx, ln, h); err != nil {
+				logger.Error("admin api", "addr", o.httpAddr, "err", err)
+			}
+		}()
+	}
+	logger.Info("starting watcher", "watches", len(cfg.Watches))
+	go reloadOnChange(ctx, cfgPath, super, logger)
+	err = super.Run(ctx)
+	report := super.Summary()
+	if o.printSummary {
+		report.WriteText(os.Stdout)
+	}
+	if o.summaryFile != "" {
+		if werr := writeSummary(o.summaryFile, report); werr != nil {
+			logger.Error("write summary failed", "path", o.summaryFile, "err", werr)
+		}
+	}
+	if m := cfg.Global.Metrics; m.PushURL != "" {
+		body := super.RenderMetrics()
+		if perr := metrics.Push(context.Background(), m.PushURL, m.PushJob, body); perr != nil {
+			logger.Error("metrics push failed", "url", m.PushURL, "err", perr)
+		} else {
+			logger.Info("metrics pushed", "url", m.PushURL, "job", m.PushJob)
+		}
+	}
+	return err
+}
+
+func writeSummary(path string, report summary.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func validateCmd(cfgPath *string) *cobra.Command {
+	var strict bool
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load(*cfgPath)
 			if err != nil {
@@ -55,21 +198,38 @@ func runCmd(cfgPath *string) *cobra.Command {
 			if err := cfg.ResolvePaths(); err != nil {
 				return err
 			}
-			logger := logging.New(slog.LevelInfo)
-			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-			defer cancel()
-			super := watcher.NewSupervisor(cfg, logger, cfg.Global.DryRun)
-			logger.Info("starting watcher", "watches", len(cfg.Watches))
-			return super.Run(ctx)
+			for _, w := range cfg.Warnings {
+				fmt.Println("warning:", w)
+			}
+			fmt.Println("config OK")
+			if strict {
+				return reportLint(cfg)
+			}
+			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&strict, "strict", false, "also fail on lint findings (overlapping or shadowed actions)")
+	return cmd
 }
 
-func validateCmd(cfgPath *string) *cobra.Command {
-	return &cobra.Command{
-		Use:   "validate",
-		Short: "Validate configuration",
+func configCmd(cfgPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect configuration",
+	}
+	var effective bool
+	show := &cobra.Command{
+		Use:   "show",
+		Short: "Print the configuration file, or with --effective the merged result",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if !effective {
+				data, err := os.ReadFile(*cfgPath)
+				if err != nil {
+					return err
+				}
+				fmt.Print(string(data))
+				return nil
+			}
 			cfg, err := config.Load(*cfgPath)
 			if err != nil {
 				return err
@@ -77,10 +237,102 @@ func validateCmd(cfgPath *string) *cobra.Command {
 			if err := cfg.ResolvePaths(); err != nil {
 				return err
 			}
-			fmt.Println("config OK")
+			doc, err := cfg.Display()
+			if err != nil {
+				return err
+			}
+			enc := yaml.NewEncoder(os.Stdout)
+			enc.SetIndent(2)
+			defer enc.Close()
+			return enc.Encode(doc)
+		},
+	}
+	show.Flags().BoolVar(&effective, "effective", false, "apply defaults, normalize durations and resolve paths; secrets are masked")
+	cmd.AddCommand(show)
+	cmd.AddCommand(migrateCmd(cfgPath))
+	return cmd
+}
+
+func migrateCmd(cfgPath *string) *cobra.Command {
+	var write bool
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the configuration file to the current version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if config.IsEncrypted(data) {
+				return fmt.Errorf("%s is encrypted; decrypt it, migrate, and re-encrypt", *cfgPath)
+			}
+			var doc yaml.Node
+			if err := yaml.Unmarshal(data, &doc); err != nil {
+				return fmt.Errorf("parse config: %w", err)
+			}
+			from, notes, err := config.Migrate(&doc)
+			if err != nil {
+				return err
+			}
+			for _, n := range notes {
+				fmt.Fprintln(os.Stderr, "migrated", n)
+			}
+			var buf bytes.Buffer
+			enc := yaml.NewEncoder(&buf)
+			enc.SetIndent(2)
+			if err := enc.Encode(&doc); err != nil {
+				return err
+			}
+			enc.Close()
+			if !write {
+				fmt.Print(buf.String())
+				return nil
+			}
+			if from == config.CurrentVersion {
+				fmt.Fprintf(os.Stderr, "%s is already at version %d\n", *cfgPath, from)
+				return nil
+			}
+			if err := os.WriteFile(*cfgPath+".bak", data, 0o644); err != nil {
+				return err
+			}
+			if err := os.WriteFile(*cfgPath, buf.Bytes(), 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "migrated %s from version %d to %d (backup at %s.bak)\n", *cfgPath, from, config.CurrentVersion, *cfgPath)
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&write, "write", false, "rewrite the file in place, keeping a .bak copy")
+	return cmd
+}
+
+func lintCmd(cfgPath *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint",
+		Short: "Report overlapping, shadowed and self-cancelling actions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ResolvePaths(); err != nil {
+				return err
+			}
+			return reportLint(cfg)
+		},
+	}
+}
+
+func reportLint(cfg config.Config) error {
+	findings := append(lint.Check(cfg), lint.Mounts(cfg)...)
+	for _, f := range findings {
+		fmt.Println("warning:", f)
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("%d lint issue(s) found", len(findings))
+	}
+	fmt.Println("lint OK")
+	return nil
 }
 
 func initCmd() *cobra.Command {
@@ -101,27 +353,237 @@ func initCmd() *cobra.Command {
 	}
 }
 
-func statusCmd() *cobra.Command {
-	return &cobra.Command{
+func statusCmd(cfgPath *string) *cobra.Command {
+	var reset bool
+	cmd := &cobra.Command{
 		Use:   "status",
-		Short: "Show status (available while running in same process)",
+		Short: "Show status counters of the running watcher",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ResolvePaths(); err != nil {
+				return err
+			}
+			if !reset {
+				sock, ok := control.Resolve(cfg.Global.ControlSocket)
+				if ok {
+					ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+					defer cancel()
+					report, err := control.FetchStatus(ctx, sock)
+					if err == nil {
+						printCounters(report.Counters)
+						printHealth(report.Health)
+						return nil
+					}
+					if !cfg.Global.PersistStatus {
+						fmt.Printf("no running watcher at %s; set global.persist_status to keep counters in state_dir\n", sock)
+						return nil
+					}
+					fmt.Printf("no running watcher at %s; showing persisted counters\n", sock)
+				}
+			}
+			if !cfg.Global.PersistStatus {
+				fmt.Println("control socket disabled; set global.persist_status to keep counters in state_dir")
+				return nil
+			}
+			path := filepath.Join(cfg.Global.StateDir, status.FileName)
+			if reset {
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				fmt.Println("status counters reset")
+				return nil
+			}
+			counters, err := status.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			printCounters(counters)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&reset, "reset", false, "delete persisted counters (stop the watcher first)")
+	return cmd
+}
+
+func historyCmd(cfgPath *string) *cobra.Command {
+	var path, watchPath, since string
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show which actions ran, for a file or a watch",
+		Example: `  watcher history --path /in/a.pdf
+  watcher history --watch inbox --since 1h`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("status is available during run; expose via future IPC")
+			if path == "" && watchPath == "" && since == "" {
+				return fmt.Errorf("one of --path, --watch or --since is required")
+			}
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ResolvePaths(); err != nil {
+				return err
+			}
+			if !cfg.Global.History {
+				fmt.Println("history is off; set global.history to record actions per file in state_dir")
+				return nil
+			}
+			keep := func(history.Entry) bool { return true }
+			if path != "" {
+				abs, err := filepath.Abs(path)
+				if err != nil {
+					return err
+				}
+				path = abs
+				keep = func(e history.Entry) bool { return e.Path == abs }
+			}
+			if watchPath != "" {
+				w := pickWatch(cfg.Watches, watchPath)
+				if w == nil {
+					return fmt.Errorf("watch not found: %s", watchPath)
+				}
+				byPath := keep
+				keep = func(e history.Entry) bool { return e.Watch == w.Key() && byPath(e) }
+			}
+			if since != "" {
+				d, err := parseSince(since)
+				if err != nil {
+					return err
+				}
+				after := time.Now().Add(-d)
+				byWatch := keep
+				keep = func(e history.Entry) bool { return !e.Time.Before(after) && byWatch(e) }
+			}
+			all, err := history.ReadAll(filepath.Join(cfg.Global.StateDir, history.FileName))
+			if err != nil {
+				return err
+			}
+			var entries []history.Entry
+			for _, e := range all {
+				if keep(e) {
+					entries = append(entries, e)
+				}
+			}
+			if len(entries) == 0 {
+				if path != "" {
+					fmt.Println("no actions recorded for", path)
+				} else {
+					fmt.Println("no actions recorded")
+				}
+				return nil
+			}
+			for _, e := range entries {
+				// Without --path, lines name their file.
+				file := ""
+				if path == "" {
+					file = fmt.Sprintf(" path=%q", e.Path)
+				}
+				took := ""
+				if e.DurationMS > 0 {
+					took = fmt.Sprintf(" took=%dms", e.DurationMS)
+				}
+				if e.Outcome != "" {
+					result := "outcome=" + e.Outcome
+					if len(e.Failed) > 0 {
+						result += " failed=" + strings.Join(e.Failed, ",")
+					}
+					fmt.Printf("%s %s handled event=%s id=%s%s %s%s\n", e.Time.Format(time.RFC3339), e.Watch, e.Event, e.EventID, file, result, took)
+					continue
+				}
+				result := "ok"
+				if !e.OK {
+					result = fmt.Sprintf("error=%q", e.Error)
+					if e.ErrorClass != "" {
+						result += " error_class=" + e.ErrorClass
+					}
+				}
+				fmt.Printf("%s %s %s event=%s id=%s%s %s%s\n", e.Time.Format(time.RFC3339), e.Watch, e.Action, e.Event, e.EventID, file, result, took)
+			}
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&path, "path", "", "file to show the history of")
+	cmd.Flags().StringVar(&watchPath, "watch", "", "only entries of this watch (name or path)")
+	cmd.Flags().StringVar(&since, "since", "", "only entries within this long, e.g. 1h or 7d")
+	return cmd
+}
+
+func printCounters(counters map[string]status.Counter) {
+	if len(counters) == 0 {
+		fmt.Println("no counters recorded")
+		return
+	}
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c := counters[name]
+		fmt.Printf("%s: events=%d actions=%d ok=%d errors=%d", name, c.EventsSeen, c.ActionsRun, c.ActionsOK, c.ActionsError)
+		if c.EventsHandled+c.EventsPartial+c.EventsFailed > 0 {
+			fmt.Printf(" handled=%d partially_failed=%d failed=%d", c.EventsHandled, c.EventsPartial, c.EventsFailed)
+		}
+		if c.LastError != "" {
+			fmt.Printf(" last_error=%q", c.LastError)
+		}
+		if len(c.ErrorClasses) > 0 {
+			classes := make([]string, 0, len(c.ErrorClasses))
+			for class, n := range c.ErrorClasses {
+				classes = append(classes, fmt.Sprintf("%s=%d", class, n))
+			}
+			sort.Strings(classes)
+			fmt.Printf(" error_classes=%s", strings.Join(classes, ","))
+		}
+		if c.Restarts > 0 {
+			fmt.Printf(" restarts=%d last_crash=%q", c.Restarts, c.LastCrash)
+		}
+		if len(c.Upstream) > 0 {
+			from := make([]string, 0, len(c.Upstream))
+			for f, n := range c.Upstream {
+				from = append(from, fmt.Sprintf("%s=%d", f, n))
+			}
+			sort.Strings(from)
+			fmt.Printf(" from=%s", strings.Join(from, ","))
+		}
+		fmt.Println()
+	}
+}
+
+func printHealth(health map[string]status.Health) {
+	names := make([]string, 0, len(health))
+	for name := range health {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h := health[name]
+		fmt.Printf("%s: health=%s queue=%d", name, h.State, h.QueueDepth)
+		if len(h.Reasons) > 0 {
+			fmt.Printf(" reasons=%q", strings.Join(h.Reasons, "; "))
+		}
+		fmt.Println()
+	}
 }
 
 func simulateCmd(cfgPath *string) *cobra.Command {
 	var watchPath string
 	var eventType string
 	var filePath string
+	var prevPath string
 	var size int64
 	var age time.Duration
 	var execute bool
+	var failFirst int
+	var latency time.Duration
+	var failClass string
 	cmd := &cobra.Command{
 		Use:   "simulate",
 		Short: "Simulate an event through matching/actions",
+		Example: `  watcher simulate --file /in/a.pdf
+  watcher simulate --file /in/a.pdf --fail-first 2 --latency 2s`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load(*cfgPath)
 			if err != nil {
@@ -140,42 +602,72 @@ func simulateCmd(cfgPath *string) *cobra.Command {
 			if eventType == "" {
 				eventType = string(config.EventCreate)
 			}
+			if failFirst < 0 || latency < 0 {
+				return fmt.Errorf("--fail-first and --latency must not be negative")
+			}
+			if failClass != "" && !slices.Contains(config.ErrorClasses, config.ErrorClass(failClass)) {
+				return fmt.Errorf("unknown --fail-class %q", failClass)
+			}
 			rel, _ := filepath.Rel(w.Path, filePath)
 			info := scanner.FileInfo{
 				Size:    size,
 				ModTime: time.Now().Add(-age),
 				IsDir:   false,
 			}
+			if w.NeedsXattrs() {
+				info.Xattrs = xattr.List(filePath)
+			}
 			ev := scanner.Event{
-				Path:    filePath,
-				RelPath: rel,
-				Type:    eventType,
-				Info:    info,
-				Age:     age,
+				Path:     filePath,
+				RelPath:  rel,
+				PrevPath: prevPath,
+				Type:     eventType,
+				Info:     info,
+				Age:      age,
 			}
-			m := match.New()
+			m := match.New(*w)
 			selected := m.Match(ev, *w)
 			if len(selected) == 0 {
 				fmt.Println("no actions matched")
 				return nil
 			}
-			exec := &actions.Executor{Registry: actions.NewRegistry(), DryRun: !execute}
+			exec := actions.NewExecutor(cfg.Global, !execute)
+			defer exec.Close()
+			stubbed := failFirst > 0 || latency > 0
+			var began time.Time
+			if stubbed {
+				// Every action runs the stub through the real retry and
+				// timeout path, so nothing is touched.
+				exec.Registry = actions.StubRegistry(&actions.StubRunner{
+					FailFirst: failFirst,
+					Latency:   latency,
+					Class:     config.ErrorClass(failClass),
+					OnAttempt: func(action string, n int, took time.Duration, err error) {
+						at := time.Since(began).Round(time.Millisecond)
+						if err != nil {
+							fmt.Printf("  +%s attempt %d failed after %s (%s): %v\n", at, n, took.Round(time.Millisecond), actions.Classify(err), err)
+							return
+						}
+						fmt.Printf("  +%s attempt %d ok after %s\n", at, n, took.Round(time.Millisecond))
+					},
+				})
+			}
 			ctx := context.Background()
+			id := actions.NewEventID()
+			evCtx := watcher.EventContext(*w, ev, id)
+			fmt.Println("event id:", id)
 			for _, a := range selected {
-				err := exec.Execute(ctx, actions.Context{
-					Path:     ev.Path,
-					RelPath:  ev.RelPath,
-					PrevPath: ev.PrevPath,
-					Event:    ev.Type,
-					Size:     ev.Info.Size,
-					ModTime:  ev.Info.ModTime,
-					Age:      ev.Age,
-					IsDir:    ev.Info.IsDir,
-				}, a)
+				if stubbed {
+					fmt.Printf("action %s: timeout %s, retries %d, backoff %s\n", a.Name, a.Timeout.Duration(), a.MaxRetries(), a.Backoff())
+					began = time.Now()
+				}
+				err := exec.Execute(ctx, evCtx, a)
 				if err != nil {
 					fmt.Printf("action %s error: %v\n", a.Name, err)
 				} else {
-					if execute {
+					if stubbed {
+						fmt.Printf("action %s ok (stubbed) after %s\n", a.Name, time.Since(began).Round(time.Millisecond))
+					} else if execute {
 						fmt.Printf("action %s executed\n", a.Name)
 					} else {
 						fmt.Printf("action %s (dry-run)\n", a.Name)
@@ -185,12 +677,73 @@ func simulateCmd(cfgPath *string) *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&watchPath, "watch", "", "watch path to use (defaults to first)")
+	cmd.Flags().StringVar(&watchPath, "watch", "", "watch name or path to use (defaults to first)")
 	cmd.Flags().StringVar(&eventType, "event", "create", "event type (create|modify|delete|move)")
 	cmd.Flags().StringVar(&filePath, "file", "", "file path for the simulated event")
+	cmd.Flags().StringVar(&prevPath, "prev", "", "previous path for a simulated move")
 	cmd.Flags().Int64Var(&size, "size", 0, "file size bytes")
 	cmd.Flags().DurationVar(&age, "age", 0, "age of file (e.g., 10s, 2m)")
 	cmd.Flags().BoolVar(&execute, "execute", false, "actually run actions (default dry-run)")
+	cmd.Flags().IntVar(&failFirst, "fail-first", 0, "replace actions with a stub whose first N attempts fail, to try retries")
+	cmd.Flags().DurationVar(&latency, "latency", 0, "replace actions with a stub taking this long per attempt, to try timeouts")
+	cmd.Flags().StringVar(&failClass, "fail-class", "", "error class of the stub's failures (default other)")
+	return cmd
+}
+
+func benchCmd(cfgPath *string) *cobra.Command {
+	var path string
+	var synthetic int
+	var rounds int
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure scan, diff and match times for a watch",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ResolvePaths(); err != nil {
+				return err
+			}
+			w := pickWatch(cfg.Watches, path)
+			if w == nil {
+				// Benchmark an arbitrary directory against the first
+				// watch's actions.
+				if len(cfg.Watches) == 0 {
+					return fmt.Errorf("no watches configured")
+				}
+				abs, err := filepath.Abs(path)
+				if err != nil {
+					return err
+				}
+				if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+					return fmt.Errorf("not a watch or directory: %s", path)
+				}
+				cp := cfg.Watches[0]
+				cp.Path = abs
+				w = &cp
+			}
+			if w.IsRemote() && synthetic == 0 {
+				return fmt.Errorf("watch %s lists a remote source; bench it with --synthetic", w.Key())
+			}
+			report, err := bench.Run(*w, bench.Options{Synthetic: synthetic, Rounds: rounds})
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			report.WriteText(os.Stdout)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&path, "path", "", "watch name, watch path or directory to benchmark (defaults to first watch)")
+	cmd.Flags().IntVar(&synthetic, "synthetic", 0, "benchmark a generated tree of N files instead")
+	cmd.Flags().IntVar(&rounds, "rounds", 3, "repeat each phase and report the fastest")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the report as JSON")
 	return cmd
 }
 
@@ -201,6 +754,11 @@ func pickWatch(watches []config.Watch, path string) *config.Watch {
 	if path == "" {
 		return &watches[0]
 	}
+	for i := range watches {
+		if watches[i].Name == path {
+			return &watches[i]
+		}
+	}
 	for i := range watches {
 		if filepath.Clean(watches[i].Path) == filepath.Clean(path) {
 			return &watches[i]
@@ -209,7 +767,8 @@ func pickWatch(watches []config.Watch, path string) *config.Watch {
 	return nil
 }
 
-const sampleConfig = `global:
+const sampleConfig = `version: 1
+global:
   scan_interval_ms: 1000
   debounce_ms: 200
   dry_run: false
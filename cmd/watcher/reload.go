@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/watcher"
+)
+
+// configPollInterval is how often the config file is checked for changes.
+const configPollInterval = 2 * time.Second
+
+// reloadOnChange reloads the config into super on SIGHUP and whenever the
+// config file changes, until ctx is done. An invalid config is reported
+// and the running one kept.
+func reloadOnChange(ctx context.Context, path string, super *watcher.Supervisor, logger *slog.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	last, _ := os.Stat(path)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			logger.Info("SIGHUP received, reloading config", "path", path)
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || (last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+				continue
+			}
+			last = info
+			logger.Info("config file changed, reloading", "path", path)
+		}
+		reloadConfig(path, super, logger)
+	}
+}
+
+// reloadConfig loads the config at path into super. An invalid config is
+// reported, returned and the running one kept.
+func reloadConfig(path string, super *watcher.Supervisor, logger *slog.Logger) error {
+	cfg, err := config.Load(path)
+	if err == nil {
+		err = cfg.ResolvePaths()
+	}
+	if err != nil {
+		logger.Error("config reload failed, keeping the running config", "path", path, "err", err)
+		return err
+	}
+	for _, w := range cfg.Warnings {
+		logger.Warn(w)
+	}
+	super.Reload(cfg)
+	return nil
+}
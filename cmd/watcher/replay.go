@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/deadletter"
+	"watcher-cli/internal/match"
+	"watcher-cli/internal/scanner"
+)
+
+func replayCmd(cfgPath *string) *cobra.Command {
+	var file string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Run the events of a dead-letter file through matching/actions again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return fmt.Errorf("--dead-letter is required")
+			}
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ResolvePaths(); err != nil {
+				return err
+			}
+			// A config in dry_run only reports what would run.
+			dryRun = dryRun || cfg.Global.DryRun
+			entries, err := deadletter.Read(file)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("no events in", file)
+				return nil
+			}
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+			exec := actions.NewExecutor(cfg.Global, dryRun)
+			defer exec.Close()
+			var keep []deadletter.Entry
+			var ok, failed int
+			for i, e := range entries {
+				if ctx.Err() != nil {
+					keep = append(keep, entries[i:]...)
+					break
+				}
+				err := replayEntry(ctx, exec, cfg, e, dryRun)
+				switch {
+				case err == nil:
+					ok++
+					if dryRun {
+						fmt.Printf("%s %s %s: would run\n", e.EventID, e.Action, e.Path)
+					} else {
+						fmt.Printf("%s %s %s: ok\n", e.EventID, e.Action, e.Path)
+					}
+				case errors.Is(err, errNotReplayed):
+					fmt.Printf("%s %s %s: skipped: %v\n", e.EventID, e.Action, e.Path, err)
+					keep = append(keep, e)
+				default:
+					failed++
+					fmt.Printf("%s %s %s: error: %v\n", e.EventID, e.Action, e.Path, err)
+					e.Time, e.Error, e.ErrorClass = time.Now(), err.Error(), string(actions.Classify(err))
+					keep = append(keep, e)
+				}
+			}
+			fmt.Printf("replayed %d, failed %d, skipped %d\n", ok, failed, len(keep)-failed)
+			if dryRun {
+				return nil
+			}
+			return deadletter.Replace(file, len(entries), keep)
+		},
+	}
+	cmd.Flags().StringVar(&file, "dead-letter", "", "dead-letter file written by an action's dead_letter")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would run without running actions or changing the file")
+	return cmd
+}
+
+// errNotReplayed marks entries that cannot be run against the current
+// config or file; they stay in the dead-letter file.
+var errNotReplayed = errors.New("not replayed")
+
+// replayEntry matches e's event against its watch as configured now and
+// runs e's action if it still matches, unless dryRun.
+func replayEntry(ctx context.Context, exec *actions.Executor, cfg config.Config, e deadletter.Entry, dryRun bool) error {
+	w := pickWatch(cfg.Watches, e.Watch)
+	if w == nil || e.Watch == "" {
+		return fmt.Errorf("%w: watch %q not in config", errNotReplayed, e.Watch)
+	}
+	if w.IsRemote() {
+		return fmt.Errorf("%w: remote watches cannot be replayed", errNotReplayed)
+	}
+	info := scanner.FileInfo{Size: e.Size, ModTime: e.ModTime, IsDir: e.IsDir}
+	if e.Event != string(config.EventDelete) {
+		// Act on the file as it is now.
+		st, err := os.Stat(e.Path)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errNotReplayed, err)
+		}
+		info = scanner.FileInfo{Size: st.Size(), ModTime: st.ModTime(), IsDir: st.IsDir()}
+	}
+	ev := scanner.Event{Path: e.Path, RelPath: e.RelPath, PrevPath: e.PrevPath, Type: e.Event, Info: info, Age: time.Since(info.ModTime)}
+	for k, v := range e.Meta {
+		if name, ok := strings.CutPrefix(k, "field:"); ok {
+			if ev.Fields == nil {
+				ev.Fields = map[string]string{}
+			}
+			ev.Fields[name] = v
+		}
+		if name, ok := strings.CutPrefix(k, "xattr:"); ok {
+			if ev.Info.Xattrs == nil {
+				ev.Info.Xattrs = map[string]string{}
+			}
+			ev.Info.Xattrs[name] = v
+		}
+	}
+	var action *config.Action
+	for _, a := range match.New(*w).Match(ev, *w) {
+		if a.Name == e.Action {
+			action = &a
+			break
+		}
+	}
+	if action == nil {
+		return fmt.Errorf("%w: action %q no longer matches", errNotReplayed, e.Action)
+	}
+	if dryRun {
+		return nil
+	}
+	var deletedAt time.Time
+	if e.DeletedAt != nil {
+		deletedAt = *e.DeletedAt
+	}
+	outputs := e.Outputs
+	if outputs == nil {
+		outputs = map[string]string{}
+	}
+	return exec.Execute(ctx, actions.Context{
+		ID:          e.EventID,
+		Path:        e.Path,
+		RelPath:     e.RelPath,
+		PrevPath:    e.PrevPath,
+		PrevRelPath: e.PrevRelPath,
+		Event:       e.Event,
+		Size:        info.Size,
+		ModTime:     info.ModTime,
+		Age:         ev.Age,
+		IsDir:       info.IsDir,
+		DeletedAt:   deletedAt,
+		Location:    w.Location(),
+		Normalize:   w.NormalizeUnicode,
+		Meta:        e.Meta,
+		Outputs:     outputs,
+	}, *action)
+}
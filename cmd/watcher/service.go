@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/service"
+)
+
+func installCmd(cfgPath *string) *cobra.Command {
+	o := service.Options{Name: service.DefaultName}
+	var printUnit bool
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Register watcher as a systemd unit (Linux) or Windows service and start it",
+		Example: `  sudo watcher install --config /etc/watcher/watcher.yaml --run-as watcher
+  watcher install --user --config ~/watcher.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			if exe, err = filepath.EvalSymlinks(exe); err != nil {
+				return err
+			}
+			cfg, err := enterConfigDir(*cfgPath)
+			if err != nil {
+				return err
+			}
+			// Refuse to install a service that would fail on start.
+			if _, err := config.Load(cfg); err != nil {
+				return err
+			}
+			o.Config, o.Executable = cfg, exe
+			if printUnit {
+				if err := o.Validate(); err != nil {
+					return err
+				}
+				fmt.Print(service.Unit(o))
+				return nil
+			}
+			where, err := service.Install(o)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("installed and started %s (%s)\n", o.Name, where)
+			return nil
+		},
+	}
+	f := cmd.Flags()
+	f.StringVar(&o.Name, "name", o.Name, "service name")
+	f.StringVar(&o.RunAs, "run-as", "", "account the systemd service runs as (default root)")
+	f.BoolVar(&o.UserUnit, "user", false, "install a systemd --user unit")
+	f.DurationVar(&o.Watchdog, "watchdog", 30*time.Second, "systemd watchdog timeout; 0 disables it")
+	f.BoolVar(&o.Force, "force", false, "replace an existing service")
+	f.BoolVar(&printUnit, "print", false, "print the systemd unit instead of installing it")
+	return cmd
+}
+
+func uninstallCmd() *cobra.Command {
+	o := service.Options{Name: service.DefaultName}
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Stop and remove the service registered by install",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			where, err := service.Uninstall(o)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("removed %s (%s)\n", o.Name, where)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&o.Name, "name", o.Name, "service name")
+	cmd.Flags().BoolVar(&o.UserUnit, "user", false, "remove a systemd --user unit")
+	return cmd
+}
+
+func serviceCmd(cfgPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "Commands used by the installed service",
+	}
+	var name string
+	var o runOptions
+	run := &cobra.Command{
+		Use:   "run",
+		Short: "Run as a service: notify systemd or answer the Windows service manager",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := enterConfigDir(*cfgPath)
+			if err != nil {
+				return err
+			}
+			return service.Run(name, func(ctx context.Context) error {
+				return runWatcher(ctx, cfg, o)
+			})
+		},
+	}
+	run.Flags().StringVar(&name, "name", service.DefaultName, "service name")
+	run.Flags().StringVar(&o.logFormat, "log-format", "", "log format: text or json (default from global.log_format, else text)")
+	cmd.AddCommand(run)
+	return cmd
+}
+
+// enterConfigDir changes to the directory of the config at path and
+// returns its absolute path. Services start in / or System32, so relative
+// paths in the config are taken relative to the config instead.
+func enterConfigDir(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return abs, os.Chdir(filepath.Dir(abs))
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/control"
+	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/watcher"
+)
+
+func snapshotCmd(cfgPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Inspect the file state the watcher believes a watch is in",
+	}
+	cmd.AddCommand(snapshotDumpCmd(cfgPath))
+	cmd.AddCommand(snapshotDiffCmd())
+	return cmd
+}
+
+func snapshotDumpCmd(cfgPath *string) *cobra.Command {
+	var watchPath, format string
+	var saved bool
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Print a watch's snapshot from the running watcher, or the one saved in state_dir",
+		Example: `  watcher snapshot dump --watch inbox
+  watcher snapshot dump --watch inbox --format json > before.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSnapshotFormat(format); err != nil {
+				return err
+			}
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ResolvePaths(); err != nil {
+				return err
+			}
+			w := pickWatch(cfg.Watches, watchPath)
+			if w == nil {
+				return fmt.Errorf("watch not found: %s", watchPath)
+			}
+			if !saved {
+				sock, ok := control.Resolve(cfg.Global.ControlSocket)
+				if ok {
+					ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+					defer cancel()
+					snap, err := control.FetchSnapshot(ctx, sock, w.Key())
+					if err == nil {
+						return printSnapshot(os.Stdout, snap, format)
+					}
+					fmt.Fprintf(os.Stderr, "no running watcher at %s; showing the saved snapshot\n", sock)
+				}
+			}
+			if !cfg.Global.PersistSnapshots {
+				return errors.New("no saved snapshots; set global.persist_snapshots to keep them in state_dir")
+			}
+			path := watcher.SnapshotFile(cfg.Global.StateDir, w.Key())
+			snap, err := scanner.ReadSaved(path)
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("no saved snapshot for %s at %s", w.Key(), path)
+			}
+			if err != nil {
+				return err
+			}
+			return printSnapshot(os.Stdout, snap, format)
+		},
+	}
+	cmd.Flags().StringVar(&watchPath, "watch", "", "watch name or path (defaults to first)")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text or json (json is the saved snapshot format)")
+	cmd.Flags().BoolVar(&saved, "saved", false, "read the snapshot saved in state_dir instead of asking the running watcher")
+	return cmd
+}
+
+func snapshotDiffCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "List the events between two saved snapshots",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSnapshotFormat(format); err != nil {
+				return err
+			}
+			old, err := scanner.ReadSaved(args[0])
+			if err != nil {
+				return err
+			}
+			curr, err := scanner.ReadSaved(args[1])
+			if err != nil {
+				return err
+			}
+			if old.Root != curr.Root {
+				fmt.Fprintf(os.Stderr, "warning: snapshots are of different roots (%s, %s)\n", old.Root, curr.Root)
+			}
+			events := scanner.Diff(curr.Root, old.Entries, curr.Entries)
+			if format == "json" {
+				out := make([]snapshotChange, len(events))
+				for i, ev := range events {
+					out[i] = snapshotChange{Type: ev.Type, Path: ev.Path, PrevPath: ev.PrevPath, Size: ev.Info.Size, ModTime: ev.Info.ModTime}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+			if len(events) == 0 {
+				fmt.Println("no changes")
+				return nil
+			}
+			for _, ev := range events {
+				switch ev.Type {
+				case "move":
+					fmt.Printf("%-6s %s -> %s\n", ev.Type, ev.PrevPath, ev.Path)
+				case "modify":
+					before := old.Entries[ev.Path]
+					fmt.Printf("%-6s %s (size %d -> %d, mtime %s -> %s)\n", ev.Type, ev.Path, before.Size, ev.Info.Size, before.ModTime.Format(time.RFC3339), ev.Info.ModTime.Format(time.RFC3339))
+				default:
+					fmt.Printf("%-6s %s\n", ev.Type, ev.Path)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text or json")
+	return cmd
+}
+
+// snapshotChange is one event of `snapshot diff --format json`.
+type snapshotChange struct {
+	Type     string    `json:"type"`
+	Path     string    `json:"path"`
+	PrevPath string    `json:"prev_path,omitempty"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mtime"`
+}
+
+func checkSnapshotFormat(format string) error {
+	switch format {
+	case "text", "json":
+		return nil
+	}
+	return fmt.Errorf("unknown --format %q (want text or json)", format)
+}
+
+func printSnapshot(w io.Writer, snap scanner.Saved, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snap)
+	}
+	fmt.Fprintf(w, "# root=%s recursive=%v entries=%d\n", snap.Root, snap.Recursive, len(snap.Entries))
+	keys := make([]string, 0, len(snap.Entries))
+	for k := range snap.Entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		info := snap.Entries[k]
+		name := relPath(snap.Root, k)
+		if info.IsDir {
+			name += string(filepath.Separator)
+		}
+		fmt.Fprintf(w, "%s %12d %s %s\n", info.Mode, info.Size, info.ModTime.Format(time.RFC3339), name)
+	}
+	return nil
+}
+
+func relPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
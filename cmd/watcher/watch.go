@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/logging"
+	"watcher-cli/internal/watcher"
+)
+
+// adhocWatch holds the flags of the watch command.
+type adhocWatch struct {
+	include   []string
+	exclude   []string
+	on        []string
+	exec      string
+	webhook   string
+	recursive bool
+	interval  time.Duration
+	debounce  time.Duration
+	dryRun    bool
+}
+
+func watchCmd() *cobra.Command {
+	var o adhocWatch
+	var printConfig bool
+	var logFormat string
+	cmd := &cobra.Command{
+		Use:   "watch <dir>",
+		Short: "Watch a directory with one action given by flags, without a config file",
+		Example: `  watcher watch ./photos --include '**/*.jpg' --on create --exec 'convert {path} {dir}/{stem}.png'
+  watcher watch ./inbox --webhook https://example.com/hook --print-config > watcher.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := o.config(args[0])
+			if err != nil {
+				return err
+			}
+			if printConfig {
+				enc := yaml.NewEncoder(os.Stdout)
+				enc.SetIndent(2)
+				defer enc.Close()
+				return enc.Encode(cfg)
+			}
+			// Leave the socket to a configured watcher running alongside.
+			cfg.Global.ControlSocket = config.ControlSocketNone
+			if err := cfg.Prepare(); err != nil {
+				return err
+			}
+			switch config.LogFormat(logFormat) {
+			case "", config.LogText, config.LogJSON:
+			default:
+				return fmt.Errorf("unknown --log-format %q (want text or json)", logFormat)
+			}
+			logger := logging.New(slog.LevelInfo, config.LogFormat(logFormat))
+			slog.SetDefault(logger)
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+			super := watcher.NewSupervisor(cfg, logger, cfg.Global.DryRun)
+			logger.Info("watching", "path", cfg.Watches[0].Path, "events", cfg.Watches[0].Actions[0].Events)
+			return super.Run(ctx)
+		},
+	}
+	f := cmd.Flags()
+	f.StringArrayVar(&o.include, "include", nil, "glob of files to act on, relative to dir (repeatable; default all)")
+	f.StringArrayVar(&o.exclude, "exclude", nil, "glob of files to ignore (repeatable)")
+	f.StringSliceVar(&o.on, "on", []string{"create", "modify"}, "events to act on: create, modify, delete, move")
+	f.StringVar(&o.exec, "exec", "", "command to run per event; tokens such as {path} are expanded")
+	f.StringVar(&o.webhook, "webhook", "", "URL to POST each event to as JSON")
+	f.BoolVar(&o.recursive, "recursive", true, "watch subdirectories too")
+	f.DurationVar(&o.interval, "interval", time.Second, "scan interval")
+	f.DurationVar(&o.debounce, "debounce", 200*time.Millisecond, "ignore repeated events for a file within this window")
+	f.BoolVar(&o.dryRun, "dry-run", false, "log what would run without running it")
+	f.BoolVar(&printConfig, "print-config", false, "print the equivalent config file and exit")
+	f.StringVar(&logFormat, "log-format", "", "log format: text or json (default text)")
+	return cmd
+}
+
+// config builds a config with one watch on dir and one action.
+func (o adhocWatch) config(dir string) (config.Config, error) {
+	if (o.exec == "") == (o.webhook == "") {
+		return config.Config{}, errors.New("give exactly one of --exec or --webhook")
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return config.Config{}, err
+	}
+	if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+		return config.Config{}, fmt.Errorf("%s is not a directory", dir)
+	}
+	action := config.Action{
+		Name:    "exec",
+		Type:    config.ActionExec,
+		Include: o.include,
+		Exclude: o.exclude,
+		Cmd:     config.Command{Line: o.exec},
+	}
+	if o.webhook != "" {
+		action = config.Action{Name: "webhook", Type: config.ActionWebhook, Include: o.include, Exclude: o.exclude, URL: o.webhook}
+	}
+	for _, ev := range o.on {
+		switch e := config.EventType(ev); e {
+		case config.EventCreate, config.EventModify, config.EventDelete, config.EventMove:
+			action.Events = append(action.Events, e)
+		default:
+			return config.Config{}, fmt.Errorf("unknown --on event %q (want create, modify, delete or move)", ev)
+		}
+	}
+	return config.Config{
+		Version: config.CurrentVersion,
+		Global: config.Global{
+			ScanInterval: config.MillisFromDuration(o.interval),
+			Debounce:     config.MillisFromDuration(o.debounce),
+			DryRun:       o.dryRun,
+		},
+		Watches: []config.Watch{{
+			Path:      abs,
+			Recursive: o.recursive,
+			Actions:   []config.Action{action},
+		}},
+	}, nil
+}
@@ -2,12 +2,20 @@ package actions
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
+	"strconv"
 	"time"
 
+	"watcher-cli/internal/chaos"
 	"watcher-cli/internal/config"
 	"watcher-cli/internal/template"
+	"watcher-cli/internal/unorm"
 )
 
 // Runner executes a single action.
@@ -18,22 +26,42 @@ type Runner interface {
 // Registry maps action types to runners.
 type Registry struct {
 	entries map[config.ActionType]Runner
+	// custom holds the runners added with Register, which per-watch
+	// registries keep instead of fresh defaults.
+	custom map[config.ActionType]Runner
 }
 
 // NewRegistry builds default registry.
 func NewRegistry() *Registry {
-	r := &Registry{entries: map[config.ActionType]Runner{}}
-	r.Register(config.ActionExec, &ExecRunner{})
-	r.Register(config.ActionCopy, &CopyMoveRunner{Mode: config.ActionCopy})
-	r.Register(config.ActionMove, &CopyMoveRunner{Mode: config.ActionMove})
-	r.Register(config.ActionRename, &CopyMoveRunner{Mode: config.ActionRename})
-	r.Register(config.ActionWebhook, &WebhookRunner{})
-	return r
+	return &Registry{entries: map[config.ActionType]Runner{
+		config.ActionExec:          &ExecRunner{},
+		config.ActionCopy:          &CopyMoveRunner{Mode: config.ActionCopy},
+		config.ActionMove:          &CopyMoveRunner{Mode: config.ActionMove},
+		config.ActionRename:        &CopyMoveRunner{Mode: config.ActionRename},
+		config.ActionWebhook:       &WebhookRunner{},
+		config.ActionRenamePattern: &RenamePatternRunner{},
+		config.ActionClamScan:      &ClamScanRunner{},
+		config.ActionTransfer:      &TransferRunner{},
+		config.ActionTag:           &TagRunner{},
+		config.ActionArchive:       &ArchiveRunner{},
+		config.ActionSSHExec:       &SSHRunner{},
+	}, custom: map[config.ActionType]Runner{}}
 }
 
 // Register adds a runner.
 func (r *Registry) Register(kind config.ActionType, runner Runner) {
 	r.entries[kind] = runner
+	r.custom[kind] = runner
+}
+
+// forWatch returns a registry with fresh default runners and the runners
+// added to r with Register.
+func (r *Registry) forWatch() *Registry {
+	fresh := NewRegistry()
+	for kind, runner := range r.custom {
+		fresh.Register(kind, runner)
+	}
+	return fresh
 }
 
 // Get fetches a runner.
@@ -42,61 +70,332 @@ func (r *Registry) Get(kind config.ActionType) (Runner, bool) {
 	return val, ok
 }
 
+// Close releases what the registry's default runners hold, such as the
+// ssh_exec connection sockets. Runners added with Register belong to the
+// caller and are left open.
+func (r *Registry) Close() error {
+	var errs []error
+	for kind, runner := range r.entries {
+		if _, ok := r.custom[kind]; ok {
+			continue
+		}
+		if c, ok := runner.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Executor wraps runners with retries/timeouts and templating.
 type Executor struct {
 	Registry *Registry
 	DryRun   bool
+	// Budget, when set, limits retries across all actions.
+	Budget *RetryBudget
+	// Faults, when set, injects failures for resilience testing.
+	Faults *chaos.Injector
+	// Limits, when set, enforces the actions' rate_limit.
+	Limits *RateLimits
+	// Safety, when set, enforces global.safety.
+	Safety *Guard
 }
 
 // Context is the data for templating and payloads.
 type Context struct {
+	// ID correlates logs, child env and payloads for one event.
+	ID       string
 	Path     string
 	RelPath  string
 	PrevPath string
-	Event    string
-	Size     int64
-	ModTime  time.Time
-	Age      time.Duration
-	IsDir    bool
+	// PrevRelPath is PrevPath relative to the watch root.
+	PrevRelPath string
+	Event       string
+	Size        int64
+	ModTime     time.Time
+	Age         time.Duration
+	IsDir       bool
+	// DeletedAt is when a delete event was detected. For deletes, Size,
+	// ModTime and Age are the last-known values as of deletion.
+	DeletedAt time.Time
+	// Location is the watch's time zone for time tokens.
+	Location *time.Location
+	// Normalize is applied to copy, move and rename destinations.
+	Normalize unorm.Form
+	// Duplicates lists other paths with identical content (duplicate events).
+	Duplicates []string
+	// Meta holds extracted metadata keyed as "<kind>:<field>".
+	Meta map[string]string
+	// Cache is shared by all actions of one event to read the source once;
+	// nil reads from disk.
+	Cache *ReadCache
+	// Outputs is shared by all actions of one event; captured values are
+	// stored here and exposed to later actions as {out:<var>}.
+	Outputs map[string]string
+	// Remote opens the event's file when the watch lists a remote source;
+	// nil for local watches.
+	Remote Remote
 }
 
-// Execute runs an action with retries and timeout.
+// Execute runs an action with retries and timeout, retrying in place
+// after the action's backoff. Callers that must not block on retries use
+// Attempt, AllowRetry and RetryDelay.
 func (e *Executor) Execute(ctx context.Context, ev Context, action config.Action) error {
+	return e.retry(ctx, ev, action, func() error { return e.Attempt(ctx, ev, action) })
+}
+
+// retry calls attempt until it succeeds, fails permanently or runs out of
+// the retries the action allows for the class of its latest error.
+func (e *Executor) retry(ctx context.Context, ev Context, action config.Action, attempt func() error) error {
+	for n := 0; ; n++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+		class := Classify(err)
+		if !Retryable(err) || n >= action.RetriesFor(class) {
+			return err
+		}
+		slog.Warn("action attempt failed", "event_id", ev.ID, "action", action.Name, "attempt", n+1, "error_class", class, "err", err)
+		if !e.AllowRetry(ev, action) {
+			return err
+		}
+		if sleepCtx(ctx, RetryDelay(action, n+1)) != nil {
+			return err
+		}
+	}
+}
+
+// NewExecutor returns the executor for actions under g, as the daemon and
+// one-shot commands such as backfill and replay run them: with g's retry
+// budget, safety policy and per-action rate limits.
+func NewExecutor(g config.Global, dryRun bool) *Executor {
+	var budget *RetryBudget
+	if g.RetryBudget > 0 {
+		budget = NewRetryBudget(g.RetryBudget)
+	}
+	return &Executor{Registry: NewRegistry(), DryRun: dryRun, Budget: budget, Limits: NewRateLimits(), Safety: NewGuard(g.Safety)}
+}
+
+// ForWatch returns an executor with runners of its own for one watch. It
+// shares e's global limits, such as the retry budget, and fault injection,
+// and keeps the runners registered on e.
+func (e *Executor) ForWatch() *Executor {
+	return &Executor{Registry: e.Registry.forWatch(), DryRun: e.DryRun, Budget: e.Budget, Faults: e.Faults, Limits: NewRateLimits(), Safety: e.Safety}
+}
+
+// Close releases what e's default runners hold once e is done with.
+func (e *Executor) Close() error {
+	return e.Registry.Close()
+}
+
+// ErrPanic marks an action whose runner panicked.
+var ErrPanic = errors.New("action panicked")
+
+// abandonGrace is how long a runner may overrun its timeout before the
+// attempt gives up on it, so a runner ignoring cancellation cannot wedge
+// the watch.
+const abandonGrace = 5 * time.Second
+
+// Attempt runs an action once under its timeout. A panicking runner fails
+// the attempt permanently instead of crashing the watcher.
+func (e *Executor) Attempt(ctx context.Context, ev Context, action config.Action) error {
 	runner, ok := e.Registry.Get(action.Type)
 	if !ok {
-		return fmt.Errorf("no runner for type %s", action.Type)
+		return Permanent(fmt.Errorf("no runner for type %s", action.Type))
+	}
+	if err := e.Safety.Check(ev, action); err != nil {
+		return err
+	}
+	if err := e.throttle(ctx, ev, action); err != nil {
+		return err
 	}
+	if err := e.Faults.FailAction(action.Name); err != nil {
+		return err
+	}
+	return e.guard(ctx, ev.ID, action, func(ctx context.Context) error {
+		return runner.Run(ctx, ev, action)
+	})
+}
+
+// guard calls run under the action's timeout, turning a panic into a
+// permanent error and giving up on a run that ignores cancellation.
+func (e *Executor) guard(ctx context.Context, id string, action config.Action, run func(context.Context) error) error {
 	timeout := action.Timeout.Duration()
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
-	run := func() error {
-		ctxRun, cancel := context.WithTimeout(ctx, timeout)
-		defer cancel()
-		return runner.Run(ctxRun, ev, action)
+	ctxRun, cancel := context.WithTimeout(chaos.With(ctx, e.Faults), timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("action panicked", "event_id", id, "action", action.Name, "panic", r, "stack", string(debug.Stack()))
+				done <- Permanent(fmt.Errorf("%w: %v", ErrPanic, r))
+			}
+		}()
+		done <- run(ctxRun)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctxRun.Done():
 	}
-	var lastErr error
-	for attempt := 0; attempt <= action.Retries; attempt++ {
-		if err := run(); err != nil {
-			lastErr = err
-			continue
-		}
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(abandonGrace):
+		slog.Error("action ignored cancellation, abandoning it", "event_id", id, "action", action.Name)
+		return Permanent(fmt.Errorf("action did not stop within %s of its timeout: %w", abandonGrace, ctxRun.Err()))
+	}
+}
+
+// AllowRetry takes a retry from the budget, if any, and reports whether
+// the retry may proceed.
+func (e *Executor) AllowRetry(ev Context, action config.Action) bool {
+	if e.Budget == nil {
+		return true
+	}
+	ok, alert := e.Budget.Take()
+	if alert {
+		slog.Warn("retry budget exhausted, pausing retries", "event_id", ev.ID, "action", action.Name)
+	}
+	return ok
+}
+
+// Retryable reports whether a failed attempt is worth repeating.
+func Retryable(err error) bool {
+	return err != nil && !IsPermanent(err) && !errors.Is(err, ErrSkip)
+}
+
+// NewEventID returns a random identifier for a detected event.
+func NewEventID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ErrSkip reports success while asking the caller to skip the remaining
+// actions for the event.
+var ErrSkip = errors.New("remaining actions skipped")
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
 		return nil
 	}
-	if lastErr == nil {
-		lastErr = errors.New("unknown action error")
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// EventPayload is the JSON representation of an event shared by webhooks
+// and exec stdin.
+func EventPayload(ev Context) map[string]interface{} {
+	payload := map[string]interface{}{
+		"id":        ev.ID,
+		"path":      ev.Path,
+		"relpath":   ev.RelPath,
+		"prev_path": ev.PrevPath,
+		"event":     ev.Event,
+		"size":      ev.Size,
+		"mtime":     ev.ModTime,
+		"age_ms":    ev.Age.Milliseconds(),
+		"is_dir":    ev.IsDir,
+	}
+	if len(ev.Duplicates) > 0 {
+		payload["duplicates"] = ev.Duplicates
 	}
-	return lastErr
+	if !ev.DeletedAt.IsZero() {
+		payload["deleted_at"] = ev.DeletedAt
+		payload["last_known"] = true
+	}
+	return payload
 }
 
 // BuildTemplateContext converts action Context to template.Context.
 func BuildTemplateContext(ev Context) template.Context {
 	return template.Context{
-		Path:    ev.Path,
-		RelPath: ev.RelPath,
-		Event:   ev.Event,
-		Size:    ev.Size,
-		ModTime: ev.ModTime,
-		Age:     ev.Age,
+		ID:          ev.ID,
+		Path:        ev.Path,
+		RelPath:     ev.RelPath,
+		PrevPath:    ev.PrevPath,
+		PrevRelPath: ev.PrevRelPath,
+		Event:       ev.Event,
+		Size:        ev.Size,
+		ModTime:     ev.ModTime,
+		Age:         ev.Age,
+		Duplicates:  ev.Duplicates,
+		DeletedAt:   ev.DeletedAt,
+		Location:    ev.Location,
+		Meta:        templateMeta(ev),
+		LastOutput:  ev.Outputs[lastOutput],
+	}
+}
+
+func templateMeta(ev Context) map[string]string {
+	if len(ev.Outputs) == 0 {
+		return ev.Meta
+	}
+	meta := make(map[string]string, len(ev.Meta)+len(ev.Outputs))
+	for k, v := range ev.Meta {
+		meta[k] = v
+	}
+	for k, v := range ev.Outputs {
+		meta["out:"+k] = v
+	}
+	return meta
+}
+
+// destOutput is the Outputs key holding the destination the running
+// action wrote to; capture variable names cannot contain "@".
+const destOutput = "@dest"
+
+// lastOutput is the Outputs key holding the stdout of the event's latest
+// exec or ssh_exec action, for {last_output}.
+const lastOutput = "@last_output"
+
+// TakeDest returns and forgets the destination the last action wrote to,
+// or "" when it wrote none.
+func TakeDest(ev Context) string {
+	dest := ev.Outputs[destOutput]
+	delete(ev.Outputs, destOutput)
+	return dest
+}
+
+// capture stores value under every variable of cfg.Capture bound to source.
+func capture(ev Context, cfg config.Action, source config.CaptureSource, value string) {
+	if ev.Outputs == nil {
+		return
+	}
+	if source == config.CaptureDest {
+		ev.Outputs[destOutput] = value
+	}
+	for name, src := range cfg.Capture {
+		if src == source {
+			ev.Outputs[name] = value
+		}
+	}
+}
+
+func wantsCapture(cfg config.Action, source config.CaptureSource) bool {
+	for _, src := range cfg.Capture {
+		if src == source {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,353 @@
+package actions
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/template"
+)
+
+// archiveLocks serializes writers of the same archive across watches.
+var archiveLocks sync.Map // dest -> *sync.Mutex
+
+// ArchiveRunner adds the file to a .tar.gz or .zip archive, creating it or
+// rewriting it with the existing entries kept, then removes the source
+// unless keep_source is set. Entries are named by the file's path relative
+// to the watch. Every run rewrites the whole archive, so a batch block,
+// which adds a batch's files in one rewrite, keeps busy archives cheap.
+type ArchiveRunner struct{}
+
+func (r *ArchiveRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	return r.RunBatch(ctx, []Context{ev}, cfg)
+}
+
+// RunBatch adds the events' files to their archives, rewriting each
+// archive once.
+func (r *ArchiveRunner) RunBatch(ctx context.Context, evs []Context, cfg config.Action) error {
+	groups := map[string][]Context{}
+	var dests []string
+	for _, ev := range evs {
+		if ev.IsDir {
+			continue
+		}
+		dest := template.Expand(cfg.Dest, BuildTemplateContext(ev))
+		if config.ArchiveFormat(dest) == "" {
+			return Permanent(fmt.Errorf("archive dest %q must end in .tar.gz, .tgz or .zip", dest))
+		}
+		if _, ok := groups[dest]; !ok {
+			dests = append(dests, dest)
+		}
+		groups[dest] = append(groups[dest], ev)
+	}
+	for _, dest := range dests {
+		if err := addToArchive(ctx, dest, groups[dest], cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveEntry is a file being added to an archive.
+type archiveEntry struct {
+	ev   Context
+	name string
+	src  *os.File
+	info os.FileInfo
+	// added is set once the entry is in the new archive; an entry that
+	// on_conflict skip turned away keeps its source. A superseded entry
+	// was replaced by a later one of the same batch and is not written.
+	added, superseded bool
+}
+
+// addToArchive adds the events' files to the archive at dest in one
+// rewrite.
+func addToArchive(ctx context.Context, dest string, evs []Context, cfg config.Action) error {
+	mu, _ := archiveLocks.LoadOrStore(dest, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	entries := make([]*archiveEntry, 0, len(evs))
+	for _, ev := range evs {
+		src, err := os.Open(ev.Path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		info, err := src.Stat()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, &archiveEntry{ev: ev, name: archiveEntryName(ev), src: src, info: info})
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	tmp := dest + ".partial"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if config.ArchiveFormat(dest) == "zip" {
+		err = rewriteZip(ctx, dest, out, entries, conflictPolicy(cfg))
+	} else {
+		err = rewriteTarGz(ctx, dest, out, entries, conflictPolicy(cfg))
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if errors.Is(err, errArchiveSkip) {
+		os.Remove(tmp)
+		return nil
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return err
+	}
+	if err := syncDir(filepath.Dir(dest)); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.added {
+			continue
+		}
+		if !cfg.KeepSource {
+			if err := os.Remove(e.ev.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		capture(e.ev, cfg, config.CaptureDest, dest)
+	}
+	return nil
+}
+
+// resolveEntries applies the conflict policy to the new entries against
+// the archive's names and each other. It returns the existing names the
+// new entries replace, or errArchiveSkip when none is left to add.
+func resolveEntries(entries []*archiveEntry, names map[string]bool, policy config.ConflictPolicy) (map[string]bool, error) {
+	taken := map[string]*archiveEntry{}
+	replaced := map[string]bool{}
+	exists := func(n string) bool { return names[n] || taken[n] != nil }
+	for _, e := range entries {
+		name, replace, err := entryConflict(e.name, exists, policy)
+		if errors.Is(err, errArchiveSkip) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if replace {
+			if prev := taken[name]; prev != nil {
+				// A later file of the batch wins, as it would have run
+				// after the earlier one.
+				prev.superseded = true
+			} else {
+				replaced[name] = true
+			}
+		}
+		e.name, e.added = name, true
+		taken[name] = e
+	}
+	if len(taken) == 0 {
+		return nil, errArchiveSkip
+	}
+	return replaced, nil
+}
+
+// errArchiveSkip reports that on_conflict skip found the entry present.
+var errArchiveSkip = errors.New("archive entry exists")
+
+// archiveEntryName is the event's path relative to the watch, with forward
+// slashes and no leading slash.
+func archiveEntryName(ev Context) string {
+	name := ev.RelPath
+	if name == "" {
+		name = filepath.Base(ev.Path)
+	}
+	return strings.TrimLeft(filepath.ToSlash(name), "/")
+}
+
+// entryConflict applies the conflict policy to an entry name already in
+// the archive: the name to add, or whether to drop the existing entry.
+func entryConflict(name string, exists func(string) bool, policy config.ConflictPolicy) (string, bool, error) {
+	if !exists(name) {
+		return name, false, nil
+	}
+	switch policy {
+	case config.ConflictOverwrite:
+		return name, true, nil
+	case config.ConflictSkip:
+		return "", false, errArchiveSkip
+	case config.ConflictSuffix:
+		dir, base := "", name
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			dir, base = name[:i+1], name[i+1:]
+		}
+		ext := filepath.Ext(base)
+		stem := strings.TrimSuffix(base, ext)
+		for i := 1; i < 10000; i++ {
+			candidate := fmt.Sprintf("%s%s (%d)%s", dir, stem, i, ext)
+			if !exists(candidate) {
+				return candidate, false, nil
+			}
+		}
+		return "", false, fmt.Errorf("no free entry name for %s", name)
+	default:
+		return "", false, Permanent(fmt.Errorf("archive entry exists: %s", name))
+	}
+}
+
+func rewriteZip(ctx context.Context, dest string, out io.Writer, entries []*archiveEntry, policy config.ConflictPolicy) error {
+	zw := zip.NewWriter(out)
+	var existing *zip.ReadCloser
+	if _, err := os.Stat(dest); err == nil {
+		existing, err = zip.OpenReader(dest)
+		if err != nil {
+			return Permanent(fmt.Errorf("open archive %s: %w", dest, err))
+		}
+		defer existing.Close()
+	}
+	names := map[string]bool{}
+	if existing != nil {
+		for _, f := range existing.File {
+			names[f.Name] = true
+		}
+	}
+	replaced, err := resolveEntries(entries, names, policy)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		for _, f := range existing.File {
+			if replaced[f.Name] {
+				continue
+			}
+			// Copy keeps entries compressed as they are.
+			if err := zw.Copy(f); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range entries {
+		if !e.added || e.superseded {
+			continue
+		}
+		hdr, err := zip.FileInfoHeader(e.info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = e.name
+		hdr.Method = zip.Deflate
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, readerWithContext(ctx, e.src)); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func rewriteTarGz(ctx context.Context, dest string, out io.Writer, entries []*archiveEntry, policy config.ConflictPolicy) error {
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	var names map[string]bool
+	var existing *os.File
+	if f, err := os.Open(dest); err == nil {
+		existing = f
+		defer existing.Close()
+		// A first pass collects the names so conflicts are known before
+		// anything is written.
+		names, err = tarNames(existing)
+		if err != nil {
+			return Permanent(fmt.Errorf("read archive %s: %w", dest, err))
+		}
+		if _, err := existing.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	replaced, err := resolveEntries(entries, names, policy)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		zr, err := gzip.NewReader(existing)
+		if err != nil {
+			return err
+		}
+		tr := tar.NewReader(zr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if replaced[hdr.Name] {
+				continue
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, readerWithContext(ctx, tr)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range entries {
+		if !e.added || e.superseded {
+			continue
+		}
+		hdr, err := tar.FileInfoHeader(e.info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = e.name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, readerWithContext(ctx, e.src)); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func tarNames(r io.Reader) (map[string]bool, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(zr)
+	names := map[string]bool{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names[hdr.Name] = true
+	}
+}
@@ -0,0 +1,145 @@
+package actions
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+// archiveContents lists "name=content" for every entry of a tar.gz or zip.
+func archiveContents(t *testing.T, path string) []string {
+	t.Helper()
+	var out []string
+	if strings.HasSuffix(path, ".zip") {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatalf("open zip: %v", err)
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			out = append(out, f.Name+"="+string(data))
+		}
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip: %v", err)
+		}
+		tr := tar.NewReader(zr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("tar: %v", err)
+			}
+			data, _ := io.ReadAll(tr)
+			out = append(out, hdr.Name+"="+string(data))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestArchiveAppends(t *testing.T) {
+	for _, ext := range []string{".tar.gz", ".zip"} {
+		t.Run(ext, func(t *testing.T) {
+			dir := t.TempDir()
+			dest := filepath.Join(dir, "backup", "logs"+ext)
+			cfg := config.Action{Type: config.ActionArchive, Dest: dest}
+			add := func(rel, content string, cfg config.Action) error {
+				src := filepath.Join(dir, filepath.FromSlash(rel))
+				os.MkdirAll(filepath.Dir(src), 0o755)
+				if err := os.WriteFile(src, []byte(content), 0o644); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+				return (&ArchiveRunner{}).Run(context.Background(), Context{Path: src, RelPath: rel}, cfg)
+			}
+			if err := add("a.log", "one", cfg); err != nil {
+				t.Fatalf("first: %v", err)
+			}
+			if err := add("sub/b.log", "two", cfg); err != nil {
+				t.Fatalf("second: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "a.log")); !os.IsNotExist(err) {
+				t.Fatalf("expected source removed, got %v", err)
+			}
+			// The same entry name conflicts like a file would.
+			if err := add("a.log", "again", cfg); err == nil || !IsPermanent(err) {
+				t.Fatalf("expected a permanent conflict error, got %v", err)
+			}
+			cfg.OnConflict = config.ConflictSuffix
+			cfg.KeepSource = true
+			if err := add("a.log", "again", cfg); err != nil {
+				t.Fatalf("suffix: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "a.log")); err != nil {
+				t.Fatalf("keep_source removed the source: %v", err)
+			}
+			got := strings.Join(archiveContents(t, dest), ",")
+			if want := "a (1).log=again,a.log=one,sub/b.log=two"; got != want {
+				t.Fatalf("archive holds %s, want %s", got, want)
+			}
+			cfg.OnConflict = config.ConflictOverwrite
+			if err := add("sub/b.log", "three", cfg); err != nil {
+				t.Fatalf("overwrite: %v", err)
+			}
+			got = strings.Join(archiveContents(t, dest), ",")
+			if want := "a (1).log=again,a.log=one,sub/b.log=three"; got != want {
+				t.Fatalf("archive holds %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestArchiveBatchRewritesOnce(t *testing.T) {
+	for _, ext := range []string{".tar.gz", ".zip"} {
+		t.Run(ext, func(t *testing.T) {
+			dir := t.TempDir()
+			dest := filepath.Join(dir, "logs"+ext)
+			var evs []Context
+			for rel, content := range map[string]string{"a.log": "one", "b.log": "two", "sub/a.log": "three"} {
+				src := filepath.Join(dir, "in", filepath.FromSlash(rel))
+				os.MkdirAll(filepath.Dir(src), 0o755)
+				os.WriteFile(src, []byte(content), 0o644)
+				evs = append(evs, Context{Path: src, RelPath: rel})
+			}
+			// A same-named file later in the batch gets a suffix.
+			dup := filepath.Join(dir, "in", "b2", "b.log")
+			os.MkdirAll(filepath.Dir(dup), 0o755)
+			os.WriteFile(dup, []byte("four"), 0o644)
+			evs = append(evs, Context{Path: dup, RelPath: "b.log"})
+
+			cfg := config.Action{Type: config.ActionArchive, Dest: dest, OnConflict: config.ConflictSuffix}
+			if err := (&ArchiveRunner{}).RunBatch(context.Background(), evs, cfg); err != nil {
+				t.Fatalf("batch: %v", err)
+			}
+			got := strings.Join(archiveContents(t, dest), ",")
+			if want := "a.log=one,b (1).log=four,b.log=two,sub/a.log=three"; got != want {
+				t.Fatalf("archive holds %s, want %s", got, want)
+			}
+			for _, ev := range evs {
+				if _, err := os.Stat(ev.Path); !os.IsNotExist(err) {
+					t.Fatalf("expected %s removed, got %v", ev.Path, err)
+				}
+			}
+		})
+	}
+}
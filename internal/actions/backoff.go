@@ -0,0 +1,55 @@
+package actions
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+// MaxRetryDelay caps the backoff between retries, unless an action's own
+// retry_backoff_ms is longer.
+const MaxRetryDelay = time.Minute
+
+// RetryDelay returns the wait before retry number attempt (from 1) of
+// action: retry_backoff_ms grown by retry_backoff_factor per further
+// attempt, spread by up to ±retry_jitter of itself.
+func RetryDelay(action config.Action, attempt int) time.Duration {
+	base := action.Backoff()
+	if base <= 0 {
+		return 0
+	}
+	factor := action.RetryBackoffFactor
+	if factor < 1 {
+		factor = 1
+	}
+	limit := MaxRetryDelay
+	if base > limit {
+		limit = base
+	}
+	delay := float64(base) * math.Pow(factor, float64(attempt-1))
+	if delay > float64(limit) {
+		delay = float64(limit)
+	}
+	if j := action.Jitter(); j > 0 {
+		delay *= 1 + j*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+func TestRetryDelay(t *testing.T) {
+	millis := func(d time.Duration) *config.MillisDuration {
+		m := config.MillisFromDuration(d)
+		return &m
+	}
+	a := config.Action{RetryBackoff: millis(time.Second), RetryBackoffFactor: 3}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 3 * time.Second, 3: 9 * time.Second, 5: MaxRetryDelay} {
+		if got := RetryDelay(a, attempt); got != want {
+			t.Fatalf("attempt %d: delay %s, want %s", attempt, got, want)
+		}
+	}
+	jitter := 0.5
+	a.RetryJitter = &jitter
+	for i := 0; i < 100; i++ {
+		if got := RetryDelay(a, 2); got < 1500*time.Millisecond || got > 4500*time.Millisecond {
+			t.Fatalf("jittered delay %s outside ±50%% of 3s", got)
+		}
+	}
+	// A backoff above the cap is used as is.
+	long := config.Action{RetryBackoff: millis(2 * time.Minute), RetryBackoffFactor: 2}
+	if got := RetryDelay(long, 3); got != 2*time.Minute {
+		t.Fatalf("long backoff capped to %s", got)
+	}
+	if got := RetryDelay(config.Action{}, 1); got != 0 {
+		t.Fatalf("unset backoff should not wait, got %s", got)
+	}
+}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"watcher-cli/internal/config"
+)
+
+// BatchRunner is implemented by runners that can handle many events in
+// one run, for actions with a batch block.
+type BatchRunner interface {
+	RunBatch(ctx context.Context, evs []Context, cfg config.Action) error
+}
+
+// ExecuteBatch runs a batched action once for evs, retrying in place like
+// Execute. The first event fills the action's other template tokens.
+func (e *Executor) ExecuteBatch(ctx context.Context, evs []Context, action config.Action) error {
+	if len(evs) == 0 {
+		return nil
+	}
+	return e.retry(ctx, evs[0], action, func() error { return e.AttemptBatch(ctx, evs, action) })
+}
+
+// AttemptBatch runs a batched action once under its timeout; the batch
+// counts as one run against the action's rate_limit.
+func (e *Executor) AttemptBatch(ctx context.Context, evs []Context, action config.Action) error {
+	runner, ok := e.Registry.Get(action.Type)
+	if !ok {
+		return Permanent(fmt.Errorf("no runner for type %s", action.Type))
+	}
+	br, ok := runner.(BatchRunner)
+	if !ok {
+		return Permanent(fmt.Errorf("%s actions cannot run in batches", action.Type))
+	}
+	for _, ev := range evs {
+		if err := e.Safety.Check(ev, action); err != nil {
+			return err
+		}
+	}
+	if err := e.throttle(ctx, evs[0], action); err != nil {
+		return err
+	}
+	if err := e.Faults.FailAction(action.Name); err != nil {
+		return err
+	}
+	return e.guard(ctx, evs[0].ID, action, func(ctx context.Context) error {
+		return br.RunBatch(ctx, evs, action)
+	})
+}
+
+// batchPaths lists the events' paths in order.
+func batchPaths(evs []Context) []string {
+	paths := make([]string, len(evs))
+	for i, ev := range evs {
+		paths[i] = ev.Path
+	}
+	return paths
+}
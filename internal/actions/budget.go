@@ -0,0 +1,44 @@
+package actions
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudget caps the number of retries across all actions within a
+// rolling one-minute window, so an outage is not amplified by every queued
+// failure retrying at once.
+type RetryBudget struct {
+	mu      sync.Mutex
+	limit   int
+	start   time.Time
+	used    int
+	alerted bool
+	now     func() time.Time
+}
+
+// NewRetryBudget allows perMinute retries per minute.
+func NewRetryBudget(perMinute int) *RetryBudget {
+	return &RetryBudget{limit: perMinute, now: time.Now}
+}
+
+// Take consumes one retry. It reports whether the retry may proceed and,
+// when it may not, whether this is the first refusal in the window so the
+// caller can raise a single alert.
+func (b *RetryBudget) Take() (ok, alert bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if now.Sub(b.start) >= time.Minute {
+		b.start = now
+		b.used = 0
+		b.alerted = false
+	}
+	if b.used < b.limit {
+		b.used++
+		return true, false
+	}
+	alert = !b.alerted
+	b.alerted = true
+	return false, alert
+}
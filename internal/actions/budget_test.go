@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewRetryBudget(2)
+	b.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if ok, _ := b.Take(); !ok {
+			t.Fatalf("retry %d refused", i)
+		}
+	}
+	if ok, alert := b.Take(); ok || !alert {
+		t.Fatalf("expected first refusal to alert, got ok=%v alert=%v", ok, alert)
+	}
+	if ok, alert := b.Take(); ok || alert {
+		t.Fatalf("expected silent refusal, got ok=%v alert=%v", ok, alert)
+	}
+	now = now.Add(time.Minute)
+	if ok, _ := b.Take(); !ok {
+		t.Fatalf("budget not refilled after window")
+	}
+}
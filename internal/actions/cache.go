@@ -0,0 +1,106 @@
+package actions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ReadCache shares reads of source files between the actions of one event,
+// so copying, scanning and uploading the same file does not read it from
+// disk each time. Files up to a size limit are buffered in memory and
+// SHA-256 sums are computed once. Entries are revalidated against size and
+// mtime on every use. A nil cache reads straight from disk.
+type ReadCache struct {
+	max int64
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	size  int64
+	mtime time.Time
+	data  []byte // nil when the file is larger than the limit
+	sum   string
+}
+
+// NewReadCache returns a cache buffering files of at most max bytes.
+func NewReadCache(max int64) *ReadCache {
+	return &ReadCache{max: max, entries: map[string]*cacheEntry{}}
+}
+
+// Open returns the contents of path, from memory when buffered.
+func (c *ReadCache) Open(path string) (io.ReadCloser, error) {
+	if c == nil {
+		return os.Open(path)
+	}
+	e, err := c.entry(path)
+	if err != nil {
+		return nil, err
+	}
+	if e.data != nil {
+		return io.NopCloser(bytes.NewReader(e.data)), nil
+	}
+	return os.Open(path)
+}
+
+// SHA256 returns the hex SHA-256 sum and size of path.
+func (c *ReadCache) SHA256(path string) (string, int64, error) {
+	if c == nil {
+		return hashPath(path)
+	}
+	e, err := c.entry(path)
+	if err != nil {
+		return "", 0, err
+	}
+	c.mu.Lock()
+	sum := e.sum
+	c.mu.Unlock()
+	if sum != "" {
+		return sum, e.size, nil
+	}
+	if e.data != nil {
+		h := sha256.Sum256(e.data)
+		sum = hex.EncodeToString(h[:])
+	} else if sum, _, err = hashPath(path); err != nil {
+		return "", 0, err
+	}
+	c.mu.Lock()
+	e.sum = sum
+	c.mu.Unlock()
+	return sum, e.size, nil
+}
+
+// entry returns a current entry for path, reading small files into memory.
+func (c *ReadCache) entry(path string) (*cacheEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	e, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && e.size == info.Size() && e.mtime.Equal(info.ModTime()) {
+		return e, nil
+	}
+	e = &cacheEntry{size: info.Size(), mtime: info.ModTime()}
+	if info.Mode().IsRegular() && info.Size() <= c.max {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		// A file still growing is not worth keeping.
+		if int64(len(data)) == info.Size() {
+			e.data = data
+		}
+	}
+	c.mu.Lock()
+	c.entries[path] = e
+	c.mu.Unlock()
+	return e, nil
+}
@@ -0,0 +1,48 @@
+package actions
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadCacheServesBufferedCopyAndRevalidates(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(src, []byte("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewReadCache(1 << 20)
+	sum, size, err := c.SHA256(src)
+	if err != nil || size != 5 {
+		t.Fatalf("sha256: %v (size %d)", err, size)
+	}
+	if want, _, _ := hashPath(src); sum != want {
+		t.Fatalf("sum %s, want %s", sum, want)
+	}
+	// Copies of a buffered file come from memory.
+	if err := copyFile(context.Background(), src, filepath.Join(dir, "out", "a.txt"), copyOptions{cache: c}); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "out", "a.txt")); string(got) != "first" {
+		t.Fatalf("unexpected copy %q", got)
+	}
+
+	// A rewritten file is read again.
+	if err := os.WriteFile(src, []byte("second!"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(src, later, later)
+	r, err := c.Open(src)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer r.Close()
+	if got, _ := io.ReadAll(r); string(got) != "second!" {
+		t.Fatalf("expected fresh contents, got %q", got)
+	}
+}
@@ -0,0 +1,132 @@
+package actions
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+
+	"watcher-cli/internal/config"
+)
+
+const clamChunkSize = 64 << 10
+
+// InfectedError reports a clamd detection.
+type InfectedError struct {
+	Path      string
+	Signature string
+}
+
+func (e *InfectedError) Error() string {
+	return fmt.Sprintf("infected: %s (%s)", e.Path, e.Signature)
+}
+
+// ClamScanRunner streams files to clamd with the INSTREAM command.
+type ClamScanRunner struct{}
+
+func (r *ClamScanRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	if ev.IsDir {
+		return nil
+	}
+	network, addr, err := parseClamdAddr(cfg.Clamd)
+	if err != nil {
+		return Permanent(err)
+	}
+	f, err := ev.Cache.Open(ev.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return fmt.Errorf("clamd dial: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	reply, err := clamInstream(conn, f)
+	if err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+	// Replies look like "stream: OK" or "stream: <signature> FOUND". Only
+	// an explicit OK passes the file; anything else fails the action.
+	result, ok := strings.CutPrefix(reply, "stream: ")
+	switch {
+	case !ok:
+		return fmt.Errorf("clamd: unexpected reply %q", reply)
+	case result == "OK":
+		return nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Permanent(&InfectedError{Path: ev.Path, Signature: strings.TrimSuffix(result, " FOUND")})
+	default:
+		return fmt.Errorf("clamd: %s", result)
+	}
+}
+
+func clamInstream(conn net.Conn, src io.Reader) (string, error) {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return "", err
+	}
+	buf := make([]byte, clamChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, werr := conn.Write(size); werr != nil {
+				return "", werr
+			}
+			if _, werr := conn.Write(buf[:n]); werr != nil {
+				return "", werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return "", err
+	}
+	// A reply cut short by a dropped connection is not a verdict.
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return "", fmt.Errorf("reading reply: %w", err)
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+// parseClamdAddr accepts unix:///path/clamd.sock, tcp://host:3310, a bare
+// socket path, or host:port.
+func parseClamdAddr(addr string) (string, string, error) {
+	if addr == "" {
+		return "", "", fmt.Errorf("clamd address is empty")
+	}
+	if strings.Contains(addr, "://") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return "", "", err
+		}
+		switch u.Scheme {
+		case "unix":
+			return "unix", u.Path, nil
+		case "tcp":
+			return "tcp", u.Host, nil
+		default:
+			return "", "", fmt.Errorf("unsupported clamd scheme %q", u.Scheme)
+		}
+	}
+	if strings.HasPrefix(addr, "/") {
+		return "unix", addr, nil
+	}
+	return "tcp", addr, nil
+}
@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+// fakeClamd answers every INSTREAM request, flagging payloads containing "EICAR".
+func fakeClamd(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				r := bufio.NewReader(c)
+				if _, err := r.ReadString(0); err != nil {
+					return
+				}
+				var data []byte
+				size := make([]byte, 4)
+				for {
+					if _, err := io.ReadFull(r, size); err != nil {
+						return
+					}
+					n := binary.BigEndian.Uint32(size)
+					if n == 0 {
+						break
+					}
+					chunk := make([]byte, n)
+					if _, err := io.ReadFull(r, chunk); err != nil {
+						return
+					}
+					data = append(data, chunk...)
+				}
+				if bytes.Contains(data, []byte("EICAR")) {
+					c.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				c.Write([]byte("stream: OK\x00"))
+			}(conn)
+		}
+	}()
+	return "tcp://" + ln.Addr().String()
+}
+
+func TestClamScanDetects(t *testing.T) {
+	addr := fakeClamd(t)
+	dir := t.TempDir()
+	clean := filepath.Join(dir, "clean.txt")
+	bad := filepath.Join(dir, "bad.txt")
+	os.WriteFile(clean, []byte("hello"), 0o644)
+	os.WriteFile(bad, []byte("xxEICARxx"), 0o644)
+
+	r := &ClamScanRunner{}
+	cfg := config.Action{Type: config.ActionClamScan, Clamd: addr}
+	if err := r.Run(context.Background(), Context{Path: clean}, cfg); err != nil {
+		t.Fatalf("expected clean file to pass, got %v", err)
+	}
+	err := r.Run(context.Background(), Context{Path: bad}, cfg)
+	var infected *InfectedError
+	if !errors.As(err, &infected) || infected.Signature != "Eicar-Test-Signature" {
+		t.Fatalf("expected infection, got %v", err)
+	}
+	if !IsPermanent(err) {
+		t.Fatalf("expected detection to be permanent")
+	}
+}
+
+// answerClamd accepts one connection, drains the request and sends reply.
+func answerClamd(t *testing.T, reply string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		r := bufio.NewReader(c)
+		r.ReadString(0)
+		size := make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(r, size); err != nil {
+				return
+			}
+			n := binary.BigEndian.Uint32(size)
+			if n == 0 {
+				break
+			}
+			if _, err := io.CopyN(io.Discard, r, int64(n)); err != nil {
+				return
+			}
+		}
+		c.Write([]byte(reply))
+	}()
+	return "tcp://" + ln.Addr().String()
+}
+
+func TestClamScanFailsClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	os.WriteFile(path, []byte("hello"), 0o644)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	refused := "tcp://" + ln.Addr().String()
+	ln.Close()
+
+	for name, addr := range map[string]string{
+		"refused":   refused,
+		"garbage":   answerClamd(t, "hello there\x00"),
+		"truncated": answerClamd(t, "stream: O"),
+		"error":     answerClamd(t, "stream: INSTREAM size limit exceeded. ERROR\x00"),
+	} {
+		cfg := config.Action{Type: config.ActionClamScan, Clamd: addr}
+		err := (&ClamScanRunner{}).Run(context.Background(), Context{Path: path}, cfg)
+		if err == nil {
+			t.Fatalf("%s: expected the scan to fail", name)
+		}
+		var infected *InfectedError
+		if errors.As(err, &infected) || IsPermanent(err) {
+			t.Fatalf("%s: expected a retryable failure, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"os"
+	"time"
+)
+
+// cloudEventsType prefixes the event kind in the CloudEvents type field,
+// e.g. "io.watcher.file.create".
+const cloudEventsType = "io.watcher.file."
+
+// CloudEvent is a CloudEvents 1.0 envelope in structured JSON mode.
+type CloudEvent struct {
+	SpecVersion     string                 `json:"specversion"`
+	ID              string                 `json:"id"`
+	Source          string                 `json:"source"`
+	Type            string                 `json:"type"`
+	Subject         string                 `json:"subject,omitempty"`
+	Time            time.Time              `json:"time"`
+	DataContentType string                 `json:"datacontenttype"`
+	Data            map[string]interface{} `json:"data"`
+}
+
+// NewCloudEvent wraps the event payload for action. The id includes the
+// action name so each delivery of one event is distinct for consumers
+// that deduplicate on source and id.
+func NewCloudEvent(ev Context, action string, now time.Time) CloudEvent {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	subject := ev.RelPath
+	if subject == "" {
+		subject = ev.Path
+	}
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              ev.ID + "." + action,
+		Source:          "watcher://" + host,
+		Type:            cloudEventsType + ev.Event,
+		Subject:         subject,
+		Time:            now.UTC(),
+		DataContentType: "application/json",
+		Data:            EventPayload(ev),
+	}
+}
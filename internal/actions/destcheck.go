@@ -0,0 +1,123 @@
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/diskusage"
+)
+
+// DestError is a destination refused before anything was written to it.
+type DestError struct {
+	Dest   string
+	Reason string
+}
+
+func (e *DestError) Error() string {
+	return fmt.Sprintf("invalid destination %s: %s", e.Dest, e.Reason)
+}
+
+// pathLimits are a target OS's limits on a path and on one of its names.
+type pathLimits struct {
+	path, name int
+	// utf16 counts UTF-16 units rather than bytes, as Windows does.
+	utf16 bool
+}
+
+var osPathLimits = map[string]pathLimits{
+	"linux":   {path: 4096, name: 255},
+	"darwin":  {path: 1024, name: 255},
+	"windows": {path: 32767, name: 255, utf16: true},
+}
+
+// checkDest reports why dest cannot be created on goos, as a permanent
+// invalid_path error: retrying will not make the name fit.
+func checkDest(dest, goos string) error {
+	reason := destProblem(dest, goos)
+	if reason == "" {
+		return nil
+	}
+	return Permanent(Classified(config.ClassInvalidPath, &DestError{Dest: dest, Reason: reason}))
+}
+
+func destProblem(dest, goos string) string {
+	limits, ok := osPathLimits[goos]
+	if !ok {
+		limits = pathLimits{path: 1024, name: 255}
+	}
+	length := func(s string) int {
+		if limits.utf16 {
+			return len(utf16.Encode([]rune(s)))
+		}
+		return len(s)
+	}
+	if n := length(dest); n > limits.path {
+		return fmt.Sprintf("path is %d long, over the limit of %d", n, limits.path)
+	}
+	names := strings.Split(filepath.ToSlash(dest), "/")
+	if goos == "windows" {
+		dest = strings.TrimPrefix(dest, `\\?\`)
+		names = strings.FieldsFunc(dest, func(r rune) bool { return r == '/' || r == '\\' })
+		if len(names) > 0 && len(names[0]) == 2 && names[0][1] == ':' {
+			names = names[1:] // drive letter
+		}
+	}
+	for _, name := range names {
+		if n := length(name); n > limits.name {
+			return fmt.Sprintf("name %.32q... is %d long, over the limit of %d", name, n, limits.name)
+		}
+		if goos == "windows" {
+			if reason := windowsNameProblem(name); reason != "" {
+				return reason
+			}
+		}
+	}
+	return ""
+}
+
+// windowsReserved are device names Windows refuses as file names, with or
+// without an extension.
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+func windowsNameProblem(name string) string {
+	if name == "." || name == ".." {
+		return ""
+	}
+	for _, r := range name {
+		if r < 32 || strings.ContainsRune(`<>:"|?*`, r) {
+			return fmt.Sprintf("name %q contains %q, which Windows does not allow", name, r)
+		}
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return fmt.Sprintf("name %q ends in a dot or space, which Windows drops", name)
+	}
+	stem, _, _ := strings.Cut(name, ".")
+	if windowsReserved[strings.ToUpper(strings.TrimRight(stem, " "))] {
+		return fmt.Sprintf("name %q is reserved on Windows", name)
+	}
+	return ""
+}
+
+// checkInodes fails when the filesystem holding dest has fewer than min
+// free inodes, or none at all. Filesystems that report no inode counts
+// pass.
+func checkInodes(dest string, min uint64) error {
+	usage, err := diskusage.Of(filepath.Dir(dest))
+	if err != nil || usage.Inodes == 0 {
+		return nil
+	}
+	if usage.FreeInodes == 0 || usage.FreeInodes < min {
+		return Classified(config.ClassQuota, &DestError{
+			Dest:   dest,
+			Reason: fmt.Sprintf("filesystem has %d free inodes, need %d", usage.FreeInodes, max(min, 1)),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package actions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestCheckDest(t *testing.T) {
+	long := strings.Repeat("a", 256)
+	for _, tc := range []struct {
+		dest, goos string
+		ok         bool
+	}{
+		{"/srv/out/photo.jpg", "linux", true},
+		{"/srv/out/" + long, "linux", false},
+		{"/srv/" + strings.Repeat("d/", 2100), "linux", false},
+		{"/srv/out/a:b?.txt", "linux", true},
+		{`C:\out\photo.jpg`, "windows", true},
+		{`\\?\C:\out\photo.jpg`, "windows", true},
+		{`C:\out\a:b.txt`, "windows", false},
+		{`C:\out\what?.txt`, "windows", false},
+		{`C:\out\CON.txt`, "windows", false},
+		{`C:\out\console.txt`, "windows", true},
+		{`C:\out\trailing.`, "windows", false},
+	} {
+		err := checkDest(tc.dest, tc.goos)
+		if (err == nil) != tc.ok {
+			t.Fatalf("checkDest(%.60q, %s) = %v, want ok=%v", tc.dest, tc.goos, err, tc.ok)
+		}
+		if err == nil {
+			continue
+		}
+		var de *DestError
+		if !errors.As(err, &de) || Classify(err) != config.ClassInvalidPath || !IsPermanent(err) {
+			t.Fatalf("checkDest(%.60q) = %v, want a permanent invalid_path DestError", tc.dest, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"watcher-cli/internal/config"
+)
+
+// ClassError is an error its runner placed in an error class.
+type ClassError struct {
+	Class config.ErrorClass
+	Err   error
+}
+
+func (e *ClassError) Error() string { return e.Err.Error() }
+func (e *ClassError) Unwrap() error { return e.Err }
+
+// Classified marks err as being of class.
+func Classified(class config.ErrorClass, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ClassError{Class: class, Err: err}
+}
+
+// StatusError is an HTTP response with a non-2xx status.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string { return fmt.Sprintf("webhook status %d", e.Code) }
+
+// Classify returns the class of an action error, or "" for nil. Errors
+// marked with Classified keep their class; the rest are told apart by the
+// underlying OS, network and HTTP errors.
+func Classify(err error) config.ErrorClass {
+	if err == nil {
+		return ""
+	}
+	var ce *ClassError
+	if errors.As(err, &ce) {
+		return ce.Class
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return statusClass(se.Code)
+	}
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		// The shell's codes for a command it could not run or find.
+		switch exit.ExitCode() {
+		case 126:
+			return config.ClassPermission
+		case 127:
+			return config.ClassNotFound
+		}
+		return config.ClassOther
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return config.ClassTimeout
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, exec.ErrNotFound):
+		return config.ClassNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return config.ClassPermission
+	case errors.Is(err, fs.ErrExist):
+		return config.ClassConflict
+	case errors.Is(err, syscall.ENOSPC), errors.Is(err, syscall.EDQUOT):
+		return config.ClassQuota
+	case errors.Is(err, syscall.ENAMETOOLONG):
+		return config.ClassInvalidPath
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return config.ClassNetwork
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return config.ClassTimeout
+		}
+		return config.ClassNetwork
+	}
+	return config.ClassOther
+}
+
+func statusClass(code int) config.ErrorClass {
+	switch {
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return config.ClassNotFound
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return config.ClassPermission
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return config.ClassTimeout
+	case code == http.StatusConflict || code == http.StatusPreconditionFailed:
+		return config.ClassConflict
+	case code == http.StatusTooManyRequests || code == http.StatusInsufficientStorage:
+		return config.ClassQuota
+	case code == http.StatusBadGateway || code == http.StatusServiceUnavailable:
+		return config.ClassNetwork
+	}
+	return config.ClassOther
+}
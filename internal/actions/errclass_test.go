@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestClassify(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want config.ErrorClass
+	}{
+		{nil, ""},
+		{&fs.PathError{Op: "open", Path: "/x", Err: syscall.ENOENT}, config.ClassNotFound},
+		{fmt.Errorf("copy: %w", os.ErrPermission), config.ClassPermission},
+		{context.DeadlineExceeded, config.ClassTimeout},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, config.ClassNetwork},
+		{Classified(config.ClassConflict, errors.New("dest exists: /x")), config.ClassConflict},
+		{&fs.PathError{Op: "write", Path: "/x", Err: syscall.ENOSPC}, config.ClassQuota},
+		{&StatusError{Code: 429}, config.ClassQuota},
+		{&StatusError{Code: 403}, config.ClassPermission},
+		{&StatusError{Code: 503}, config.ClassNetwork},
+		{Permanent(&StatusError{Code: 500}), config.ClassOther},
+		{errors.New("boom"), config.ClassOther},
+	} {
+		if got := Classify(tc.err); got != tc.want {
+			t.Fatalf("Classify(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+type failRunner struct {
+	err   error
+	calls int
+}
+
+func (r *failRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	r.calls++
+	return r.err
+}
+
+func TestRetriesFollowErrorClass(t *testing.T) {
+	retries := 1
+	action := config.Action{
+		Name: "up", Type: config.ActionExec, Retries: &retries,
+		RetryClasses: map[config.ErrorClass]int{config.ClassNetwork: 3, config.ClassPermission: 0},
+	}
+	for _, tc := range []struct {
+		err   error
+		calls int
+	}{
+		{&net.OpError{Op: "dial", Err: syscall.ECONNRESET}, 4},
+		{os.ErrPermission, 1},
+		{errors.New("boom"), 2},
+	} {
+		e := (&Executor{Registry: NewRegistry()}).ForWatch()
+		r := &failRunner{err: tc.err}
+		e.Registry.Register(config.ActionExec, r)
+		if err := e.Execute(context.Background(), Context{ID: "ev"}, action); err == nil {
+			t.Fatalf("expected %v to fail", tc.err)
+		}
+		if r.calls != tc.calls {
+			t.Fatalf("%v: %d attempts, want %d", tc.err, r.calls, tc.calls)
+		}
+	}
+}
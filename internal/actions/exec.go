@@ -1,10 +1,18 @@
 package actions
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"watcher-cli/internal/config"
 	"watcher-cli/internal/template"
@@ -14,20 +22,330 @@ import (
 type ExecRunner struct{}
 
 func (r *ExecRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
-	cmdStr := template.Expand(cfg.Cmd, BuildTemplateContext(ev))
-	parts := strings.Fields(cmdStr)
-	if len(parts) == 0 {
-		return nil
+	tctx := BuildTemplateContext(ev)
+	dir, err := workDir(cfg, &tctx)
+	if err != nil {
+		return err
 	}
-	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
-	if cfg.Cwd != "" {
-		cmd.Dir = cfg.Cwd
+	var cmd *exec.Cmd
+	if cfg.Shell {
+		line := strings.TrimSpace(template.ExpandQuoted(cfg.Cmd.Line, tctx, shellQuoteArg))
+		if line == "" {
+			return nil
+		}
+		cmd = shellCommand(line)
+	} else {
+		parts, err := commandArgs(cfg.Cmd, tctx)
+		if err != nil {
+			return Permanent(err)
+		}
+		if len(parts) == 0 {
+			return nil
+		}
+		cmd = exec.Command(parts[0], parts[1:]...)
+	}
+	cmd.Dir = dir
+	cmd.Env = append(baseEnv(cfg), eventEnv(ev, cfg)...)
+	if tctx.OutputDir != "" {
+		cmd.Env = append(cmd.Env, "WATCHER_OUTPUT_DIR="+tctx.OutputDir)
+	}
+	for k, v := range cfg.Env {
+		cmd.Env = append(cmd.Env, k+"="+template.Expand(v, tctx))
+	}
+	switch cfg.Stdin {
+	case config.StdinFile:
+		f, err := ev.Cache.Open(ev.Path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		cmd.Stdin = f
+	case config.StdinJSON:
+		body, err := json.Marshal(actionPayload(ev, cfg))
+		if err != nil {
+			return err
+		}
+		cmd.Stdin = bytes.NewReader(body)
+	}
+	return runOutput(ctx, ev, cfg, cmd)
+}
+
+// commandArgs expands c into the program and its arguments. A string cmd
+// is split before expansion, so values with spaces stay one argument; a
+// bare {duplicates} there gives one argument per duplicate.
+func commandArgs(c config.Command, tctx template.Context) ([]string, error) {
+	if len(c.Args) > 0 {
+		// List form: each element is one argument, spaces and all.
+		parts := make([]string, len(c.Args))
+		for i, arg := range c.Args {
+			parts[i] = template.Expand(arg, tctx)
+		}
+		return parts, nil
+	}
+	fields, err := template.Fields(c.Line)
+	if err != nil {
+		return nil, fmt.Errorf("cmd: %w", err)
+	}
+	var parts []string
+	for _, f := range fields {
+		if f == "{duplicates}" {
+			parts = append(parts, tctx.Duplicates...)
+			continue
+		}
+		parts = append(parts, template.Expand(f, tctx))
+	}
+	return parts, nil
+}
+
+// workDir expands cfg's output_dir into tctx and returns the directory the
+// command runs in: the expanded cwd, else the output_dir. Both are created
+// if missing so tools writing relative to them find the directory.
+func workDir(cfg config.Action, tctx *template.Context) (string, error) {
+	if cfg.OutputDir != "" {
+		tctx.OutputDir = template.Expand(cfg.OutputDir, *tctx)
+		if err := os.MkdirAll(tctx.OutputDir, 0o755); err != nil {
+			return "", err
+		}
+	}
+	if cfg.Cwd == "" {
+		return tctx.OutputDir, nil
+	}
+	dir := template.Expand(cfg.Cwd, *tctx)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// pathsArg is the argument a batched exec replaces with its paths.
+const pathsArg = "{paths}"
+
+// RunBatch runs the command once for evs. The paths replace a "{paths}"
+// argument or follow the command, or with batch input stdin are written
+// one per line to the child's stdin.
+func (r *ExecRunner) RunBatch(ctx context.Context, evs []Context, cfg config.Action) error {
+	tctx := BuildTemplateContext(evs[0])
+	dir, err := workDir(cfg, &tctx)
+	if err != nil {
+		return err
+	}
+	paths := batchPaths(evs)
+	onStdin := cfg.Batch != nil && cfg.Batch.Input == config.BatchStdin
+	var cmd *exec.Cmd
+	if cfg.Shell {
+		line := strings.TrimSpace(template.ExpandQuoted(cfg.Cmd.Line, tctx, shellQuoteArg))
+		if line == "" {
+			return nil
+		}
+		if !onStdin {
+			quoted := make([]string, len(paths))
+			for i, p := range paths {
+				quoted[i] = shellQuoteArg(p)
+			}
+			if strings.Contains(line, pathsArg) {
+				line = strings.ReplaceAll(line, pathsArg, strings.Join(quoted, " "))
+			} else {
+				line += " " + strings.Join(quoted, " ")
+			}
+		}
+		cmd = shellCommand(line)
+	} else {
+		args := cfg.Cmd.Args
+		if len(args) == 0 {
+			if args, err = template.Fields(cfg.Cmd.Line); err != nil {
+				return Permanent(err)
+			}
+		}
+		var parts []string
+		placed := onStdin
+		for _, arg := range args {
+			if arg == pathsArg {
+				if !onStdin {
+					parts = append(parts, paths...)
+				}
+				placed = true
+				continue
+			}
+			parts = append(parts, template.Expand(arg, tctx))
+		}
+		if !placed {
+			parts = append(parts, paths...)
+		}
+		if len(parts) == 0 {
+			return nil
+		}
+		cmd = exec.Command(parts[0], parts[1:]...)
+	}
+	cmd.Dir = dir
+	ids := make([]string, len(evs))
+	for i, ev := range evs {
+		ids[i] = ev.ID
+	}
+	cmd.Env = append(baseEnv(cfg),
+		"WATCHER_ACTION="+cfg.Name,
+		"WATCHER_BATCH_SIZE="+strconv.Itoa(len(evs)),
+		"WATCHER_EVENT_IDS="+strings.Join(ids, ","),
+	)
+	if tctx.OutputDir != "" {
+		cmd.Env = append(cmd.Env, "WATCHER_OUTPUT_DIR="+tctx.OutputDir)
 	}
-	cmd.Env = os.Environ()
 	for k, v := range cfg.Env {
-		cmd.Env = append(cmd.Env, k+"="+template.Expand(v, BuildTemplateContext(ev)))
+		cmd.Env = append(cmd.Env, k+"="+template.Expand(v, tctx))
+	}
+	if onStdin {
+		cmd.Stdin = strings.NewReader(strings.Join(paths, "\n") + "\n")
+	}
+	// A batch has no chain to pass {last_output} or captures along.
+	first := evs[0]
+	first.Outputs = nil
+	return runOutput(ctx, first, cfg, cmd)
+}
+
+// maxCapture bounds how much output is kept for capture variables.
+const maxCapture = 1 << 20
+
+// maxLoggedOutput bounds the output attached to a log entry; the end of
+// the output is kept, where errors usually are.
+const maxLoggedOutput = 8 << 10
+
+// runOutput runs cmd and handles its output as cfg's log_output says:
+// unset streams it to the daemon's stdout and stderr, true logs it with
+// the event, on_error logs it only when the command fails, and false
+// drops it. Stdout becomes the event's {last_output} and feeds capture.
+func runOutput(ctx context.Context, ev Context, cfg config.Action, cmd *exec.Cmd) error {
+	stdout, stderr := &cappedBuffer{max: maxCapture}, &cappedBuffer{max: maxCapture}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if cfg.LogOutput == "" {
+		cmd.Stdout = io.MultiWriter(os.Stdout, stdout)
+		cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	}
+	err := runWithGrace(ctx, cmd, cfg.KillGrace.Duration())
+	out, errOut := strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String())
+	switch {
+	case err != nil && cfg.LogOutput != "" && cfg.LogOutput != config.LogOutputOff:
+		slog.Warn("exec output", "event_id", ev.ID, "action", cfg.Name, "stdout", lastBytes(out, maxLoggedOutput), "stderr", lastBytes(errOut, maxLoggedOutput), "err", err)
+	case err == nil && cfg.LogOutput == config.LogOutputOn:
+		slog.Info("exec output", "event_id", ev.ID, "action", cfg.Name, "stdout", lastBytes(out, maxLoggedOutput), "stderr", lastBytes(errOut, maxLoggedOutput))
+	}
+	if ev.Outputs != nil {
+		ev.Outputs[lastOutput] = out
+	}
+	if err != nil {
+		return err
+	}
+	capture(ev, cfg, config.CaptureStdout, out)
+	capture(ev, cfg, config.CaptureStderr, errOut)
+	return nil
+}
+
+// lastBytes returns the last n bytes of s, marking a cut.
+func lastBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
+
+// cappedBuffer keeps the first max bytes written and drops the rest.
+type cappedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// runWithGrace runs cmd in its own process group, or job object on
+// Windows. When ctx ends first the group gets SIGTERM, then SIGKILL if it
+// is still running after grace.
+func runWithGrace(ctx context.Context, cmd *exec.Cmd, grace time.Duration) error {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	group := newProcGroup(cmd)
+	defer group.close()
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	_ = group.terminate()
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		_ = group.kill()
+		<-done
+	}
+	return ctx.Err()
+}
+
+// eventEnv exposes the event to children as WATCHER_* variables.
+func eventEnv(ev Context, cfg config.Action) []string {
+	env := []string{
+		"WATCHER_EVENT_ID=" + ev.ID,
+		"WATCHER_ACTION=" + cfg.Name,
+		"WATCHER_EVENT=" + ev.Event,
+		"WATCHER_PATH=" + ev.Path,
+		"WATCHER_RELPATH=" + ev.RelPath,
+		"WATCHER_DIR=" + filepath.Dir(ev.Path),
+		"WATCHER_NAME=" + filepath.Base(ev.Path),
+		"WATCHER_SIZE=" + strconv.FormatInt(ev.Size, 10),
+		"WATCHER_AGE_MS=" + strconv.FormatInt(ev.Age.Milliseconds(), 10),
+		"WATCHER_IS_DIR=" + strconv.FormatBool(ev.IsDir),
+	}
+	if !ev.ModTime.IsZero() {
+		env = append(env, "WATCHER_MTIME="+ev.ModTime.Format(time.RFC3339))
+	}
+	if ev.PrevPath != "" {
+		env = append(env, "WATCHER_PREV_PATH="+ev.PrevPath)
+	}
+	if !ev.DeletedAt.IsZero() {
+		env = append(env, "WATCHER_DELETED_AT="+ev.DeletedAt.Format(time.RFC3339))
+	}
+	return env
+}
+
+// baseEnv returns the inherited part of a child's environment.
+func baseEnv(cfg config.Action) []string {
+	switch cfg.EnvMode {
+	case config.EnvClean:
+		return []string{}
+	case config.EnvAllowlist:
+		var out []string
+		for _, kv := range os.Environ() {
+			name, _, _ := strings.Cut(kv, "=")
+			if envAllowed(name, cfg.EnvAllowlist) {
+				out = append(out, kv)
+			}
+		}
+		return out
+	default:
+		return os.Environ()
+	}
+}
+
+func envAllowed(name string, allow []string) bool {
+	for _, a := range allow {
+		if prefix, ok := strings.CutSuffix(a, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if a == name {
+			return true
+		}
 	}
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return false
 }
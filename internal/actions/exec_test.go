@@ -0,0 +1,327 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/template"
+)
+
+func TestExecTimeoutKillsProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	cfg := config.Action{
+		Type:      config.ActionExec,
+		Cmd:       config.Command{Args: []string{"sh", "-c", "trap '' TERM; sleep 30 & wait"}},
+		KillGrace: config.MillisFromDuration(100 * time.Millisecond),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := (&ExecRunner{}).Run(ctx, Context{Path: "/tmp/x"}, cfg)
+	if err == nil {
+		t.Fatalf("expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected escalation to SIGKILL, took %s", elapsed)
+	}
+}
+
+func TestExecCaptureFeedsLaterTemplates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	outputs := map[string]string{}
+	ev := Context{Path: "/tmp/x.txt", Outputs: outputs}
+	cfg := config.Action{
+		Type:    config.ActionExec,
+		Cmd:     config.Command{Args: []string{"sh", "-c", "echo sorted/2024"}},
+		Capture: map[string]config.CaptureSource{"target": config.CaptureStdout},
+	}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	got := template.Expand("/archive/{out:target}/{name}", BuildTemplateContext(ev))
+	if got != "/archive/sorted/2024/x.txt" {
+		t.Fatalf("unexpected expansion %q", got)
+	}
+}
+
+func TestExecReceivesEventID(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	outputs := map[string]string{}
+	ev := Context{ID: "abc123", Path: "/tmp/x.txt", Outputs: outputs}
+	cfg := config.Action{
+		Type:    config.ActionExec,
+		Cmd:     config.Command{Args: []string{"sh", "-c", "printf %s \"$WATCHER_EVENT_ID\""}},
+		Capture: map[string]config.CaptureSource{"id": config.CaptureStdout},
+	}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if outputs["id"] != "abc123" {
+		t.Fatalf("expected event id in env, got %q", outputs["id"])
+	}
+	if got := template.Expand("{event_id}", BuildTemplateContext(ev)); got != "abc123" {
+		t.Fatalf("unexpected {event_id} expansion %q", got)
+	}
+}
+
+func TestExecRunsInTemplatedOutputDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	root := t.TempDir()
+	outputs := map[string]string{}
+	ev := Context{Path: filepath.Join(root, "in", "a.txt"), Outputs: outputs}
+	cfg := config.Action{
+		Type:      config.ActionExec,
+		Cmd:       config.Command{Args: []string{"sh", "-c", "pwd; touch made"}},
+		OutputDir: "{dir}/out/{stem}",
+		Capture:   map[string]config.CaptureSource{"pwd": config.CaptureStdout},
+	}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	want := filepath.Join(root, "in", "out", "a")
+	if outputs["pwd"] != want {
+		t.Fatalf("ran in %q, want %q", outputs["pwd"], want)
+	}
+	if _, err := os.Stat(filepath.Join(want, "made")); err != nil {
+		t.Fatalf("output not next to the source: %v", err)
+	}
+}
+
+func TestExecKeepsSpacesInPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	for _, cfg := range []config.Action{
+		{Type: config.ActionExec, Cmd: config.Command{Line: `printf "%s|" {path} {name}`}},
+		{Type: config.ActionExec, Cmd: config.Command{Line: `printf "%s|" {path} {name} | tr a-z A-Z`}, Shell: true},
+	} {
+		outputs := map[string]string{}
+		cfg.Capture = map[string]config.CaptureSource{"out": config.CaptureStdout}
+		ev := Context{Path: "/in/my file's $HOME.txt", Outputs: outputs}
+		if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		want := "/in/my file's $HOME.txt|my file's $HOME.txt|"
+		if cfg.Shell {
+			want = strings.ToUpper(want)
+		}
+		if outputs["out"] != want {
+			t.Fatalf("shell=%v: got %q, want %q", cfg.Shell, outputs["out"], want)
+		}
+	}
+}
+
+func TestExecArgListPassesArgumentsVerbatim(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	outputs := map[string]string{}
+	cfg := config.Action{
+		Type:    config.ActionExec,
+		Cmd:     config.Command{Args: []string{"printf", "%s|", "{path}", "{dir}/out/{stem}.webp", "two  spaces", ""}},
+		Capture: map[string]config.CaptureSource{"out": config.CaptureStdout},
+	}
+	ev := Context{Path: "/in/my photo.jpeg", Outputs: outputs}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if want := "/in/my photo.jpeg|/in/out/my photo.webp|two  spaces||"; outputs["out"] != want {
+		t.Fatalf("got %q, want %q", outputs["out"], want)
+	}
+}
+
+func TestExecEventEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	outputs := map[string]string{}
+	cfg := config.Action{
+		Name:    "report",
+		Type:    config.ActionExec,
+		Cmd:     config.Command{Args: []string{"sh", "-c", `printf "%s|" "$WATCHER_PATH" "$WATCHER_RELPATH" "$WATCHER_DIR" "$WATCHER_NAME" "$WATCHER_EVENT" "$WATCHER_SIZE" "$WATCHER_IS_DIR" "$WATCHER_ACTION" "$WATCHER_PREV_PATH"`}},
+		Capture: map[string]config.CaptureSource{"env": config.CaptureStdout},
+	}
+	ev := Context{Path: "/in/sub/a b.txt", RelPath: "sub/a b.txt", Event: "modify", Size: 42, PrevPath: "/in/old.txt", Outputs: outputs}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if want := "/in/sub/a b.txt|sub/a b.txt|/in/sub|a b.txt|modify|42|false|report|/in/old.txt|"; outputs["env"] != want {
+		t.Fatalf("got %q, want %q", outputs["env"], want)
+	}
+}
+
+func TestExecShellModeDoesNotRunTokensInValues(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "pwned")
+	outputs := map[string]string{}
+	ev := Context{
+		Path:    "/in/{exif:Artist}.jpg",
+		Meta:    map[string]string{"exif:Artist": "x; touch " + marker + "; echo x"},
+		Outputs: outputs,
+	}
+	cfg := config.Action{Type: config.ActionExec, Cmd: config.Command{Line: "echo {path}"}, Shell: true, Capture: map[string]config.CaptureSource{"out": config.CaptureStdout}}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatalf("a token inside the path was expanded and run by the shell")
+	}
+	if outputs["out"] != "/in/{exif:Artist}.jpg" {
+		t.Fatalf("got %q", outputs["out"])
+	}
+}
+
+func TestExecShellModeKeepsParameterExpansions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	t.Setenv("HOME", "/home/feed")
+	outputs := map[string]string{}
+	ev := Context{Path: "/in/a.txt", Outputs: outputs}
+	line := `echo "${HOME:-/tmp}" "${WATCHER_NO_SUCH_VAR:-/tmp}" {name}`
+	cfg := config.Action{Type: config.ActionExec, Cmd: config.Command{Line: line}, Shell: true, Capture: map[string]config.CaptureSource{"out": config.CaptureStdout}}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if outputs["out"] != "/home/feed /tmp a.txt" {
+		t.Fatalf("got %q", outputs["out"])
+	}
+}
+
+func TestExecLastOutputAndLogOutputOnError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	var logged strings.Builder
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logged, nil)))
+	defer slog.SetDefault(prev)
+
+	outputs := map[string]string{}
+	ev := Context{ID: "e1", Path: "/tmp/x.txt", Outputs: outputs}
+	quiet := config.Action{Name: "sum", Type: config.ActionExec, Cmd: config.Command{Line: "echo 42"}, LogOutput: config.LogOutputOnError}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, quiet); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := template.Expand("n={last_output}", BuildTemplateContext(ev)); got != "n=42" {
+		t.Fatalf("unexpected {last_output} expansion %q", got)
+	}
+	if logged.Len() != 0 {
+		t.Fatalf("on_error logged a successful run: %s", logged.String())
+	}
+	failing := config.Action{Name: "check", Type: config.ActionExec, Cmd: config.Command{Args: []string{"sh", "-c", "echo bad header >&2; exit 3"}}, LogOutput: config.LogOutputOnError}
+	if err := (&ExecRunner{}).Run(context.Background(), ev, failing); err == nil {
+		t.Fatalf("expected failure")
+	}
+	if out := logged.String(); !strings.Contains(out, "exec output") || !strings.Contains(out, `stderr="bad header"`) || !strings.Contains(out, "event_id=e1") {
+		t.Fatalf("expected the failure's output in the log, got %s", out)
+	}
+}
+
+func TestExecEnvModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	t.Setenv("TEST_WATCHER_SECRET", "s3cret")
+	t.Setenv("TEST_WATCHER_KEEP", "kept")
+	t.Setenv("TEST_PREFIX_A", "a")
+	t.Setenv("TEST_PREFIX_B", "b")
+	run := func(cfg config.Action) map[string]string {
+		t.Helper()
+		outputs := map[string]string{}
+		cfg.Type = config.ActionExec
+		cfg.Cmd = config.Command{Args: []string{"sh", "-c", "env"}}
+		cfg.Capture = map[string]config.CaptureSource{"env": config.CaptureStdout}
+		if err := (&ExecRunner{}).Run(context.Background(), Context{ID: "ev1", Path: "/tmp/x.txt", Outputs: outputs}, cfg); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		env := map[string]string{}
+		for _, line := range strings.Split(outputs["env"], "\n") {
+			if k, v, ok := strings.Cut(line, "="); ok {
+				env[k] = v
+			}
+		}
+		return env
+	}
+
+	env := run(config.Action{})
+	if env["TEST_WATCHER_SECRET"] != "s3cret" || env["WATCHER_EVENT_ID"] != "ev1" {
+		t.Fatalf("inherit should pass the daemon's env: %v", env)
+	}
+
+	env = run(config.Action{EnvMode: config.EnvClean, Env: map[string]string{"STEM": "{stem}"}})
+	for _, name := range []string{"TEST_WATCHER_SECRET", "TEST_WATCHER_KEEP", "TEST_PREFIX_A", "HOME"} {
+		if _, ok := env[name]; ok {
+			t.Fatalf("clean env leaked %s: %v", name, env)
+		}
+	}
+	if env["WATCHER_EVENT_ID"] != "ev1" || env["STEM"] != "x" {
+		t.Fatalf("clean env should keep event and action variables: %v", env)
+	}
+
+	env = run(config.Action{EnvMode: config.EnvAllowlist, EnvAllowlist: []string{"TEST_WATCHER_KEEP", "TEST_PREFIX_*"}})
+	if _, ok := env["TEST_WATCHER_SECRET"]; ok {
+		t.Fatalf("allowlist leaked TEST_WATCHER_SECRET: %v", env)
+	}
+	if env["TEST_WATCHER_KEEP"] != "kept" || env["TEST_PREFIX_A"] != "a" || env["TEST_PREFIX_B"] != "b" || env["WATCHER_EVENT_ID"] != "ev1" {
+		t.Fatalf("allowlist dropped allowed variables: %v", env)
+	}
+}
+
+func TestExecStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	home := t.TempDir()
+	path := filepath.Join(home, "in", "a.txt")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("line one\nline two\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	run := func(cfg config.Action) string {
+		t.Helper()
+		outputs := map[string]string{}
+		cfg.Type = config.ActionExec
+		cfg.Cmd = config.Command{Args: []string{"cat"}}
+		cfg.Capture = map[string]config.CaptureSource{"in": config.CaptureStdout}
+		ev := Context{ID: "ev1", Path: path, RelPath: "in/a.txt", Event: "create", Size: 18, Outputs: outputs}
+		if err := (&ExecRunner{}).Run(context.Background(), ev, cfg); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		return outputs["in"]
+	}
+
+	if got := run(config.Action{Stdin: config.StdinFile}); got != "line one\nline two" {
+		t.Fatalf("stdin file: child read %q", got)
+	}
+
+	got := run(config.Action{Stdin: config.StdinJSON, Redact: []string{home}, PayloadFields: []string{"path", "size"}})
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(got), &payload); err != nil {
+		t.Fatalf("stdin json: %v in %q", err, got)
+	}
+	if len(payload) != 2 || payload["path"] != filepath.Join(redactedPrefix, "in", "a.txt") || payload["size"] != float64(18) {
+		t.Fatalf("stdin json ignored redact or payload_fields: %v", payload)
+	}
+}
@@ -0,0 +1,76 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+type panicRunner struct{}
+
+func (panicRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	panic("boom")
+}
+
+func TestPanickingRunnerFailsPermanently(t *testing.T) {
+	shared := &Executor{Registry: NewRegistry(), Budget: NewRetryBudget(5)}
+	e := shared.ForWatch()
+	e.Registry.Register(config.ActionExec, panicRunner{})
+	action := config.Action{Name: "bad", Type: config.ActionExec}
+	err := e.Execute(context.Background(), Context{ID: "ev"}, action)
+	if !errors.Is(err, ErrPanic) || !IsPermanent(err) {
+		t.Fatalf("expected a permanent panic error, got %v", err)
+	}
+	// Other watches keep their own runners and share the budget.
+	other := shared.ForWatch()
+	if r, _ := other.Registry.Get(config.ActionExec); r == (panicRunner{}) {
+		t.Fatalf("runner leaked into another watch's executor")
+	}
+	if other.Budget != shared.Budget {
+		t.Fatalf("retry budget not shared")
+	}
+}
+
+func TestStubRunnerExercisesRetriesAndTimeout(t *testing.T) {
+	retries := 2
+	action := config.Action{Name: "up", Type: config.ActionWebhook, Retries: &retries, RetryClasses: map[config.ErrorClass]int{config.ClassNetwork: 1}}
+	var attempts []error
+	stub := &StubRunner{FailFirst: 2, Class: config.ClassNetwork, OnAttempt: func(_ string, _ int, _ time.Duration, err error) {
+		attempts = append(attempts, err)
+	}}
+	e := &Executor{Registry: StubRegistry(stub)}
+	err := e.Execute(context.Background(), Context{ID: "ev"}, action)
+	if !errors.Is(err, ErrStubFailure) || len(attempts) != 2 {
+		t.Fatalf("network retries: err %v after %d attempts, want failure after 2", err, len(attempts))
+	}
+
+	attempts = nil
+	action.RetryClasses = nil
+	if err := e.Execute(context.Background(), Context{ID: "ev"}, action); err != nil || len(attempts) != 1 {
+		t.Fatalf("after failing twice the stub should succeed at once, got %v after %d attempts", err, len(attempts))
+	}
+
+	attempts = nil
+	action.Timeout = config.MillisFromDuration(20 * time.Millisecond)
+	e = &Executor{Registry: StubRegistry(&StubRunner{Latency: time.Second})}
+	if err := e.Execute(context.Background(), Context{ID: "ev"}, action); Classify(err) != config.ClassTimeout {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func TestForWatchKeepsRegisteredRunners(t *testing.T) {
+	shared := &Executor{Registry: NewRegistry()}
+	shared.Registry.Register(config.ActionExec, panicRunner{})
+	e := shared.ForWatch()
+	if r, _ := e.Registry.Get(config.ActionExec); r != (panicRunner{}) {
+		t.Fatalf("registered runner dropped from the per-watch executor, got %T", r)
+	}
+	a, _ := e.Registry.Get(config.ActionCopy)
+	b, _ := shared.Registry.Get(config.ActionCopy)
+	if a == b {
+		t.Fatalf("default runners shared between watches")
+	}
+}
@@ -2,18 +2,29 @@ package actions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
+	"watcher-cli/internal/chaos"
 	"watcher-cli/internal/config"
+	"watcher-cli/internal/diskusage"
+	"watcher-cli/internal/fsys"
 	"watcher-cli/internal/template"
 )
 
+const spacePollInterval = time.Second
+
 // CopyMoveRunner handles copy/move/rename operations.
 type CopyMoveRunner struct {
 	Mode config.ActionType
+	// FS is the filesystem to operate on; nil means the local one.
+	FS fsys.FS
 }
 
 func (r *CopyMoveRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
@@ -21,64 +32,258 @@ func (r *CopyMoveRunner) Run(ctx context.Context, ev Context, cfg config.Action)
 	if destTmpl == "" {
 		return fmt.Errorf("empty dest")
 	}
-	dest := destTmpl
+	dest := ev.Normalize.String(destTmpl)
 	if cfg.Type == config.ActionRename && ev.RelPath != "" {
-		dest = filepath.Join(filepath.Dir(ev.Path), destTmpl)
+		dest = filepath.Join(filepath.Dir(ev.Path), dest)
+	}
+	fs := r.FS
+	if fs == nil {
+		fs = fsys.OS
 	}
-	overwrite := false
-	if cfg.Overwrite != nil {
-		overwrite = *cfg.Overwrite
+	dest, ok, err := resolveConflict(fs, dest, conflictPolicy(cfg))
+	if err != nil || !ok {
+		return err
+	}
+	if fsys.IsOS(fs) {
+		if err := checkDest(dest, runtime.GOOS); err != nil {
+			return err
+		}
+		if err := checkInodes(dest, uint64(cfg.DestMinFreeInodes)); err != nil {
+			return err
+		}
+	}
+	opts := copyOptions{
+		fs:        fs,
+		overwrite: conflictPolicy(cfg) == config.ConflictOverwrite,
+		bandwidth: int64(cfg.BandwidthLimit),
+		resumable: cfg.Resumable,
+		fsync:     cfg.Fsync,
+		eventID:   ev.ID,
+		cache:     ev.Cache,
+	}
+	if cfg.DestMinFreeBytes > 0 && fsys.IsOS(fs) {
+		if err := waitForSpace(ctx, dest, uint64(cfg.DestMinFreeBytes)); err != nil {
+			return err
+		}
 	}
-	switch r.Mode {
-	case config.ActionCopy:
-		return copyFile(ev.Path, dest, overwrite)
-	case config.ActionMove, config.ActionRename:
-		return moveFile(ev.Path, dest, overwrite)
+	switch {
+	case ev.Remote != nil && r.Mode == config.ActionCopy:
+		err = download(ctx, ev, dest, opts)
+	case ev.Remote != nil:
+		return Permanent(fmt.Errorf("%s needs a local file; remote files can only be copied", r.Mode))
+	case r.Mode == config.ActionCopy, r.Mode == config.ActionMove, r.Mode == config.ActionRename:
+		err = transferGroup(ctx, r.Mode, conflictPolicy(cfg), sidecar{src: ev.Path, dest: dest}, sidecars(fs, ev, cfg, dest), opts)
 	default:
 		return fmt.Errorf("unsupported mode %s", r.Mode)
 	}
+	if err == nil {
+		capture(ev, cfg, config.CaptureDest, dest)
+	}
+	return err
+}
+
+func conflictPolicy(cfg config.Action) config.ConflictPolicy {
+	if cfg.OnConflict != "" {
+		return cfg.OnConflict
+	}
+	if cfg.Overwrite != nil && *cfg.Overwrite {
+		return config.ConflictOverwrite
+	}
+	return config.ConflictFail
 }
 
-func copyFile(src, dest string, overwrite bool) error {
-	if !overwrite {
-		if _, err := os.Stat(dest); err == nil {
-			return fmt.Errorf("dest exists: %s", dest)
+// resolveConflict applies policy when dest already exists. It returns the
+// path to write to, or ok=false when the action should be skipped.
+func resolveConflict(fs fsys.FS, dest string, policy config.ConflictPolicy) (string, bool, error) {
+	if _, err := fs.Lstat(dest); err != nil {
+		return dest, true, nil
+	}
+	switch policy {
+	case config.ConflictOverwrite:
+		return dest, true, nil
+	case config.ConflictSkip:
+		return dest, false, nil
+	case config.ConflictSuffix:
+		dir, name := filepath.Split(dest)
+		ext := filepath.Ext(name)
+		stem := strings.TrimSuffix(name, ext)
+		for i := 1; i < 10000; i++ {
+			candidate := filepath.Join(dir, fmt.Sprintf("%s (%d)%s", stem, i, ext))
+			if _, err := fs.Lstat(candidate); err != nil {
+				return candidate, true, nil
+			}
 		}
+		return "", false, fmt.Errorf("no free name for %s", dest)
+	default:
+		return "", false, Classified(config.ClassConflict, fmt.Errorf("dest exists: %s", dest))
 	}
-	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+}
+
+// diskUsage probes free space; tests stub it.
+var diskUsage = diskusage.Of
+
+// waitForSpace blocks until the filesystem holding dest has at least min
+// bytes free, polling until ctx is done.
+func waitForSpace(ctx context.Context, dest string, min uint64) error {
+	ticker := time.NewTicker(spacePollInterval)
+	defer ticker.Stop()
+	for {
+		usage, err := diskUsage(filepath.Dir(dest))
+		if err != nil {
+			return fmt.Errorf("check free space: %w", err)
+		}
+		if usage.Free >= min {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("destination low on space (%d bytes free, need %d): %w", usage.Free, min, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// copyOptions tunes copyFile and moveFile.
+type copyOptions struct {
+	fs        fsys.FS // nil means the local filesystem
+	overwrite bool
+	bandwidth int64 // bytes per second, 0 = unlimited
+	resumable bool
+	fsync     bool // sync file and parent directory before returning
+	eventID   string
+	cache     *ReadCache // local sources only
+}
+
+func (o copyOptions) filesystem() fsys.FS {
+	if o.fs == nil {
+		return fsys.OS
+	}
+	return o.fs
+}
+
+func copyFile(ctx context.Context, src, dest string, opts copyOptions) error {
+	fs := opts.filesystem()
+	if !opts.overwrite {
+		if _, err := fs.Stat(dest); err == nil {
+			return Classified(config.ClassConflict, fmt.Errorf("dest exists: %s", dest))
+		}
+	}
+	// Opening a FIFO or device blocks or never ends; refuse them up front.
+	srcInfo, err := fs.Stat(src)
+	if err != nil {
 		return err
 	}
-	in, err := os.Open(src)
+	if !srcInfo.Mode().IsRegular() {
+		return Permanent(fmt.Errorf("refusing to copy special file %s (%s)", src, srcInfo.Mode().Type()))
+	}
+	if err := fs.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	if opts.resumable {
+		// Resuming needs random access, which only local files offer.
+		if !fsys.IsOS(fs) {
+			return Permanent(errors.New("resumable copies need the local filesystem"))
+		}
+		return copyResumable(ctx, src, dest, opts)
+	}
+	in, err := opts.open(src, srcInfo)
 	if err != nil {
 		return err
 	}
 	defer in.Close()
-	out, err := os.Create(dest)
+	out, err := fs.Create(dest)
 	if err != nil {
 		return err
 	}
 	defer out.Close()
-	if _, err := io.Copy(out, in); err != nil {
+	var r io.Reader = in
+	if opts.bandwidth > 0 {
+		r = newRateLimitedReader(ctx, in, opts.bandwidth)
+	}
+	r = chaos.From(ctx).Truncate(r, srcInfo.Size())
+	if f, ok := out.(*os.File); ok && isSparse(srcInfo) {
+		err = copySparse(f, r, srcInfo.Size())
+	} else {
+		_, err = io.Copy(out, r)
+	}
+	if err != nil {
+		// A partial file would block the retry as an existing dest.
+		out.Close()
+		fs.Remove(dest)
 		return err
 	}
-	return nil
+	if !opts.fsync {
+		return nil
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return opts.syncDir(filepath.Dir(dest))
+}
+
+// open reads src through the event's read cache when it is a local,
+// non-sparse file.
+func (o copyOptions) open(src string, info os.FileInfo) (io.ReadCloser, error) {
+	if o.cache != nil && fsys.IsOS(o.fs) && !isSparse(info) {
+		return o.cache.Open(src)
+	}
+	return o.filesystem().Open(src)
+}
+
+// syncDir syncs dir on the local filesystem; other filesystems persist
+// directory entries themselves.
+func (o copyOptions) syncDir(dir string) error {
+	if !fsys.IsOS(o.fs) {
+		return nil
+	}
+	return syncDir(dir)
 }
 
-func moveFile(src, dest string, overwrite bool) error {
-	if !overwrite {
-		if _, err := os.Stat(dest); err == nil {
-			return fmt.Errorf("dest exists: %s", dest)
+// syncDir flushes directory entries so a new or renamed file survives a
+// crash. Windows has no equivalent for directories.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func moveFile(ctx context.Context, src, dest string, opts copyOptions) error {
+	fs := opts.filesystem()
+	if !opts.overwrite {
+		if _, err := fs.Stat(dest); err == nil {
+			return Classified(config.ClassConflict, fmt.Errorf("dest exists: %s", dest))
 		}
 	}
-	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+	if err := fs.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
 		return err
 	}
-	if err := os.Rename(src, dest); err == nil {
-		return nil
+	if err := fs.Rename(src, dest); err == nil {
+		if !opts.fsync {
+			return nil
+		}
+		if err := opts.syncDir(filepath.Dir(dest)); err != nil {
+			return err
+		}
+		return opts.syncDir(filepath.Dir(src))
 	}
 	// Fallback to copy+remove
-	if err := copyFile(src, dest, overwrite); err != nil {
+	if err := copyFile(ctx, src, dest, opts); err != nil {
 		return err
 	}
-	return os.Remove(src)
+	if err := fs.Remove(src); err != nil {
+		return err
+	}
+	if opts.fsync {
+		return opts.syncDir(filepath.Dir(src))
+	}
+	return nil
 }
@@ -0,0 +1,150 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/diskusage"
+	"watcher-cli/internal/fsys"
+)
+
+func TestCopyMoveOnMemFS(t *testing.T) {
+	mem := fsys.NewMem()
+	src := filepath.FromSlash("/in/report.pdf")
+	if err := mem.WriteFile(src, []byte("pdf")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := mem.WriteFile(filepath.FromSlash("/out/report.pdf"), []byte("old")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	ev := Context{Path: src, RelPath: "report.pdf"}
+
+	cp := &CopyMoveRunner{Mode: config.ActionCopy, FS: mem}
+	cfg := config.Action{Type: config.ActionCopy, Dest: "/out/{name}", OnConflict: config.ConflictSuffix}
+	if err := cp.Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if got, err := mem.ReadFile(filepath.FromSlash("/out/report (1).pdf")); err != nil || string(got) != "pdf" {
+		t.Fatalf("unexpected copy %q, %v", got, err)
+	}
+
+	mv := &CopyMoveRunner{Mode: config.ActionMove, FS: mem}
+	cfg = config.Action{Type: config.ActionMove, Dest: "/archive/2024/{name}", OnConflict: config.ConflictFail}
+	if err := mv.Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if _, err := mem.Stat(src); err == nil {
+		t.Fatalf("source still exists after move")
+	}
+	if got, _ := mem.ReadFile(filepath.FromSlash("/archive/2024/report.pdf")); string(got) != "pdf" {
+		t.Fatalf("unexpected moved content %q", got)
+	}
+}
+
+func TestMoveCarriesSidecars(t *testing.T) {
+	mem := fsys.NewMem()
+	for name, data := range map[string]string{"/in/IMG_1.CR2": "raw", "/in/IMG_1.xmp": "xmp", "/in/IMG_2.xmp": "other"} {
+		if err := mem.WriteFile(filepath.FromSlash(name), []byte(data)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	ev := Context{Path: filepath.FromSlash("/in/IMG_1.CR2"), RelPath: "IMG_1.CR2"}
+	mv := &CopyMoveRunner{Mode: config.ActionMove, FS: mem}
+	cfg := config.Action{Type: config.ActionMove, Dest: "/out/2024 {name}", Sidecars: []string{"{stem}.xmp", "{stem}.srt"}}
+	if err := mv.Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if got, _ := mem.ReadFile(filepath.FromSlash("/out/2024 IMG_1.xmp")); string(got) != "xmp" {
+		t.Fatalf("sidecar not moved with its primary, got %q", got)
+	}
+	if _, err := mem.Stat(filepath.FromSlash("/in/IMG_2.xmp")); err != nil {
+		t.Fatalf("unrelated sidecar moved: %v", err)
+	}
+
+	// A failure part way rolls back the sidecars already moved.
+	for name, data := range map[string]string{"/in/IMG_3.CR2": "raw", "/in/IMG_3.xmp": "xmp", "/in/IMG_3.srt": "srt", "/out/IMG_3.srt": "taken"} {
+		if err := mem.WriteFile(filepath.FromSlash(name), []byte(data)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	ev = Context{Path: filepath.FromSlash("/in/IMG_3.CR2"), RelPath: "IMG_3.CR2"}
+	cfg = config.Action{Type: config.ActionMove, Dest: "/out/{name}", Sidecars: []string{"{stem}.xmp", "{stem}.srt"}}
+	if err := mv.Run(context.Background(), ev, cfg); err == nil {
+		t.Fatalf("expected the move to fail on the existing sidecar dest")
+	}
+	if got, _ := mem.ReadFile(filepath.FromSlash("/in/IMG_3.xmp")); string(got) != "xmp" {
+		t.Fatalf("sidecar not rolled back")
+	}
+	if _, err := mem.Stat(filepath.FromSlash("/in/IMG_3.CR2")); err != nil {
+		t.Fatalf("primary moved despite the failure: %v", err)
+	}
+}
+
+func TestSidecarConflictsAndRollbackKeepExistingFiles(t *testing.T) {
+	mem := fsys.NewMem()
+	write := func(files map[string]string) {
+		for name, data := range files {
+			if err := mem.WriteFile(filepath.FromSlash(name), []byte(data)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+		}
+	}
+	read := func(name string) string {
+		got, _ := mem.ReadFile(filepath.FromSlash(name))
+		return string(got)
+	}
+	cp := &CopyMoveRunner{Mode: config.ActionCopy, FS: mem}
+
+	// A taken sidecar dest is resolved like the primary's.
+	write(map[string]string{"/in/IMG_5.CR2": "raw", "/in/IMG_5.xmp": "xmp", "/out/IMG_5.xmp": "theirs"})
+	ev := Context{Path: filepath.FromSlash("/in/IMG_5.CR2"), RelPath: "IMG_5.CR2"}
+	cfg := config.Action{Type: config.ActionCopy, Dest: "/out/{name}", Sidecars: []string{"{stem}.xmp"}, OnConflict: config.ConflictSuffix}
+	if err := cp.Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if read("/out/IMG_5.xmp") != "theirs" || read("/out/IMG_5 (1).xmp") != "xmp" {
+		t.Fatalf("sidecar overwrote an existing file")
+	}
+
+	// Files a failed group overwrote are restored, not removed.
+	write(map[string]string{"/in/IMG_6.xmp": "xmp", "/out/IMG_6.xmp": "old xmp", "/out/IMG_6.CR2": "old raw"})
+	ev = Context{Path: filepath.FromSlash("/in/IMG_6.CR2"), RelPath: "IMG_6.CR2"}
+	cfg = config.Action{Type: config.ActionCopy, Dest: "/out/{name}", Sidecars: []string{"{stem}.xmp"}, OnConflict: config.ConflictOverwrite}
+	if err := cp.Run(context.Background(), ev, cfg); err == nil {
+		t.Fatalf("expected the copy of a missing primary to fail")
+	}
+	if read("/out/IMG_6.xmp") != "old xmp" || read("/out/IMG_6.CR2") != "old raw" {
+		t.Fatalf("rollback lost overwritten files: %q, %q", read("/out/IMG_6.xmp"), read("/out/IMG_6.CR2"))
+	}
+	entries, _ := mem.ReadDir(filepath.FromSlash("/out"))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".watcher-backup") {
+			t.Fatalf("backup %s left behind", e.Name())
+		}
+	}
+}
+
+func TestWaitForSpaceThreshold(t *testing.T) {
+	orig := diskUsage
+	t.Cleanup(func() { diskUsage = orig })
+	for _, c := range []struct {
+		name string
+		free uint64
+		wait bool
+	}{{"below", 99, true}, {"at", 100, false}, {"above", 101, false}} {
+		t.Run(c.name, func(t *testing.T) {
+			diskUsage = func(string) (diskusage.Usage, error) { return diskusage.Usage{Total: 1000, Free: c.free}, nil }
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := waitForSpace(ctx, "/out/file", 100)
+			if waited := errors.Is(err, context.DeadlineExceeded); waited != c.wait || (!c.wait && err != nil) {
+				t.Fatalf("%d bytes free for 100: got %v, waiting %v", c.free, err, c.wait)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+const httpTimeout = 10 * time.Second
+
+type clientKey struct {
+	tls   config.ClientTLS
+	proxy string
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[clientKey]*http.Client{}
+)
+
+// httpClientFor returns a client honouring the action's TLS and proxy
+// settings. Clients are shared between actions with identical settings so
+// connections are reused.
+func httpClientFor(cfg config.Action) (*http.Client, error) {
+	key := clientKey{proxy: cfg.Proxy}
+	if cfg.TLS != nil {
+		key.tls = *cfg.TLS
+	}
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if c, ok := clients[key]; ok {
+		return c, nil
+	}
+	// The default transport already honours HTTP_PROXY/HTTPS_PROXY/NO_PROXY.
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	switch cfg.Proxy {
+	case "":
+	case config.ProxyNone:
+		tr.Proxy = nil
+	default:
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy: %w", err)
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+	if cfg.TLS != nil {
+		tlsCfg, err := clientTLS(key.tls)
+		if err != nil {
+			return nil, err
+		}
+		tr.TLSClientConfig = tlsCfg
+	}
+	c := &http.Client{Timeout: httpTimeout, Transport: tr}
+	clients[key] = c
+	return c, nil
+}
+
+func clientTLS(t config.ClientTLS) (*tls.Config, error) {
+	out := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: t.InsecureSkipVerify}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("tls ca_file: no certificates found")
+		}
+		out.RootCAs = pool
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls client certificate: %w", err)
+		}
+		out.Certificates = []tls.Certificate{cert}
+	}
+	return out, nil
+}
@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"path/filepath"
+	"strings"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/template"
+)
+
+// redactedPrefix replaces a path prefix listed in an action's redact.
+const redactedPrefix = "<redacted>"
+
+// actionPayload is the event payload as action sends it: redacted and
+// limited to its payload_fields.
+func actionPayload(ev Context, cfg config.Action) map[string]interface{} {
+	payload := EventPayload(ev)
+	if len(cfg.Redact) > 0 {
+		for _, key := range []string{"path", "relpath", "prev_path"} {
+			if s, ok := payload[key].(string); ok {
+				payload[key] = redactPath(s, cfg.Redact)
+			}
+		}
+		if dups, ok := payload["duplicates"].([]string); ok {
+			masked := make([]string, len(dups))
+			for i, d := range dups {
+				masked[i] = redactPath(d, cfg.Redact)
+			}
+			payload["duplicates"] = masked
+		}
+	}
+	if len(cfg.PayloadFields) > 0 {
+		keep := make(map[string]bool, len(cfg.PayloadFields))
+		for _, f := range cfg.PayloadFields {
+			keep[f] = true
+		}
+		for key := range payload {
+			if !keep[key] {
+				delete(payload, key)
+			}
+		}
+	}
+	return payload
+}
+
+// redactTemplateContext masks the redact prefixes in tctx's paths, so a
+// body_template exposes no more than the JSON payload would.
+func redactTemplateContext(tctx template.Context, prefixes []string) template.Context {
+	if len(prefixes) == 0 {
+		return tctx
+	}
+	tctx.Path = redactPath(tctx.Path, prefixes)
+	tctx.RelPath = redactPath(tctx.RelPath, prefixes)
+	tctx.PrevPath = redactPath(tctx.PrevPath, prefixes)
+	tctx.PrevRelPath = redactPath(tctx.PrevRelPath, prefixes)
+	if len(tctx.Duplicates) > 0 {
+		masked := make([]string, len(tctx.Duplicates))
+		for i, d := range tctx.Duplicates {
+			masked[i] = redactPath(d, prefixes)
+		}
+		tctx.Duplicates = masked
+	}
+	return tctx
+}
+
+// redactPath replaces the first of prefixes that path starts with, matching
+// whole path elements only.
+func redactPath(path string, prefixes []string) string {
+	for _, p := range prefixes {
+		p = filepath.Clean(p)
+		if path == p {
+			return redactedPrefix
+		}
+		if rest, ok := strings.CutPrefix(path, strings.TrimSuffix(p, string(filepath.Separator))+string(filepath.Separator)); ok {
+			return redactedPrefix + string(filepath.Separator) + rest
+		}
+	}
+	return path
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package actions
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// shellCommand runs line with /bin/sh.
+func shellCommand(line string) *exec.Cmd {
+	return exec.Command("/bin/sh", "-c", line)
+}
+
+// shellQuoteArg quotes s as one word for /bin/sh.
+func shellQuoteArg(s string) string {
+	return shellQuote(s)
+}
+
+// setProcessGroup starts the child in its own process group so the whole
+// tree can be signalled.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// procGroup is the process group of a started child.
+type procGroup struct {
+	pid int
+}
+
+func newProcGroup(cmd *exec.Cmd) *procGroup {
+	return &procGroup{pid: cmd.Process.Pid}
+}
+
+func (g *procGroup) terminate() error {
+	return syscall.Kill(-g.pid, syscall.SIGTERM)
+}
+
+func (g *procGroup) kill() error {
+	return syscall.Kill(-g.pid, syscall.SIGKILL)
+}
+
+func (g *procGroup) close() {}
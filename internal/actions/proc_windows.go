@@ -0,0 +1,92 @@
+//go:build windows
+
+package actions
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// shellCommand runs line with cmd.exe. The command line is passed as
+// written; Go's argument escaping is not what cmd.exe expects.
+func shellCommand(line string) *exec.Cmd {
+	comspec := os.Getenv("ComSpec")
+	if comspec == "" {
+		comspec = "cmd.exe"
+	}
+	cmd := exec.Command(comspec)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd.exe /d /s /c "` + line + `"`}
+	return cmd
+}
+
+// shellQuoteArg double-quotes s for cmd.exe. cmd.exe still expands
+// %VARIABLES% inside quotes.
+func shellQuoteArg(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+var (
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObject          = kernel32.NewProc("CreateJobObjectW")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = kernel32.NewProc("TerminateJobObject")
+)
+
+const (
+	processTerminate = 0x0001
+	processSetQuota  = 0x0100
+)
+
+// procGroup is a job object holding a started child, so the processes it
+// starts are stopped with it. Processes started before the child joins the
+// job, right after it starts, are not in it. Without a job only the child
+// is stopped.
+type procGroup struct {
+	cmd *exec.Cmd
+	job syscall.Handle
+}
+
+func newProcGroup(cmd *exec.Cmd) *procGroup {
+	g := &procGroup{cmd: cmd}
+	job, _, _ := procCreateJobObject.Call(0, 0)
+	if job == 0 {
+		return g
+	}
+	h, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		syscall.CloseHandle(syscall.Handle(job))
+		return g
+	}
+	defer syscall.CloseHandle(h)
+	if ok, _, _ := procAssignProcessToJobObject.Call(job, uintptr(h)); ok == 0 {
+		syscall.CloseHandle(syscall.Handle(job))
+		return g
+	}
+	g.job = syscall.Handle(job)
+	return g
+}
+
+// Windows has no SIGTERM; terminating is the same as killing.
+func (g *procGroup) terminate() error {
+	return g.kill()
+}
+
+func (g *procGroup) kill() error {
+	if g.job == 0 {
+		return g.cmd.Process.Kill()
+	}
+	if ok, _, err := procTerminateJobObject.Call(uintptr(g.job), 1); ok == 0 {
+		return err
+	}
+	return nil
+}
+
+func (g *procGroup) close() {
+	if g.job != 0 {
+		syscall.CloseHandle(g.job)
+	}
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// rateLimitedReader throttles reads to a fixed number of bytes per second.
+type rateLimitedReader struct {
+	ctx   context.Context
+	r     io.Reader
+	rate  int64
+	start time.Time
+	read  int64
+}
+
+func newRateLimitedReader(ctx context.Context, r io.Reader, bytesPerSec int64) *rateLimitedReader {
+	return &rateLimitedReader{ctx: ctx, r: r, rate: bytesPerSec, start: time.Now()}
+}
+
+func (l *rateLimitedReader) Read(p []byte) (int, error) {
+	// Read in slices of ~1/10s worth of data so throttling stays smooth.
+	if chunk := l.rate / 10; chunk > 0 && int64(len(p)) > chunk {
+		p = p[:chunk]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	due := l.start.Add(time.Duration(float64(l.read) / float64(l.rate) * float64(time.Second)))
+	if wait := time.Until(due); wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-l.ctx.Done():
+			return n, l.ctx.Err()
+		case <-t.C:
+		}
+	}
+	return n, err
+}
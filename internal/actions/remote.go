@@ -0,0 +1,61 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Remote opens the files of a watch that lists a remote source.
+type Remote interface {
+	Open(name string) (io.ReadCloser, error)
+}
+
+// download copies the event's remote file to the local dest. It writes
+// through a .partial file renamed into place, so an interrupted download
+// never looks complete.
+func download(ctx context.Context, ev Context, dest string, opts copyOptions) error {
+	if ev.IsDir {
+		return Permanent(fmt.Errorf("refusing to copy directory %s", ev.Path))
+	}
+	in, err := ev.Remote.Open(ev.Path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	tmp := dest + partialSuffix
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	r := readerWithContext(ctx, in)
+	if opts.bandwidth > 0 {
+		r = newRateLimitedReader(ctx, r, opts.bandwidth)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if opts.fsync {
+		if err := out.Sync(); err != nil {
+			out.Close()
+			return err
+		}
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return err
+	}
+	if opts.fsync {
+		return syncDir(filepath.Dir(dest))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package actions
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+type mapRemote map[string]string
+
+func (m mapRemote) Open(name string) (io.ReadCloser, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func TestCopyDownloadsRemoteFile(t *testing.T) {
+	dir := t.TempDir()
+	ev := Context{Path: "sftp://box/in/a.csv", RelPath: "a.csv", Remote: mapRemote{"sftp://box/in/a.csv": "1,2,3"}}
+	cfg := config.Action{Type: config.ActionCopy, Dest: filepath.Join(dir, "{relpath}")}
+	if err := (&CopyMoveRunner{Mode: config.ActionCopy}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "a.csv")); err != nil || string(data) != "1,2,3" {
+		t.Fatalf("unexpected download %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.csv.partial")); !os.IsNotExist(err) {
+		t.Fatalf("expected no partial file left, got %v", err)
+	}
+	err := (&CopyMoveRunner{Mode: config.ActionMove}).Run(context.Background(), ev, config.Action{Type: config.ActionMove, Dest: filepath.Join(dir, "b.csv")})
+	if err == nil || Retryable(err) {
+		t.Fatalf("expected a permanent error moving a remote file, got %v", err)
+	}
+}
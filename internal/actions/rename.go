@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/fsys"
+	"watcher-cli/internal/template"
+)
+
+// RenamePatternRunner renames files by rewriting their name with a regex.
+type RenamePatternRunner struct{}
+
+func (r *RenamePatternRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	re, err := cfg.FromPattern()
+	if err != nil {
+		return fmt.Errorf("invalid from pattern: %w", err)
+	}
+	name := filepath.Base(ev.Path)
+	if !re.MatchString(name) {
+		return nil
+	}
+	newName := template.Expand(re.ReplaceAllString(name, cfg.To), BuildTemplateContext(ev))
+	if newName == "" || newName == name {
+		return nil
+	}
+	dest := filepath.Join(filepath.Dir(ev.Path), newName)
+	dest, ok, err := resolveConflict(fsys.OS, dest, conflictPolicy(cfg))
+	if err != nil || !ok {
+		return err
+	}
+	if err := moveFile(ctx, ev.Path, dest, copyOptions{overwrite: true}); err != nil {
+		return err
+	}
+	capture(ev, cfg, config.CaptureDest, dest)
+	return nil
+}
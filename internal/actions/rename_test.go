@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestRenamePatternRewritesName(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "IMG_0042.jpeg")
+	if err := os.WriteFile(src, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "photo_0042.jpg"), []byte("y"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg := config.Action{
+		Type:       config.ActionRenamePattern,
+		From:       `^IMG_(\d+)\.jpeg$`,
+		To:         "photo_$1.jpg",
+		OnConflict: config.ConflictSuffix,
+	}
+	r := &RenamePatternRunner{}
+	if err := r.Run(context.Background(), Context{Path: src}, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "photo_0042 (1).jpg")); err != nil {
+		t.Fatalf("expected suffixed rename: %v", err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("expected source to be gone, got %v", err)
+	}
+}
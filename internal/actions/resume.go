@@ -0,0 +1,169 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"watcher-cli/internal/chaos"
+)
+
+const (
+	partialSuffix    = ".partial"
+	verifyTailSize   = 64 << 10
+	progressInterval = 10 * time.Second
+)
+
+// copyResumable copies src into dest+".partial", continuing from an earlier
+// attempt when the partial file's tail still matches the source, and renames
+// it into place once the trailing bytes verify.
+func copyResumable(ctx context.Context, src, dest string, opts copyOptions) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	total := info.Size()
+	partial := dest + partialSuffix
+	out, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	offset, err := resumeOffset(in, out, total)
+	if err != nil {
+		return err
+	}
+	if err := out.Truncate(offset); err != nil {
+		return err
+	}
+	if offset > 0 {
+		slog.Info("resuming copy", "event_id", opts.eventID, "src", src, "dest", dest, "offset", offset, "total", total)
+	}
+	if _, err := in.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	var r io.Reader = in
+	if opts.bandwidth > 0 {
+		r = newRateLimitedReader(ctx, in, opts.bandwidth)
+	}
+	r = chaos.From(ctx).Truncate(r, total-offset)
+	pw := &progressWriter{w: out, id: opts.eventID, src: src, done: offset, total: total, last: time.Now()}
+	if _, err := io.Copy(pw, readerWithContext(ctx, r)); err != nil {
+		return err
+	}
+	if ok, err := tailMatches(in, out, total); err != nil {
+		return err
+	} else if !ok {
+		// Leave nothing behind that a later attempt could trust.
+		out.Truncate(0)
+		return fmt.Errorf("verification failed for %s", partial)
+	}
+	if opts.fsync {
+		if err := out.Sync(); err != nil {
+			return err
+		}
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		return err
+	}
+	if opts.fsync {
+		return syncDir(filepath.Dir(dest))
+	}
+	return nil
+}
+
+// resumeOffset returns how many bytes of an existing partial file can be
+// kept: its length when its tail matches the source at the same offset,
+// otherwise zero.
+func resumeOffset(in, out *os.File, total int64) (int64, error) {
+	st, err := out.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := st.Size()
+	if size == 0 || size > total {
+		return 0, nil
+	}
+	ok, err := tailMatches(in, out, size)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return size, nil
+}
+
+// tailMatches compares the bytes just before end in both files.
+func tailMatches(a, b *os.File, end int64) (bool, error) {
+	n := int64(verifyTailSize)
+	if end < n {
+		n = end
+	}
+	bufA := make([]byte, n)
+	bufB := make([]byte, n)
+	if _, err := a.ReadAt(bufA, end-n); err != nil && err != io.EOF {
+		return false, err
+	}
+	if _, err := b.ReadAt(bufB, end-n); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(bufA, bufB), nil
+}
+
+// progressWriter logs copy progress at most every progressInterval.
+type progressWriter struct {
+	w     io.Writer
+	id    string
+	src   string
+	done  int64
+	total int64
+	last  time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	if time.Since(p.last) >= progressInterval {
+		p.last = time.Now()
+		pct := 100.0
+		if p.total > 0 {
+			pct = float64(p.done) / float64(p.total) * 100
+		}
+		slog.Info("copy progress", "event_id", p.id, "src", p.src, "bytes", p.done, "total", p.total, "percent", fmt.Sprintf("%.1f", pct))
+	}
+	return n, err
+}
+
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+func readerWithContext(ctx context.Context, r io.Reader) io.Reader {
+	return ctxReader{ctx: ctx, r: r}
+}
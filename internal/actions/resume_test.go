@@ -0,0 +1,43 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyResumableContinuesPartial(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "big.bin")
+	dest := filepath.Join(dir, "out", "big.bin")
+	data := bytes.Repeat([]byte("0123456789"), 20000)
+	if err := os.WriteFile(src, data, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	os.MkdirAll(filepath.Dir(dest), 0o755)
+	if err := os.WriteFile(dest+partialSuffix, data[:len(data)/2], 0o644); err != nil {
+		t.Fatalf("write partial: %v", err)
+	}
+	if err := copyFile(context.Background(), src, dest, copyOptions{resumable: true}); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	got, err := os.ReadFile(dest)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("dest content mismatch (err %v, len %d)", err, len(got))
+	}
+	if _, err := os.Stat(dest + partialSuffix); !os.IsNotExist(err) {
+		t.Fatalf("expected partial to be renamed away, got %v", err)
+	}
+
+	// A partial whose tail does not match restarts from zero.
+	dest2 := filepath.Join(dir, "out", "big2.bin")
+	os.WriteFile(dest2+partialSuffix, []byte("garbage"), 0o644)
+	if err := copyFile(context.Background(), src, dest2, copyOptions{resumable: true}); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if got, _ := os.ReadFile(dest2); !bytes.Equal(got, data) {
+		t.Fatalf("expected restart to produce exact copy")
+	}
+}
@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/template"
+)
+
+// ErrUnsafe marks an operation refused by global.safety.
+var ErrUnsafe = errors.New("refused by safety policy")
+
+// Guard enforces global.safety while actions run: destinations on the
+// deny list are refused, and destructive operations are capped per minute
+// across all watches.
+type Guard struct {
+	policy *config.Safety
+	// ops counts destructive operations; it limits like a retry budget.
+	ops *RetryBudget
+}
+
+// NewGuard returns a guard for p, or nil without a policy.
+func NewGuard(p *config.Safety) *Guard {
+	if p == nil {
+		return nil
+	}
+	g := &Guard{policy: p}
+	if p.MaxDestructivePerMinute > 0 {
+		g.ops = NewRetryBudget(p.MaxDestructivePerMinute)
+	}
+	return g
+}
+
+// Check refuses an action run the policy forbids. A denied destination
+// fails permanently; a capped destructive run may be retried.
+func (g *Guard) Check(ev Context, action config.Action) error {
+	if g == nil {
+		return nil
+	}
+	if action.Dest != "" {
+		dest := template.Expand(action.Dest, BuildTemplateContext(ev))
+		if action.Type == config.ActionRename && ev.RelPath != "" {
+			dest = filepath.Join(filepath.Dir(ev.Path), dest)
+		}
+		if entry, denied := g.policy.DeniedDest(dest); denied {
+			return Permanent(Classified(config.ClassPermission, fmt.Errorf("%w: dest %s is denied (%s)", ErrUnsafe, dest, entry)))
+		}
+	}
+	if action.Destructive() {
+		return g.TakeDestructive(ev.ID, action.Name)
+	}
+	return nil
+}
+
+// TakeDestructive counts one destructive operation against the cap.
+func (g *Guard) TakeDestructive(eventID, name string) error {
+	if g == nil || g.ops == nil {
+		return nil
+	}
+	ok, alert := g.ops.Take()
+	if ok {
+		return nil
+	}
+	if alert {
+		slog.Warn("destructive operation cap reached, refusing more this minute", "event_id", eventID, "action", name, "per_minute", g.policy.MaxDestructivePerMinute)
+	}
+	return Classified(config.ClassQuota, fmt.Errorf("%w: more than %d destructive operations per minute", ErrUnsafe, g.policy.MaxDestructivePerMinute))
+}
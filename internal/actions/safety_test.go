@@ -0,0 +1,29 @@
+package actions
+
+import (
+	"errors"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestGuardDeniesDestAndCapsDestructiveRuns(t *testing.T) {
+	g := NewGuard(&config.Safety{AllowDestructive: true, MaxDestructivePerMinute: 2})
+	ev := Context{Path: "/in/a.txt", RelPath: "a.txt"}
+	err := g.Check(ev, config.Action{Name: "c", Type: config.ActionCopy, Dest: "/etc/{name}"})
+	if !errors.Is(err, ErrUnsafe) || Retryable(err) {
+		t.Fatalf("expected a permanent safety error, got %v", err)
+	}
+	move := config.Action{Name: "m", Type: config.ActionMove, Dest: "/out/{name}"}
+	for i := 0; i < 2; i++ {
+		if err := g.Check(ev, move); err != nil {
+			t.Fatalf("move %d: %v", i, err)
+		}
+	}
+	if err := g.Check(ev, move); !errors.Is(err, ErrUnsafe) {
+		t.Fatalf("expected the cap to refuse a third move, got %v", err)
+	}
+	if err := g.Check(ev, config.Action{Name: "c", Type: config.ActionCopy, Dest: "/out/{name}"}); err != nil {
+		t.Fatalf("copies are not capped: %v", err)
+	}
+}
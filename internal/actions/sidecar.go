@@ -0,0 +1,158 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/fsys"
+	"watcher-cli/internal/template"
+)
+
+// sidecar is a companion file and where it goes.
+type sidecar struct {
+	src, dest string
+}
+
+// sidecars resolves cfg.Sidecars next to the source and places each one
+// that exists next to dest. A sidecar named after the source's stem is
+// renamed along with it, so IMG_1.xmp follows IMG_1.CR2 to "2024 IMG_1.CR2"
+// as "2024 IMG_1.xmp".
+func sidecars(fs fsys.FS, ev Context, cfg config.Action, dest string) []sidecar {
+	if len(cfg.Sidecars) == 0 {
+		return nil
+	}
+	srcDir := filepath.Dir(ev.Path)
+	srcStem := stem(filepath.Base(ev.Path))
+	destStem := stem(filepath.Base(dest))
+	tctx := BuildTemplateContext(ev)
+	var out []sidecar
+	for _, tmpl := range cfg.Sidecars {
+		name := template.Expand(tmpl, tctx)
+		if name == "" {
+			continue
+		}
+		src := name
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(srcDir, name)
+		}
+		if src == ev.Path {
+			continue
+		}
+		if info, err := fs.Stat(src); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		base := filepath.Base(src)
+		if rest, ok := strings.CutPrefix(base, srcStem); ok {
+			base = destStem + rest
+		}
+		out = append(out, sidecar{src: src, dest: filepath.Join(filepath.Dir(dest), ev.Normalize.String(base))})
+	}
+	return out
+}
+
+func stem(name string) string {
+	if dot := strings.LastIndex(name, "."); dot > 0 {
+		return name[:dot]
+	}
+	return name
+}
+
+// placed is a file transferGroup put in place.
+type placed struct {
+	sidecar
+	// backup is where an overwritten dest was set aside, or "".
+	backup string
+}
+
+// transferGroup copies or moves the sidecars and then the primary file as
+// one unit. Sidecars go first so the primary appearing means its group is
+// complete. Sidecar dests are resolved under policy like the primary's, and
+// a dest being overwritten is set aside until the group is in place. On
+// failure only what this transfer did is undone: files it created are
+// removed or moved back and the files they replaced are restored.
+func transferGroup(ctx context.Context, mode config.ActionType, policy config.ConflictPolicy, primary sidecar, companions []sidecar, opts copyOptions) error {
+	fs := opts.filesystem()
+	transfer := func(s sidecar, opts copyOptions) error {
+		if mode == config.ActionCopy {
+			return copyFile(ctx, s.src, s.dest, opts)
+		}
+		return moveFile(ctx, s.src, s.dest, opts)
+	}
+	if len(companions) == 0 {
+		return transfer(primary, opts)
+	}
+	var done []placed
+	place := func(s sidecar, opts copyOptions) error {
+		p := placed{sidecar: s}
+		if _, err := fs.Lstat(s.dest); err == nil {
+			p.backup = backupPath(s.dest)
+			if err := fs.Rename(s.dest, p.backup); err != nil {
+				return fmt.Errorf("set aside %s: %w", s.dest, err)
+			}
+		}
+		if err := transfer(s, opts); err != nil {
+			if p.backup != "" {
+				if rerr := fs.Rename(p.backup, s.dest); rerr != nil {
+					err = errors.Join(err, fmt.Errorf("restore %s: %w", s.dest, rerr))
+				}
+			}
+			return err
+		}
+		done = append(done, p)
+		return nil
+	}
+	rollback := func(cause error) error {
+		errs := []error{cause}
+		for i := len(done) - 1; i >= 0; i-- {
+			p := done[i]
+			var err error
+			if mode == config.ActionCopy {
+				err = fs.Remove(p.dest)
+			} else {
+				err = moveFile(ctx, p.dest, p.src, copyOptions{fs: opts.fs})
+			}
+			if err == nil && p.backup != "" {
+				err = fs.Rename(p.backup, p.dest)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("roll back %s: %w", p.dest, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	// Only the primary may come from the event's read cache.
+	sideOpts := opts
+	sideOpts.cache = nil
+	for _, s := range companions {
+		dest, ok, err := resolveConflict(fs, s.dest, policy)
+		if err != nil {
+			return rollback(fmt.Errorf("sidecar %s: %w", s.src, err))
+		}
+		if !ok {
+			continue
+		}
+		s.dest = dest
+		if err := place(s, sideOpts); err != nil {
+			return rollback(fmt.Errorf("sidecar %s: %w", s.src, err))
+		}
+	}
+	if err := place(primary, opts); err != nil {
+		return rollback(err)
+	}
+	for _, p := range done {
+		if p.backup != "" {
+			_ = fs.Remove(p.backup)
+		}
+	}
+	return nil
+}
+
+// backupPath is where transferGroup sets aside a file it overwrites: a
+// hidden name next to it, so restoring it is a rename.
+func backupPath(dest string) string {
+	return filepath.Join(filepath.Dir(dest), "."+filepath.Base(dest)+".watcher-backup")
+}
@@ -0,0 +1,43 @@
+package actions
+
+import (
+	"io"
+	"os"
+)
+
+const sparseChunk = 64 << 10
+
+// copySparse copies r to out, seeking over all-zero chunks instead of
+// writing them so holes in the source stay holes in the copy.
+func copySparse(out *os.File, r io.Reader, size int64) error {
+	buf := make([]byte, sparseChunk)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if isZero(buf[:n]) {
+				if _, err := out.Seek(int64(n), io.SeekCurrent); err != nil {
+					return err
+				}
+			} else if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// A trailing hole has no bytes written; truncate sets the length.
+	return out.Truncate(size)
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package actions
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// isSparse reports whether a file allocates fewer blocks than its size,
+// i.e. has holes worth preserving when copied.
+func isSparse(info fs.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && st.Blocks*512 < info.Size()
+}
@@ -0,0 +1,59 @@
+//go:build !windows
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCopyRefusesFIFO(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "pipe")
+	if err := syscall.Mkfifo(src, 0o644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- copyFile(context.Background(), src, filepath.Join(dir, "out"), copyOptions{}) }()
+	select {
+	case err := <-done:
+		if err == nil || !IsPermanent(err) {
+			t.Fatalf("expected permanent error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("copying a FIFO blocked")
+	}
+}
+
+func TestCopyKeepsSparseContent(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "disk.img")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("head"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("tail"), 4<<20); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(8 << 20); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	dest := filepath.Join(dir, "copy.img")
+	if err := copyFile(context.Background(), src, dest, copyOptions{}); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	want, _ := os.ReadFile(src)
+	got, err := os.ReadFile(dest)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("copy differs from source (len %d vs %d): %v", len(got), len(want), err)
+	}
+}
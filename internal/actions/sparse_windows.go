@@ -0,0 +1,7 @@
+//go:build windows
+
+package actions
+
+import "io/fs"
+
+func isSparse(info fs.FileInfo) bool { return false }
@@ -0,0 +1,97 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/sshcmd"
+	"watcher-cli/internal/template"
+)
+
+// SSHRunner runs commands on remote hosts through the ssh client. Runs to
+// the same host share one connection.
+type SSHRunner struct {
+	mux sshcmd.Mux
+}
+
+// Close removes the runner's shared connection sockets.
+func (r *SSHRunner) Close() error {
+	return r.mux.Close()
+}
+
+func (r *SSHRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	remote := remoteCommand(ev, cfg)
+	if remote == "" {
+		return nil
+	}
+	cmd := sshcmd.Command(cfg.SSH, r.mux.ControlPath())
+	cmd.Args = append(cmd.Args, remote)
+	switch cfg.Stdin {
+	case config.StdinFile:
+		f, err := ev.Cache.Open(ev.Path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		cmd.Stdin = f
+	case config.StdinJSON:
+		body, err := json.Marshal(actionPayload(ev, cfg))
+		if err != nil {
+			return err
+		}
+		cmd.Stdin = bytes.NewReader(body)
+	}
+	return runOutput(ctx, ev, cfg, cmd)
+}
+
+// remoteCommand renders the shell command line run on the remote host.
+// The event's WATCHER_* variables and env are exported first, and cwd is
+// entered before the command runs. List-form cmd arguments are quoted so
+// each reaches the remote command as one argument; a string cmd is a
+// remote shell line whose substituted values are quoted the same way.
+func remoteCommand(ev Context, cfg config.Action) string {
+	tctx := BuildTemplateContext(ev)
+	var line string
+	if len(cfg.Cmd.Args) > 0 {
+		quoted := make([]string, 0, len(cfg.Cmd.Args))
+		for _, arg := range cfg.Cmd.Args {
+			quoted = append(quoted, shellQuote(template.Expand(arg, tctx)))
+		}
+		line = strings.Join(quoted, " ")
+	} else {
+		line = strings.TrimSpace(template.ExpandQuoted(cfg.Cmd.Line, tctx, shellQuote))
+	}
+	if line == "" {
+		return ""
+	}
+	env := eventEnv(ev, cfg)
+	names := make([]string, 0, len(cfg.Env))
+	for k := range cfg.Env {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		env = append(env, k+"="+template.Expand(cfg.Env[k], tctx))
+	}
+	var b strings.Builder
+	b.WriteString("export")
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		b.WriteString(" " + name + "=" + shellQuote(value))
+	}
+	b.WriteString("; ")
+	if cfg.Cwd != "" {
+		b.WriteString("cd " + shellQuote(template.Expand(cfg.Cwd, tctx)) + " && ")
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/sshcmd"
+)
+
+func TestSSHExecRunsQuotedCommandOnHost(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	dir := t.TempDir()
+	// The stub records its arguments and runs the remote command locally.
+	stub := filepath.Join(dir, "ssh")
+	script := "#!/bin/sh\necho \"$@\" > " + filepath.Join(dir, "args") + "\nfor a; do last=$a; done\nexec sh -c \"$last\"\n"
+	if err := os.WriteFile(stub, []byte(script), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	old := sshcmd.Binary
+	sshcmd.Binary = stub
+	defer func() { sshcmd.Binary = old }()
+
+	outputs := map[string]string{}
+	ev := Context{Path: "/data/it's here.txt", Event: "create", Outputs: outputs}
+	cfg := config.Action{
+		Name:    "remote",
+		Type:    config.ActionSSHExec,
+		Cmd:     config.Command{Args: []string{"printf", "%s|%s|%s", "{name}", "$WATCHER_EVENT", "$LABEL"}},
+		Env:     map[string]string{"LABEL": "{ext}"},
+		SSH:     &config.SSHTarget{Host: "box", User: "deploy", Port: 2222},
+		Capture: map[string]config.CaptureSource{"out": config.CaptureStdout},
+	}
+	if err := (&SSHRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	// List-form arguments arrive verbatim, not expanded by the remote shell.
+	if got := outputs["out"]; got != "it's here.txt|$WATCHER_EVENT|$LABEL" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatalf("read args: %v", err)
+	}
+	for _, want := range []string{"-l deploy", "-p 2222", "ControlMaster=auto", "-- box "} {
+		if !strings.Contains(string(args), want) {
+			t.Fatalf("expected %q in ssh args %q", want, args)
+		}
+	}
+
+	// The line form goes to the remote shell, which sees the exported env.
+	cfg.Cmd = config.Command{Line: `printf '%s|%s' "$WATCHER_EVENT" "$LABEL"`}
+	if err := (&SSHRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := outputs["out"]; got != "create|.txt" {
+		t.Fatalf("unexpected output %q", got)
+	}
+
+	// Values in the line are quoted, so a hostile name stays one argument.
+	ev.Path = "/data/x; touch pwned; $(touch pwned) `touch pwned`.txt"
+	cfg.Cmd = config.Command{Line: `printf '%s' {name}`}
+	cfg.Cwd = dir
+	marker := filepath.Join(dir, "pwned")
+	if err := (&SSHRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatalf("the file name ran as a command on the remote host")
+	}
+	if got, want := outputs["out"], "x; touch pwned; $(touch pwned) `touch pwned`.txt"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
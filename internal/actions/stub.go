@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+// ErrStubFailure marks the failures a StubRunner makes up.
+var ErrStubFailure = errors.New("simulated failure")
+
+// StubRunner stands in for every runner when simulating an event, so an
+// action's retries, backoff and timeout can be tried without touching
+// anything. Each attempt takes Latency, or until its timeout, and the
+// first FailFirst attempts of each action fail with class Class.
+type StubRunner struct {
+	FailFirst int
+	Latency   time.Duration
+	// Class is the class of the made-up failures; empty is "other".
+	Class config.ErrorClass
+	// OnAttempt, when set, is called after each attempt; n is 1-based.
+	OnAttempt func(action string, n int, took time.Duration, err error)
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+// Run implements Runner.
+func (s *StubRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	s.mu.Lock()
+	if s.attempts == nil {
+		s.attempts = map[string]int{}
+	}
+	s.attempts[cfg.Name]++
+	n := s.attempts[cfg.Name]
+	s.mu.Unlock()
+
+	started := time.Now()
+	err := sleepCtx(ctx, s.Latency)
+	if err == nil && n <= s.FailFirst {
+		class := s.Class
+		if class == "" {
+			class = config.ClassOther
+		}
+		err = Classified(class, fmt.Errorf("%w %d of %d", ErrStubFailure, n, s.FailFirst))
+	}
+	if s.OnAttempt != nil {
+		s.OnAttempt(cfg.Name, n, time.Since(started), err)
+	}
+	return err
+}
+
+// StubRegistry returns a registry that runs stub for every action type.
+func StubRegistry(stub Runner) *Registry {
+	r := NewRegistry()
+	for kind := range r.entries {
+		r.Register(kind, stub)
+	}
+	return r
+}
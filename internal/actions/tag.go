@@ -0,0 +1,34 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/template"
+	"watcher-cli/internal/xattr"
+)
+
+// TagRunner writes user extended attributes onto the event's file, so an
+// xattr condition can skip files that were already handled.
+type TagRunner struct{}
+
+func (r *TagRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	keys := make([]string, 0, len(cfg.Xattrs))
+	for k := range cfg.Xattrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tctx := BuildTemplateContext(ev)
+	for _, k := range keys {
+		err := xattr.Set(ev.Path, k, template.Expand(cfg.Xattrs[k], tctx))
+		if errors.Is(err, xattr.ErrUnsupported) {
+			return Permanent(err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/xattr"
+)
+
+func TestTagWritesTemplatedXattrs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.pdf")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ev := Context{Path: path, Event: "create"}
+	cfg := config.Action{Type: config.ActionTag, Xattrs: map[string]string{"user.processed": "true", "on": "{event}"}}
+	err := (&TagRunner{}).Run(context.Background(), ev, cfg)
+	if errors.Is(err, xattr.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+	got := xattr.List(path)
+	if got["processed"] != "true" || got["on"] != "create" {
+		t.Fatalf("unexpected xattrs %#v", got)
+	}
+}
@@ -0,0 +1,139 @@
+package actions
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+// RateLimiter is a token bucket: it holds up to burst tokens and gains one
+// every interval of the rate.
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     config.Rate
+	burst    int
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+	interval time.Duration
+}
+
+// NewRateLimiter allows runs at rate with up to burst (at least 1) at once.
+func NewRateLimiter(rate config.Rate, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	l := &RateLimiter{rate: rate, burst: burst, interval: rate.Interval(), now: time.Now}
+	l.tokens = float64(burst)
+	l.last = l.now()
+	return l
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.tokens = min(float64(l.burst), l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// cancel returns a token reserved but not used.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+// Wait blocks until a run may start or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	d := l.reserve()
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// RateLimits holds the limiters of one watch's actions, keyed by name.
+type RateLimits struct {
+	mu       sync.Mutex
+	limiters map[string]*RateLimiter
+}
+
+// NewRateLimits returns an empty set.
+func NewRateLimits() *RateLimits {
+	return &RateLimits{limiters: map[string]*RateLimiter{}}
+}
+
+// limiter returns the action's limiter, or nil without a rate_limit. A
+// reloaded action with a different rate starts a new bucket.
+func (r *RateLimits) limiter(action config.Action) *RateLimiter {
+	if r == nil || action.RateLimit.IsZero() {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l := r.limiters[action.Name]
+	if l == nil || l.rate != action.RateLimit || l.burst != max(action.Burst, 1) {
+		l = NewRateLimiter(action.RateLimit, action.Burst)
+		r.limiters[action.Name] = l
+	}
+	return l
+}
+
+// throttleToken is a rate-limit token taken ahead of a run; the run's own
+// throttle uses it instead of waiting again.
+type throttleToken struct {
+	action string
+	used   atomic.Bool
+}
+
+type throttleKey struct{}
+
+// Throttle waits until the action's rate_limit allows another run and
+// returns a context carrying the token, so the next run of the action
+// under it starts without waiting. Callers that take a slot for the run
+// wait here first, so the wait holds no slot. When ctx is done first the
+// run's own wait reports it. Dry runs are not throttled.
+func (e *Executor) Throttle(ctx context.Context, ev Context, action config.Action) context.Context {
+	if e.DryRun || e.Limits.limiter(action) == nil || e.throttle(ctx, ev, action) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, throttleKey{}, &throttleToken{action: action.Name})
+}
+
+// throttle waits until the action's rate_limit allows another run.
+func (e *Executor) throttle(ctx context.Context, ev Context, action config.Action) error {
+	l := e.Limits.limiter(action)
+	if l == nil {
+		return nil
+	}
+	if t, ok := ctx.Value(throttleKey{}).(*throttleToken); ok && t.action == action.Name && t.used.CompareAndSwap(false, true) {
+		return nil
+	}
+	started := time.Now()
+	if err := l.Wait(ctx); err != nil {
+		return err
+	}
+	if waited := time.Since(started); waited >= time.Millisecond {
+		slog.Debug("action throttled", "event_id", ev.ID, "action", action.Name, "waited_ms", waited.Milliseconds())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+func TestRateLimiterSpacesRunsAfterBurst(t *testing.T) {
+	now := time.Unix(0, 0)
+	rate, err := config.ParseRate("10/1m")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	l := NewRateLimiter(rate, 2)
+	l.now = func() time.Time { return now }
+	l.last = now
+	for i, want := range []time.Duration{0, 0, 6 * time.Second, 12 * time.Second} {
+		if got := l.reserve(); got != want {
+			t.Fatalf("reserve %d: wait %v, want %v", i, got, want)
+		}
+	}
+	// A quiet spell refills the bucket, but never beyond the burst.
+	now = now.Add(time.Hour)
+	for i, want := range []time.Duration{0, 0, 6 * time.Second} {
+		if got := l.reserve(); got != want {
+			t.Fatalf("after refill, reserve %d: wait %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestRateLimiterWaitHonoursCancel(t *testing.T) {
+	l := NewRateLimiter(config.Rate{Count: 1, Per: time.Hour}, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Fatalf("expected the second run to wait past the deadline")
+	}
+}
+
+type nopRunner struct{ runs int }
+
+func (r *nopRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	r.runs++
+	return nil
+}
+
+func TestThrottleTokenCoversOneRun(t *testing.T) {
+	runner := &nopRunner{}
+	reg := NewRegistry()
+	reg.Register(config.ActionExec, runner)
+	e := &Executor{Registry: reg, Limits: NewRateLimits()}
+	action := config.Action{Name: "a", Type: config.ActionExec, RateLimit: config.Rate{Count: 1, Per: time.Hour}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ctx = e.Throttle(ctx, Context{}, action)
+	if err := e.Attempt(ctx, Context{}, action); err != nil {
+		t.Fatalf("the run waited again for the token it was given: %v", err)
+	}
+	if err := e.Attempt(ctx, Context{}, action); err == nil {
+		t.Fatalf("the token let a second run through")
+	}
+	if runner.runs != 1 {
+		t.Fatalf("expected one run, got %d", runner.runs)
+	}
+}
@@ -0,0 +1,164 @@
+package actions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/fsys"
+	"watcher-cli/internal/template"
+)
+
+const journalSuffix = ".transfer"
+
+// Transfer journal states, in order.
+const (
+	transferStarted  = "started"
+	transferCopied   = "copied"
+	transferVerified = "verified"
+)
+
+// transferJournal records progress of a transfer next to its destination so
+// an interrupted sequence resumes at the right step.
+type transferJournal struct {
+	Source  string    `json:"source"`
+	Dest    string    `json:"dest"`
+	Size    int64     `json:"size"`
+	SHA256  string    `json:"sha256"`
+	State   string    `json:"state"`
+	Updated time.Time `json:"updated"`
+}
+
+// TransferRunner copies a file, verifies the copy by checksum and only then
+// removes the source.
+type TransferRunner struct{}
+
+func (r *TransferRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
+	dest := template.Expand(cfg.Dest, BuildTemplateContext(ev))
+	if dest == "" {
+		return fmt.Errorf("empty dest")
+	}
+	// The journal is keyed on the templated dest, not the name a conflict
+	// resolves it to, so a retry finds it; it records the resolved name.
+	journalPath := dest + journalSuffix
+	j, err := readJournal(journalPath)
+	if err != nil {
+		return err
+	}
+	if j == nil || j.Source != ev.Path {
+		var ok bool
+		dest, ok, err = resolveConflict(fsys.OS, dest, conflictPolicy(cfg))
+		if err != nil || !ok {
+			return err
+		}
+		sum, size, err := ev.Cache.SHA256(ev.Path)
+		if err != nil {
+			return err
+		}
+		j = &transferJournal{Source: ev.Path, Dest: dest, Size: size, SHA256: sum, State: transferStarted}
+		if err := writeJournal(journalPath, j); err != nil {
+			return err
+		}
+	}
+	opts := copyOptions{
+		overwrite: true,
+		bandwidth: int64(cfg.BandwidthLimit),
+		resumable: cfg.Resumable,
+		fsync:     true,
+		cache:     ev.Cache,
+	}
+	if j.State == transferStarted {
+		if err := os.MkdirAll(filepath.Dir(j.Dest), 0o755); err != nil {
+			return err
+		}
+		if err := copyFile(ctx, j.Source, j.Dest, opts); err != nil {
+			return err
+		}
+		j.State = transferCopied
+		if err := writeJournal(journalPath, j); err != nil {
+			return err
+		}
+	}
+	if j.State == transferCopied {
+		sum, _, err := hashPath(j.Dest)
+		if err != nil {
+			return err
+		}
+		if sum != j.SHA256 {
+			// Start over on the next attempt.
+			j.State = transferStarted
+			if err := writeJournal(journalPath, j); err != nil {
+				return err
+			}
+			return fmt.Errorf("checksum mismatch for %s", j.Dest)
+		}
+		j.State = transferVerified
+		if err := writeJournal(journalPath, j); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(j.Source); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := syncDir(filepath.Dir(j.Source)); err != nil {
+		return err
+	}
+	if err := os.Remove(journalPath); err != nil {
+		return err
+	}
+	capture(ev, cfg, config.CaptureDest, j.Dest)
+	return nil
+}
+
+func hashPath(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func readJournal(path string) (*transferJournal, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var j transferJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("read journal %s: %w", path, err)
+	}
+	return &j, nil
+}
+
+func writeJournal(path string, j *transferJournal) error {
+	j.Updated = time.Now()
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
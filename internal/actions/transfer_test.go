@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestTransferResumesFromJournal(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in.txt")
+	dest := filepath.Join(dir, "out", "in.txt")
+	if err := os.WriteFile(src, []byte("payload"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// Simulate a crash after the copy was verified but before the source
+	// was removed.
+	sum, size, _ := hashPath(src)
+	os.MkdirAll(filepath.Dir(dest), 0o755)
+	os.WriteFile(dest, []byte("payload"), 0o644)
+	writeJournal(dest+journalSuffix, &transferJournal{Source: src, Dest: dest, Size: size, SHA256: sum, State: transferVerified})
+
+	cfg := config.Action{Type: config.ActionTransfer, Dest: dest}
+	if err := (&TransferRunner{}).Run(context.Background(), Context{Path: src}, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("expected source removed, got %v", err)
+	}
+	if _, err := os.Stat(dest + journalSuffix); !os.IsNotExist(err) {
+		t.Fatalf("expected journal removed, got %v", err)
+	}
+	if got, _ := os.ReadFile(dest); string(got) != "payload" {
+		t.Fatalf("unexpected dest content %q", got)
+	}
+}
+
+func TestTransferResumesToSuffixedDest(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in.txt")
+	dest := filepath.Join(dir, "out", "in.txt")
+	os.WriteFile(src, []byte("payload"), 0o644)
+	os.MkdirAll(filepath.Dir(dest), 0o755)
+	os.WriteFile(dest, []byte("older"), 0o644)
+
+	// The first attempt resolves the conflict and copies, but the copy
+	// fails verification; the retry must pick up the same suffixed file
+	// rather than pick a new name.
+	cfg := config.Action{Type: config.ActionTransfer, Dest: dest, OnConflict: config.ConflictSuffix}
+	info, _ := os.Stat(src)
+	sum, _, _ := hashPath(src)
+	corrupt := NewReadCache(1 << 20)
+	corrupt.entries[src] = &cacheEntry{size: info.Size(), mtime: info.ModTime(), data: []byte("garbage"), sum: sum}
+	if err := (&TransferRunner{}).Run(context.Background(), Context{Path: src, Cache: corrupt}, cfg); err == nil {
+		t.Fatalf("expected the corrupted copy to fail verification")
+	}
+	if err := (&TransferRunner{}).Run(context.Background(), Context{Path: src}, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(dest))
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 || names[0] != "in (1).txt" || names[1] != "in.txt" {
+		t.Fatalf("expected the retry to finish in (1).txt only, got %v", names)
+	}
+	if got, _ := os.ReadFile(dest); string(got) != "older" {
+		t.Fatalf("existing dest was touched: %q", got)
+	}
+}
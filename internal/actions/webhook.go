@@ -5,7 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"watcher-cli/internal/config"
@@ -18,37 +23,229 @@ type WebhookRunner struct {
 }
 
 func (r *WebhookRunner) Run(ctx context.Context, ev Context, cfg config.Action) error {
-	url := template.Expand(cfg.URL, BuildTemplateContext(ev))
+	tctx := BuildTemplateContext(ev)
+	url := template.Expand(cfg.URL, tctx)
 	if url == "" {
 		return nil
 	}
-	payload := map[string]interface{}{
-		"path":      ev.Path,
-		"relpath":   ev.RelPath,
-		"prev_path": ev.PrevPath,
-		"event":     ev.Event,
-		"size":      ev.Size,
-		"mtime":     ev.ModTime,
-		"age_ms":    ev.Age.Milliseconds(),
-		"is_dir":    ev.IsDir,
-	}
-	body, _ := json.Marshal(payload)
-	client := r.Client
-	if client == nil {
-		client = &http.Client{Timeout: 10 * time.Second}
+	contentType := "application/json"
+	var body []byte
+	if cfg.BodyTemplate != "" {
+		body = []byte(template.Expand(cfg.BodyTemplate, redactTemplateContext(tctx, cfg.Redact)))
+	} else {
+		var payload interface{} = actionPayload(ev, cfg)
+		if cfg.PayloadFormat == config.PayloadCloudEvents {
+			contentType = "application/cloudevents+json"
+			ce := NewCloudEvent(ev, cfg.Name, time.Now())
+			ce.Subject = redactPath(ce.Subject, cfg.Redact)
+			ce.Data = payload.(map[string]interface{})
+			payload = ce
+		}
+		body, _ = json.Marshal(payload)
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	req, err := newWebhookRequest(ctx, cfg, url, body, tctx)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
+	setDefaultHeader(req, "Content-Type", contentType)
+	req.Header.Set("X-Watcher-Event-Id", ev.ID)
+	resp, err := r.do(req, cfg)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook status %d", resp.StatusCode)
+	if cfg.Response == nil {
+		return nil
+	}
+	return handleResponse(resp.Body, ev, cfg)
+}
+
+// RunBatch posts one JSON array holding the payloads of evs, or with
+// payload_format cloudevents a CloudEvents batch.
+func (r *WebhookRunner) RunBatch(ctx context.Context, evs []Context, cfg config.Action) error {
+	tctx := BuildTemplateContext(evs[0])
+	url := template.Expand(cfg.URL, tctx)
+	if url == "" {
+		return nil
+	}
+	contentType := "application/json"
+	payloads := make([]interface{}, len(evs))
+	for i, ev := range evs {
+		payload := actionPayload(ev, cfg)
+		if cfg.PayloadFormat == config.PayloadCloudEvents {
+			ce := NewCloudEvent(ev, cfg.Name, time.Now())
+			ce.Subject = redactPath(ce.Subject, cfg.Redact)
+			ce.Data = payload
+			payloads[i] = ce
+			continue
+		}
+		payloads[i] = payload
+	}
+	if cfg.PayloadFormat == config.PayloadCloudEvents {
+		contentType = "application/cloudevents-batch+json"
+	}
+	body, _ := json.Marshal(payloads)
+	req, err := newWebhookRequest(ctx, cfg, url, body, tctx)
+	if err != nil {
+		return err
 	}
+	setDefaultHeader(req, "Content-Type", contentType)
+	req.Header.Set("X-Watcher-Event-Id", evs[0].ID)
+	req.Header.Set("X-Watcher-Batch-Size", strconv.Itoa(len(evs)))
+	resp, err := r.do(req, cfg)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
 	return nil
 }
+
+// newWebhookRequest builds a request with cfg's method, headers and auth.
+// GET and HEAD requests carry no body.
+func newWebhookRequest(ctx context.Context, cfg config.Action, url string, body []byte, tctx template.Context) (*http.Request, error) {
+	method := http.MethodPost
+	if cfg.Method != "" {
+		method = strings.ToUpper(cfg.Method)
+	}
+	var rd io.Reader
+	if method != http.MethodGet && method != http.MethodHead {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, rd)
+	if err != nil {
+		return nil, err
+	}
+	for name, v := range cfg.Headers {
+		req.Header.Set(name, template.Expand(v, tctx))
+	}
+	if au := cfg.Auth; au != nil {
+		if au.BearerEnv != "" {
+			token, err := authEnv(au.BearerEnv)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else {
+			user, err := authEnv(au.UsernameEnv)
+			if err != nil {
+				return nil, err
+			}
+			pass := ""
+			if au.PasswordEnv != "" {
+				if pass, err = authEnv(au.PasswordEnv); err != nil {
+					return nil, err
+				}
+			}
+			req.SetBasicAuth(user, pass)
+		}
+	}
+	return req, nil
+}
+
+// authEnv reads a credential from the environment; an unset variable
+// fails the action for good, as retrying will not set it.
+func authEnv(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok || v == "" {
+		return "", Permanent(Classified(config.ClassPermission, fmt.Errorf("webhook auth: $%s is not set", name)))
+	}
+	return v, nil
+}
+
+// setDefaultHeader sets name unless the action's headers already did.
+func setDefaultHeader(req *http.Request, name, value string) {
+	if req.Header.Get(name) == "" {
+		req.Header.Set(name, value)
+	}
+}
+
+// do sends req and fails on a status outside cfg's success_status, or a
+// non-2xx one when that is unset.
+func (r *WebhookRunner) do(req *http.Request, cfg config.Action) (*http.Response, error) {
+	client := r.Client
+	if client == nil {
+		var err error
+		if client, err = httpClientFor(cfg); err != nil {
+			return nil, Permanent(err)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	ok := resp.StatusCode < 300
+	if len(cfg.SuccessStatus) > 0 {
+		ok = slices.Contains(cfg.SuccessStatus, resp.StatusCode)
+	}
+	if !ok {
+		resp.Body.Close()
+		return nil, &StatusError{Code: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// handleResponse captures fields from a JSON response body and maps the
+// configured outcome field to an action result.
+func handleResponse(body io.Reader, ev Context, cfg config.Action) error {
+	var doc interface{}
+	if err := json.NewDecoder(io.LimitReader(body, maxCapture)).Decode(&doc); err != nil {
+		return fmt.Errorf("decode webhook response: %w", err)
+	}
+	rc := cfg.Response
+	if ev.Outputs != nil {
+		for name, field := range rc.Capture {
+			if v, ok := lookupJSON(doc, field); ok {
+				ev.Outputs[name] = v
+			}
+		}
+	}
+	if rc.OutcomeField == "" {
+		return nil
+	}
+	value, _ := lookupJSON(doc, rc.OutcomeField)
+	switch rc.Outcomes[value] {
+	case config.OutcomeSkip:
+		return ErrSkip
+	case config.OutcomeRetry:
+		return fmt.Errorf("webhook asked to retry (%s=%s)", rc.OutcomeField, value)
+	case config.OutcomeFail:
+		return Permanent(fmt.Errorf("webhook rejected event (%s=%s)", rc.OutcomeField, value))
+	default:
+		return nil
+	}
+}
+
+// lookupJSON resolves a dot-separated path (object keys or array indexes)
+// and renders the value as a string.
+func lookupJSON(doc interface{}, path string) (string, bool) {
+	cur := doc
+	for _, part := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			v, ok := node[part]
+			if !ok {
+				return "", false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			cur = node[i]
+		default:
+			return "", false
+		}
+	}
+	switch v := cur.(type) {
+	case string:
+		return v, true
+	case nil:
+		return "", true
+	case float64, bool:
+		return fmt.Sprint(v), true
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw), true
+	}
+}
@@ -0,0 +1,209 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestWebhookResponseRouting(t *testing.T) {
+	status := "accepted"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"job":{"id":"j-42"},"status":"` + status + `"}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.Action{
+		Type: config.ActionWebhook,
+		URL:  srv.URL,
+		Response: &config.WebhookResponse{
+			Capture:      map[string]string{"job_id": "job.id"},
+			OutcomeField: "status",
+			Outcomes: map[string]config.Outcome{
+				"duplicate": config.OutcomeSkip,
+				"rejected":  config.OutcomeFail,
+			},
+		},
+	}
+	outputs := map[string]string{}
+	r := &WebhookRunner{}
+	if err := r.Run(context.Background(), Context{Path: "/x", Outputs: outputs}, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if outputs["job_id"] != "j-42" {
+		t.Fatalf("expected captured job id, got %#v", outputs)
+	}
+
+	status = "duplicate"
+	if err := r.Run(context.Background(), Context{Path: "/x"}, cfg); !errors.Is(err, ErrSkip) {
+		t.Fatalf("expected skip, got %v", err)
+	}
+	status = "rejected"
+	if err := r.Run(context.Background(), Context{Path: "/x"}, cfg); !IsPermanent(err) {
+		t.Fatalf("expected permanent failure, got %v", err)
+	}
+}
+
+func TestWebhookCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, pemBytes, 0o644); err != nil {
+		t.Fatalf("write ca: %v", err)
+	}
+	r := &WebhookRunner{}
+	cfg := config.Action{Type: config.ActionWebhook, URL: srv.URL}
+	if err := r.Run(context.Background(), Context{Path: "/x"}, cfg); err == nil {
+		t.Fatalf("expected verification failure without ca_file")
+	}
+	cfg.TLS = &config.ClientTLS{CAFile: caFile}
+	if err := r.Run(context.Background(), Context{Path: "/x"}, cfg); err != nil {
+		t.Fatalf("expected success with ca_file, got %v", err)
+	}
+}
+
+func TestWebhookCloudEventsPayload(t *testing.T) {
+	var got map[string]interface{}
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	cfg := config.Action{Name: "notify", Type: config.ActionWebhook, URL: srv.URL, PayloadFormat: config.PayloadCloudEvents}
+	ev := Context{ID: "abc", Path: "/in/a.pdf", RelPath: "a.pdf", Event: "create"}
+	if err := (&WebhookRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if contentType != "application/cloudevents+json" {
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+	if got["specversion"] != "1.0" || got["id"] != "abc.notify" || got["type"] != "io.watcher.file.create" || got["subject"] != "a.pdf" {
+		t.Fatalf("unexpected envelope %#v", got)
+	}
+	if src, _ := got["source"].(string); !strings.HasPrefix(src, "watcher://") {
+		t.Fatalf("unexpected source %v", got["source"])
+	}
+	if data, _ := got["data"].(map[string]interface{}); data["path"] != "/in/a.pdf" {
+		t.Fatalf("unexpected data %#v", got["data"])
+	}
+}
+
+func TestWebhookPayloadFieldsAndRedaction(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	home := filepath.FromSlash("/home/alice")
+	cfg := config.Action{
+		Type:          config.ActionWebhook,
+		URL:           srv.URL,
+		PayloadFields: []string{"path", "prev_path", "event"},
+		Redact:        []string{home},
+	}
+	ev := Context{
+		Path:     filepath.Join(home, "in", "a.txt"),
+		PrevPath: filepath.FromSlash("/home/alicia/a.txt"),
+		Event:    "move",
+		Size:     10,
+	}
+	if err := (&WebhookRunner{}).Run(context.Background(), ev, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	want := map[string]interface{}{
+		"path":      filepath.Join("<redacted>", "in", "a.txt"),
+		"prev_path": filepath.FromSlash("/home/alicia/a.txt"),
+		"event":     "move",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("payload %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("payload %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWebhookMethodHeadersAuthAndBodyTemplate(t *testing.T) {
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got, body = r, string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	t.Setenv("TEST_WEBHOOK_TOKEN", "s3cret")
+	cfg := config.Action{
+		Type:          config.ActionWebhook,
+		URL:           srv.URL + "/files/{name}",
+		Method:        "put",
+		Headers:       map[string]string{"X-File": "{stem|upper}", "Content-Type": "application/vnd.api+json"},
+		BodyTemplate:  `{"name": {name|json}, "size": {size}}`,
+		Auth:          &config.WebhookAuth{BearerEnv: "TEST_WEBHOOK_TOKEN"},
+		SuccessStatus: []int{http.StatusAccepted},
+	}
+	r := &WebhookRunner{}
+	if err := r.Run(context.Background(), Context{Path: `/in/a "b".txt`, Size: 3}, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got.Method != http.MethodPut || got.URL.Path != `/files/a "b".txt` {
+		t.Fatalf("unexpected request %s %s", got.Method, got.URL.Path)
+	}
+	if got.Header.Get("X-File") != `A "B"` || got.Header.Get("Content-Type") != "application/vnd.api+json" {
+		t.Fatalf("unexpected headers %v", got.Header)
+	}
+	if got.Header.Get("Authorization") != "Bearer s3cret" {
+		t.Fatalf("unexpected auth %q", got.Header.Get("Authorization"))
+	}
+	if body != `{"name": "a \"b\".txt", "size": 3}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+
+	// Tokens inside a file name are data, not part of the template.
+	cfg.URL = srv.URL
+	if err := r.Run(context.Background(), Context{Path: `/in/{size}", "admin": true, "x": "{path}.txt`, Size: 3}, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil || len(decoded) != 2 || decoded["name"] != `{size}", "admin": true, "x": "{path}.txt` {
+		t.Fatalf("file name leaked into the body: %s (%v)", body, err)
+	}
+
+	// redact applies to the paths a body_template expands.
+	cfg.Redact = []string{"/home/u"}
+	cfg.BodyTemplate = `{"path": {path|json}, "dir": {dir|json}}`
+	if err := r.Run(context.Background(), Context{Path: "/home/u/in/a.txt"}, cfg); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var masked map[string]string
+	if err := json.Unmarshal([]byte(body), &masked); err != nil || masked["path"] != "<redacted>/in/a.txt" || masked["dir"] != "<redacted>/in" {
+		t.Fatalf("body_template ignored redact: %s (%v)", body, err)
+	}
+
+	// 202 is no longer enough once success_status names only 200.
+	cfg.SuccessStatus = []int{http.StatusOK}
+	var se *StatusError
+	if err := r.Run(context.Background(), Context{Path: "/in/x"}, cfg); !errors.As(err, &se) || se.Code != http.StatusAccepted {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	cfg.Auth = &config.WebhookAuth{BearerEnv: "TEST_WEBHOOK_UNSET"}
+	if err := r.Run(context.Background(), Context{Path: "/in/x"}, cfg); !IsPermanent(err) {
+		t.Fatalf("expected permanent error for a missing token, got %v", err)
+	}
+}
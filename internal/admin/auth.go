@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"watcher-cli/internal/config"
+)
+
+// Auth authenticates admin API requests by bearer token or verified client
+// certificate and checks the scope each endpoint requires.
+type Auth struct {
+	tokens  []config.AdminToken
+	clients map[string]config.AdminScope
+}
+
+// NewAuth builds an authenticator from config.
+func NewAuth(cfg config.Admin) *Auth {
+	return &Auth{tokens: cfg.Tokens, clients: cfg.ClientScopes}
+}
+
+// Enabled reports whether any credential is configured. Without one the
+// API must only be bound to loopback.
+func (a *Auth) Enabled() bool {
+	return len(a.tokens) > 0 || len(a.clients) > 0
+}
+
+// Scope returns the strongest scope the request is entitled to.
+func (a *Auth) Scope(r *http.Request) (config.AdminScope, bool) {
+	best, ok := config.AdminScope(""), false
+	grant := func(s config.AdminScope) {
+		if !ok || s == config.ScopeControl {
+			best, ok = s, true
+		}
+	}
+	if tok, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		for _, t := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(tok), []byte(t.Token)) == 1 {
+				grant(t.Scope)
+			}
+		}
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		if s, found := a.clients[cn]; found {
+			grant(s)
+		}
+	}
+	return best, ok
+}
+
+// Require wraps h so it only runs for callers holding scope. Control
+// implies read.
+func (a *Auth) Require(scope config.AdminScope, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			if !localRequest(r) {
+				http.Error(w, "forbidden: the admin api without credentials only answers local clients", http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+			return
+		}
+		got, ok := a.Scope(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="watcher"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if scope == config.ScopeControl && got != config.ScopeControl {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// localRequest reports whether r can come from a local client rather than
+// a web page: a page on another site can make the browser send requests to
+// a loopback port, but the browser then sets Origin, and a rebound DNS
+// name shows in Host.
+func localRequest(r *http.Request) bool {
+	if r.Header.Get("Origin") != "" {
+		return false
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return isLoopback(strings.Trim(host, "[]"))
+}
+
+// ServerTLS builds the listener TLS config, requesting client certificates
+// when a client CA is configured.
+func ServerTLS(cfg *config.AdminTLS) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("admin tls: %w", err)
+	}
+	out := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("admin tls: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("admin tls: no certificates in client_ca_file")
+		}
+		out.ClientCAs = pool
+		// Tokens still work for clients without certificates.
+		out.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestRequireScopes(t *testing.T) {
+	auth := NewAuth(config.Admin{Tokens: []config.AdminToken{
+		{Token: "reader", Scope: config.ScopeRead},
+		{Token: "operator", Scope: config.ScopeControl},
+	}})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	cases := []struct {
+		scope config.AdminScope
+		token string
+		want  int
+	}{
+		{config.ScopeRead, "", http.StatusUnauthorized},
+		{config.ScopeRead, "wrong", http.StatusUnauthorized},
+		{config.ScopeRead, "reader", http.StatusOK},
+		{config.ScopeControl, "reader", http.StatusForbidden},
+		{config.ScopeControl, "operator", http.StatusOK},
+		{config.ScopeRead, "operator", http.StatusOK},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		rec := httptest.NewRecorder()
+		auth.Require(c.scope, ok).ServeHTTP(rec, req)
+		if rec.Code != c.want {
+			t.Fatalf("scope %s token %q: got %d want %d", c.scope, c.token, rec.Code, c.want)
+		}
+	}
+}
+
+func TestWithoutCredentialsOnlyLocalClients(t *testing.T) {
+	auth := NewAuth(config.Admin{})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	cases := []struct {
+		host, origin string
+		want         int
+	}{
+		{"127.0.0.1:9090", "", http.StatusOK},
+		{"localhost:9090", "", http.StatusOK},
+		{"[::1]:9090", "", http.StatusOK},
+		{"127.0.0.1:9090", "https://evil.example", http.StatusForbidden},
+		{"rebound.example:9090", "", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/pause", nil)
+		req.Host = c.host
+		if c.origin != "" {
+			req.Header.Set("Origin", c.origin)
+		}
+		rec := httptest.NewRecorder()
+		auth.Require(config.ScopeControl, ok).ServeHTTP(rec, req)
+		if rec.Code != c.want {
+			t.Fatalf("host %s origin %q: got %d want %d", c.host, c.origin, rec.Code, c.want)
+		}
+	}
+}
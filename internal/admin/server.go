@@ -0,0 +1,153 @@
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/control"
+	"watcher-cli/internal/status"
+	"watcher-cli/internal/watcher"
+)
+
+// Source is the running watcher the API reports on and controls.
+type Source interface {
+	Status() map[string]status.Counter
+	Health() map[string]status.Health
+	Watches() []watcher.WatchState
+	Pause(watch string) error
+	Resume(watch string) error
+}
+
+// Handler serves the admin API: GET /status and /watches with read scope,
+// POST /pause, /resume and /reload with control scope, and an
+// unauthenticated GET /healthz for probes, which answers 503 with the
+// degraded watches while any watch is degraded. Pause and resume act on
+// the watch named by ?watch=, or on all watches without it.
+func Handler(src Source, auth *Auth, reload func() error) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		var degraded []string
+		for watch, h := range src.Health() {
+			if h.State == status.HealthDegraded {
+				degraded = append(degraded, watch)
+			}
+		}
+		if len(degraded) > 0 {
+			sort.Strings(degraded)
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{"status": string(status.HealthDegraded), "degraded": degraded})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": string(status.HealthOK)})
+	})
+	mux.Handle("/status", auth.Require(config.ScopeRead, method(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, control.Report{Counters: src.Status(), Health: src.Health()})
+	})))
+	mux.Handle("/watches", auth.Require(config.ScopeRead, method(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, src.Watches())
+	})))
+	pause := func(set func(string) error) http.Handler {
+		return auth.Require(config.ScopeControl, method(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			watches := []string{r.URL.Query().Get("watch")}
+			if watches[0] == "" {
+				watches = watches[:0]
+				for _, st := range src.Watches() {
+					watches = append(watches, st.Key)
+				}
+			}
+			for _, key := range watches {
+				if err := set(key); err != nil {
+					writeError(w, http.StatusNotFound, err)
+					return
+				}
+			}
+			writeJSON(w, http.StatusOK, src.Watches())
+		}))
+	}
+	mux.Handle("/pause", pause(src.Pause))
+	mux.Handle("/resume", pause(src.Resume))
+	mux.Handle("/reload", auth.Require(config.ScopeControl, method(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+		if err := reload(); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, src.Watches())
+	})))
+	return mux
+}
+
+// method rejects requests with any other method than m.
+func method(m string, h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != m {
+			w.Header().Set("Allow", m)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s only", m))
+			return
+		}
+		h(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// Listen binds addr, with TLS when configured. Without credentials the API
+// is open to anyone who can connect, so addr must then be a loopback
+// address.
+func Listen(addr string, cfg config.Admin) (net.Listener, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("admin api: %w", err)
+	}
+	if !NewAuth(cfg).Enabled() && !isLoopback(host) {
+		return nil, fmt.Errorf("admin api: %s is not a loopback address; configure global.admin.tokens to serve it", addr)
+	}
+	tlsCfg, err := ServerTLS(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		ln = tls.NewListener(ln, tlsCfg)
+	}
+	return ln, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Serve answers API requests on ln until ctx is done.
+func Serve(ctx context.Context, ln net.Listener, h http.Handler) error {
+	srv := &http.Server{Handler: h, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	err := srv.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/status"
+	"watcher-cli/internal/watcher"
+)
+
+type fakeSource struct {
+	paused map[string]bool
+	health map[string]status.Health
+}
+
+func (f *fakeSource) Status() map[string]status.Counter {
+	return map[string]status.Counter{"inbox": {EventsSeen: 2}}
+}
+
+func (f *fakeSource) Health() map[string]status.Health { return f.health }
+
+func (f *fakeSource) Watches() []watcher.WatchState {
+	return []watcher.WatchState{{Key: "inbox", Paused: f.paused["inbox"]}, {Key: "photos", Paused: f.paused["photos"]}}
+}
+
+func (f *fakeSource) Pause(watch string) error  { return f.set(watch, true) }
+func (f *fakeSource) Resume(watch string) error { return f.set(watch, false) }
+
+func (f *fakeSource) set(watch string, paused bool) error {
+	if watch != "inbox" && watch != "photos" {
+		return fmt.Errorf("unknown watch %q", watch)
+	}
+	f.paused[watch] = paused
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	src := &fakeSource{paused: map[string]bool{}}
+	reloads := 0
+	var reloadErr error
+	auth := NewAuth(config.Admin{Tokens: []config.AdminToken{
+		{Token: "reader", Scope: config.ScopeRead},
+		{Token: "operator", Scope: config.ScopeControl},
+	}})
+	h := Handler(src, auth, func() error { reloads++; return reloadErr })
+	do := func(method, target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	cases := []struct {
+		method, target, token string
+		want                  int
+	}{
+		{http.MethodGet, "/healthz", "", http.StatusOK},
+		{http.MethodGet, "/status", "", http.StatusUnauthorized},
+		{http.MethodGet, "/status", "reader", http.StatusOK},
+		{http.MethodGet, "/watches", "reader", http.StatusOK},
+		{http.MethodPost, "/pause?watch=inbox", "reader", http.StatusForbidden},
+		{http.MethodGet, "/pause?watch=inbox", "operator", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/pause?watch=nope", "operator", http.StatusNotFound},
+		{http.MethodPost, "/pause?watch=inbox", "operator", http.StatusOK},
+	}
+	for _, c := range cases {
+		if rec := do(c.method, c.target, c.token); rec.Code != c.want {
+			t.Fatalf("%s %s as %q: got %d want %d", c.method, c.target, c.token, rec.Code, c.want)
+		}
+	}
+	if !src.paused["inbox"] || src.paused["photos"] {
+		t.Fatalf("only inbox should be paused: %v", src.paused)
+	}
+
+	rec := do(http.MethodPost, "/pause", "operator")
+	var watches []watcher.WatchState
+	if err := json.NewDecoder(rec.Body).Decode(&watches); err != nil || len(watches) != 2 || !watches[1].Paused {
+		t.Fatalf("pause without watch should pause all, got %+v (%v)", watches, err)
+	}
+	do(http.MethodPost, "/resume?watch=photos", "operator")
+	if src.paused["photos"] || !src.paused["inbox"] {
+		t.Fatalf("resume photos: %v", src.paused)
+	}
+
+	if rec := do(http.MethodPost, "/reload", "operator"); rec.Code != http.StatusOK || reloads != 1 {
+		t.Fatalf("reload: got %d after %d reloads", rec.Code, reloads)
+	}
+	reloadErr = errors.New("bad config")
+	rec = do(http.MethodPost, "/reload", "operator")
+	var body map[string]string
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusUnprocessableEntity || body["error"] != "bad config" {
+		t.Fatalf("failed reload: got %d %v", rec.Code, body)
+	}
+}
+
+func TestHealthzReportsDegradedWatches(t *testing.T) {
+	src := &fakeSource{health: map[string]status.Health{
+		"inbox":  {State: status.HealthOK},
+		"photos": {State: status.HealthDegraded, Reasons: []string{"queue depth 90 > 50"}},
+	}}
+	h := Handler(src, NewAuth(config.Admin{}), nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var body struct {
+		Status   string
+		Degraded []string
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusServiceUnavailable || body.Status != "degraded" || len(body.Degraded) != 1 || body.Degraded[0] != "photos" {
+		t.Fatalf("got %d %+v", rec.Code, body)
+	}
+	src.health["photos"] = status.Health{State: status.HealthOK}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("healthy watches: got %d", rec.Code)
+	}
+}
+
+func TestListenRequiresCredentialsOffLoopback(t *testing.T) {
+	if _, err := Listen("0.0.0.0:0", config.Admin{}); err == nil {
+		t.Fatalf("expected an open API on all interfaces to be refused")
+	}
+	ln, err := Listen("127.0.0.1:0", config.Admin{})
+	if err != nil {
+		t.Fatalf("loopback: %v", err)
+	}
+	ln.Close()
+	ln, err = Listen("0.0.0.0:0", config.Admin{Tokens: []config.AdminToken{{Token: "t", Scope: config.ScopeRead}}})
+	if err != nil {
+		t.Fatalf("with a token: %v", err)
+	}
+	ln.Close()
+}
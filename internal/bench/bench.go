@@ -0,0 +1,187 @@
+// Package bench measures how long scanning, diffing and matching take for
+// a watch, so scan intervals can be tuned with data.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/match"
+	"watcher-cli/internal/scanner"
+)
+
+// Options configures a benchmark run.
+type Options struct {
+	// Synthetic, when positive, benchmarks a generated tree of that many
+	// files in a temp dir instead of the watch's own path.
+	Synthetic int
+	// Rounds is how often each phase is repeated; the fastest round is
+	// reported.
+	Rounds int
+}
+
+// Report holds the measurements of one benchmark run.
+type Report struct {
+	Watch        string        `json:"watch"`
+	Root         string        `json:"root"`
+	Synthetic    bool          `json:"synthetic"`
+	Entries      int           `json:"entries"`
+	Scan         time.Duration `json:"scan_ns"`
+	DiffCreated  time.Duration `json:"diff_created_ns"`
+	DiffIdle     time.Duration `json:"diff_unchanged_ns"`
+	SnapshotHeap uint64        `json:"snapshot_heap_bytes"`
+	Events       int           `json:"events"`
+	Matched      int           `json:"matched_actions"`
+	MatchTotal   time.Duration `json:"match_ns"`
+	ScanInterval time.Duration `json:"scan_interval_ns"`
+}
+
+// syntheticExts cycles through common extensions so include patterns
+// match a realistic share of generated files.
+var syntheticExts = []string{".txt", ".jpg", ".csv", ".log", ".pdf"}
+
+// syntheticPerDir is how many generated files share a subdirectory in
+// recursive watches.
+const syntheticPerDir = 100
+
+// Run benchmarks watch. The watch's actions are matched but never run.
+func Run(watch config.Watch, opts Options) (Report, error) {
+	if opts.Rounds <= 0 {
+		opts.Rounds = 3
+	}
+	rep := Report{Watch: watch.Key(), Root: watch.Path, ScanInterval: watch.ScanInterval.Duration()}
+	if opts.Synthetic > 0 {
+		dir, err := os.MkdirTemp("", "watcher-bench-")
+		if err != nil {
+			return rep, err
+		}
+		defer os.RemoveAll(dir)
+		if err := populate(dir, opts.Synthetic, watch.Recursive); err != nil {
+			return rep, err
+		}
+		watch.Path = dir
+		rep.Root = dir
+		rep.Synthetic = true
+	}
+	scn := scanner.New(watch.Path, watch.Recursive)
+	scn.SetNormalization(watch.NormalizeUnicode)
+	scn.SetXattrs(watch.NeedsXattrs())
+
+	var snap scanner.Snapshot
+	var before, after runtime.MemStats
+	for i := 0; i < opts.Rounds; i++ {
+		snap = nil
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		s, err := scn.Scan()
+		if err != nil {
+			return rep, err
+		}
+		rep.Scan = fastest(rep.Scan, time.Since(start))
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		snap = s
+		if after.HeapAlloc > before.HeapAlloc {
+			rep.SnapshotHeap = after.HeapAlloc - before.HeapAlloc
+		}
+	}
+	rep.Entries = len(snap)
+
+	var events []scanner.Event
+	for i := 0; i < opts.Rounds; i++ {
+		start := time.Now()
+		events = scanner.Diff(watch.Path, scanner.Snapshot{}, snap)
+		rep.DiffCreated = fastest(rep.DiffCreated, time.Since(start))
+		start = time.Now()
+		scanner.Diff(watch.Path, snap, snap)
+		rep.DiffIdle = fastest(rep.DiffIdle, time.Since(start))
+	}
+
+	rep.Events = len(events)
+	m := match.New(watch)
+	for i := 0; i < opts.Rounds; i++ {
+		matched := 0
+		start := time.Now()
+		for _, ev := range events {
+			matched += len(m.Match(ev, watch))
+		}
+		rep.MatchTotal = fastest(rep.MatchTotal, time.Since(start))
+		rep.Matched = matched
+	}
+	return rep, nil
+}
+
+// populate writes n small files below dir, spread over subdirectories
+// when recursive.
+func populate(dir string, n int, recursive bool) error {
+	for i := 0; i < n; i++ {
+		parent := dir
+		if recursive {
+			parent = filepath.Join(dir, fmt.Sprintf("d%04d", i/syntheticPerDir))
+			if i%syntheticPerDir == 0 {
+				if err := os.Mkdir(parent, 0o755); err != nil {
+					return err
+				}
+			}
+		}
+		name := fmt.Sprintf("file%06d%s", i, syntheticExts[i%len(syntheticExts)])
+		// Vary sizes so move detection does not pair unrelated files.
+		data := make([]byte, i%512)
+		if err := os.WriteFile(filepath.Join(parent, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fastest(best, d time.Duration) time.Duration {
+	if best == 0 || d < best {
+		return d
+	}
+	return best
+}
+
+// WriteText writes a human-readable report.
+func (rep Report) WriteText(w io.Writer) {
+	root := rep.Root
+	if rep.Synthetic {
+		root = "synthetic tree"
+	}
+	fmt.Fprintf(w, "watch %s (%s, %d entries)\n", rep.Watch, root, rep.Entries)
+	fmt.Fprintf(w, "  scan:             %s (%s per entry)\n", rep.Scan.Round(time.Microsecond), perEntry(rep.Scan, rep.Entries))
+	fmt.Fprintf(w, "  diff, all new:    %s\n", rep.DiffCreated.Round(time.Microsecond))
+	fmt.Fprintf(w, "  diff, unchanged:  %s\n", rep.DiffIdle.Round(time.Microsecond))
+	fmt.Fprintf(w, "  snapshot memory:  %d KiB (%d bytes per entry)\n", rep.SnapshotHeap/1024, perEntryBytes(rep.SnapshotHeap, rep.Entries))
+	fmt.Fprintf(w, "  match:            %s for %d events, %d actions selected", rep.MatchTotal.Round(time.Microsecond), rep.Events, rep.Matched)
+	if rep.MatchTotal > 0 {
+		fmt.Fprintf(w, " (%.0f events/s)", float64(rep.Events)/rep.MatchTotal.Seconds())
+	}
+	fmt.Fprintln(w)
+	if rep.ScanInterval > 0 {
+		cycle := rep.Scan + rep.DiffIdle
+		fmt.Fprintf(w, "  scan_interval_ms: %s; an idle cycle uses %.1f%% of it\n", rep.ScanInterval, 100*cycle.Seconds()/rep.ScanInterval.Seconds())
+		if cycle > rep.ScanInterval/2 {
+			fmt.Fprintln(w, "  hint: scans take more than half the interval; raise scan_interval_ms or narrow the watch")
+		}
+	}
+}
+
+func perEntry(d time.Duration, n int) time.Duration {
+	if n == 0 {
+		return 0
+	}
+	return d / time.Duration(n)
+}
+
+func perEntryBytes(b uint64, n int) uint64 {
+	if n == 0 {
+		return 0
+	}
+	return b / uint64(n)
+}
@@ -0,0 +1,41 @@
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestSyntheticRun(t *testing.T) {
+	watch := config.Watch{
+		Path:      t.TempDir(),
+		Recursive: true,
+		Actions: []config.Action{{
+			Name:    "jpgs",
+			Type:    config.ActionExec,
+			Include: []string{"**/*.jpg"},
+			Events:  []config.EventType{config.EventCreate},
+		}},
+	}
+	rep, err := Run(watch, Options{Synthetic: 250, Rounds: 1})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	// 250 files in three subdirectories.
+	if rep.Entries != 253 || rep.Events != 253 {
+		t.Fatalf("entries=%d events=%d, want 253", rep.Entries, rep.Events)
+	}
+	if rep.Matched != 50 {
+		t.Fatalf("matched = %d, want 50 jpgs", rep.Matched)
+	}
+	if !rep.Synthetic || rep.Root == watch.Path {
+		t.Fatalf("synthetic tree not used: %+v", rep)
+	}
+	var buf bytes.Buffer
+	rep.WriteText(&buf)
+	if !strings.Contains(buf.String(), "253 entries") {
+		t.Fatalf("report:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,330 @@
+// Package bucket lists objects in S3 and GCS buckets so a bucket prefix
+// can be watched like a directory. Both are reached through the S3
+// ListObjectsV2 API (GCS via its XML interoperability endpoint and HMAC
+// keys), signed with AWS Signature Version 4.
+package bucket
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"watcher-cli/internal/scanner"
+)
+
+// Location is a bucket prefix, parsed from s3://bucket/prefix or
+// gs://bucket/prefix.
+type Location struct {
+	Scheme string
+	Bucket string
+	// Prefix is empty or ends in a slash.
+	Prefix string
+}
+
+// IsURL reports whether path names a bucket rather than a local path.
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "gs://")
+}
+
+// Parse parses a bucket URL.
+func Parse(raw string) (Location, error) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok || (scheme != "s3" && scheme != "gs") {
+		return Location{}, fmt.Errorf("bucket url %q: want s3://bucket/prefix or gs://bucket/prefix", raw)
+	}
+	name, prefix, _ := strings.Cut(rest, "/")
+	if name == "" {
+		return Location{}, fmt.Errorf("bucket url %q: missing bucket", raw)
+	}
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return Location{Scheme: scheme, Bucket: name, Prefix: prefix}, nil
+}
+
+// Credentials sign requests; without an access key requests are sent
+// unsigned, which suits public buckets.
+type Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// Object is one listed object.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ETag         string
+}
+
+// Client lists objects over the S3 API.
+type Client struct {
+	// Endpoint is the service URL, e.g. https://s3.eu-west-1.amazonaws.com;
+	// buckets are addressed path-style below it.
+	Endpoint string
+	Region   string
+	Creds    Credentials
+	HTTP     *http.Client
+	// now is the signing clock; tests pin it.
+	now func() time.Time
+}
+
+// Options configures NewClient; empty fields take defaults for the
+// location's service.
+type Options struct {
+	Endpoint     string
+	Region       string
+	AccessKeyEnv string
+	SecretKeyEnv string
+}
+
+// NewClient returns a client for loc, reading credentials from the
+// environment: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN unless other variables are named.
+func NewClient(loc Location, opts Options) *Client {
+	region := opts.Region
+	if region == "" {
+		if loc.Scheme == "gs" {
+			region = "auto"
+		} else if region = os.Getenv("AWS_REGION"); region == "" {
+			region = "us-east-1"
+		}
+	}
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		if loc.Scheme == "gs" {
+			endpoint = "https://storage.googleapis.com"
+		} else {
+			endpoint = "https://s3." + region + ".amazonaws.com"
+		}
+	}
+	creds := Credentials{AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"), SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY")}
+	if opts.AccessKeyEnv != "" || opts.SecretKeyEnv != "" {
+		creds = Credentials{AccessKey: os.Getenv(opts.AccessKeyEnv), SecretKey: os.Getenv(opts.SecretKeyEnv)}
+	} else {
+		creds.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	return &Client{
+		Endpoint: strings.TrimRight(endpoint, "/"),
+		Region:   region,
+		Creds:    creds,
+		HTTP:     &http.Client{Timeout: time.Minute},
+	}
+}
+
+// listResult is a ListObjectsV2 response page.
+type listResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		ETag         string    `xml:"ETag"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+// List returns every object under prefix, following pagination.
+func (c *Client) List(ctx context.Context, bucket, prefix string) ([]Object, error) {
+	var out []Object
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			q.Set("prefix", prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		page, err := c.listPage(ctx, bucket, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range page.Contents {
+			out = append(out, Object{Key: o.Key, Size: o.Size, LastModified: o.LastModified, ETag: strings.Trim(o.ETag, `"`)})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return out, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (c *Client) listPage(ctx context.Context, bucket string, q url.Values) (*listResult, error) {
+	u, err := url.Parse(c.Endpoint + "/" + bucket)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = canonicalQuery(q)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.sign(req)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("list %s: %s: %s", bucket, e.Code, e.Message)
+		}
+		return nil, fmt.Errorf("list %s: %s", bucket, resp.Status)
+	}
+	var page listResult
+	if err := xml.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("list %s: %w", bucket, err)
+	}
+	return &page, nil
+}
+
+// emptySHA256 is the hex SHA-256 of an empty payload.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds AWS Signature Version 4 headers to a request without a body.
+func (c *Client) sign(req *http.Request) {
+	if c.Creds.AccessKey == "" {
+		return
+	}
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	day := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+	if c.Creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.Creds.SessionToken)
+	}
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, name := range names {
+		canonHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{req.Method, path, req.URL.RawQuery, canonHeaders.String(), signed, emptySHA256}, "\n")
+	scope := day + "/" + c.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonical)
+	key := hmacSHA256([]byte("AWS4"+c.Creds.SecretKey), day)
+	for _, part := range []string{c.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+c.Creds.AccessKey+"/"+scope+", SignedHeaders="+signed+", Signature="+sig)
+}
+
+// canonicalQuery encodes q sorted by key with RFC 3986 escaping, as
+// SigV4 requires.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// listTimeout bounds one full listing.
+const listTimeout = 5 * time.Minute
+
+// Lister lists a bucket prefix for a scanner.
+type Lister struct {
+	Client *Client
+	Loc    Location
+}
+
+// NewLister returns a lister for the bucket URL raw.
+func NewLister(raw string, opts Options) (*Lister, error) {
+	loc, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Lister{Client: NewClient(loc, opts), Loc: loc}, nil
+}
+
+// List returns the objects under the prefix keyed by their path below it.
+// Folder placeholder objects (keys ending in a slash) are skipped.
+func (l *Lister) List() (map[string]scanner.FileInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+	objects, err := l.Client.List(ctx, l.Loc.Bucket, l.Loc.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]scanner.FileInfo, len(objects))
+	for _, o := range objects {
+		rel := strings.TrimPrefix(o.Key, l.Loc.Prefix)
+		if rel == "" || strings.HasSuffix(rel, "/") {
+			continue
+		}
+		out[rel] = scanner.FileInfo{Size: o.Size, ModTime: o.LastModified, Mode: 0o644}
+	}
+	return out, nil
+}
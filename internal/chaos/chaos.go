@@ -0,0 +1,160 @@
+// Package chaos injects faults into actions, scans and copies so tests and
+// users can check that retries, journals and resumable copies recover.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvVar must be set to "1" for the CLI to accept a fault spec, so the
+// mode cannot be enabled by a stray flag in production.
+const EnvVar = "WATCHER_FAULT_INJECTION"
+
+// ErrInjected marks failures caused by the injector.
+var ErrInjected = errors.New("injected fault")
+
+// Faults configures the injector.
+type Faults struct {
+	// FailRate is the share (0..1) of action attempts that fail.
+	FailRate float64
+	// ScanDelay is the longest random delay added before a scan.
+	ScanDelay time.Duration
+	// TruncateRate is the share (0..1) of copies cut short with an error.
+	TruncateRate float64
+	// Seed makes the faults reproducible; 0 picks one from the clock.
+	Seed int64
+}
+
+// Injector decides which faults happen. A nil Injector injects nothing.
+type Injector struct {
+	faults Faults
+	mu     sync.Mutex
+	rng    *rand.Rand
+}
+
+// New returns an injector for f.
+func New(f Faults) *Injector {
+	seed := f.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{faults: f, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Parse reads a spec such as "fail=0.2,delay=500ms,truncate=0.1,seed=42".
+func Parse(spec string) (Faults, error) {
+	var f Faults
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Faults{}, fmt.Errorf("fault %q: want key=value", part)
+		}
+		var err error
+		switch key {
+		case "fail":
+			f.FailRate, err = parseRate(value)
+		case "truncate":
+			f.TruncateRate, err = parseRate(value)
+		case "delay":
+			f.ScanDelay, err = time.ParseDuration(value)
+		case "seed":
+			f.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			return Faults{}, fmt.Errorf("unknown fault %q", key)
+		}
+		if err != nil {
+			return Faults{}, fmt.Errorf("fault %s: %w", key, err)
+		}
+	}
+	return f, nil
+}
+
+func parseRate(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 || v > 1 {
+		return 0, errors.New("must be within 0..1")
+	}
+	return v, nil
+}
+
+func (i *Injector) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Float64() < rate
+}
+
+// FailAction returns an error for an action attempt picked to fail.
+func (i *Injector) FailAction(action string) error {
+	if i == nil || !i.chance(i.faults.FailRate) {
+		return nil
+	}
+	return fmt.Errorf("action %s: %w", action, ErrInjected)
+}
+
+// ScanDelay returns how long to stall before the next scan.
+func (i *Injector) ScanDelay() time.Duration {
+	if i == nil || i.faults.ScanDelay <= 0 {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return time.Duration(i.rng.Int63n(int64(i.faults.ScanDelay) + 1))
+}
+
+// Truncate wraps r of the given size so that, when picked, it fails part
+// way through as an interrupted copy would.
+func (i *Injector) Truncate(r io.Reader, size int64) io.Reader {
+	if i == nil || size <= 0 || !i.chance(i.faults.TruncateRate) {
+		return r
+	}
+	i.mu.Lock()
+	cut := i.rng.Int63n(size)
+	i.mu.Unlock()
+	return &truncated{r: io.LimitReader(r, cut)}
+}
+
+type truncated struct {
+	r io.Reader
+}
+
+func (t *truncated) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if err == io.EOF {
+		return n, fmt.Errorf("copy truncated: %w", ErrInjected)
+	}
+	return n, err
+}
+
+type ctxKey struct{}
+
+// With returns ctx carrying i for code below the executor, such as copies.
+func With(ctx context.Context, i *Injector) context.Context {
+	if i == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, i)
+}
+
+// From returns the injector carried by ctx, or nil.
+func From(ctx context.Context) *Injector {
+	i, _ := ctx.Value(ctxKey{}).(*Injector)
+	return i
+}
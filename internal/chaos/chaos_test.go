@@ -0,0 +1,46 @@
+package chaos
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	f, err := Parse("fail=0.2, delay=500ms,truncate=1,seed=42")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if f.FailRate != 0.2 || f.ScanDelay != 500*time.Millisecond || f.TruncateRate != 1 || f.Seed != 42 {
+		t.Fatalf("unexpected faults %+v", f)
+	}
+	for _, bad := range []string{"fail=2", "explode=1", "fail"} {
+		if _, err := Parse(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestTruncateFailsPartWay(t *testing.T) {
+	i := New(Faults{TruncateRate: 1, Seed: 1})
+	data := strings.Repeat("x", 1000)
+	var out bytes.Buffer
+	_, err := io.Copy(&out, i.Truncate(strings.NewReader(data), int64(len(data))))
+	if !errors.Is(err, ErrInjected) || out.Len() >= len(data) {
+		t.Fatalf("expected a truncated copy, got %d bytes, %v", out.Len(), err)
+	}
+}
+
+func TestNilInjector(t *testing.T) {
+	var i *Injector
+	if i.FailAction("a") != nil || i.ScanDelay() != 0 {
+		t.Fatalf("nil injector should inject nothing")
+	}
+	r := strings.NewReader("x")
+	if i.Truncate(r, 1) != r {
+		t.Fatalf("nil injector should not wrap readers")
+	}
+}
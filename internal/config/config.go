@@ -1,16 +1,24 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/bmatcuk/doublestar/v4"
 	"gopkg.in/yaml.v3"
+
+	"watcher-cli/internal/bucket"
+	"watcher-cli/internal/template"
+	"watcher-cli/internal/unorm"
 )
 
 // EventType enumerates filesystem events we handle.
@@ -21,6 +29,11 @@ const (
 	EventModify EventType = "modify"
 	EventDelete EventType = "delete"
 	EventMove   EventType = "move"
+	// EventLowSpace is synthesized when a watch's filesystem crosses its
+	// low_space threshold; it never comes from a scan.
+	EventLowSpace EventType = "low_space"
+	// EventDuplicate is synthesized per duplicate group found by a dedup scan.
+	EventDuplicate EventType = "duplicate"
 )
 
 // ActionType enumerates supported action kinds.
@@ -32,64 +45,993 @@ const (
 	ActionMove    ActionType = "move"
 	ActionRename  ActionType = "rename"
 	ActionWebhook ActionType = "webhook"
+	// ActionRenamePattern renames via a regex applied to the file name.
+	ActionRenamePattern ActionType = "rename_pattern"
+	// ActionClamScan submits files to clamd and fails on detection.
+	ActionClamScan ActionType = "clamscan"
+	// ActionTransfer copies, verifies by checksum, then removes the source.
+	ActionTransfer ActionType = "transfer"
+	// ActionTag writes user extended attributes onto the file.
+	ActionTag ActionType = "tag"
+	// ActionArchive moves the file into a .tar.gz or .zip archive.
+	ActionArchive ActionType = "archive"
+	// ActionSSHExec runs cmd on a remote host through the ssh client.
+	ActionSSHExec ActionType = "ssh_exec"
+)
+
+// LogFormat selects how log lines are written.
+type LogFormat string
+
+const (
+	LogText LogFormat = "text"
+	// LogJSON writes one JSON object per line, for Loki, ELK and the like.
+	LogJSON LogFormat = "json"
+)
+
+// PayloadFormat selects the body a webhook sends.
+type PayloadFormat string
+
+const (
+	PayloadJSON PayloadFormat = "json"
+	// PayloadCloudEvents wraps the event in a CloudEvents 1.0 envelope
+	// (structured JSON mode).
+	PayloadCloudEvents PayloadFormat = "cloudevents"
+)
+
+// EnvMode controls which daemon environment variables exec children see.
+type EnvMode string
+
+const (
+	EnvInherit   EnvMode = "inherit"
+	EnvClean     EnvMode = "clean"
+	EnvAllowlist EnvMode = "allowlist"
+)
+
+// StdinMode selects what an exec child reads on stdin.
+type StdinMode string
+
+const (
+	StdinNone StdinMode = "none"
+	StdinFile StdinMode = "file"
+	StdinJSON StdinMode = "json"
+)
+
+// BatchInput selects how a batched exec receives its paths.
+type BatchInput string
+
+const (
+	BatchArgs  BatchInput = "args"
+	BatchStdin BatchInput = "stdin"
+)
+
+// Batch collects an action's events and runs it once for many files.
+type Batch struct {
+	// MaxItems runs the batch once it holds this many events (default 100).
+	MaxItems int `yaml:"max_items,omitempty"`
+	// MaxWait runs a batch this long after its first event (default 1s).
+	MaxWait MillisDuration `yaml:"max_wait_ms,omitempty"`
+	// Input passes exec the paths as arguments (args, the default) in
+	// place of a "{paths}" argument or after the command, or one per line
+	// on stdin (stdin).
+	Input BatchInput `yaml:"input,omitempty"`
+}
+
+// CaptureSource names an action output that can be captured.
+type CaptureSource string
+
+const (
+	CaptureStdout CaptureSource = "stdout"
+	CaptureStderr CaptureSource = "stderr"
+	// CaptureDest is the final destination path of copy/move style actions.
+	CaptureDest CaptureSource = "dest"
+)
+
+// ClientTLS configures TLS for outbound HTTP actions.
+type ClientTLS struct {
+	CAFile             string `yaml:"ca_file,omitempty"`
+	CertFile           string `yaml:"cert_file,omitempty"`
+	KeyFile            string `yaml:"key_file,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+}
+
+// SSHTarget is the remote host of an ssh_exec action. Unset fields fall
+// back to the ssh client's own configuration (~/.ssh/config).
+type SSHTarget struct {
+	Host string `yaml:"host,omitempty"`
+	User string `yaml:"user,omitempty"`
+	Port int    `yaml:"port,omitempty"`
+	// Key is a private key file; KnownHosts replaces the known_hosts file.
+	Key        string `yaml:"key,omitempty"`
+	KnownHosts string `yaml:"known_hosts,omitempty"`
+	// Persist is how long an idle shared connection stays open (default 60s).
+	Persist MillisDuration `yaml:"persist_ms,omitempty"`
+	// Timeout bounds connecting, a silent connection and each SFTP
+	// request (default 30s).
+	Timeout MillisDuration `yaml:"timeout_ms,omitempty"`
+}
+
+// LogOutput is an exec action's log_output setting.
+type LogOutput string
+
+const (
+	// LogOutputOn logs the command's output with the event.
+	LogOutputOn LogOutput = "true"
+	// LogOutputOff drops the output.
+	LogOutputOff LogOutput = "false"
+	// LogOutputOnError logs the output only when the command fails.
+	LogOutputOnError LogOutput = "on_error"
+)
+
+// ProxyNone disables proxies for an action, including HTTP(S)_PROXY.
+const ProxyNone = "none"
+
+// Outcome is how a webhook response value maps to an action result.
+type Outcome string
+
+const (
+	OutcomeOK    Outcome = "ok"
+	OutcomeSkip  Outcome = "skip"
+	OutcomeRetry Outcome = "retry"
+	OutcomeFail  Outcome = "fail"
+)
+
+// HandlingOutcome sums up the results of all actions one event triggered.
+type HandlingOutcome string
+
+const (
+	// HandlingHandled means every action succeeded.
+	HandlingHandled HandlingOutcome = "handled"
+	// HandlingPartial means some actions succeeded and some failed.
+	HandlingPartial HandlingOutcome = "partially_failed"
+	// HandlingFailed means every action failed.
+	HandlingFailed HandlingOutcome = "failed"
+)
+
+// HandledWebhook posts a summary of each event's actions once the last of
+// them has finished.
+type HandledWebhook struct {
+	URL string `yaml:"url,omitempty" secret:"url"`
+	// Outcomes limits the posts to events with these outcomes; empty
+	// posts every event.
+	Outcomes []HandlingOutcome `yaml:"outcomes,omitempty"`
+	Timeout  MillisDuration    `yaml:"timeout_ms,omitempty"`
+}
+
+// WebhookAuth takes webhook credentials from the environment when each
+// request is sent, so they stay out of the config: a bearer token, or a
+// username and password for basic auth.
+type WebhookAuth struct {
+	BearerEnv   string `yaml:"bearer_env,omitempty"`
+	UsernameEnv string `yaml:"username_env,omitempty"`
+	PasswordEnv string `yaml:"password_env,omitempty"`
+}
+
+// WebhookResponse describes how to interpret a webhook's JSON response.
+// Field paths are dot-separated, e.g. "result.id" or "items.0.status".
+type WebhookResponse struct {
+	Capture      map[string]string  `yaml:"capture,omitempty"`
+	OutcomeField string             `yaml:"outcome_field,omitempty"`
+	Outcomes     map[string]Outcome `yaml:"outcomes,omitempty"`
+}
+
+// ErrorClass groups action errors by cause, for retry_classes and for
+// status, metrics and exports.
+type ErrorClass string
+
+const (
+	ClassNotFound   ErrorClass = "not_found"
+	ClassPermission ErrorClass = "permission"
+	ClassTimeout    ErrorClass = "timeout"
+	ClassNetwork    ErrorClass = "network"
+	ClassConflict   ErrorClass = "conflict"
+	ClassQuota      ErrorClass = "quota"
+	// ClassInvalidPath is a destination the target OS cannot hold: too
+	// long, or with characters or names it forbids.
+	ClassInvalidPath ErrorClass = "invalid_path"
+	// ClassOther is every error that fits none of the above.
+	ClassOther ErrorClass = "other"
+)
+
+// ErrorClasses lists the error classes.
+var ErrorClasses = []ErrorClass{ClassNotFound, ClassPermission, ClassTimeout, ClassNetwork, ClassConflict, ClassQuota, ClassInvalidPath, ClassOther}
+
+// ConflictPolicy decides what happens when a destination already exists.
+type ConflictPolicy string
+
+const (
+	ConflictFail      ConflictPolicy = "fail"
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictSuffix appends " (1)", " (2)", ... before the extension.
+	ConflictSuffix ConflictPolicy = "suffix"
 )
 
 // Defaults holds global defaults.
 type Defaults struct {
-	Overwrite bool `yaml:"overwrite"`
+	Overwrite bool `yaml:"overwrite,omitempty"`
+	// Retries, Timeout, Events and IgnoreHidden apply to actions that do
+	// not set their own.
+	Retries      int            `yaml:"retries,omitempty"`
+	Timeout      MillisDuration `yaml:"timeout_ms,omitempty"`
+	Events       []EventType    `yaml:"events,omitempty"`
+	IgnoreHidden *bool          `yaml:"ignore_hidden,omitempty"`
+	// RetryBackoff, RetryBackoffFactor and RetryJitter apply to actions
+	// that do not set their own.
+	RetryBackoff       *MillisDuration `yaml:"retry_backoff_ms,omitempty"`
+	RetryBackoffFactor float64         `yaml:"retry_backoff_factor,omitempty"`
+	RetryJitter        *float64        `yaml:"retry_jitter,omitempty"`
+	// RetryClasses applies to classes an action's retry_classes leaves out.
+	RetryClasses map[ErrorClass]int `yaml:"retry_classes,omitempty"`
+	// DeadLetter applies to actions without a dead_letter file.
+	DeadLetter string `yaml:"dead_letter,omitempty"`
+	// RateLimit and Burst apply to actions without a rate_limit.
+	RateLimit Rate `yaml:"rate_limit,omitempty"`
+	Burst     int  `yaml:"burst,omitempty"`
+}
+
+// Safety is a policy for actions that remove files: move, rename,
+// rename_pattern, transfer, archive without keep_source, and retention.
+type Safety struct {
+	// AllowDestructive must be set for any of them to be configured.
+	AllowDestructive bool `yaml:"allow_destructive,omitempty"`
+	// DenyDest lists paths no action may write to or below; a root such
+	// as / only denies itself. Empty means DefaultDenyDest.
+	DenyDest []string `yaml:"deny_dest,omitempty"`
+	// RequireOlderThan makes destructive actions set condition.min_age_ms
+	// and retention rules set max_age_ms.
+	RequireOlderThan bool `yaml:"require_older_than,omitempty"`
+	// MaxDestructivePerMinute caps destructive operations across all
+	// watches; further ones fail until the minute is over. 0 means no cap.
+	MaxDestructivePerMinute int `yaml:"max_destructive_per_minute,omitempty"`
+}
+
+// DefaultDenyDest are system directories no action should write into.
+var DefaultDenyDest = []string{
+	"/", "/bin", "/boot", "/dev", "/etc", "/lib", "/proc", "/sbin", "/sys", "/usr",
+	`C:\`, `C:\Windows`, `C:\Program Files`,
+}
+
+// Destructive reports whether a's runs remove the file they handle.
+func (a Action) Destructive() bool {
+	switch a.Type {
+	case ActionMove, ActionRename, ActionRenamePattern, ActionTransfer:
+		return true
+	case ActionArchive:
+		return !a.KeepSource
+	}
+	return false
+}
+
+// DeniedDest returns the deny-list entry covering path, if any.
+func (s *Safety) DeniedDest(path string) (string, bool) {
+	deny := s.DenyDest
+	if len(deny) == 0 {
+		deny = DefaultDenyDest
+	}
+	fold := func(p string) string { return p }
+	if filepath.Separator == '\\' {
+		fold = strings.ToLower
+	}
+	p := fold(filepath.Clean(path))
+	for _, entry := range deny {
+		e := fold(filepath.Clean(entry))
+		if p == e || filepath.Dir(e) != e && strings.HasPrefix(p, e+string(filepath.Separator)) {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// validate checks a watch against the safety policy.
+func (s *Safety) validate(w *Watch) error {
+	for _, a := range w.Actions {
+		if a.Destructive() {
+			if !s.AllowDestructive {
+				return fmt.Errorf("action %s: %s removes files; set global.safety.allow_destructive to allow it", a.Name, a.Type)
+			}
+			if s.RequireOlderThan && a.Condition.MinAge.Duration() <= 0 {
+				return fmt.Errorf("action %s: global.safety.require_older_than needs condition.min_age_ms", a.Name)
+			}
+		}
+		if dest := staticPrefix(a.Dest); dest != "" && filepath.IsAbs(dest) {
+			if entry, denied := s.DeniedDest(dest); denied {
+				return fmt.Errorf("action %s: dest %s is denied by global.safety (%s)", a.Name, a.Dest, entry)
+			}
+		}
+	}
+	for i, r := range w.Retention {
+		if !s.AllowDestructive {
+			return fmt.Errorf("retention %d: retention removes files; set global.safety.allow_destructive to allow it", i)
+		}
+		if s.RequireOlderThan && r.MaxAge.Duration() <= 0 {
+			return fmt.Errorf("retention %d: global.safety.require_older_than needs max_age_ms", i)
+		}
+		if dest := staticPrefix(r.Dest); dest != "" && filepath.IsAbs(dest) {
+			if entry, denied := s.DeniedDest(dest); denied {
+				return fmt.Errorf("retention %d: dest %s is denied by global.safety (%s)", i, r.Dest, entry)
+			}
+		}
+	}
+	return nil
+}
+
+// staticPrefix returns the part of a dest template before its first
+// token, or the whole dest without tokens.
+func staticPrefix(dest string) string {
+	if i := strings.IndexByte(dest, '{'); i >= 0 {
+		return filepath.Dir(dest[:i] + "x")
+	}
+	return dest
+}
+
+// AdminScope is a permission level on the admin API.
+type AdminScope string
+
+const (
+	// ScopeRead allows status and listing endpoints.
+	ScopeRead AdminScope = "read"
+	// ScopeControl additionally allows pause/resume/reload/trigger.
+	ScopeControl AdminScope = "control"
+)
+
+// AdminToken is a bearer token and the scope it grants.
+type AdminToken struct {
+	Token string     `yaml:"token,omitempty" secret:"true"`
+	Scope AdminScope `yaml:"scope,omitempty"`
+}
+
+// AdminTLS configures TLS for the admin API; ClientCAFile enables mTLS.
+type AdminTLS struct {
+	CertFile     string `yaml:"cert_file,omitempty"`
+	KeyFile      string `yaml:"key_file,omitempty"`
+	ClientCAFile string `yaml:"client_ca_file,omitempty"`
+}
+
+// Admin secures the control plane. ClientScopes maps verified client
+// certificate common names to scopes.
+type Admin struct {
+	Tokens       []AdminToken          `yaml:"tokens,omitempty"`
+	TLS          *AdminTLS             `yaml:"tls,omitempty"`
+	ClientScopes map[string]AdminScope `yaml:"client_scopes,omitempty"`
+}
+
+// NotifyType selects how a notification target is delivered.
+type NotifyType string
+
+const (
+	NotifyWebhook NotifyType = "webhook"
+	NotifySlack   NotifyType = "slack"
+	NotifyEmail   NotifyType = "email"
+)
+
+// NotifyTarget is a destination for escalation notifications. Webhook and
+// slack targets use URL; email targets use SMTP, From and To.
+type NotifyTarget struct {
+	Name     string     `yaml:"name,omitempty"`
+	Type     NotifyType `yaml:"type,omitempty"`
+	URL      string     `yaml:"url,omitempty" secret:"url"`
+	SMTP     string     `yaml:"smtp,omitempty"`
+	From     string     `yaml:"from,omitempty"`
+	To       []string   `yaml:"to,omitempty"`
+	Username string     `yaml:"username,omitempty"`
+	Password string     `yaml:"password,omitempty" secret:"true"`
+}
+
+// Threshold fires once Count failures happen within Window.
+type Threshold struct {
+	Count  int            `yaml:"count,omitempty"`
+	Window MillisDuration `yaml:"window_ms,omitempty"`
+}
+
+// TransientMode decides what happens to files that vanish shortly after
+// appearing.
+type TransientMode string
+
+const (
+	// TransientDrop skips events whose file is already gone when they are
+	// dispatched, and the delete that follows.
+	TransientDrop TransientMode = "drop"
+	// TransientDelay holds creates for HoldScans scans and drops them if
+	// the file is deleted meanwhile.
+	TransientDelay TransientMode = "delay"
+)
+
+// Strategy selects how a watch notices changes.
+type Strategy string
+
+const (
+	// StrategyAuto uses hybrid where native notifications are available and
+	// polling elsewhere.
+	StrategyAuto Strategy = "auto"
+	// StrategyPoll rescans every scan interval.
+	StrategyPoll Strategy = "poll"
+	// StrategyNative rescans only when the OS reports a change.
+	StrategyNative Strategy = "native"
+	// StrategyHybrid rescans on OS notifications and every scan interval,
+	// catching changes that notifications miss such as on network mounts.
+	StrategyHybrid Strategy = "hybrid"
+)
+
+// CaseSensitivity says whether a watch's names differ by case alone.
+type CaseSensitivity string
+
+const (
+	// CaseAuto probes the watched filesystem at start; remote sources are
+	// taken as case-sensitive.
+	CaseAuto        CaseSensitivity = "auto"
+	CaseSensitive   CaseSensitivity = "sensitive"
+	CaseInsensitive CaseSensitivity = "insensitive"
+)
+
+// SpecialFilePolicy decides how FIFOs, sockets and device nodes are
+// treated. They never reach actions.
+type SpecialFilePolicy string
+
+const (
+	// SpecialSkip ignores special files silently.
+	SpecialSkip SpecialFilePolicy = "skip"
+	// SpecialReport logs a warning for each special file seen.
+	SpecialReport SpecialFilePolicy = "report"
+	// SpecialError reports special files as scan errors.
+	SpecialError SpecialFilePolicy = "error"
+)
+
+// EventOrder decides the order in which the events of one scan are
+// handled; ties are broken by path.
+type EventOrder string
+
+const (
+	// OrderType handles modifies, then moves, creates and deletes.
+	OrderType EventOrder = "type"
+	// OrderPath handles events by path.
+	OrderPath EventOrder = "path"
+	// OrderOldest handles the least recently modified files first.
+	OrderOldest EventOrder = "oldest"
+	// OrderNewest handles the most recently modified files first.
+	OrderNewest EventOrder = "newest"
+	// OrderSmallest handles the smallest files first.
+	OrderSmallest EventOrder = "smallest"
+	// OrderLargest handles the largest files first.
+	OrderLargest EventOrder = "largest"
+)
+
+// Transient suppresses short-lived files such as editor temp files.
+type Transient struct {
+	Mode      TransientMode `yaml:"mode,omitempty"`
+	HoldScans int           `yaml:"hold_scans,omitempty"`
+}
+
+// Health sets thresholds that mark a watch as degraded.
+type Health struct {
+	// MaxErrorRate is the tolerated share (0..1) of failed actions among
+	// the latest 50.
+	MaxErrorRate  float64        `yaml:"max_error_rate,omitempty"`
+	MaxQueueDepth int            `yaml:"max_queue_depth,omitempty"`
+	MaxIdle       MillisDuration `yaml:"max_idle_ms,omitempty"`
+}
+
+// Notifications escalates repeated failures to humans.
+type Notifications struct {
+	Targets        []NotifyTarget `yaml:"targets,omitempty"`
+	ActionFailures *Threshold     `yaml:"action_failures,omitempty"`
+	ScanErrors     *Threshold     `yaml:"scan_errors,omitempty"`
+	// SafetyHolds notifies when a watch holds back events it distrusts,
+	// such as a burst above max_events_per_scan.
+	SafetyHolds *Threshold `yaml:"safety_holds,omitempty"`
+}
+
+// Metrics configures metrics export. PushURL is a Prometheus Pushgateway
+// that receives the final counters when run exits.
+type Metrics struct {
+	PushURL string `yaml:"push_url,omitempty" secret:"url"`
+	PushJob string `yaml:"push_job,omitempty"`
+	// Labels are attached to the per-event series: watch, action, event
+	// and ext. Defaults to watch, action and event; ext is opt-in since
+	// every new extension adds series.
+	Labels []string `yaml:"labels,omitempty"`
+	// MaxLabelValues caps the distinct values kept per label; later values
+	// are counted as "other". Defaults to 100.
+	MaxLabelValues int `yaml:"max_label_values,omitempty"`
+}
+
+// metricLabels are the labels Metrics.Labels accepts.
+var metricLabels = []string{"watch", "action", "event", "ext"}
+
+// ExportFormat selects the file format of the export sink.
+type ExportFormat string
+
+const (
+	ExportCSV ExportFormat = "csv"
+	// ExportParquet is recognised so configs get a clear error; no
+	// Parquet encoder is vendored yet.
+	ExportParquet ExportFormat = "parquet"
+)
+
+// Export appends every event and action result to rotating files in Dir
+// for offline analysis.
+type Export struct {
+	Dir    string       `yaml:"dir,omitempty"`
+	Format ExportFormat `yaml:"format,omitempty"`
+	// MaxSizeBytes rotates the current file once it grows past this size.
+	MaxSizeBytes int64 `yaml:"max_size_bytes,omitempty"`
+	// MaxFiles is how many rotated files are kept.
+	MaxFiles int `yaml:"max_files,omitempty"`
 }
 
 // Global applies to all watches unless overridden.
 type Global struct {
-	ScanInterval MillisDuration `yaml:"scan_interval_ms"`
-	Debounce     MillisDuration `yaml:"debounce_ms"`
-	DryRun       bool           `yaml:"dry_run"`
-	Defaults     Defaults       `yaml:"defaults"`
+	ScanInterval MillisDuration `yaml:"scan_interval_ms,omitempty"`
+	Debounce     MillisDuration `yaml:"debounce_ms,omitempty"`
+	DryRun       bool           `yaml:"dry_run,omitempty"`
+	Defaults     Defaults       `yaml:"defaults,omitempty"`
+	Admin        Admin          `yaml:"admin,omitempty"`
+	// RetryBudget caps retries per minute across all actions; 0 means
+	// unlimited.
+	RetryBudget int     `yaml:"retry_budget_per_minute,omitempty"`
+	Metrics     Metrics `yaml:"metrics,omitempty"`
+	// MaxConcurrentActions caps action runs in progress across all
+	// watches. Above 1 a watch keeps handling events while earlier ones
+	// are still running their actions; events for the same file still run
+	// in order. 0 or 1 runs every action in turn.
+	MaxConcurrentActions int `yaml:"max_concurrent_actions,omitempty"`
+	// Safety, when set, guards against destructive configuration mistakes.
+	Safety *Safety `yaml:"safety,omitempty"`
+	// StateDir holds state kept across restarts.
+	StateDir string `yaml:"state_dir,omitempty"`
+	// PersistStatus saves status counters in StateDir and restores them
+	// at startup.
+	PersistStatus bool `yaml:"persist_status,omitempty"`
+	// History records per file which actions ran, in StateDir.
+	History bool `yaml:"history,omitempty"`
+	// HistoryMaxSizeBytes rotates the history file once it grows past
+	// this size, keeping the latest HistoryMaxFiles rotated files.
+	HistoryMaxSizeBytes int64 `yaml:"history_max_size_bytes,omitempty"`
+	HistoryMaxFiles     int   `yaml:"history_max_files,omitempty"`
+	// PersistSnapshots saves each watch's last snapshot in StateDir, so
+	// changes made while the watcher was stopped fire at startup.
+	PersistSnapshots bool `yaml:"persist_snapshots,omitempty"`
+	// ControlSocket is the Unix socket `watcher status` queries while
+	// running; empty means $XDG_RUNTIME_DIR/watcher.sock and "none"
+	// disables it.
+	ControlSocket string `yaml:"control_socket,omitempty"`
+	// Timezone is an IANA name such as "Europe/Berlin" used for time
+	// tokens; empty means the host's zone.
+	Timezone string `yaml:"timezone,omitempty"`
+	// NormalizeUnicode is the default name normalization for watches.
+	NormalizeUnicode unorm.Form `yaml:"normalize_unicode,omitempty"`
+	Export           *Export    `yaml:"export,omitempty"`
+	// LogFormat is text (default) or json; run --log-format overrides it.
+	LogFormat LogFormat `yaml:"log_format,omitempty"`
 }
 
 // Condition filters actions.
 type Condition struct {
-	MinSizeBytes int64          `yaml:"min_size_bytes"`
-	MaxSizeBytes int64          `yaml:"max_size_bytes"`
-	MinAge       MillisDuration `yaml:"min_age_ms"`
-	MaxAge       MillisDuration `yaml:"max_age_ms"`
-	OnlyFiles    bool           `yaml:"only_files"`
-	OnlyDirs     bool           `yaml:"only_dirs"`
-	IgnoreHidden *bool          `yaml:"ignore_hidden"`
+	MinSizeBytes int64          `yaml:"min_size_bytes,omitempty"`
+	MaxSizeBytes int64          `yaml:"max_size_bytes,omitempty"`
+	MinAge       MillisDuration `yaml:"min_age_ms,omitempty"`
+	MaxAge       MillisDuration `yaml:"max_age_ms,omitempty"`
+	OnlyFiles    bool           `yaml:"only_files,omitempty"`
+	OnlyDirs     bool           `yaml:"only_dirs,omitempty"`
+	IgnoreHidden *bool          `yaml:"ignore_hidden,omitempty"`
+	Xattr        *XattrMatch    `yaml:"xattr,omitempty"`
+	// NotPreviouslyRun names an action of the same watch; files it already
+	// completed for are skipped. Requires global.history.
+	NotPreviouslyRun string `yaml:"not_previously_run,omitempty"`
+	// StableFor holds create and modify events of files until their size
+	// and mtime have stayed unchanged across scans for this long, so a file
+	// still being written is only processed once the writer finishes.
+	StableFor MillisDuration `yaml:"stable_for_ms,omitempty"`
+	// Fields requires enriched fields to match glob patterns, e.g.
+	// mime: "image/*"; a missing field fails.
+	Fields map[string]string `yaml:"fields,omitempty"`
+	// Filesystem requires properties of the filesystem holding the watch.
+	Filesystem *FilesystemMatch `yaml:"filesystem,omitempty"`
+}
+
+// FilesystemMatch tests the filesystem holding a watch, e.g. to verify
+// copies only on network mounts. Unset fields match anything; where the
+// type cannot be detected, conditions setting any field fail.
+type FilesystemMatch struct {
+	// Types lists accepted types as the OS names them, e.g. ext4, nfs,
+	// cifs or tmpfs.
+	Types    []string `yaml:"types,omitempty"`
+	Network  *bool    `yaml:"network,omitempty"`
+	ReadOnly *bool    `yaml:"read_only,omitempty"`
+}
+
+// XattrMatch requires a user extended attribute; an empty Value only
+// requires the key to exist, and Absent requires it to be missing, e.g.
+// to skip files a tag action already marked. Key may omit the "user."
+// prefix.
+type XattrMatch struct {
+	Key    string `yaml:"key,omitempty"`
+	Value  string `yaml:"value,omitempty"`
+	Absent bool   `yaml:"absent,omitempty"`
 }
 
 // Action describes an action bound to a watch.
 type Action struct {
-	Name      string            `yaml:"name"`
-	Type      ActionType        `yaml:"type"`
-	Include   []string          `yaml:"include"`
-	Exclude   []string          `yaml:"exclude"`
-	Events    []EventType       `yaml:"events"`
-	Dest      string            `yaml:"dest"` // copy/move/rename
-	Cmd       string            `yaml:"cmd"`  // exec
-	URL       string            `yaml:"url"`  // webhook
-	Env       map[string]string `yaml:"env"`
-	Cwd       string            `yaml:"cwd"`
-	Timeout   MillisDuration    `yaml:"timeout_ms"`
-	Retries   int               `yaml:"retries"`
-	Overwrite *bool             `yaml:"overwrite"`
-	Condition Condition         `yaml:"condition"`
+	Name    string      `yaml:"name,omitempty"`
+	Type    ActionType  `yaml:"type,omitempty"`
+	Include []string    `yaml:"include,omitempty"`
+	Exclude []string    `yaml:"exclude,omitempty"`
+	Events  []EventType `yaml:"events,omitempty"`
+	Dest    string      `yaml:"dest,omitempty"`             // copy/move/rename
+	From    string      `yaml:"from,omitempty"`             // rename_pattern regex
+	To      string      `yaml:"to,omitempty"`               // rename_pattern replacement
+	Cmd     Command     `yaml:"cmd,omitempty"`              // exec
+	URL     string      `yaml:"url,omitempty" secret:"url"` // webhook
+	// PayloadFormat is json (default) or cloudevents for webhooks.
+	PayloadFormat PayloadFormat `yaml:"payload_format,omitempty"`
+	// PayloadFields limits webhook and exec stdin JSON payloads to these
+	// keys; empty sends them all.
+	PayloadFields []string `yaml:"payload_fields,omitempty"`
+	// Redact masks these path prefixes in payload paths, e.g. a home dir.
+	Redact []string `yaml:"redact,omitempty"`
+	// Clamd is the clamd address (unix:///path or tcp://host:port); Quarantine
+	// names the action to run when a file is infected.
+	Clamd      string            `yaml:"clamd,omitempty"`
+	Quarantine string            `yaml:"quarantine,omitempty"`
+	Env        map[string]string `yaml:"env,omitempty" secret:"true"`
+	EnvMode    EnvMode           `yaml:"env_mode,omitempty"`
+	// EnvAllowlist names inherited variables for env_mode allowlist; a
+	// trailing * matches a prefix.
+	EnvAllowlist []string       `yaml:"env_allowlist,omitempty"`
+	Cwd          string         `yaml:"cwd,omitempty"`
+	OutputDir    string         `yaml:"output_dir,omitempty"`
+	Timeout      MillisDuration `yaml:"timeout_ms,omitempty"`
+	Retries      *int           `yaml:"retries,omitempty"`
+	// Concurrency caps runs of this action in progress at once; 0 leaves
+	// it to global.max_concurrent_actions.
+	Concurrency int `yaml:"concurrency,omitempty"`
+	// RetryBackoff is the wait before the first retry (default 1s); each
+	// further retry waits RetryBackoffFactor (default 2) times longer, up
+	// to a minute. RetryJitter (0..1) spreads waits by up to that share.
+	// Both are pointers so an explicit 0 overrides global.defaults.
+	RetryBackoff       *MillisDuration `yaml:"retry_backoff_ms,omitempty"`
+	RetryBackoffFactor float64         `yaml:"retry_backoff_factor,omitempty"`
+	RetryJitter        *float64        `yaml:"retry_jitter,omitempty"`
+	// RetryClasses sets the retries for errors of a class in place of
+	// Retries, e.g. {network: 5, permission: 0}.
+	RetryClasses map[ErrorClass]int `yaml:"retry_classes,omitempty"`
+	// DeadLetter is a JSON-lines file that events are appended to when
+	// the action fails for good, for `watcher replay`.
+	DeadLetter string `yaml:"dead_letter,omitempty"`
+	Overwrite  *bool  `yaml:"overwrite,omitempty"`
+	// OnConflict overrides Overwrite when set.
+	OnConflict ConflictPolicy `yaml:"on_conflict,omitempty"`
+	Condition  Condition      `yaml:"condition,omitempty"`
+	// DestMinFreeBytes pauses copy/move until the destination filesystem
+	// has at least this much free space (or the action times out).
+	DestMinFreeBytes int64 `yaml:"dest_min_free_bytes,omitempty"`
+	// DestMinFreeInodes fails copy/move when the destination filesystem
+	// has fewer free inodes; one with none left always fails.
+	DestMinFreeInodes int64 `yaml:"dest_min_free_inodes,omitempty"`
+	// BandwidthLimit caps copy/move throughput, e.g. "10MB/s".
+	BandwidthLimit ByteRate `yaml:"bandwidth_limit,omitempty"`
+	// RateLimit spaces out runs of the action, e.g. "10/1m"; Burst runs
+	// (default 1) may go ahead at once after a quiet spell.
+	RateLimit Rate `yaml:"rate_limit,omitempty"`
+	Burst     int  `yaml:"burst,omitempty"`
+	// Batch runs exec, webhook and archive actions once for a list of events
+	// instead of once per event.
+	Batch *Batch `yaml:"batch,omitempty"`
+	// Resumable copies through a .partial file that later attempts resume.
+	Resumable bool `yaml:"resumable,omitempty"`
+	// Fsync syncs the destination file and directory before reporting success.
+	Fsync bool `yaml:"fsync,omitempty"`
+	// ThenWatch hands the action's output (its destination, or the event's
+	// file) to the named watch's actions as a create event.
+	ThenWatch string `yaml:"then_watch,omitempty"`
+	// Sidecars are companion files, templated relative to the source's
+	// directory (e.g. "{stem}.xmp"), that copy/move carry along with it.
+	Sidecars []string `yaml:"sidecars,omitempty"`
+	// Stdin streams the file (file) or the event as JSON (json) to exec.
+	Stdin StdinMode `yaml:"stdin,omitempty"`
+	// LogOutput says what becomes of exec and ssh_exec output; unset
+	// streams it to the daemon's stdout and stderr.
+	LogOutput LogOutput `yaml:"log_output,omitempty"`
+	// Shell runs a string exec cmd with /bin/sh -c (cmd /C on Windows),
+	// quoting the values of its tokens.
+	Shell bool `yaml:"shell,omitempty"`
+	// KillGrace is how long a timed-out exec group gets between SIGTERM
+	// and SIGKILL.
+	KillGrace MillisDuration `yaml:"kill_grace_ms,omitempty"`
+	// Capture maps variable names to outputs of this action; later actions
+	// for the same event read them as {out:<var>}.
+	Capture map[string]CaptureSource `yaml:"capture,omitempty"`
+	// Response maps webhook JSON responses to captures and outcomes.
+	Response *WebhookResponse `yaml:"response,omitempty"`
+	// TLS customises certificate verification for webhook requests.
+	TLS *ClientTLS `yaml:"tls,omitempty"`
+	// Method is the webhook's HTTP method (default POST).
+	Method string `yaml:"method,omitempty"`
+	// Headers are added to webhook requests; values are templates.
+	Headers map[string]string `yaml:"headers,omitempty" secret:"true"`
+	// BodyTemplate replaces the JSON payload with its expansion.
+	BodyTemplate string       `yaml:"body_template,omitempty"`
+	Auth         *WebhookAuth `yaml:"auth,omitempty"`
+	// SuccessStatus lists the response codes that count as success in
+	// place of any 2xx.
+	SuccessStatus []int `yaml:"success_status,omitempty"`
+	// Proxy overrides HTTP(S)_PROXY for this action: an http, https or
+	// socks5 URL, or "none".
+	Proxy string `yaml:"proxy,omitempty" secret:"url"`
+	// Xattrs are written by tag actions; values are templates, e.g.
+	// {processed: "{now}"}. Keys may omit the "user." prefix.
+	Xattrs map[string]string `yaml:"xattrs,omitempty"`
+	// KeepSource makes archive actions add a copy of the file and leave
+	// the source in place.
+	KeepSource bool `yaml:"keep_source,omitempty"`
+	// SSH is the remote host of ssh_exec actions.
+	SSH *SSHTarget `yaml:"ssh,omitempty"`
+
+	// Compiled by Load and shared read-only between copies.
+	includeGlobs *GlobSet
+	excludeGlobs *GlobSet
+	fromPattern  *regexp.Regexp
+}
+
+// FromPattern returns the compiled from regex of a rename_pattern action.
+func (a Action) FromPattern() (*regexp.Regexp, error) {
+	if a.fromPattern != nil {
+		return a.fromPattern, nil
+	}
+	return regexp.Compile(a.From)
+}
+
+// PayloadFieldNames lists the keys of event payloads.
+var PayloadFieldNames = []string{"id", "path", "relpath", "prev_path", "event", "size", "mtime", "age_ms", "is_dir", "duplicates", "deleted_at", "last_known"}
+
+// ArchiveFormat returns "tar.gz" or "zip" by the archive path's extension,
+// or "" for other paths.
+func ArchiveFormat(dest string) string {
+	lower := strings.ToLower(dest)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	}
+	return ""
+}
+
+// MaxRetries returns how often a failed action is retried.
+func (a Action) MaxRetries() int {
+	if a.Retries == nil || *a.Retries < 0 {
+		return 0
+	}
+	return *a.Retries
+}
+
+// Backoff returns the wait before the first retry.
+func (a Action) Backoff() time.Duration {
+	if a.RetryBackoff == nil {
+		return 0
+	}
+	return a.RetryBackoff.Duration()
+}
+
+// Jitter returns the share by which retry waits are spread.
+func (a Action) Jitter() float64 {
+	if a.RetryJitter == nil {
+		return 0
+	}
+	return *a.RetryJitter
+}
+
+// RetriesFor returns how often an action failing with an error of class
+// is retried.
+func (a Action) RetriesFor(class ErrorClass) int {
+	if n, ok := a.RetryClasses[class]; ok {
+		return max(n, 0)
+	}
+	return a.MaxRetries()
+}
+
+// LowSpace fires an action when the watched filesystem runs low on space.
+type LowSpace struct {
+	MinFreeBytes   int64   `yaml:"min_free_bytes,omitempty"`
+	MinFreePercent float64 `yaml:"min_free_percent,omitempty"`
+	Action         string  `yaml:"action,omitempty"`
+}
+
+// RetentionMode selects what happens to files pruned by a retention rule.
+type RetentionMode string
+
+const (
+	RetentionDelete RetentionMode = "delete"
+	RetentionMove   RetentionMode = "move"
+)
+
+// Retention prunes the oldest files matching a pattern set. Any limit that
+// is exceeded marks a file for pruning.
+type Retention struct {
+	Include           []string       `yaml:"include,omitempty"`
+	Exclude           []string       `yaml:"exclude,omitempty"`
+	MaxAge            MillisDuration `yaml:"max_age_ms,omitempty"`
+	MaxTotalSizeBytes int64          `yaml:"max_total_size_bytes,omitempty"`
+	KeepLastN         int            `yaml:"keep_last_n,omitempty"`
+	Mode              RetentionMode  `yaml:"mode,omitempty"`
+	Dest              string         `yaml:"dest,omitempty"` // move mode
+	Interval          MillisDuration `yaml:"interval_ms,omitempty"`
+
+	includeGlobs *GlobSet
+	excludeGlobs *GlobSet
+}
+
+// Dedup periodically hashes files and reports groups with identical content.
+type Dedup struct {
+	Include      []string       `yaml:"include,omitempty"`
+	Exclude      []string       `yaml:"exclude,omitempty"`
+	MinSizeBytes int64          `yaml:"min_size_bytes,omitempty"`
+	Interval     MillisDuration `yaml:"interval_ms,omitempty"`
+	Action       string         `yaml:"action,omitempty"` // optional, run per group
+
+	includeGlobs *GlobSet
+	excludeGlobs *GlobSet
 }
 
 // Watch is a folder with actions.
 type Watch struct {
-	Path             string         `yaml:"path"`
-	Recursive        bool           `yaml:"recursive"`
-	ScanInterval     MillisDuration `yaml:"scan_interval_ms"`
-	Debounce         MillisDuration `yaml:"debounce_ms"`
-	StopOnFirstMatch bool           `yaml:"stop_on_first_match"`
-	Actions          []Action       `yaml:"actions"`
+	// Name identifies the watch in logs, status, metrics and the CLI;
+	// it defaults to Path.
+	Name             string            `yaml:"name,omitempty"`
+	Path             string            `yaml:"path,omitempty"`
+	Recursive        bool              `yaml:"recursive,omitempty"`
+	Strategy         Strategy          `yaml:"strategy,omitempty"`
+	ScanInterval     MillisDuration    `yaml:"scan_interval_ms,omitempty"`
+	Coalesce         MillisDuration    `yaml:"coalesce_ms,omitempty"` // native notification window
+	Timezone         string            `yaml:"timezone,omitempty"`    // defaults to global timezone
+	NormalizeUnicode unorm.Form        `yaml:"normalize_unicode,omitempty"`
+	SpecialFiles     SpecialFilePolicy `yaml:"special_files,omitempty"`
+	EventOrder       EventOrder        `yaml:"event_order,omitempty"`
+	Debounce         MillisDuration    `yaml:"debounce_ms,omitempty"`
+	StopOnFirstMatch bool              `yaml:"stop_on_first_match,omitempty"`
+	LowSpace         *LowSpace         `yaml:"low_space,omitempty"`
+	Retention        []Retention       `yaml:"retention,omitempty"`
+	Dedup            *Dedup            `yaml:"dedup,omitempty"`
+	Metadata         []string          `yaml:"metadata,omitempty"` // exif, id3, pdf
+	Health           *Health           `yaml:"health,omitempty"`
+	Transient        *Transient        `yaml:"transient,omitempty"`
+	// Enrich attaches fields to events before actions are matched.
+	Enrich []Enricher `yaml:"enrich,omitempty"`
+	// CaseSensitivity decides whether a name changing only in case is a
+	// rename of the same file (insensitive) or a different file.
+	CaseSensitivity CaseSensitivity `yaml:"case_sensitivity,omitempty"`
+	// HandledWebhook reports each event once all its actions are done.
+	HandledWebhook *HandledWebhook `yaml:"handled_webhook,omitempty"`
+	// MaxEventsPerScan holds back scans yielding more events, e.g. when a
+	// flapping mount makes everything look deleted; 0 disables the guard.
+	MaxEventsPerScan int `yaml:"max_events_per_scan,omitempty"`
+	// BurstConfirmScans dispatches a held burst once it has been seen on
+	// that many consecutive scans; 0 pauses the watch until it subsides.
+	BurstConfirmScans int `yaml:"burst_confirm_scans,omitempty"`
+	// MassDeleteThreshold is the share (0..1] of known entries vanishing
+	// in one scan that has the root checked before deletes fire; default 1.
+	MassDeleteThreshold float64 `yaml:"mass_delete_threshold,omitempty"`
+	// Bucket configures access to an s3:// or gs:// path.
+	Bucket *BucketSource `yaml:"bucket,omitempty"`
+	// Source is local (default) or sftp, which lists Path on SSH's host.
+	Source  WatchSource `yaml:"source,omitempty"`
+	SSH     *SSHTarget  `yaml:"ssh,omitempty"`
+	Actions []Action    `yaml:"actions,omitempty"`
+}
+
+// EnricherType names an enricher.
+type EnricherType string
+
+const (
+	// EnrichStat adds mode, and on Unix uid, gid, nlink and inode.
+	EnrichStat EnricherType = "stat"
+	// EnrichHash adds the content digest under the algorithm's name.
+	EnrichHash EnricherType = "hash"
+	// EnrichMIME adds mime and mime_kind.
+	EnrichMIME EnricherType = "mime"
+	// EnrichExec runs a command printing a JSON object whose members
+	// become fields.
+	EnrichExec EnricherType = "exec"
+)
+
+// Enricher adds fields to a watch's create, modify and move events of
+// files. Conditions test them with condition.fields and templates read
+// them as {field:NAME}.
+type Enricher struct {
+	Type EnricherType `yaml:"type"`
+	// Algorithm is the hash digest: sha256 (default), sha1 or md5.
+	Algorithm string `yaml:"algorithm,omitempty"`
+	// Cmd is the exec enricher's command; its arguments take the same
+	// tokens as an exec action's.
+	Cmd     Command        `yaml:"cmd,omitempty"`
+	Timeout MillisDuration `yaml:"timeout_ms,omitempty"`
+}
+
+// WatchSource selects where a watch lists files.
+type WatchSource string
+
+const (
+	SourceLocal WatchSource = "local"
+	// SourceSFTP polls a remote directory over SFTP.
+	SourceSFTP WatchSource = "sftp"
+)
+
+// BucketSource configures a watch on a bucket prefix. Empty fields take
+// the service's defaults; credentials come from AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY unless other variables are named.
+type BucketSource struct {
+	// Endpoint is an S3-compatible service URL, e.g. a MinIO server.
+	Endpoint     string `yaml:"endpoint,omitempty"`
+	Region       string `yaml:"region,omitempty"`
+	AccessKeyEnv string `yaml:"access_key_env,omitempty"`
+	SecretKeyEnv string `yaml:"secret_key_env,omitempty"`
+}
+
+// IsBucket reports whether the watch lists a bucket prefix instead of
+// scanning a local directory.
+func (w Watch) IsBucket() bool {
+	return bucket.IsURL(w.Path)
+}
+
+// IsRemote reports whether the watch lists a bucket or SFTP directory.
+func (w Watch) IsRemote() bool {
+	return w.IsBucket() || w.Source == SourceSFTP
+}
+
+// Key identifies the watch: its name, or its path when unnamed.
+func (w Watch) Key() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return w.Path
+}
+
+// SameSettings reports whether w and o differ at most in their actions,
+// so a running watch can take o's actions without rescanning.
+func (w Watch) SameSettings(o Watch) bool {
+	if w.NeedsXattrs() != o.NeedsXattrs() {
+		return false
+	}
+	w.Actions, o.Actions = nil, nil
+	a, errA := yaml.Marshal(w)
+	b, errB := yaml.Marshal(o)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// Location returns the watch's time zone, falling back to the host's zone
+// when none or an unknown one is set.
+func (w Watch) Location() *time.Location {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil || w.Timezone == "" {
+		return time.Local
+	}
+	return loc
+}
+
+// NeedsXattrs reports whether scans must capture extended attributes, for
+// the xattr metadata kind or an xattr condition.
+func (w Watch) NeedsXattrs() bool {
+	for _, kind := range w.Metadata {
+		if kind == "xattr" {
+			return true
+		}
+	}
+	for _, a := range w.Actions {
+		if a.Condition.Xattr != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // Config is the root.
 type Config struct {
-	Global  Global  `yaml:"global"`
-	Watches []Watch `yaml:"watches"`
+	Version       int           `yaml:"version,omitempty"`
+	Global        Global        `yaml:"global,omitempty"`
+	Notifications Notifications `yaml:"notifications,omitempty"`
+	Watches       []Watch       `yaml:"watches,omitempty"`
+
+	// Warnings collects deprecation notices from loading.
+	Warnings []string `yaml:"-"`
 }
 
 // Load reads and validates the config file.
@@ -98,32 +1040,134 @@ func Load(path string) (Config, error) {
 	if err != nil {
 		return Config{}, fmt.Errorf("read config: %w", err)
 	}
+	data, err = decrypt(path, data)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
 	expanded := os.ExpandEnv(string(data))
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(expanded), &doc); err != nil {
+		return Config{}, fmt.Errorf("parse config: %w", err)
+	}
+	from, notes, err := Migrate(&doc)
+	if err != nil {
+		return Config{}, fmt.Errorf("parse config: %w", err)
+	}
 	var cfg Config
-	if err := yaml.Unmarshal([]byte(expanded), &cfg); err != nil {
+	if err := doc.Decode(&cfg); err != nil {
 		return Config{}, fmt.Errorf("parse config: %w", err)
 	}
-	cfg.normalizeDurations()
-	if err := cfg.applyDefaults(); err != nil {
-		return Config{}, err
+	if from < CurrentVersion {
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("config version %d is deprecated (%d field(s) rewritten in memory); run `watcher config migrate` to upgrade to version %d", from, len(notes), CurrentVersion))
 	}
-	if err := cfg.Validate(); err != nil {
+	cfg.normalizeDurations()
+	if err := cfg.Prepare(); err != nil {
 		return Config{}, err
 	}
 	return cfg, nil
 }
 
+// Prepare applies defaults to a parsed or hand-built config, validates it
+// and compiles its patterns.
+func (c *Config) Prepare() error {
+	if err := c.applyDefaults(); err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return c.compilePatterns()
+}
+
 // Validate verifies config consistency.
 func (c *Config) Validate() error {
 	if len(c.Watches) == 0 {
 		return errors.New("at least one watch must be defined")
 	}
+	if err := validateAdmin(&c.Global.Admin); err != nil {
+		return fmt.Errorf("global admin: %w", err)
+	}
+	if s := c.Global.Safety; s != nil && s.MaxDestructivePerMinute < 0 {
+		return errors.New("global safety max_destructive_per_minute must be >= 0")
+	}
+	if c.Global.MaxConcurrentActions < 0 {
+		return errors.New("global max_concurrent_actions must be >= 0")
+	}
+	if c.Global.RetryBudget < 0 {
+		return errors.New("global retry_budget_per_minute must be >= 0")
+	}
+	if c.Global.PersistStatus && c.Global.StateDir == "" {
+		return errors.New("global persist_status requires state_dir")
+	}
+	if c.Global.History && c.Global.StateDir == "" {
+		return errors.New("global history requires state_dir")
+	}
+	if c.Global.HistoryMaxSizeBytes < 0 || c.Global.HistoryMaxFiles < 0 {
+		return errors.New("global history_max_size_bytes and history_max_files must be >= 0")
+	}
+	if c.Global.PersistSnapshots && c.Global.StateDir == "" {
+		return errors.New("global persist_snapshots requires state_dir")
+	}
+	if u := c.Global.Metrics.PushURL; u != "" {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return fmt.Errorf("global metrics: invalid push_url %q", u)
+		}
+	}
+	for i, l := range c.Global.Metrics.Labels {
+		if !slices.Contains(metricLabels, l) {
+			return fmt.Errorf("global metrics: unknown label %q (want one of %s)", l, strings.Join(metricLabels, ", "))
+		}
+		if slices.Contains(c.Global.Metrics.Labels[:i], l) {
+			return fmt.Errorf("global metrics: duplicate label %q", l)
+		}
+	}
+	if c.Global.Metrics.MaxLabelValues < 0 {
+		return errors.New("global metrics: max_label_values must be >= 0")
+	}
+	if err := validateNotifications(&c.Notifications); err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+	if _, err := time.LoadLocation(c.Global.Timezone); err != nil {
+		return fmt.Errorf("global timezone: %w", err)
+	}
+	if _, err := unorm.ParseForm(string(c.Global.NormalizeUnicode)); err != nil {
+		return fmt.Errorf("global normalize_unicode: %w", err)
+	}
+	switch c.Global.LogFormat {
+	case "", LogText, LogJSON:
+	default:
+		return fmt.Errorf("global: unknown log_format %q", c.Global.LogFormat)
+	}
+	if e := c.Global.Export; e != nil {
+		if err := validateExport(e); err != nil {
+			return fmt.Errorf("global export: %w", err)
+		}
+	}
+	keys := map[string]struct{}{}
 	for i := range c.Watches {
 		w := &c.Watches[i]
 		if w.Path == "" {
 			return fmt.Errorf("watch %d: path is required", i)
 		}
-		if _, err := os.Stat(w.Path); err != nil {
+		if _, dup := keys[w.Key()]; dup {
+			if w.Name != "" {
+				return fmt.Errorf("watch %s: duplicate name", w.Name)
+			}
+			return fmt.Errorf("watch %s: path watched twice; give each watch a unique name", w.Path)
+		}
+		keys[w.Key()] = struct{}{}
+		switch w.Source {
+		case "", SourceLocal, SourceSFTP:
+		default:
+			return fmt.Errorf("watch %s: unknown source %q", w.Path, w.Source)
+		}
+		if w.IsRemote() {
+			if err := validateRemoteWatch(w); err != nil {
+				return fmt.Errorf("watch %s: %w", w.Path, err)
+			}
+		} else if w.Bucket != nil {
+			return fmt.Errorf("watch %s: bucket requires an s3:// or gs:// path", w.Path)
+		} else if _, err := os.Stat(w.Path); err != nil {
 			return fmt.Errorf("watch %s: path error: %w", w.Path, err)
 		}
 		if w.ScanInterval.Duration() <= 0 {
@@ -132,6 +1176,35 @@ func (c *Config) Validate() error {
 		if w.Debounce.Duration() < 0 {
 			return fmt.Errorf("watch %s: debounce_ms must be >= 0", w.Path)
 		}
+		switch w.Strategy {
+		case "", StrategyAuto, StrategyPoll, StrategyNative, StrategyHybrid:
+		default:
+			return fmt.Errorf("watch %s: unknown strategy %q", w.Path, w.Strategy)
+		}
+		if w.Coalesce.Duration() < 0 {
+			return fmt.Errorf("watch %s: coalesce_ms must be >= 0", w.Path)
+		}
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("watch %s: timezone: %w", w.Path, err)
+		}
+		if _, err := unorm.ParseForm(string(w.NormalizeUnicode)); err != nil {
+			return fmt.Errorf("watch %s: normalize_unicode: %w", w.Path, err)
+		}
+		switch w.CaseSensitivity {
+		case "", CaseAuto, CaseSensitive, CaseInsensitive:
+		default:
+			return fmt.Errorf("watch %s: unknown case_sensitivity %q", w.Path, w.CaseSensitivity)
+		}
+		switch w.SpecialFiles {
+		case "", SpecialSkip, SpecialReport, SpecialError:
+		default:
+			return fmt.Errorf("watch %s: unknown special_files policy %q", w.Path, w.SpecialFiles)
+		}
+		switch w.EventOrder {
+		case "", OrderType, OrderPath, OrderOldest, OrderNewest, OrderSmallest, OrderLargest:
+		default:
+			return fmt.Errorf("watch %s: unknown event_order %q", w.Path, w.EventOrder)
+		}
 		if len(w.Actions) == 0 {
 			return fmt.Errorf("watch %s: at least one action is required", w.Path)
 		}
@@ -148,7 +1221,415 @@ func (c *Config) Validate() error {
 			if err := validateAction(a); err != nil {
 				return fmt.Errorf("watch %s action %s: %w", w.Path, a.Name, err)
 			}
+			if a.Concurrency > 1 && c.Global.MaxConcurrentActions <= 1 {
+				return fmt.Errorf("watch %s action %s: concurrency requires global max_concurrent_actions above 1", w.Path, a.Name)
+			}
+		}
+		for _, a := range w.Actions {
+			prev := a.Condition.NotPreviouslyRun
+			if prev == "" {
+				continue
+			}
+			if _, ok := names[prev]; !ok {
+				return fmt.Errorf("watch %s action %s: not_previously_run: unknown action %s", w.Path, a.Name, prev)
+			}
+			if !c.Global.History {
+				return fmt.Errorf("watch %s action %s: not_previously_run requires global history", w.Path, a.Name)
+			}
+		}
+		for _, kind := range w.Metadata {
+			switch kind {
+			case "exif", "id3", "pdf", "xattr":
+			default:
+				return fmt.Errorf("watch %s: unknown metadata kind %q", w.Path, kind)
+			}
+		}
+		if s := c.Global.Safety; s != nil {
+			if err := s.validate(w); err != nil {
+				return fmt.Errorf("watch %s: %w", w.Path, err)
+			}
+		}
+		if h := w.HandledWebhook; h != nil {
+			if strings.TrimSpace(h.URL) == "" {
+				return fmt.Errorf("watch %s: handled_webhook requires url", w.Path)
+			}
+			for _, o := range h.Outcomes {
+				switch o {
+				case HandlingHandled, HandlingPartial, HandlingFailed:
+				default:
+					return fmt.Errorf("watch %s: handled_webhook: unknown outcome %q", w.Path, o)
+				}
+			}
+			if h.Timeout < 0 {
+				return fmt.Errorf("watch %s: handled_webhook timeout_ms must be >= 0", w.Path)
+			}
+		}
+		for j, e := range w.Enrich {
+			if err := validateEnricher(e); err != nil {
+				return fmt.Errorf("watch %s enrich %d: %w", w.Path, j, err)
+			}
+		}
+		for j := range w.Retention {
+			if err := validateRetention(&w.Retention[j]); err != nil {
+				return fmt.Errorf("watch %s retention %d: %w", w.Path, j, err)
+			}
+		}
+		if w.LowSpace != nil {
+			if err := validateLowSpace(w.LowSpace, names); err != nil {
+				return fmt.Errorf("watch %s: low_space: %w", w.Path, err)
+			}
+		}
+		// A batched action returns before its batch runs, so the actions
+		// after it would not wait for it.
+		batched := ""
+		for _, a := range w.Actions {
+			switch {
+			case a.Batch != nil:
+				batched = a.Name
+			case batched != "":
+				return fmt.Errorf("watch %s action %s: follows batched action %s; batched actions must come last", w.Path, a.Name, batched)
+			}
+		}
+		for j := range w.Actions {
+			a := &w.Actions[j]
+			if a.Quarantine == "" {
+				continue
+			}
+			if _, ok := names[a.Quarantine]; !ok || a.Quarantine == a.Name {
+				return fmt.Errorf("watch %s action %s: invalid quarantine action %s", w.Path, a.Name, a.Quarantine)
+			}
+		}
+		if w.Dedup != nil && w.Dedup.Action != "" {
+			if _, ok := names[w.Dedup.Action]; !ok {
+				return fmt.Errorf("watch %s: dedup: unknown action %s", w.Path, w.Dedup.Action)
+			}
+		}
+		if t := w.Transient; t != nil {
+			if t.Mode != TransientDrop && t.Mode != TransientDelay {
+				return fmt.Errorf("watch %s: transient: unknown mode %q", w.Path, t.Mode)
+			}
+			if t.HoldScans < 0 {
+				return fmt.Errorf("watch %s: transient: hold_scans must be >= 0", w.Path)
+			}
+		}
+		if w.MaxEventsPerScan < 0 {
+			return fmt.Errorf("watch %s: max_events_per_scan must be >= 0", w.Path)
+		}
+		if w.BurstConfirmScans < 0 || w.BurstConfirmScans == 1 {
+			return fmt.Errorf("watch %s: burst_confirm_scans must be 0 (pause) or at least 2", w.Path)
+		}
+		if w.MassDeleteThreshold < 0 || w.MassDeleteThreshold > 1 {
+			return fmt.Errorf("watch %s: mass_delete_threshold must be within 0..1", w.Path)
+		}
+		if h := w.Health; h != nil && (h.MaxErrorRate < 0 || h.MaxErrorRate > 1 || h.MaxQueueDepth < 0) {
+			return fmt.Errorf("watch %s: health: max_error_rate must be within 0..1 and max_queue_depth >= 0", w.Path)
+		}
+	}
+	return c.validateHandoffs(keys)
+}
+
+// validateRemoteWatch checks a watch on a bucket or SFTP directory.
+// Features and actions that need a local directory are rejected; files
+// on SFTP can still be copied to local destinations.
+func validateRemoteWatch(w *Watch) error {
+	if w.Source == SourceSFTP {
+		if w.IsBucket() || w.Bucket != nil {
+			return errors.New("source sftp cannot watch a bucket")
+		}
+		if w.SSH == nil || strings.TrimSpace(w.SSH.Host) == "" {
+			return errors.New("source sftp requires ssh.host")
+		}
+		if !strings.HasPrefix(w.Path, "/") {
+			return errors.New("source sftp requires an absolute remote path")
+		}
+	} else if _, err := bucket.Parse(w.Path); err != nil {
+		return err
+	}
+	switch {
+	case w.Strategy == StrategyNative || w.Strategy == StrategyHybrid:
+		return fmt.Errorf("strategy %s is not available for remote sources; they are polled", w.Strategy)
+	case w.LowSpace != nil, len(w.Retention) > 0, w.Dedup != nil, len(w.Metadata) > 0, len(w.Enrich) > 0, w.NeedsXattrs():
+		return errors.New("low_space, retention, dedup, metadata, enrich and xattr conditions need a local directory")
+	case w.Transient != nil && w.Transient.Mode == TransientDrop:
+		// Drop mode looks the file up at dispatch time; delay mode only
+		// compares listings.
+		return errors.New("transient mode drop needs a local directory; use mode delay")
+	}
+	for _, a := range w.Actions {
+		if a.Condition.Filesystem != nil {
+			return fmt.Errorf("action %s: filesystem conditions need a local directory", a.Name)
+		}
+		switch a.Type {
+		case ActionExec, ActionWebhook, ActionSSHExec:
+			if a.Stdin == StdinFile {
+				return fmt.Errorf("action %s: stdin file needs a local file", a.Name)
+			}
+		case ActionCopy:
+			if w.IsBucket() || a.Resumable || len(a.Sidecars) > 0 {
+				return fmt.Errorf("action %s: copy from a remote source is supported for sftp, without resumable and sidecars", a.Name)
+			}
+		default:
+			return fmt.Errorf("action %s: %s needs a local file", a.Name, a.Type)
+		}
+	}
+	return nil
+}
+
+func validateEnricher(e Enricher) error {
+	switch e.Type {
+	case EnrichStat, EnrichMIME:
+	case EnrichHash:
+		switch e.Algorithm {
+		case "", "sha256", "sha1", "md5":
+		default:
+			return fmt.Errorf("unknown hash algorithm %q", e.Algorithm)
+		}
+	case EnrichExec:
+		if e.Cmd.IsZero() {
+			return errors.New("exec enricher requires cmd")
+		}
+	default:
+		return fmt.Errorf("unknown enricher type %q", e.Type)
+	}
+	if e.Timeout < 0 {
+		return errors.New("timeout_ms must be >= 0")
+	}
+	return nil
+}
+
+// validateHandoffs checks that then_watch names another watch and that
+// handoffs never loop back to a watch already in the chain.
+func (c *Config) validateHandoffs(keys map[string]struct{}) error {
+	next := map[string][]string{}
+	remote := map[string]bool{}
+	for _, w := range c.Watches {
+		remote[w.Key()] = w.IsRemote()
+	}
+	for _, w := range c.Watches {
+		for _, a := range w.Actions {
+			if a.ThenWatch == "" {
+				continue
+			}
+			if _, ok := keys[a.ThenWatch]; !ok {
+				return fmt.Errorf("watch %s action %s: then_watch: unknown watch %s", w.Key(), a.Name, a.ThenWatch)
+			}
+			if remote[a.ThenWatch] {
+				return fmt.Errorf("watch %s action %s: then_watch: %s watches a remote source and cannot receive files", w.Key(), a.Name, a.ThenWatch)
+			}
+			next[w.Key()] = append(next[w.Key()], a.ThenWatch)
+		}
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("watch %s: then_watch handoffs form a loop", key)
+		case done:
+			return nil
+		}
+		state[key] = visiting
+		for _, n := range next[key] {
+			if err := visit(n); err != nil {
+				return err
+			}
+		}
+		state[key] = done
+		return nil
+	}
+	for _, w := range c.Watches {
+		if err := visit(w.Key()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateExport(e *Export) error {
+	if strings.TrimSpace(e.Dir) == "" {
+		return errors.New("dir is required")
+	}
+	switch e.Format {
+	case ExportCSV:
+	case ExportParquet:
+		return errors.New("format parquet is not supported in this build; use csv")
+	default:
+		return fmt.Errorf("unknown format %q", e.Format)
+	}
+	if e.MaxSizeBytes < 0 || e.MaxFiles < 0 {
+		return errors.New("max_size_bytes and max_files must be >= 0")
+	}
+	return nil
+}
+
+func validateProxy(proxy string) error {
+	if proxy == "" || proxy == ProxyNone {
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return nil
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+}
+
+func validateResponse(r *WebhookResponse) error {
+	for name := range r.Capture {
+		if !validVarName(name) {
+			return fmt.Errorf("invalid capture variable %q", name)
+		}
+	}
+	if len(r.Outcomes) > 0 && r.OutcomeField == "" {
+		return errors.New("outcomes require outcome_field")
+	}
+	for value, o := range r.Outcomes {
+		switch o {
+		case OutcomeOK, OutcomeSkip, OutcomeRetry, OutcomeFail:
+		default:
+			return fmt.Errorf("unknown outcome %q for %q", o, value)
+		}
+	}
+	return nil
+}
+
+func validVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func validateAdmin(a *Admin) error {
+	validScope := func(s AdminScope) bool { return s == ScopeRead || s == ScopeControl }
+	for i, t := range a.Tokens {
+		if strings.TrimSpace(t.Token) == "" {
+			return fmt.Errorf("token %d is empty", i)
+		}
+		if !validScope(t.Scope) {
+			return fmt.Errorf("token %d: unknown scope %q", i, t.Scope)
+		}
+	}
+	for cn, s := range a.ClientScopes {
+		if !validScope(s) {
+			return fmt.Errorf("client %s: unknown scope %q", cn, s)
+		}
+	}
+	if a.TLS != nil {
+		if a.TLS.CertFile == "" || a.TLS.KeyFile == "" {
+			return errors.New("tls requires cert_file and key_file")
+		}
+		if len(a.ClientScopes) > 0 && a.TLS.ClientCAFile == "" {
+			return errors.New("client_scopes require tls.client_ca_file")
+		}
+	}
+	return nil
+}
+
+func validateNotifications(n *Notifications) error {
+	for i, t := range n.Targets {
+		switch t.Type {
+		case NotifyWebhook, NotifySlack:
+			if t.URL == "" {
+				return fmt.Errorf("target %d: url is required", i)
+			}
+		case NotifyEmail:
+			if t.SMTP == "" || t.From == "" || len(t.To) == 0 {
+				return fmt.Errorf("target %d: smtp, from and to are required", i)
+			}
+		default:
+			return fmt.Errorf("target %d: unknown type %q", i, t.Type)
+		}
+	}
+	for name, th := range map[string]*Threshold{"action_failures": n.ActionFailures, "scan_errors": n.ScanErrors, "safety_holds": n.SafetyHolds} {
+		if th == nil {
+			continue
+		}
+		if len(n.Targets) == 0 {
+			return fmt.Errorf("%s requires at least one target", name)
+		}
+		if th.Count < 1 {
+			return fmt.Errorf("%s: count must be >= 1", name)
+		}
+	}
+	return nil
+}
+
+func validateRetention(r *Retention) error {
+	if r.MaxAge.Duration() <= 0 && r.MaxTotalSizeBytes <= 0 && r.KeepLastN <= 0 {
+		return errors.New("one of max_age_ms, max_total_size_bytes or keep_last_n is required")
+	}
+	switch r.Mode {
+	case RetentionDelete:
+	case RetentionMove:
+		if strings.TrimSpace(r.Dest) == "" {
+			return errors.New("move mode requires dest")
+		}
+	default:
+		return fmt.Errorf("unknown mode %q", r.Mode)
+	}
+	return nil
+}
+
+func validateLowSpace(ls *LowSpace, actions map[string]struct{}) error {
+	if ls.MinFreeBytes <= 0 && ls.MinFreePercent <= 0 {
+		return errors.New("min_free_bytes or min_free_percent is required")
+	}
+	if ls.MinFreePercent < 0 || ls.MinFreePercent > 100 {
+		return errors.New("min_free_percent must be between 0 and 100")
+	}
+	if ls.Action == "" {
+		return errors.New("action is required")
+	}
+	if _, ok := actions[ls.Action]; !ok {
+		return fmt.Errorf("unknown action %s", ls.Action)
+	}
+	return nil
+}
+
+// validateBatch checks a's batch block. Batches run once for many events,
+// so per-event outputs such as capture have nothing to attach to.
+func validateBatch(a *Action) error {
+	b := a.Batch
+	if a.Type != ActionExec && a.Type != ActionWebhook && a.Type != ActionArchive {
+		return errors.New("batch is supported on exec, webhook and archive actions")
+	}
+	if b.MaxItems < 0 {
+		return errors.New("batch max_items must be >= 0")
+	}
+	if b.MaxWait < 0 {
+		return errors.New("batch max_wait_ms must be >= 0")
+	}
+	if a.Stdin != "" && a.Stdin != StdinNone {
+		return errors.New("batch cannot be combined with stdin; use batch input stdin")
+	}
+	switch b.Input {
+	case "", BatchArgs:
+	case BatchStdin:
+		if a.Type != ActionExec {
+			return errors.New("batch input stdin is supported on exec actions")
 		}
+	default:
+		return fmt.Errorf("unknown batch input %q", b.Input)
+	}
+	if len(a.Capture) > 0 || a.Response != nil {
+		return errors.New("batch cannot be combined with capture or response")
+	}
+	if a.ThenWatch != "" {
+		return errors.New("batch cannot be combined with then_watch")
 	}
 	return nil
 }
@@ -156,26 +1637,268 @@ func (c *Config) Validate() error {
 func validateAction(a *Action) error {
 	switch a.Type {
 	case ActionExec:
-		if strings.TrimSpace(a.Cmd) == "" {
+		if a.Cmd.IsZero() {
 			return errors.New("exec action requires cmd")
 		}
-	case ActionCopy, ActionMove, ActionRename:
+		if err := validateLogOutput(a.LogOutput); err != nil {
+			return err
+		}
+		if a.Shell && len(a.Cmd.Args) > 0 {
+			return errors.New("shell needs cmd as a string")
+		}
+		if !a.Shell && a.Cmd.Line != "" {
+			if _, err := template.Fields(a.Cmd.Line); err != nil {
+				return fmt.Errorf("cmd: %w", err)
+			}
+		}
+		switch a.Stdin {
+		case "", StdinNone, StdinFile, StdinJSON:
+		default:
+			return fmt.Errorf("unknown stdin %q", a.Stdin)
+		}
+		switch a.EnvMode {
+		case "", EnvInherit, EnvClean:
+		case EnvAllowlist:
+			if len(a.EnvAllowlist) == 0 {
+				return errors.New("env_mode allowlist requires env_allowlist")
+			}
+		default:
+			return fmt.Errorf("unknown env_mode %q", a.EnvMode)
+		}
+	case ActionSSHExec:
+		if a.Cmd.IsZero() {
+			return errors.New("ssh_exec action requires cmd")
+		}
+		if a.SSH == nil || strings.TrimSpace(a.SSH.Host) == "" {
+			return errors.New("ssh_exec action requires ssh.host")
+		}
+		if a.SSH.Port < 0 || a.SSH.Port > 65535 {
+			return fmt.Errorf("invalid ssh.port %d", a.SSH.Port)
+		}
+		if a.SSH.Persist.Duration() < 0 {
+			return errors.New("ssh.persist_ms must be >= 0")
+		}
+		if err := validateLogOutput(a.LogOutput); err != nil {
+			return err
+		}
+		switch a.Stdin {
+		case "", StdinNone, StdinFile, StdinJSON:
+		default:
+			return fmt.Errorf("unknown stdin %q", a.Stdin)
+		}
+	case ActionCopy, ActionMove, ActionRename, ActionTransfer:
 		if strings.TrimSpace(a.Dest) == "" {
 			return fmt.Errorf("%s action requires dest", a.Type)
 		}
+	case ActionTag:
+		if len(a.Xattrs) == 0 {
+			return errors.New("tag action requires xattrs")
+		}
+	case ActionArchive:
+		if strings.TrimSpace(a.Dest) == "" {
+			return errors.New("archive action requires dest")
+		}
+		if ArchiveFormat(a.Dest) == "" {
+			return fmt.Errorf("archive dest %q must end in .tar.gz, .tgz or .zip", a.Dest)
+		}
 	case ActionWebhook:
 		if strings.TrimSpace(a.URL) == "" {
 			return errors.New("webhook action requires url")
 		}
+		switch a.PayloadFormat {
+		case "", PayloadJSON, PayloadCloudEvents:
+		default:
+			return fmt.Errorf("unknown payload_format %q", a.PayloadFormat)
+		}
+		if a.Response != nil {
+			if err := validateResponse(a.Response); err != nil {
+				return fmt.Errorf("response: %w", err)
+			}
+		}
+		if t := a.TLS; t != nil && (t.CertFile == "") != (t.KeyFile == "") {
+			return errors.New("tls cert_file and key_file must be set together")
+		}
+		if err := validateWebhookRequest(a); err != nil {
+			return err
+		}
+		if err := validateProxy(a.Proxy); err != nil {
+			return err
+		}
+	case ActionRenamePattern:
+		if a.From == "" || a.To == "" {
+			return errors.New("rename_pattern action requires from and to")
+		}
+		if _, err := regexp.Compile(a.From); err != nil {
+			return fmt.Errorf("invalid from pattern: %w", err)
+		}
+	case ActionClamScan:
+		if strings.TrimSpace(a.Clamd) == "" {
+			return errors.New("clamscan action requires clamd")
+		}
 	default:
 		return fmt.Errorf("unknown action type %q", a.Type)
 	}
 	if len(a.Events) == 0 {
 		a.Events = []EventType{EventCreate, EventModify}
 	}
+	if len(a.PayloadFields) > 0 || len(a.Redact) > 0 {
+		if a.Type != ActionWebhook && a.Type != ActionExec && a.Type != ActionSSHExec {
+			return errors.New("payload_fields and redact are supported on webhook, exec and ssh_exec actions")
+		}
+	}
+	for _, f := range a.PayloadFields {
+		if !slices.Contains(PayloadFieldNames, f) {
+			return fmt.Errorf("unknown payload field %q (known: %s)", f, strings.Join(PayloadFieldNames, ", "))
+		}
+	}
+	for _, p := range a.Redact {
+		if strings.TrimSpace(p) == "" {
+			return errors.New("redact entries must not be empty")
+		}
+	}
+	if a.Backoff() < 0 {
+		return errors.New("retry_backoff_ms must be >= 0")
+	}
+	if a.RetryBackoffFactor != 0 && a.RetryBackoffFactor < 1 {
+		return errors.New("retry_backoff_factor must be >= 1")
+	}
+	if j := a.Jitter(); j < 0 || j > 1 {
+		return errors.New("retry_jitter must be between 0 and 1")
+	}
+	for class, n := range a.RetryClasses {
+		if !slices.Contains(ErrorClasses, class) {
+			return fmt.Errorf("unknown retry_classes class %q", class)
+		}
+		if n < 0 {
+			return fmt.Errorf("retry_classes %s must be >= 0", class)
+		}
+	}
+	if len(a.Sidecars) > 0 && a.Type != ActionCopy && a.Type != ActionMove && a.Type != ActionRename {
+		return errors.New("sidecars are supported on copy, move and rename actions")
+	}
 	if a.Condition.OnlyDirs && a.Condition.OnlyFiles {
 		return errors.New("cannot set both only_dirs and only_files")
 	}
+	if a.Burst < 0 {
+		return errors.New("burst must be >= 0")
+	}
+	if a.Burst > 0 && a.RateLimit.IsZero() {
+		return errors.New("burst requires rate_limit")
+	}
+	if a.Concurrency < 0 {
+		return errors.New("concurrency must be >= 0")
+	}
+	if a.Batch != nil {
+		if err := validateBatch(a); err != nil {
+			return err
+		}
+	}
+	for name, pattern := range a.Condition.Fields {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("condition fields %s: bad pattern %q", name, pattern)
+		}
+	}
+	if fs := a.Condition.Filesystem; fs != nil && slices.Contains(fs.Types, "") {
+		return errors.New("condition filesystem types cannot be empty")
+	}
+	if a.Condition.StableFor < 0 {
+		return errors.New("condition stable_for_ms must be >= 0")
+	}
+	if x := a.Condition.Xattr; x != nil {
+		if strings.TrimPrefix(x.Key, "user.") == "" {
+			return errors.New("condition xattr requires key")
+		}
+		if x.Absent && x.Value != "" {
+			return errors.New("condition xattr cannot set both value and absent")
+		}
+	}
+	switch a.OnConflict {
+	case "", ConflictFail, ConflictSkip, ConflictOverwrite, ConflictSuffix:
+	default:
+		return fmt.Errorf("unknown on_conflict %q", a.OnConflict)
+	}
+	for name, src := range a.Capture {
+		if !validVarName(name) {
+			return fmt.Errorf("invalid capture variable %q", name)
+		}
+		switch src {
+		case CaptureStdout, CaptureStderr, CaptureDest:
+		default:
+			return fmt.Errorf("unknown capture source %q", src)
+		}
+	}
+	if a.DestMinFreeBytes < 0 {
+		return errors.New("dest_min_free_bytes must be >= 0")
+	}
+	if a.DestMinFreeInodes < 0 {
+		return errors.New("dest_min_free_inodes must be >= 0")
+	}
+	return checkTemplates(a)
+}
+
+func validateLogOutput(l LogOutput) error {
+	switch l {
+	case "", LogOutputOn, LogOutputOff, LogOutputOnError:
+		return nil
+	}
+	return fmt.Errorf("unknown log_output %q (want true, false or on_error)", l)
+}
+
+// webhookMethods are the methods a webhook action may use.
+var webhookMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+func validateWebhookRequest(a *Action) error {
+	if a.Method != "" && !slices.Contains(webhookMethods, strings.ToUpper(a.Method)) {
+		return fmt.Errorf("unknown method %q", a.Method)
+	}
+	for name := range a.Headers {
+		if name == "" || strings.ContainsAny(name, " :\t\r\n") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+	if a.BodyTemplate != "" && a.Batch != nil {
+		return errors.New("body_template is not supported with batch")
+	}
+	if a.BodyTemplate != "" && a.PayloadFormat == PayloadCloudEvents {
+		return errors.New("body_template replaces the payload; drop payload_format cloudevents")
+	}
+	if a.BodyTemplate != "" && len(a.PayloadFields) > 0 {
+		return errors.New("body_template replaces the payload; drop payload_fields")
+	}
+	if au := a.Auth; au != nil {
+		bearer, basic := au.BearerEnv != "", au.UsernameEnv != "" || au.PasswordEnv != ""
+		switch {
+		case bearer && basic:
+			return errors.New("auth: use bearer_env or username_env/password_env, not both")
+		case !bearer && !basic:
+			return errors.New("auth requires bearer_env or username_env")
+		case basic && au.UsernameEnv == "":
+			return errors.New("auth: password_env requires username_env")
+		}
+	}
+	for _, code := range a.SuccessStatus {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid success_status %d", code)
+		}
+	}
+	return nil
+}
+
+// checkTemplates rejects unknown template filters in the fields actions
+// expand.
+func checkTemplates(a *Action) error {
+	fields := append([]string{a.Dest, a.To, a.URL, a.Cwd, a.OutputDir, a.BodyTemplate, a.Cmd.Line}, a.Cmd.Args...)
+	for _, v := range a.Env {
+		fields = append(fields, v)
+	}
+	for _, v := range a.Headers {
+		fields = append(fields, v)
+	}
+	for _, f := range fields {
+		if err := template.Check(f); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -186,6 +1909,39 @@ func (c *Config) applyDefaults() error {
 	if c.Global.Debounce.Duration() == 0 {
 		c.Global.Debounce = MillisFromDuration(200 * time.Millisecond)
 	}
+	if c.Global.Metrics.PushURL != "" && c.Global.Metrics.PushJob == "" {
+		c.Global.Metrics.PushJob = "watcher"
+	}
+	if c.Global.History {
+		if c.Global.HistoryMaxSizeBytes == 0 {
+			c.Global.HistoryMaxSizeBytes = 64 << 20
+		}
+		if c.Global.HistoryMaxFiles == 0 {
+			c.Global.HistoryMaxFiles = 5
+		}
+	}
+	if c.Global.Metrics.Labels == nil {
+		c.Global.Metrics.Labels = []string{"watch", "action", "event"}
+	}
+	if c.Global.Metrics.MaxLabelValues == 0 {
+		c.Global.Metrics.MaxLabelValues = 100
+	}
+	if e := c.Global.Export; e != nil {
+		if e.Format == "" {
+			e.Format = ExportCSV
+		}
+		if e.MaxSizeBytes == 0 {
+			e.MaxSizeBytes = 64 << 20
+		}
+		if e.MaxFiles == 0 {
+			e.MaxFiles = 10
+		}
+	}
+	for _, th := range []*Threshold{c.Notifications.ActionFailures, c.Notifications.ScanErrors, c.Notifications.SafetyHolds} {
+		if th != nil && th.Window.Duration() == 0 {
+			th.Window = MillisFromDuration(10 * time.Minute)
+		}
+	}
 	for i := range c.Watches {
 		w := &c.Watches[i]
 		if w.ScanInterval.Duration() == 0 {
@@ -194,20 +1950,125 @@ func (c *Config) applyDefaults() error {
 		if w.Debounce.Duration() == 0 {
 			w.Debounce = c.Global.Debounce
 		}
+		if w.Strategy == "" {
+			w.Strategy = StrategyAuto
+		}
+		if w.Timezone == "" {
+			w.Timezone = c.Global.Timezone
+		}
+		if w.NormalizeUnicode == "" {
+			w.NormalizeUnicode = c.Global.NormalizeUnicode
+		}
+		if w.MassDeleteThreshold == 0 {
+			w.MassDeleteThreshold = 1
+		}
+		if w.SpecialFiles == "" {
+			w.SpecialFiles = SpecialSkip
+		}
+		if w.EventOrder == "" {
+			w.EventOrder = OrderType
+		}
+		if w.Coalesce.Duration() == 0 {
+			w.Coalesce = MillisFromDuration(100 * time.Millisecond)
+		}
+		if w.Transient != nil && w.Transient.Mode == TransientDelay && w.Transient.HoldScans == 0 {
+			w.Transient.HoldScans = 1
+		}
+		if w.Dedup != nil && w.Dedup.Interval.Duration() == 0 {
+			w.Dedup.Interval = MillisFromDuration(time.Hour)
+		}
+		for j := range w.Retention {
+			r := &w.Retention[j]
+			if r.Mode == "" {
+				r.Mode = RetentionDelete
+			}
+			if r.Interval.Duration() == 0 {
+				r.Interval = MillisFromDuration(time.Minute)
+			}
+		}
 		for j := range w.Actions {
 			a := &w.Actions[j]
+			defaults := c.Global.Defaults
+			if a.Timeout.Duration() == 0 {
+				a.Timeout = defaults.Timeout
+			}
 			if a.Timeout.Duration() == 0 {
 				a.Timeout = MillisFromDuration(30 * time.Second)
 			}
-			if a.Retries < 0 {
-				a.Retries = 0
+			if a.Type == ActionExec && a.KillGrace.Duration() == 0 {
+				a.KillGrace = MillisFromDuration(5 * time.Second)
+			}
+			if a.Retries == nil {
+				retries := defaults.Retries
+				a.Retries = &retries
+			}
+			if *a.Retries < 0 {
+				*a.Retries = 0
+			}
+			if a.RetryBackoff == nil && defaults.RetryBackoff != nil {
+				backoff := *defaults.RetryBackoff
+				a.RetryBackoff = &backoff
+			}
+			if a.RetryBackoff == nil {
+				backoff := MillisFromDuration(time.Second)
+				a.RetryBackoff = &backoff
+			}
+			if a.RetryBackoffFactor == 0 {
+				a.RetryBackoffFactor = defaults.RetryBackoffFactor
+			}
+			if a.RetryBackoffFactor == 0 {
+				a.RetryBackoffFactor = 2
+			}
+			if a.RetryJitter == nil && defaults.RetryJitter != nil {
+				jitter := *defaults.RetryJitter
+				a.RetryJitter = &jitter
+			}
+			if a.DeadLetter == "" {
+				a.DeadLetter = defaults.DeadLetter
+			}
+			for class, n := range defaults.RetryClasses {
+				if _, ok := a.RetryClasses[class]; !ok {
+					if a.RetryClasses == nil {
+						a.RetryClasses = map[ErrorClass]int{}
+					}
+					a.RetryClasses[class] = n
+				}
+			}
+			if a.RateLimit.IsZero() {
+				a.RateLimit = defaults.RateLimit
+				if a.Burst == 0 {
+					a.Burst = defaults.Burst
+				}
+			}
+			if b := a.Batch; b != nil {
+				if b.MaxItems == 0 {
+					b.MaxItems = 100
+				}
+				if b.MaxWait == 0 {
+					b.MaxWait = MillisFromDuration(time.Second)
+				}
+				if b.Input == "" {
+					b.Input = BatchArgs
+				}
+			}
+			if len(a.Events) == 0 && len(defaults.Events) > 0 {
+				a.Events = append([]EventType(nil), defaults.Events...)
 			}
 			if a.Overwrite == nil {
 				defaultOverwrite := c.Global.Defaults.Overwrite
 				a.Overwrite = &defaultOverwrite
 			}
+			if a.OnConflict == "" {
+				a.OnConflict = ConflictFail
+				if *a.Overwrite {
+					a.OnConflict = ConflictOverwrite
+				}
+			}
 			if a.Condition.IgnoreHidden == nil {
 				def := true
+				if defaults.IgnoreHidden != nil {
+					def = *defaults.IgnoreHidden
+				}
 				a.Condition.IgnoreHidden = &def
 			}
 		}
@@ -233,6 +2094,11 @@ func (d MillisDuration) Duration() time.Duration {
 	return time.Duration(int64(d)) * time.Millisecond
 }
 
+// MarshalYAML writes durations as strings such as "1.5s".
+func (d MillisDuration) MarshalYAML() (interface{}, error) {
+	return d.Duration().String(), nil
+}
+
 // UnmarshalYAML implements yaml unmarshalling with ms support.
 func (d *MillisDuration) UnmarshalYAML(value *yaml.Node) error {
 	switch value.Kind {
@@ -254,18 +2120,169 @@ func (d *MillisDuration) UnmarshalYAML(value *yaml.Node) error {
 	}
 }
 
-// MatchesInclude tests include patterns; if none, default allow.
+// Command is an exec command given either as a string or as a list of
+// arguments, each expanded on its own. A string is split into arguments
+// like a shell line, honouring quotes, before tokens are expanded; with
+// the action's shell set it is instead run by /bin/sh -c (cmd.exe on
+// Windows) with every substituted value quoted. Shell mode takes only the
+// string form.
+type Command struct {
+	Line string
+	Args []string
+}
+
+// IsZero reports whether no command was configured.
+func (c Command) IsZero() bool {
+	return strings.TrimSpace(c.Line) == "" && len(c.Args) == 0
+}
+
+// UnmarshalYAML accepts a scalar or a sequence of scalars.
+func (c *Command) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		c.Line = value.Value
+		return nil
+	case yaml.SequenceNode:
+		return value.Decode(&c.Args)
+	default:
+		return fmt.Errorf("invalid cmd node kind: %v", value.Kind)
+	}
+}
+
+// MarshalYAML writes the command back in the form it was given.
+func (c Command) MarshalYAML() (interface{}, error) {
+	if len(c.Args) > 0 {
+		return c.Args, nil
+	}
+	return c.Line, nil
+}
+
+// Rate is a number of runs per period parsed from strings such as
+// "10/1m", "5/s" or "100/1h"; a bare unit means one of it.
+type Rate struct {
+	Count int
+	Per   time.Duration
+}
+
+// ParseRate parses a rate.
+func ParseRate(s string) (Rate, error) {
+	count, per, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("invalid rate %q: want COUNT/PERIOD, e.g. 10/1m", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: count must be a positive integer", s)
+	}
+	per = strings.TrimSpace(per)
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: bad period", s)
+	}
+	return Rate{Count: n, Per: d}, nil
+}
+
+// IsZero reports whether no rate was configured.
+func (r Rate) IsZero() bool { return r.Count == 0 }
+
+// Interval returns the time between runs at this rate.
+func (r Rate) Interval() time.Duration {
+	if r.Count == 0 {
+		return 0
+	}
+	return r.Per / time.Duration(r.Count)
+}
+
+// String formats the rate as ParseRate accepts it.
+func (r Rate) String() string {
+	per := r.Per.String()
+	for _, zero := range []string{"0s", "0m"} {
+		if p := strings.TrimSuffix(per, zero); p != per && len(p) > 0 && p[len(p)-1] >= 'a' {
+			per = p
+		}
+	}
+	return fmt.Sprintf("%d/%s", r.Count, per)
+}
+
+// UnmarshalYAML implements yaml unmarshalling for rates.
+func (r *Rate) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("invalid rate node kind: %v", value.Kind)
+	}
+	v, err := ParseRate(value.Value)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// MarshalYAML writes the rate back as a string.
+func (r Rate) MarshalYAML() (interface{}, error) {
+	return r.String(), nil
+}
+
+// ByteRate is a throughput in bytes per second parsed from strings such as
+// "10MB/s", "512KiB/s" or a plain integer.
+type ByteRate int64
+
+// UnmarshalYAML implements yaml unmarshalling for rates.
+func (r *ByteRate) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("invalid rate node kind: %v", value.Kind)
+	}
+	v, err := ParseBytes(strings.TrimSuffix(strings.TrimSpace(value.Value), "/s"))
+	if err != nil {
+		return fmt.Errorf("invalid rate %q: %w", value.Value, err)
+	}
+	*r = ByteRate(v)
+	return nil
+}
+
+var byteUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ParseBytes parses sizes like "1.5GB", "512KiB" or "1024".
+func ParseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	num, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, err
+	}
+	mult, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", s[i:])
+	}
+	if num < 0 {
+		return 0, errors.New("must be >= 0")
+	}
+	return int64(num * float64(mult)), nil
+}
+
+// MatchesInclude tests include patterns; if none, default allow. It only
+// reads the Action and is safe to call concurrently.
 func (a *Action) MatchesInclude(relPath string) bool {
 	if len(a.Include) == 0 {
 		return true
 	}
-	p := filepath.ToSlash(relPath)
-	for _, pattern := range a.Include {
-		if ok, _ := doublestar.PathMatch(pattern, p); ok {
-			return true
-		}
-	}
-	return false
+	return globsFor(a.includeGlobs, a.Include).Match(relPath)
 }
 
 // MatchesExclude tests exclude patterns.
@@ -273,18 +2290,116 @@ func (a *Action) MatchesExclude(relPath string) bool {
 	if len(a.Exclude) == 0 {
 		return false
 	}
-	p := filepath.ToSlash(relPath)
-	for _, pattern := range a.Exclude {
-		if ok, _ := doublestar.PathMatch(pattern, p); ok {
-			return true
+	return globsFor(a.excludeGlobs, a.Exclude).Match(relPath)
+}
+
+// Matches reports whether a retention rule covers relPath.
+func (r *Retention) Matches(relPath string) bool {
+	if len(r.Include) > 0 && !globsFor(r.includeGlobs, r.Include).Match(relPath) {
+		return false
+	}
+	return len(r.Exclude) == 0 || !globsFor(r.excludeGlobs, r.Exclude).Match(relPath)
+}
+
+// Matches reports whether a dedup scan covers relPath.
+func (d *Dedup) Matches(relPath string) bool {
+	if len(d.Include) > 0 && !globsFor(d.includeGlobs, d.Include).Match(relPath) {
+		return false
+	}
+	return len(d.Exclude) == 0 || !globsFor(d.excludeGlobs, d.Exclude).Match(relPath)
+}
+
+// compilePatterns precompiles every include/exclude list and rename
+// pattern so matching at runtime is read-only.
+func (c *Config) compilePatterns() error {
+	compile := func(dst **GlobSet, patterns []string) error {
+		set, err := CompileGlobs(patterns)
+		if err != nil {
+			return err
 		}
+		*dst = set
+		return nil
 	}
-	return false
+	for i := range c.Watches {
+		w := &c.Watches[i]
+		for j := range w.Actions {
+			a := &w.Actions[j]
+			if err := compile(&a.includeGlobs, a.Include); err != nil {
+				return fmt.Errorf("watch %s action %s include: %w", w.Path, a.Name, err)
+			}
+			if err := compile(&a.excludeGlobs, a.Exclude); err != nil {
+				return fmt.Errorf("watch %s action %s exclude: %w", w.Path, a.Name, err)
+			}
+			if a.Type == ActionRenamePattern {
+				re, err := regexp.Compile(a.From)
+				if err != nil {
+					return fmt.Errorf("watch %s action %s from: %w", w.Path, a.Name, err)
+				}
+				a.fromPattern = re
+			}
+		}
+		for j := range w.Retention {
+			r := &w.Retention[j]
+			if err := compile(&r.includeGlobs, r.Include); err != nil {
+				return fmt.Errorf("watch %s retention %d include: %w", w.Path, j, err)
+			}
+			if err := compile(&r.excludeGlobs, r.Exclude); err != nil {
+				return fmt.Errorf("watch %s retention %d exclude: %w", w.Path, j, err)
+			}
+		}
+		if d := w.Dedup; d != nil {
+			if err := compile(&d.includeGlobs, d.Include); err != nil {
+				return fmt.Errorf("watch %s dedup include: %w", w.Path, err)
+			}
+			if err := compile(&d.excludeGlobs, d.Exclude); err != nil {
+				return fmt.Errorf("watch %s dedup exclude: %w", w.Path, err)
+			}
+		}
+	}
+	return nil
 }
 
+// ControlSocketNone disables the control socket.
+const ControlSocketNone = "none"
+
 // ResolvePaths cleans watch paths.
 func (c *Config) ResolvePaths() error {
+	if e := c.Global.Export; e != nil && e.Dir != "" {
+		p, err := filepath.Abs(e.Dir)
+		if err != nil {
+			return err
+		}
+		e.Dir = p
+	}
+	if c.Global.StateDir != "" {
+		p, err := filepath.Abs(c.Global.StateDir)
+		if err != nil {
+			return err
+		}
+		c.Global.StateDir = p
+	}
+	if s := c.Global.ControlSocket; s != "" && s != ControlSocketNone {
+		p, err := filepath.Abs(s)
+		if err != nil {
+			return err
+		}
+		c.Global.ControlSocket = p
+	}
 	for i := range c.Watches {
+		for j := range c.Watches[i].Actions {
+			a := &c.Watches[i].Actions[j]
+			if a.DeadLetter == "" {
+				continue
+			}
+			p, err := filepath.Abs(a.DeadLetter)
+			if err != nil {
+				return err
+			}
+			a.DeadLetter = p
+		}
+		if c.Watches[i].IsRemote() {
+			continue
+		}
 		p, err := filepath.Abs(c.Watches[i].Path)
 		if err != nil {
 			return err
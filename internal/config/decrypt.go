@@ -0,0 +1,107 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Encryption formats recognised by Load.
+const (
+	encryptionNone = ""
+	encryptionSOPS = "sops"
+	encryptionAge  = "age"
+)
+
+// detectEncryption recognises age-armored/binary files and sops-encrypted
+// YAML (a top-level "sops" mapping with a mac).
+func detectEncryption(data []byte) string {
+	if bytes.HasPrefix(data, []byte("age-encryption.org/v1")) ||
+		bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		return encryptionAge
+	}
+	var probe struct {
+		SOPS *struct {
+			MAC string `yaml:"mac"`
+		} `yaml:"sops"`
+	}
+	if yaml.Unmarshal(data, &probe) == nil && probe.SOPS != nil && probe.SOPS.MAC != "" {
+		return encryptionSOPS
+	}
+	return encryptionNone
+}
+
+// IsEncrypted reports whether data is a sops or age encrypted config.
+func IsEncrypted(data []byte) bool {
+	return detectEncryption(data) != encryptionNone
+}
+
+// decrypt returns the plaintext of an encrypted config by invoking the
+// sops or age CLI. Keys come from the tools' usual environment
+// (SOPS_AGE_KEY_FILE, cloud KMS credentials, ...) or, for age, from
+// WATCHER_AGE_KEY_FILE / WATCHER_AGE_KEY.
+func decrypt(path string, data []byte) ([]byte, error) {
+	switch detectEncryption(data) {
+	case encryptionSOPS:
+		out, err := runDecrypt(exec.Command("sops", "--decrypt", "--input-type", "yaml", "--output-type", "yaml", path))
+		if err != nil {
+			return nil, fmt.Errorf("sops decrypt: %w", err)
+		}
+		return out, nil
+	case encryptionAge:
+		identity, cleanup, err := ageIdentity()
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		out, err := runDecrypt(exec.Command("age", "--decrypt", "-i", identity, path))
+		if err != nil {
+			return nil, fmt.Errorf("age decrypt: %w", err)
+		}
+		return out, nil
+	default:
+		return data, nil
+	}
+}
+
+func runDecrypt(cmd *exec.Cmd) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// ageIdentity resolves the identity file for age, writing WATCHER_AGE_KEY
+// to a private temp file when the key is passed inline.
+func ageIdentity() (string, func(), error) {
+	noop := func() {}
+	for _, env := range []string{"WATCHER_AGE_KEY_FILE", "SOPS_AGE_KEY_FILE"} {
+		if p := os.Getenv(env); p != "" {
+			return p, noop, nil
+		}
+	}
+	key := os.Getenv("WATCHER_AGE_KEY")
+	if key == "" {
+		return "", noop, errors.New("age-encrypted config needs WATCHER_AGE_KEY_FILE, SOPS_AGE_KEY_FILE or WATCHER_AGE_KEY")
+	}
+	f, err := os.CreateTemp("", "watcher-age-*.key")
+	if err != nil {
+		return "", noop, err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(key + "\n"); err != nil {
+		os.Remove(f.Name())
+		return "", noop, err
+	}
+	return f.Name(), func() { os.Remove(f.Name()) }, nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// GlobSet is a compiled list of doublestar patterns. It is immutable once
+// built, so copies of an Action can share it across goroutines.
+type GlobSet struct {
+	globs []glob
+}
+
+type globKind int
+
+const (
+	globGeneric globKind = iota
+	globLiteral          // no wildcards: exact match
+	globTopExt           // "*.ext": top-level names with a suffix
+	globAnyExt           // "**/*.ext": base names at any depth with a suffix
+	globAll              // "**": everything
+)
+
+type glob struct {
+	pattern string
+	kind    globKind
+	lit     string // literal path or suffix, depending on kind
+}
+
+// CompileGlobs validates and compiles patterns.
+func CompileGlobs(patterns []string) (*GlobSet, error) {
+	set := &GlobSet{globs: make([]glob, 0, len(patterns))}
+	for _, p := range patterns {
+		p = filepath.ToSlash(p)
+		if !doublestar.ValidatePattern(p) {
+			return nil, fmt.Errorf("invalid pattern %q", p)
+		}
+		set.globs = append(set.globs, compileGlob(p))
+	}
+	return set, nil
+}
+
+func compileGlob(p string) glob {
+	const meta = `*?[{\`
+	switch {
+	case p == "**":
+		return glob{pattern: p, kind: globAll}
+	case !strings.ContainsAny(p, meta):
+		return glob{pattern: p, kind: globLiteral, lit: p}
+	case strings.HasPrefix(p, "**/*") && !strings.ContainsAny(p[4:], meta+"/"):
+		return glob{pattern: p, kind: globAnyExt, lit: p[4:]}
+	case strings.HasPrefix(p, "*") && !strings.ContainsAny(p[1:], meta+"/"):
+		return glob{pattern: p, kind: globTopExt, lit: p[1:]}
+	default:
+		return glob{pattern: p, kind: globGeneric}
+	}
+}
+
+// Patterns returns the source patterns.
+func (s *GlobSet) Patterns() []string {
+	out := make([]string, len(s.globs))
+	for i, g := range s.globs {
+		out[i] = g.pattern
+	}
+	return out
+}
+
+// Match reports whether relPath matches any pattern.
+func (s *GlobSet) Match(relPath string) bool {
+	p := filepath.ToSlash(relPath)
+	for _, g := range s.globs {
+		if g.match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g glob) match(p string) bool {
+	switch g.kind {
+	case globAll:
+		return true
+	case globLiteral:
+		return p == g.lit
+	case globTopExt:
+		return !strings.Contains(p, "/") && strings.HasSuffix(p, g.lit)
+	case globAnyExt:
+		return strings.HasSuffix(path.Base(p), g.lit)
+	default:
+		ok, _ := doublestar.Match(g.pattern, p)
+		return ok
+	}
+}
+
+// globsFor returns the precompiled set, compiling patterns on the fly for
+// values built outside Load. Invalid patterns never match.
+func globsFor(set *GlobSet, patterns []string) *GlobSet {
+	if set != nil {
+		return set
+	}
+	set, err := CompileGlobs(patterns)
+	if err != nil {
+		valid := patterns[:0:0]
+		for _, p := range patterns {
+			if doublestar.ValidatePattern(filepath.ToSlash(p)) {
+				valid = append(valid, p)
+			}
+		}
+		set, _ = CompileGlobs(valid)
+	}
+	return set
+}
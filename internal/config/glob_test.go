@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+func TestGlobFastPathsMatchDoublestar(t *testing.T) {
+	patterns := []string{"**", "*", "*.jpg", "**/*.jpg", "a/b.txt", "docs/**/*.md", "{a,b}.txt", "**/*.tar.gz"}
+	paths := []string{"a.jpg", ".jpg", "x/a.jpg", "x/y.jpg/z", "a/b.txt", "b.txt", "docs/x/y.md", "docs/y.md", "a.tar.gz", "x/a.tar.gz", "a.jpeg"}
+	for _, p := range patterns {
+		set, err := CompileGlobs([]string{p})
+		if err != nil {
+			t.Fatalf("compile %q: %v", p, err)
+		}
+		for _, path := range paths {
+			want, _ := doublestar.Match(p, path)
+			if got := set.Match(path); got != want {
+				t.Fatalf("pattern %q path %q: got %v, want %v", p, path, got, want)
+			}
+		}
+	}
+}
+
+func TestCompileGlobsRejectsInvalid(t *testing.T) {
+	if _, err := CompileGlobs([]string{"[a"}); err == nil {
+		t.Fatalf("expected invalid pattern error")
+	}
+	a := Action{Include: []string{"[a", "*.txt"}}
+	if !a.MatchesInclude("x.txt") {
+		t.Fatalf("uncompiled action should still match valid patterns")
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config schema version written by this build.
+const CurrentVersion = 1
+
+// migrations[v] upgrades a document from version v to v+1 and returns
+// human-readable notes about what changed. Version 1 is the first schema,
+// so there is nothing to upgrade yet.
+var migrations = map[int]func(doc *yaml.Node) []string{}
+
+// Migrate upgrades a parsed config document in place to CurrentVersion.
+// It returns the version the document started at and notes describing
+// each rewrite.
+func Migrate(doc *yaml.Node) (int, []string, error) {
+	root := doc
+	if root.Kind == yaml.DocumentNode {
+		if len(root.Content) == 0 {
+			return CurrentVersion, nil, nil
+		}
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return 0, nil, fmt.Errorf("config root must be a mapping")
+	}
+	from := 1
+	if v := mappingValue(root, "version"); v != nil {
+		n, err := strconv.Atoi(v.Value)
+		if err != nil || n < 1 {
+			return 0, nil, fmt.Errorf("invalid version %q", v.Value)
+		}
+		from = n
+	}
+	if from > CurrentVersion {
+		return from, nil, fmt.Errorf("config version %d is newer than supported version %d", from, CurrentVersion)
+	}
+	var notes []string
+	for v := from; v < CurrentVersion; v++ {
+		notes = append(notes, migrations[v](root)...)
+	}
+	setVersion(root, CurrentVersion)
+	return from, notes, nil
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setVersion(root *yaml.Node, v int) {
+	if n := mappingValue(root, "version"); n != nil {
+		n.Value = strconv.Itoa(v)
+		n.Tag = "!!int"
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	val := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(v)}
+	root.Content = append([]*yaml.Node{key, val}, root.Content...)
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateStampsVersion(t *testing.T) {
+	src := "global:\n  scan_interval_ms: 1500\nwatches:\n  - path: /tmp\n    debounce_ms: 1s\n"
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	from, notes, err := Migrate(&doc)
+	if err != nil || from != 1 || len(notes) != 0 {
+		t.Fatalf("unexpected migrate result: from=%d notes=%v err=%v", from, notes, err)
+	}
+	out, _ := yaml.Marshal(&doc)
+	if !strings.Contains(string(out), "version: 1") || !strings.Contains(string(out), "scan_interval_ms: 1500") {
+		t.Fatalf("unexpected migrated document:\n%s", out)
+	}
+
+	var future yaml.Node
+	yaml.Unmarshal([]byte("version: 99\n"), &future)
+	if _, _, err := Migrate(&future); err == nil {
+		t.Fatalf("expected error for newer version")
+	}
+}
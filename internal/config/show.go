@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces secrets in Display.
+const Redacted = "REDACTED"
+
+// Display renders c for `config show --effective`: every field appears,
+// zero values included, and secrets are masked. Fields tagged
+// `secret:"true"` are replaced, or for a map each of its values; fields
+// tagged `secret:"url"` keep their URL without the password.
+func (c Config) Display() (*yaml.Node, error) {
+	return displayValue(reflect.ValueOf(c), "")
+}
+
+var (
+	yamlMarshaler = reflect.TypeOf((*yaml.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func displayValue(v reflect.Value, secret string) (*yaml.Node, error) {
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	if t := v.Type(); t.Implements(yamlMarshaler) || t.Implements(textMarshaler) {
+		var n yaml.Node
+		err := n.Encode(v.Interface())
+		return &n, err
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return displayValue(v.Elem(), secret)
+	case reflect.Struct:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			val, err := displayValue(v.Field(i), f.Tag.Get("secret"))
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, val)
+		}
+		return n, nil
+	case reflect.Map:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			key, err := displayValue(k, "")
+			if err != nil {
+				return nil, err
+			}
+			val, err := displayValue(v.MapIndex(k), secret)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, key, val)
+		}
+		if len(n.Content) == 0 {
+			n.Style = yaml.FlowStyle
+		}
+		return n, nil
+	case reflect.Slice, reflect.Array:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			val, err := displayValue(v.Index(i), secret)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, val)
+		}
+		if len(n.Content) == 0 {
+			n.Style = yaml.FlowStyle
+		}
+		return n, nil
+	case reflect.String:
+		s := v.String()
+		switch {
+		case s == "":
+		case secret == "true":
+			s = Redacted
+		case secret == "url":
+			s = redactURL(s)
+		}
+		var n yaml.Node
+		err := n.Encode(s)
+		return &n, err
+	}
+	var n yaml.Node
+	err := n.Encode(v.Interface())
+	return &n, err
+}
+
+// redactURL masks the password in a URL's user info.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	u.User = url.UserPassword(u.User.Username(), Redacted)
+	return u.String()
+}
@@ -0,0 +1,189 @@
+// Package control serves a running watcher's state on a local Unix socket
+// so CLI commands such as `watcher status` can query the live process.
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/status"
+)
+
+// snapshotTimeout bounds the wait for a worker busy scanning.
+const snapshotTimeout = 30 * time.Second
+
+// SocketName is the socket's file name in the default directory.
+const SocketName = "watcher.sock"
+
+// DefaultSocket returns watcher.sock in $XDG_RUNTIME_DIR, or when that is
+// unset in a watcher-<uid> dir under the temp dir, which Listen keeps
+// private to the user.
+func DefaultSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, SocketName)
+	}
+	return filepath.Join(userTempDir(), SocketName)
+}
+
+// userTempDir is the user's socket dir under the shared temp dir.
+func userTempDir() string {
+	name := "watcher"
+	if uid := os.Getuid(); uid >= 0 {
+		name = fmt.Sprintf("watcher-%d", uid)
+	}
+	return filepath.Join(os.TempDir(), name)
+}
+
+// Resolve returns the socket path for the configured value, or false
+// when the socket is disabled.
+func Resolve(configured string) (string, bool) {
+	switch configured {
+	case "":
+		return DefaultSocket(), true
+	case config.ControlSocketNone:
+		return "", false
+	}
+	return configured, true
+}
+
+// Report is the live state returned by the status endpoint.
+type Report struct {
+	Counters map[string]status.Counter `json:"counters"`
+	Health   map[string]status.Health  `json:"health,omitempty"`
+}
+
+// Source provides the state served on the socket.
+type Source interface {
+	Status() map[string]status.Counter
+	Health() map[string]status.Health
+	Snapshot(ctx context.Context, watch string) (scanner.Saved, error)
+}
+
+// ErrInUse reports that another process already serves the socket.
+var ErrInUse = errors.New("control socket in use by another watcher")
+
+// Listen binds the socket at path, readable only by the owner. A stale
+// socket left by a crashed process is replaced. The default dir under the
+// shared temp dir must be the user's own and closed to others, so no one
+// else can reach or replace the socket.
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if dir == userTempDir() {
+		if err := checkPrivateDir(dir); err != nil {
+			return nil, err
+		}
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s: %w", path, ErrInUse)
+	}
+	// Only a stale socket is cleared; any other file at path is not ours
+	// to delete.
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve answers control requests on ln until ctx is done, then removes
+// the socket.
+func Serve(ctx context.Context, ln net.Listener, src Source) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Report{Counters: src.Status(), Health: src.Health()})
+	})
+	mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), snapshotTimeout)
+		defer cancel()
+		saved, err := src.Snapshot(ctx, r.URL.Query().Get("watch"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(saved)
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	err := srv.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// FetchStatus asks the watcher serving path for its live state.
+func FetchStatus(ctx context.Context, path string) (Report, error) {
+	var rep Report
+	err := fetch(ctx, path, "/status", &rep)
+	return rep, err
+}
+
+// FetchSnapshot asks the watcher serving path for the current snapshot
+// of the watch with the given key.
+func FetchSnapshot(ctx context.Context, path, watch string) (scanner.Saved, error) {
+	var saved scanner.Saved
+	err := fetch(ctx, path, "/snapshot?watch="+url.QueryEscape(watch), &saved)
+	return saved, err
+}
+
+func fetch(ctx context.Context, path, target string, out any) error {
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://watcher"+target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("control socket: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("control socket: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/status"
+)
+
+type fakeSource struct{}
+
+func (fakeSource) Status() map[string]status.Counter {
+	return map[string]status.Counter{"inbox": {EventsSeen: 3, ActionsOK: 2}}
+}
+
+func (fakeSource) Health() map[string]status.Health {
+	return map[string]status.Health{"inbox": {State: status.HealthOK}}
+}
+
+func (fakeSource) Snapshot(ctx context.Context, watch string) (scanner.Saved, error) {
+	if watch != "inbox" {
+		return scanner.Saved{}, fmt.Errorf("unknown watch %q", watch)
+	}
+	return scanner.Saved{Root: "/inbox", Entries: scanner.Snapshot{"/inbox/a.txt": {Size: 3}}}, nil
+}
+
+// socketPath keeps the path short; t.TempDir can exceed the socket
+// path limit.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, SocketName)
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	path := socketPath(t)
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, ln, fakeSource{}) }()
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("socket mode: %v %v", info, err)
+	}
+	if _, err := Listen(path); !errors.Is(err, ErrInUse) {
+		t.Fatalf("second listen: %v, want ErrInUse", err)
+	}
+	rep, err := FetchStatus(ctx, path)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if c := rep.Counters["inbox"]; c.EventsSeen != 3 || c.ActionsOK != 2 {
+		t.Fatalf("counters = %+v", rep.Counters)
+	}
+	if rep.Health["inbox"].State != status.HealthOK {
+		t.Fatalf("health = %+v", rep.Health)
+	}
+	saved, err := FetchSnapshot(ctx, path, "inbox")
+	if err != nil || saved.Root != "/inbox" || saved.Entries["/inbox/a.txt"].Size != 3 {
+		t.Fatalf("snapshot = %+v, %v", saved, err)
+	}
+	if _, err := FetchSnapshot(ctx, path, "other"); err == nil || !strings.Contains(err.Error(), "unknown watch") {
+		t.Fatalf("snapshot of unknown watch: %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	if _, err := FetchStatus(context.Background(), path); err == nil {
+		t.Fatalf("fetch after shutdown succeeded")
+	}
+}
+
+func TestStaleSocketIsReplaced(t *testing.T) {
+	path := socketPath(t)
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	// Leave the socket file behind, as a daemon that crashed would.
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("listen over stale socket: %v", err)
+	}
+	ln.Close()
+}
+
+func TestListenKeepsOtherFiles(t *testing.T) {
+	path := socketPath(t)
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Listen(path); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Fatalf("expected a regular file to be refused, got %v", err)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "data" {
+		t.Fatalf("file was touched: %q, %v", got, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	if _, ok := Resolve("none"); ok {
+		t.Fatalf("none should disable the socket")
+	}
+	if p, ok := Resolve("/run/w.sock"); !ok || p != "/run/w.sock" {
+		t.Fatalf("Resolve = %q %v", p, ok)
+	}
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if p, _ := Resolve(""); p != "/run/user/1000/watcher.sock" {
+		t.Fatalf("default = %q", p)
+	}
+}
+
+func TestDefaultSocketIsPrivateToUser(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the temp dir is per user on Windows")
+	}
+	tmp := filepath.Dir(socketPath(t))
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	path := DefaultSocket()
+	dir := filepath.Join(tmp, fmt.Sprintf("watcher-%d", os.Getuid()))
+	if path != filepath.Join(dir, SocketName) {
+		t.Fatalf("default = %q", path)
+	}
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ln.Close()
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0o700 {
+		t.Fatalf("socket dir mode: %v %v", info, err)
+	}
+	// A dir others can enter, or a link to one, is refused.
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	if _, err := Listen(path); err == nil {
+		t.Fatalf("expected a shared socket dir to be refused")
+	}
+	os.RemoveAll(dir)
+	if err := os.Symlink(t.TempDir(), dir); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if _, err := Listen(path); err == nil {
+		t.Fatalf("expected a symlinked socket dir to be refused")
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package control
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivateDir reports an error unless dir is a real directory owned by
+// the current user and closed to everyone else.
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("%s is owned by uid %d, not the current user", dir, st.Uid)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("%s is accessible to other users (mode %o); chmod 700 it", dir, info.Mode().Perm())
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+package control
+
+// checkPrivateDir accepts dir: the temp dir on Windows is per user.
+func checkPrivateDir(dir string) error {
+	return nil
+}
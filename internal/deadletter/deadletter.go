@@ -0,0 +1,157 @@
+// Package deadletter keeps the events whose actions failed for good in
+// JSON-lines files, so `watcher replay` can run them again once the cause
+// is fixed.
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is one failed action with the event context it ran with.
+type Entry struct {
+	Time        time.Time         `json:"time"`
+	Watch       string            `json:"watch"`
+	Action      string            `json:"action"`
+	EventID     string            `json:"event_id"`
+	Event       string            `json:"event"`
+	Path        string            `json:"path"`
+	RelPath     string            `json:"rel_path,omitempty"`
+	PrevPath    string            `json:"prev_path,omitempty"`
+	PrevRelPath string            `json:"prev_rel_path,omitempty"`
+	Size        int64             `json:"size"`
+	ModTime     time.Time         `json:"mtime"`
+	IsDir       bool              `json:"is_dir,omitempty"`
+	DeletedAt   *time.Time        `json:"deleted_at,omitempty"`
+	Meta        map[string]string `json:"meta,omitempty"`
+	// Outputs holds the values captured by the event's earlier actions.
+	Outputs    map[string]string `json:"outputs,omitempty"`
+	Attempts   int               `json:"attempts,omitempty"`
+	Error      string            `json:"error"`
+	ErrorClass string            `json:"error_class,omitempty"`
+}
+
+// mu serializes writers within the process; several actions may share a
+// file. Across processes, such as the daemon appending while replay
+// rewrites the file, writers take a lock on a file next to it.
+var mu sync.Mutex
+
+// lock takes the cross-process lock for the file at path and returns the
+// function releasing it. The lock lives in its own file because Replace
+// swaps the data file for a new one.
+func lock(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// Append adds e to the file at path, creating it and its directory.
+func Append(path string, e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries at path, oldest first. A missing file yields
+// no entries.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 4<<20)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			// A crash can leave a torn last line; skip it.
+			continue
+		}
+		out = append(out, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// Replace swaps the first n entries at path for keep, leaving entries
+// appended since they were read in place.
+func Replace(path string, n int, keep []Entry) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	current, err := Read(path)
+	if err != nil {
+		return err
+	}
+	if n > len(current) {
+		n = len(current)
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	all := append(append([]Entry(nil), keep...), current[n:]...)
+	for _, e := range all {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,58 @@
+package deadletter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReplaceKeepsEntriesAppendedSinceRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dlq", "failed.jsonl")
+	for _, id := range []string{"a", "b"} {
+		if err := Append(path, Entry{EventID: id, Action: "upload", Error: "boom"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read, err := Read(path)
+	if err != nil || len(read) != 2 {
+		t.Fatalf("read: %v %+v", err, read)
+	}
+	// Appended by a running watcher while the first two were replayed.
+	if err := Append(path, Entry{EventID: "c", Action: "upload", Error: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(path, len(read), read[1:]); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].EventID != "b" || got[1].EventID != "c" {
+		t.Fatalf("unexpected entries %+v", got)
+	}
+}
+
+func TestAppendWaitsForAnotherProcessLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	// A lock taken through its own file stands in for another process,
+	// such as replay rewriting the file.
+	unlock, err := lock(path)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- Append(path, Entry{Action: "a"}) }()
+	select {
+	case err := <-done:
+		t.Fatalf("append did not wait for the lock: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if got, _ := Read(path); len(got) != 1 {
+		t.Fatalf("expected one entry, got %+v", got)
+	}
+}
@@ -0,0 +1,81 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+// Group is a set of files with identical content, oldest first.
+type Group struct {
+	Hash  string
+	Size  int64
+	Paths []string
+}
+
+// Find hashes candidate files in snap and returns groups of duplicates.
+// Only files sharing a size with another candidate are hashed.
+func Find(root string, snap scanner.Snapshot, rule config.Dedup) ([]Group, error) {
+	bySize := map[int64][]string{}
+	for p, info := range snap {
+		if info.IsDir || info.Size < rule.MinSizeBytes || !info.Mode.IsRegular() {
+			continue
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || !rule.Matches(rel) {
+			continue
+		}
+		bySize[info.Size] = append(bySize[info.Size], p)
+	}
+	var groups []Group
+	for size, paths := range bySize {
+		if len(paths) < 2 {
+			continue
+		}
+		byHash := map[string][]string{}
+		for _, p := range paths {
+			sum, err := hashFile(snap.DiskPath(p))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			byHash[sum] = append(byHash[sum], p)
+		}
+		for sum, same := range byHash {
+			if len(same) < 2 {
+				continue
+			}
+			sort.Slice(same, func(i, j int) bool {
+				a, b := snap[same[i]].ModTime, snap[same[j]].ModTime
+				if a.Equal(b) {
+					return same[i] < same[j]
+				}
+				return a.Before(b)
+			})
+			groups = append(groups, Group{Hash: sum, Size: size, Paths: same})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Paths[0] < groups[j].Paths[0] })
+	return groups, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,34 @@
+package dedup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+func TestFindGroupsIdenticalContent(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write("a.jpg", "same")
+	write("b.jpg", "same")
+	write("c.jpg", "diff")
+	write("d.txt", "same")
+	snap, err := scanner.New(dir, true).Scan()
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	groups, err := Find(dir, snap, config.Dedup{Include: []string{"*.jpg"}})
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Paths) != 2 {
+		t.Fatalf("expected one group of two, got %#v", groups)
+	}
+}
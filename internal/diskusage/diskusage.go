@@ -0,0 +1,45 @@
+package diskusage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Usage describes capacity of the filesystem holding a path.
+type Usage struct {
+	Total uint64
+	Free  uint64
+	// Inodes and FreeInodes count file slots; both are 0 where the
+	// filesystem does not report them, e.g. on Windows.
+	Inodes     uint64
+	FreeInodes uint64
+}
+
+// FreePercent returns free space as a percentage of the total.
+func (u Usage) FreePercent() float64 {
+	if u.Total == 0 {
+		return 0
+	}
+	return float64(u.Free) / float64(u.Total) * 100
+}
+
+// Of returns usage for the filesystem containing path. Missing path
+// components are skipped so destinations that do not exist yet resolve
+// to their nearest existing parent.
+func Of(path string) (Usage, error) {
+	return stat(existingAncestor(path))
+}
+
+func existingAncestor(path string) string {
+	p := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return p
+		}
+		p = parent
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package diskusage
+
+import "syscall"
+
+func stat(path string) (Usage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return Usage{
+		Total: uint64(st.Blocks) * bsize,
+		Free:  uint64(st.Bavail) * bsize,
+
+		Inodes:     uint64(st.Files),
+		FreeInodes: uint64(st.Ffree),
+	}, nil
+}
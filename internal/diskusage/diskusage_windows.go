@@ -0,0 +1,28 @@
+//go:build windows
+
+package diskusage
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+func stat(path string) (Usage, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return Usage{}, err
+	}
+	var freeAvail, total, totalFree uint64
+	r, _, callErr := procGetDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&freeAvail)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&totalFree)),
+	)
+	if r == 0 {
+		return Usage{}, callErr
+	}
+	return Usage{Total: total, Free: freeAvail}, nil
+}
@@ -0,0 +1,23 @@
+package diskusage
+
+import "errors"
+
+// Mount describes the filesystem holding a path.
+type Mount struct {
+	// Type is the filesystem type as the OS names it, e.g. ext4, nfs,
+	// cifs or tmpfs.
+	Type     string
+	ReadOnly bool
+	// Network is set for filesystems served by another host.
+	Network bool
+}
+
+// ErrMountUnsupported is returned where the platform does not report
+// filesystem types.
+var ErrMountUnsupported = errors.New("filesystem type detection is not supported on this platform")
+
+// MountOf returns the filesystem holding path, resolving missing
+// components like Of.
+func MountOf(path string) (Mount, error) {
+	return mountOf(existingAncestor(path))
+}
@@ -0,0 +1,24 @@
+package diskusage
+
+import "syscall"
+
+// mntRdonly is MNT_RDONLY in statfs flags.
+const mntRdonly = 0x1
+
+// networkTypes are served by another host.
+var networkTypes = map[string]bool{"nfs": true, "smbfs": true, "afpfs": true, "webdav": true}
+
+func mountOf(path string) (Mount, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Mount{}, err
+	}
+	var name []byte
+	for _, c := range st.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	return Mount{Type: string(name), ReadOnly: st.Flags&mntRdonly != 0, Network: networkTypes[string(name)]}, nil
+}
@@ -0,0 +1,48 @@
+package diskusage
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// stRdonly is ST_RDONLY in statfs flags.
+const stRdonly = 0x1
+
+// fsTypes maps statfs magic numbers to filesystem names.
+var fsTypes = map[uint32]string{
+	0xEF53:     "ext4", // also ext2 and ext3
+	0x58465342: "xfs",
+	0x9123683E: "btrfs",
+	0x2FC12FC1: "zfs",
+	0xF2F52010: "f2fs",
+	0x01021994: "tmpfs",
+	0x858458F6: "ramfs",
+	0x794C7630: "overlay",
+	0x73717368: "squashfs",
+	0x4D44:     "vfat",
+	0x2011BAB0: "exfat",
+	0x5346544E: "ntfs",
+	0x65735546: "fuse",
+	0x6969:     "nfs",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x00C36400: "ceph",
+	0x5346414F: "afs",
+	0x01021997: "9p",
+}
+
+// networkTypes are served by another host.
+var networkTypes = map[string]bool{"nfs": true, "cifs": true, "smb2": true, "ceph": true, "afs": true, "9p": true}
+
+func mountOf(path string) (Mount, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Mount{}, err
+	}
+	magic := uint32(st.Type)
+	name, ok := fsTypes[magic]
+	if !ok {
+		name = fmt.Sprintf("0x%x", magic)
+	}
+	return Mount{Type: name, ReadOnly: st.Flags&stRdonly != 0, Network: networkTypes[name]}, nil
+}
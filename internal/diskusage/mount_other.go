@@ -0,0 +1,7 @@
+//go:build !linux && !darwin
+
+package diskusage
+
+func mountOf(string) (Mount, error) {
+	return Mount{}, ErrMountUnsupported
+}
@@ -0,0 +1,179 @@
+// Package enrich attaches fields to events before actions are matched:
+// built-in stat, hash and MIME fields, or whatever a command prints as a
+// JSON object.
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/mimetype"
+	"watcher-cli/internal/template"
+)
+
+// defaultTimeout bounds an exec enricher without timeout_ms.
+const defaultTimeout = 10 * time.Second
+
+// maxOutput bounds what an exec enricher may print.
+const maxOutput = 1 << 20
+
+// validName matches field names usable as {field:NAME} tokens.
+var validName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Event is the file being enriched.
+type Event struct {
+	Path    string
+	RelPath string
+	Type    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Fields runs the enrichers in order and returns the fields they added;
+// later enrichers overwrite earlier ones. A failing enricher adds nothing
+// and its error is returned alongside the other fields.
+func Fields(ctx context.Context, ev Event, enrichers []config.Enricher) (map[string]string, error) {
+	out := map[string]string{}
+	var errs []error
+	for _, e := range enrichers {
+		var err error
+		switch e.Type {
+		case config.EnrichStat:
+			err = stat(ev.Path, out)
+		case config.EnrichHash:
+			err = digest(ev.Path, e.Algorithm, out)
+		case config.EnrichMIME:
+			mt := mimetype.Detect(ev.Path)
+			out["mime"] = mt
+			out["mime_kind"] = mimetype.Kind(mt)
+		case config.EnrichExec:
+			err = run(ctx, ev, e, out)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s enricher: %w", e.Type, err))
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+func stat(path string, out map[string]string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	out["mode"] = fmt.Sprintf("%04o", info.Mode().Perm())
+	statFields(info, out)
+	return nil
+}
+
+func digest(path, algorithm string, out map[string]string) error {
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	var h hash.Hash
+	switch algorithm {
+	case "sha1":
+		h = sha1.New()
+	case "md5":
+		h = md5.New()
+	default:
+		h = sha256.New()
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	out[algorithm] = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// run executes an exec enricher and adds the members of the JSON object it
+// prints. Strings are taken as they are, other values in their JSON form;
+// members whose names cannot be used in templates are skipped.
+func run(ctx context.Context, ev Event, e config.Enricher, out map[string]string) error {
+	timeout := e.Timeout.Duration()
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	tctx := template.Context{Path: ev.Path, RelPath: ev.RelPath, Event: ev.Type, Size: ev.Size, ModTime: ev.ModTime}
+	var parts []string
+	if len(e.Cmd.Args) > 0 {
+		for _, arg := range e.Cmd.Args {
+			parts = append(parts, template.Expand(arg, tctx))
+		}
+	} else {
+		fields, err := template.Fields(e.Cmd.Line)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			parts = append(parts, template.Expand(f, tctx))
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd.Env = append(os.Environ(),
+		"WATCHER_EVENT="+ev.Type,
+		"WATCHER_PATH="+ev.Path,
+		"WATCHER_RELPATH="+ev.RelPath,
+		"WATCHER_SIZE="+strconv.FormatInt(ev.Size, 10),
+	)
+	var stdout limitedBuffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(stdout.Bytes(), &obj); err != nil {
+		return fmt.Errorf("output is not a JSON object: %w", err)
+	}
+	for name, raw := range obj {
+		if !validName.MatchString(name) {
+			continue
+		}
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			out[name] = s
+		} else {
+			out[name] = string(bytes.TrimSpace(raw))
+		}
+	}
+	return nil
+}
+
+// limitedBuffer keeps the first maxOutput bytes written to it.
+type limitedBuffer struct{ bytes.Buffer }
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - b.Len(); room < len(p) {
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
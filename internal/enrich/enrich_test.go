@@ -0,0 +1,59 @@
+package enrich
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestFieldsFromBuiltinsAndExec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o640); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	fields, err := Fields(context.Background(), Event{Path: path, RelPath: "a.txt", Type: "create", Size: 5}, []config.Enricher{
+		{Type: config.EnrichStat},
+		{Type: config.EnrichHash},
+		{Type: config.EnrichMIME},
+		{Type: config.EnrichExec, Cmd: config.Command{Args: []string{"sh", "-c", `printf '{"label":"{name}","pages":3,"bad key":1}'`}}},
+	})
+	if err != nil {
+		t.Fatalf("fields: %v", err)
+	}
+	want := map[string]string{
+		"mode":      "0640",
+		"sha256":    "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"mime":      "text/plain",
+		"mime_kind": "document",
+		"label":     "a.txt",
+		"pages":     "3",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Fatalf("field %s = %q, want %q (all %v)", k, fields[k], v, fields)
+		}
+	}
+	if _, ok := fields["bad key"]; ok {
+		t.Fatalf("unusable field name kept: %v", fields)
+	}
+}
+
+func TestFailingEnricherKeepsOtherFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	fields, err := Fields(context.Background(), Event{Path: path}, []config.Enricher{
+		{Type: config.EnrichExec, Cmd: config.Command{Args: []string{"sh", "-c", "echo not json"}}},
+		{Type: config.EnrichHash, Algorithm: "md5"},
+	})
+	if err == nil {
+		t.Fatalf("expected an error for non-JSON output")
+	}
+	if fields["md5"] != "9dd4e461268c8034f5c8564e155c67a6" {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+}
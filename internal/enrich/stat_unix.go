@@ -0,0 +1,21 @@
+//go:build !windows
+
+package enrich
+
+import (
+	"io/fs"
+	"strconv"
+	"syscall"
+)
+
+// statFields adds the owner, link count and inode the platform reports.
+func statFields(info fs.FileInfo, out map[string]string) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	out["uid"] = strconv.FormatUint(uint64(st.Uid), 10)
+	out["gid"] = strconv.FormatUint(uint64(st.Gid), 10)
+	out["nlink"] = strconv.FormatUint(uint64(st.Nlink), 10)
+	out["inode"] = strconv.FormatUint(uint64(st.Ino), 10)
+}
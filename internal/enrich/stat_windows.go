@@ -0,0 +1,6 @@
+package enrich
+
+import "io/fs"
+
+// statFields adds nothing beyond the mode on Windows.
+func statFields(fs.FileInfo, map[string]string) {}
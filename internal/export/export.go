@@ -0,0 +1,168 @@
+// Package export appends watcher activity to rotating CSV files so it can
+// be loaded into pandas, Spark or a spreadsheet without a database.
+package export
+
+import (
+	"encoding/csv"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/rotate"
+)
+
+// current is the file being appended to; rotated files get a timestamp.
+const current = "events.csv"
+
+var header = []string{"time", "kind", "event_id", "watch", "event", "path", "size", "action", "status", "duration_ms", "error", "error_class"}
+
+// Status values of action rows.
+const (
+	StatusOK     = "ok"
+	StatusSkip   = "skip"
+	StatusError  = "error"
+	StatusDryRun = "dry_run"
+)
+
+// Sink writes one row per event and per action result. A nil Sink drops
+// everything. Write errors are logged, never returned, so exporting
+// cannot fail actions.
+type Sink struct {
+	cfg config.Export
+	now func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	csv  *csv.Writer
+	size int64
+}
+
+// New returns a sink for cfg, or nil when export is not configured.
+func New(cfg *config.Export) *Sink {
+	if cfg == nil {
+		return nil
+	}
+	return &Sink{cfg: *cfg, now: time.Now}
+}
+
+// Event records a detected event.
+func (s *Sink) Event(watch, id, event, path string, size int64) {
+	if s == nil {
+		return
+	}
+	s.write([]string{"event", id, watch, event, path, strconv.FormatInt(size, 10), "", "", "", "", ""})
+}
+
+// Action records an action result; status is one of the Status values
+// and class the error's class.
+func (s *Sink) Action(watch, id, event, path, action, status string, took time.Duration, err error, class config.ErrorClass) {
+	if s == nil {
+		return
+	}
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	}
+	s.write([]string{"action", id, watch, event, path, "", action, status, strconv.FormatInt(took.Milliseconds(), 10), msg, string(class)})
+}
+
+// Handled records the outcome of all actions of an event, listing the
+// failed ones in the error column.
+func (s *Sink) Handled(watch, id, event, path, outcome string, took time.Duration, failed []string) {
+	if s == nil {
+		return
+	}
+	s.write([]string{"handled", id, watch, event, path, "", "", outcome, strconv.FormatInt(took.Milliseconds(), 10), strings.Join(failed, ";"), ""})
+}
+
+// Close flushes and closes the current file.
+func (s *Sink) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeFile()
+}
+
+func (s *Sink) write(row []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(append([]string{s.now().UTC().Format(time.RFC3339Nano)}, row...)); err != nil {
+		slog.Default().Error("export", "dir", s.cfg.Dir, "err", err)
+	}
+}
+
+func (s *Sink) append(row []string) error {
+	if s.file != nil && s.size >= s.cfg.MaxSizeBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	if err := s.csv.Write(row); err != nil {
+		return err
+	}
+	// Flush per row so a crash loses nothing and size stays accurate.
+	s.csv.Flush()
+	if err := s.csv.Error(); err != nil {
+		return err
+	}
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	s.size = info.Size()
+	return nil
+}
+
+func (s *Sink) open() error {
+	if err := os.MkdirAll(s.cfg.Dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(s.cfg.Dir, current), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file, s.csv, s.size = f, csv.NewWriter(f), info.Size()
+	if s.size == 0 {
+		return s.csv.Write(header)
+	}
+	return nil
+}
+
+func (s *Sink) closeFile() error {
+	if s.file == nil {
+		return nil
+	}
+	s.csv.Flush()
+	err := s.csv.Error()
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	s.file, s.csv = nil, nil
+	return err
+}
+
+// rotate renames the current file with a timestamp and drops the oldest
+// rotated files beyond MaxFiles.
+func (s *Sink) rotate() error {
+	if err := s.closeFile(); err != nil {
+		return err
+	}
+	return rotate.File(filepath.Join(s.cfg.Dir, current), s.now(), s.cfg.MaxFiles)
+}
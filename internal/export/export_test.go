@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+func TestSinkWritesRowsWithHeader(t *testing.T) {
+	dir := t.TempDir()
+	s := New(&config.Export{Dir: dir, MaxSizeBytes: 1 << 20, MaxFiles: 2})
+	s.Event("/in", "e1", "create", "/in/a.txt", 3)
+	s.Action("/in", "e1", "create", "/in/a.txt", "copy", StatusError, 1500*time.Millisecond, errors.New("disk full"), config.ClassQuota)
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	rows := readCSV(t, filepath.Join(dir, current))
+	if len(rows) != 3 || rows[0][0] != "time" {
+		t.Fatalf("expected header and two rows, got %v", rows)
+	}
+	if got := rows[2][1:]; got[0] != "action" || got[6] != "copy" || got[7] != StatusError || got[8] != "1500" || got[9] != "disk full" || got[10] != "quota" {
+		t.Fatalf("unexpected action row %v", got)
+	}
+
+	// Reopening appends without a second header.
+	s = New(&config.Export{Dir: dir, MaxSizeBytes: 1 << 20, MaxFiles: 2})
+	s.Event("/in", "e2", "delete", "/in/a.txt", 0)
+	s.Close()
+	if rows := readCSV(t, filepath.Join(dir, current)); len(rows) != 4 {
+		t.Fatalf("expected appended row, got %v", rows)
+	}
+}
+
+func TestSinkRotatesAndKeepsMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	s := New(&config.Export{Dir: dir, MaxSizeBytes: 1, MaxFiles: 2})
+	tick := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { tick = tick.Add(time.Second); return tick }
+	for i := 0; i < 5; i++ {
+		s.Event("/in", "e", "create", "/in/a.txt", 1)
+	}
+	s.Close()
+	rotated, _ := filepath.Glob(filepath.Join(dir, "events-*.csv"))
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v", rotated)
+	}
+	if rows := readCSV(t, filepath.Join(dir, current)); len(rows) != 2 {
+		t.Fatalf("expected header and one row in the current file, got %v", rows)
+	}
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return rows
+}
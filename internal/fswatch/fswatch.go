@@ -0,0 +1,43 @@
+// Package fswatch wakes a watch when the operating system reports a change
+// below its root. It carries no event details; callers rescan to find out
+// what changed.
+package fswatch
+
+import "errors"
+
+// ErrUnsupported is returned where no native backend exists.
+var ErrUnsupported = errors.New("native file notifications are not supported on this platform")
+
+// Notifier signals on C after changes below its root. Signals are coalesced:
+// C holds at most one pending value.
+type Notifier struct {
+	C <-chan struct{}
+
+	c    chan struct{}
+	stop func() error
+}
+
+// New starts native notifications for root, including subdirectories when
+// recursive is set.
+func New(root string, recursive bool) (*Notifier, error) {
+	c := make(chan struct{}, 1)
+	n := &Notifier{C: c, c: c}
+	stop, err := start(root, recursive, n.signal)
+	if err != nil {
+		return nil, err
+	}
+	n.stop = stop
+	return n, nil
+}
+
+// Close stops notifications.
+func (n *Notifier) Close() error {
+	return n.stop()
+}
+
+func (n *Notifier) signal() {
+	select {
+	case n.c <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,123 @@
+//go:build linux
+
+package fswatch
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const watchMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY |
+	syscall.IN_CLOSE_WRITE | syscall.IN_ATTRIB | syscall.IN_MOVED_FROM |
+	syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+type inotify struct {
+	file      *os.File
+	fd        int
+	recursive bool
+
+	mu   sync.Mutex
+	dirs map[int32]string
+}
+
+func start(root string, recursive bool, signal func()) (func() error, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	// A non-blocking descriptor goes through the runtime poller, so Close
+	// unblocks the pending Read.
+	in := &inotify{
+		file:      os.NewFile(uintptr(fd), "inotify"),
+		fd:        fd,
+		recursive: recursive,
+		dirs:      map[int32]string{},
+	}
+	if err := in.add(root); err != nil {
+		in.file.Close()
+		return nil, err
+	}
+	go in.loop(signal)
+	return in.file.Close, nil
+}
+
+// add watches dir and, when recursive, every directory below it.
+func (in *inotify) add(dir string) error {
+	if !in.recursive {
+		return in.addOne(dir)
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := in.addOne(path); err != nil && path == dir {
+			return err
+		}
+		return nil
+	})
+}
+
+func (in *inotify) addOne(dir string) error {
+	wd, err := syscall.InotifyAddWatch(in.fd, dir, watchMask)
+	if err != nil {
+		return &os.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+	}
+	in.mu.Lock()
+	in.dirs[int32(wd)] = dir
+	in.mu.Unlock()
+	return nil
+}
+
+func (in *inotify) loop(signal func()) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := in.file.Read(buf)
+		if err != nil {
+			return
+		}
+		changed := false
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			name := buf[off+syscall.SizeofInotifyEvent : off+syscall.SizeofInotifyEvent+int(ev.Len)]
+			off += syscall.SizeofInotifyEvent + int(ev.Len)
+			if ev.Mask&syscall.IN_IGNORED != 0 {
+				in.mu.Lock()
+				delete(in.dirs, ev.Wd)
+				in.mu.Unlock()
+				continue
+			}
+			changed = true
+			if in.recursive && ev.Mask&syscall.IN_ISDIR != 0 && ev.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+				in.mu.Lock()
+				parent, ok := in.dirs[ev.Wd]
+				in.mu.Unlock()
+				if ok {
+					in.add(filepath.Join(parent, cString(name)))
+				}
+			}
+		}
+		if changed {
+			signal()
+		}
+	}
+}
+
+// cString trims the NUL padding inotify appends to names.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package fswatch
+
+func start(root string, recursive bool, signal func()) (func() error, error) {
+	return nil, ErrUnsupported
+}
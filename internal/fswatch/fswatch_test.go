@@ -0,0 +1,44 @@
+package fswatch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotifierSignalsNestedChanges(t *testing.T) {
+	root := t.TempDir()
+	n, err := New(root, true)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer n.Close()
+
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wait(t, n)
+	// The new directory is watched once its create has been seen.
+	if err := os.WriteFile(filepath.Join(root, "sub", "a.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wait(t, n)
+}
+
+func wait(t *testing.T, n *Notifier) {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case <-n.C:
+			return
+		case <-deadline:
+			t.Fatalf("no notification")
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package fsys
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// CaseInsensitive reports whether the filesystem holding dir matches names
+// regardless of case. It looks dir up with the case of its last element
+// containing letters swapped; without such an element it goes by the
+// platform's usual filesystems.
+func CaseInsensitive(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return defaultCaseInsensitive()
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return defaultCaseInsensitive()
+	}
+	var rest string
+	for p := abs; ; {
+		base := filepath.Base(p)
+		if swapped := swapCase(base); swapped != base {
+			alt, err := os.Stat(filepath.Join(filepath.Dir(p), swapped, rest))
+			return err == nil && os.SameFile(info, alt)
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return defaultCaseInsensitive()
+		}
+		rest = filepath.Join(base, rest)
+		p = parent
+	}
+}
+
+func defaultCaseInsensitive() bool {
+	return runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
+
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsUpper(r):
+			return unicode.ToLower(r)
+		case unicode.IsLower(r):
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, s)
+}
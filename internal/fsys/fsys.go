@@ -0,0 +1,99 @@
+// Package fsys abstracts the filesystem operations used by the scanner and
+// file actions, so they can run against an in-memory filesystem in tests
+// and, later, against virtual backends without OS mounts.
+package fsys
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// File is an open file.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Closer
+	Stat() (fs.FileInfo, error)
+	Sync() error
+}
+
+// FS is the set of filesystem operations watchers and actions need.
+type FS interface {
+	Open(name string) (File, error)
+	// Create truncates or creates name for writing.
+	Create(name string) (File, error)
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	// ReadDir returns the entries of a directory sorted by name.
+	ReadDir(name string) ([]fs.DirEntry, error)
+	MkdirAll(path string, perm fs.FileMode) error
+	Remove(name string) error
+	Rename(oldpath, newpath string) error
+}
+
+// OS is the local filesystem.
+var OS FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) Open(name string) (File, error)               { return os.Open(name) }
+func (osFS) Create(name string) (File, error)             { return os.Create(name) }
+func (osFS) Stat(name string) (fs.FileInfo, error)        { return os.Stat(name) }
+func (osFS) Lstat(name string) (fs.FileInfo, error)       { return os.Lstat(name) }
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error)   { return os.ReadDir(name) }
+func (osFS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+func (osFS) Remove(name string) error                     { return os.Remove(name) }
+func (osFS) Rename(oldpath, newpath string) error         { return os.Rename(oldpath, newpath) }
+
+// IsOS reports whether fsys is the local filesystem (or nil, which callers
+// treat as OS).
+func IsOS(fsys FS) bool {
+	return fsys == nil || fsys == OS
+}
+
+// WalkDir walks root like filepath.WalkDir, using fsys. Root is visited
+// first; fn may return filepath.SkipDir to skip a directory.
+func WalkDir(fsys FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Lstat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	err = walk(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	if errors.Is(err, filepath.SkipDir) || errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func walk(fsys FS, path string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if errors.Is(err, filepath.SkipDir) && d.IsDir() {
+			return nil
+		}
+		return err
+	}
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		if err := fn(path, d, err); err != nil {
+			if errors.Is(err, filepath.SkipDir) {
+				return nil
+			}
+			return err
+		}
+	}
+	for _, e := range entries {
+		if err := walk(fsys, filepath.Join(path, e.Name()), e, fn); err != nil {
+			if errors.Is(err, filepath.SkipDir) {
+				if e.IsDir() {
+					continue
+				}
+				return nil // skip remaining entries of this directory
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package fsys
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mem is an in-memory filesystem. Paths are cleaned with filepath.Clean;
+// the root ("/" or the volume root) always exists. It is safe for
+// concurrent use.
+type Mem struct {
+	mu    sync.Mutex
+	nodes map[string]*memNode
+	// Now stamps modification times; defaults to time.Now.
+	Now func() time.Time
+}
+
+type memNode struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMem returns an empty in-memory filesystem.
+func NewMem() *Mem {
+	return &Mem{nodes: map[string]*memNode{}, Now: time.Now}
+}
+
+// WriteFile creates name and its parent directories with data.
+func (m *Mem) WriteFile(name string, data []byte) error {
+	if err := m.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodes[filepath.Clean(name)] = &memNode{data: append([]byte(nil), data...), mode: 0o644, modTime: m.Now()}
+	return nil
+}
+
+// ReadFile returns the content of name.
+func (m *Mem) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, err := m.file(name, "read")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), n.data...), nil
+}
+
+// Chtimes sets the modification time of name.
+func (m *Mem) Chtimes(name string, mtime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nodes[filepath.Clean(name)]
+	if !ok {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrNotExist}
+	}
+	n.modTime = mtime
+	return nil
+}
+
+func (m *Mem) Open(name string) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, err := m.file(name, "open")
+	if err != nil {
+		return nil, err
+	}
+	return &memFile{m: m, name: filepath.Clean(name), r: bytes.NewReader(n.data)}, nil
+}
+
+func (m *Mem) Create(name string) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if !m.isDir(filepath.Dir(name)) {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrNotExist}
+	}
+	if n, ok := m.nodes[name]; ok && n.mode.IsDir() {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+	m.nodes[name] = &memNode{mode: 0o644, modTime: m.Now()}
+	return &memFile{m: m, name: name, writable: true}, nil
+}
+
+func (m *Mem) Stat(name string) (fs.FileInfo, error) {
+	return m.Lstat(name)
+}
+
+func (m *Mem) Lstat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stat(name)
+}
+
+func (m *Mem) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if !m.isDir(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	var out []fs.DirEntry
+	for p := range m.nodes {
+		if p != name && filepath.Dir(p) == name {
+			info, _ := m.stat(p)
+			out = append(out, fs.FileInfoToDirEntry(info))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+func (m *Mem) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path = filepath.Clean(path)
+	for p := path; !m.isRoot(p); p = filepath.Dir(p) {
+		if n, ok := m.nodes[p]; ok {
+			if !n.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: p, Err: fs.ErrExist}
+			}
+			continue
+		}
+		m.nodes[p] = &memNode{mode: fs.ModeDir | perm, modTime: m.Now()}
+	}
+	return nil
+}
+
+func (m *Mem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	n, ok := m.nodes[name]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if n.mode.IsDir() {
+		for p := range m.nodes {
+			if strings.HasPrefix(p, name+string(filepath.Separator)) {
+				return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrExist}
+			}
+		}
+	}
+	delete(m.nodes, name)
+	return nil
+}
+
+func (m *Mem) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	n, ok := m.nodes[oldpath]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	if !m.isDir(filepath.Dir(newpath)) {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	delete(m.nodes, oldpath)
+	m.nodes[newpath] = n
+	prefix := oldpath + string(filepath.Separator)
+	for p, child := range m.nodes {
+		if strings.HasPrefix(p, prefix) {
+			delete(m.nodes, p)
+			m.nodes[newpath+string(filepath.Separator)+strings.TrimPrefix(p, prefix)] = child
+		}
+	}
+	return nil
+}
+
+func (m *Mem) file(name, op string) (*memNode, error) {
+	n, ok := m.nodes[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if n.mode.IsDir() {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return n, nil
+}
+
+func (m *Mem) stat(name string) (fs.FileInfo, error) {
+	name = filepath.Clean(name)
+	if m.isRoot(name) {
+		return memInfo{name: name, mode: fs.ModeDir | 0o755}, nil
+	}
+	n, ok := m.nodes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return memInfo{name: filepath.Base(name), size: int64(len(n.data)), mode: n.mode, modTime: n.modTime}, nil
+}
+
+func (m *Mem) isRoot(p string) bool {
+	return filepath.Dir(p) == p
+}
+
+func (m *Mem) isDir(p string) bool {
+	if m.isRoot(p) {
+		return true
+	}
+	n, ok := m.nodes[p]
+	return ok && n.mode.IsDir()
+}
+
+type memFile struct {
+	m        *Mem
+	name     string
+	r        *bytes.Reader
+	buf      bytes.Buffer
+	writable bool
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.r == nil {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrInvalid}
+	}
+	return f.r.Read(p)
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	if !f.writable {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+	}
+	return f.buf.Write(p)
+}
+
+// Sync publishes written data to the filesystem.
+func (f *memFile) Sync() error {
+	if !f.writable {
+		return nil
+	}
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if n, ok := f.m.nodes[f.name]; ok {
+		n.data = append(n.data[:0], f.buf.Bytes()...)
+		n.modTime = f.m.Now()
+	}
+	return nil
+}
+
+func (f *memFile) Close() error {
+	return f.Sync()
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	return f.m.Lstat(f.name)
+}
+
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() interface{}   { return nil }
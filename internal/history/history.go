@@ -0,0 +1,249 @@
+// Package history records which actions ran for each file, so repeat
+// events can skip one-time processing and operators can audit a file.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"watcher-cli/internal/rotate"
+)
+
+// FileName is the history file inside the state dir.
+const FileName = "history.jsonl"
+
+// maxPerPath bounds the entries kept in memory per file, and on disk when
+// the history is compacted at startup.
+const maxPerPath = 50
+
+// Entry is one finished action for a file, or, with Outcome set and no
+// Action, the summary of all actions of one event.
+type Entry struct {
+	Path    string    `json:"path"`
+	Time    time.Time `json:"time"`
+	Watch   string    `json:"watch"`
+	Action  string    `json:"action"`
+	EventID string    `json:"event_id"`
+	Event   string    `json:"event"`
+	OK      bool      `json:"ok"`
+	Error   string    `json:"error,omitempty"`
+	// ErrorClass is the class of Error, e.g. "permission".
+	ErrorClass string `json:"error_class,omitempty"`
+	// DurationMS is how long the action, or all actions of the event,
+	// took.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+	// Outcome is "handled", "partially_failed" or "failed" and Failed
+	// lists the failed actions of an event summary.
+	Outcome string   `json:"outcome,omitempty"`
+	Failed  []string `json:"failed,omitempty"`
+}
+
+// Rotation bounds the history on disk. Once the file reaches MaxSizeBytes
+// it is renamed with a timestamp and a new one started; the latest
+// MaxFiles renamed files are kept. Zero MaxSizeBytes never rotates.
+type Rotation struct {
+	MaxSizeBytes int64
+	MaxFiles     int
+}
+
+// Store appends entries to a JSON-lines file and answers lookups from
+// memory. A nil Store records nothing.
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	rot    Rotation
+	size   int64
+	byPath map[string][]Entry
+}
+
+// Open loads the history at path and its rotated files, compacting the
+// current file to the latest entries per file.
+func Open(path string, rot Rotation) (*Store, error) {
+	byPath, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, rot: rot, byPath: byPath}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	s.size = info.Size()
+	// Lookups also see rotated entries, so not_previously_run holds
+	// across a rotation.
+	files, err := rotate.List(path)
+	if err != nil {
+		return nil, err
+	}
+	older := map[string][]Entry{}
+	for _, f := range files {
+		entries, err := readFile(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			older[e.Path] = append(older[e.Path], e)
+		}
+	}
+	for p, entries := range older {
+		entries = append(entries, s.byPath[p]...)
+		if len(entries) > maxPerPath {
+			entries = entries[len(entries)-maxPerPath:]
+		}
+		s.byPath[p] = entries
+	}
+	return s, nil
+}
+
+// Record appends e.
+func (s *Store) Record(e Entry) error {
+	if s == nil {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := append(s.byPath[e.Path], e)
+	if len(entries) > maxPerPath {
+		entries = slices.Clone(entries[len(entries)-maxPerPath:])
+	}
+	s.byPath[e.Path] = entries
+	if s.rot.MaxSizeBytes > 0 && s.size >= s.rot.MaxSizeBytes {
+		if err := rotate.File(s.path, time.Now(), s.rot.MaxFiles); err != nil {
+			return err
+		}
+		s.size = 0
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	s.size += int64(len(line)) + 1
+	return f.Close()
+}
+
+// Succeeded reports whether action has completed successfully for path.
+func (s *Store) Succeeded(path, action string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.byPath[path] {
+		if e.OK && e.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) compact() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for p, entries := range s.byPath {
+		if len(entries) > maxPerPath {
+			entries = entries[len(entries)-maxPerPath:]
+			s.byPath[p] = entries
+		}
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				f.Close()
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Read loads the history at path grouped by file, oldest first. A missing
+// file yields no entries.
+func Read(path string) (map[string][]Entry, error) {
+	entries, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string][]Entry{}
+	for _, e := range entries {
+		out[e.Path] = append(out[e.Path], e)
+	}
+	return out, nil
+}
+
+// ReadAll loads the history at path and its rotated files, ordered by
+// time.
+func ReadAll(path string) ([]Entry, error) {
+	files, err := rotate.List(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []Entry
+	for _, f := range append(files, path) {
+		entries, err := readFile(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, entries...)
+	}
+	// Compaction groups the current file by path.
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out, nil
+}
+
+func readFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			// A crash can leave a torn last line; skip it.
+			continue
+		}
+		out = append(out, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return out, nil
+}
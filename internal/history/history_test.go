@@ -0,0 +1,100 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreRecordsAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	s, err := Open(path, Rotation{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	now := time.Now()
+	s.Record(Entry{Path: "/w/a.jpg", Time: now, Action: "thumb", OK: false, Error: "boom"})
+	if s.Succeeded("/w/a.jpg", "thumb") {
+		t.Fatalf("failed runs should not count")
+	}
+	s.Record(Entry{Path: "/w/a.jpg", Time: now, Action: "thumb", OK: true})
+	if !s.Succeeded("/w/a.jpg", "thumb") || s.Succeeded("/w/b.jpg", "thumb") {
+		t.Fatalf("unexpected lookup results")
+	}
+
+	// A torn last line from a crash is skipped on reload.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString(`{"path":"/w/a.jpg","act`)
+	f.Close()
+	reopened, err := Open(path, Rotation{})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if !reopened.Succeeded("/w/a.jpg", "thumb") {
+		t.Fatalf("history lost on reload")
+	}
+	byPath, err := Read(path)
+	if err != nil || len(byPath["/w/a.jpg"]) != 2 {
+		t.Fatalf("expected 2 entries after compaction, got %v (%v)", byPath, err)
+	}
+}
+
+func TestOpenKeepsLatestEntriesPerPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	s, err := Open(path, Rotation{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for i := 0; i < maxPerPath+5; i++ {
+		s.Record(Entry{Path: "/w/a", Action: "a", EventID: string(rune('a' + i%26)), OK: i == maxPerPath+4})
+	}
+	// Memory stays bounded while running, not only after a restart.
+	if n := len(s.byPath["/w/a"]); n != maxPerPath {
+		t.Fatalf("expected %d entries in memory, got %d", maxPerPath, n)
+	}
+	if _, err := Open(path, Rotation{}); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	byPath, _ := Read(path)
+	entries := byPath["/w/a"]
+	if len(entries) != maxPerPath || !entries[len(entries)-1].OK {
+		t.Fatalf("expected the latest %d entries, got %d", maxPerPath, len(entries))
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	if err := s.Record(Entry{Path: "/w/a"}); err != nil || s.Succeeded("/w/a", "x") {
+		t.Fatalf("nil store should record nothing")
+	}
+}
+
+func TestStoreRotatesAndKeepsLookups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	s, err := Open(path, Rotation{MaxSizeBytes: 1, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		if err := s.Record(Entry{Path: "/w/" + string(rune('a'+i)), Time: start.Add(time.Duration(i) * time.Second), Action: "up", OK: true, DurationMS: 12}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "history-*.jsonl"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v", files)
+	}
+	all, err := ReadAll(path)
+	if err != nil || len(all) != 3 || all[0].Path != "/w/b" || all[2].Path != "/w/d" || all[2].DurationMS != 12 {
+		t.Fatalf("expected the latest 3 entries in order, got %+v (%v)", all, err)
+	}
+	reopened, err := Open(path, Rotation{MaxSizeBytes: 1, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if !reopened.Succeeded("/w/b", "up") || reopened.Succeeded("/w/a", "up") {
+		t.Fatalf("lookups should cover kept rotated files only")
+	}
+}
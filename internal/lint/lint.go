@@ -0,0 +1,197 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/diskusage"
+)
+
+// Finding is a potential configuration problem.
+type Finding struct {
+	Watch   string
+	Action  string
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.Action == "" {
+		return fmt.Sprintf("watch %s: %s", f.Watch, f.Message)
+	}
+	return fmt.Sprintf("watch %s action %s: %s", f.Watch, f.Action, f.Message)
+}
+
+// Check looks for overlapping, shadowed and self-cancelling actions.
+// Glob overlap is judged from representative sample paths generated from
+// each include pattern, so results are heuristics rather than proofs.
+func Check(cfg config.Config) []Finding {
+	var out []Finding
+	for _, w := range cfg.Watches {
+		for i := range w.Actions {
+			a := &w.Actions[i]
+			if cancelsAll(a) {
+				out = append(out, Finding{Watch: w.Key(), Action: a.Name, Message: "exclude patterns cancel every include; action never runs"})
+			}
+		}
+		for i := range w.Actions {
+			for j := i + 1; j < len(w.Actions); j++ {
+				a, b := &w.Actions[i], &w.Actions[j]
+				shared := sharedEvents(a, b)
+				if len(shared) == 0 || !overlaps(a, b) {
+					continue
+				}
+				if w.StopOnFirstMatch {
+					out = append(out, Finding{Watch: w.Key(), Action: b.Name, Message: fmt.Sprintf("shadowed by %s for %s events with stop_on_first_match; action order decides which runs", a.Name, strings.Join(shared, ","))})
+					continue
+				}
+				out = append(out, Finding{Watch: w.Key(), Action: b.Name, Message: fmt.Sprintf("include patterns overlap with %s on %s events; both run for the same files", a.Name, strings.Join(shared, ","))})
+			}
+		}
+	}
+	return out
+}
+
+func sharedEvents(a, b *config.Action) []string {
+	var out []string
+	for _, ea := range a.Events {
+		for _, eb := range b.Events {
+			if ea == eb {
+				out = append(out, string(ea))
+			}
+		}
+	}
+	return out
+}
+
+// overlaps reports whether some sample path selected by one action is also
+// selected by the other.
+func overlaps(a, b *config.Action) bool {
+	for _, p := range samplesFor(a) {
+		if selects(b, p) && selects(a, p) {
+			return true
+		}
+	}
+	for _, p := range samplesFor(b) {
+		if selects(a, p) && selects(b, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func cancelsAll(a *config.Action) bool {
+	if len(a.Exclude) == 0 {
+		return false
+	}
+	for _, p := range samplesFor(a) {
+		if !a.MatchesExclude(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func selects(a *config.Action, relPath string) bool {
+	return a.MatchesInclude(relPath) && !a.MatchesExclude(relPath)
+}
+
+func samplesFor(a *config.Action) []string {
+	if len(a.Include) == 0 {
+		return []string{"x", "d/x"}
+	}
+	var out []string
+	for _, p := range a.Include {
+		out = append(out, Samples(p)...)
+	}
+	return out
+}
+
+// Samples returns concrete relative paths matched by a doublestar pattern:
+// one with ** collapsed and one with ** expanded to a directory.
+func Samples(pattern string) []string {
+	collapsed := strings.NewReplacer("**/", "", "/**", "/x", "**", "x").Replace(pattern)
+	expanded := strings.NewReplacer("**/", "d/", "/**", "/d/x", "**", "d/x").Replace(pattern)
+	out := []string{concrete(collapsed)}
+	if e := concrete(expanded); e != out[0] {
+		out = append(out, e)
+	}
+	return out
+}
+
+// concrete replaces the remaining wildcards with literal characters.
+func concrete(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*', '?':
+			b.WriteByte('x')
+		case '\\':
+			if i+1 < len(p) {
+				i++
+				b.WriteByte(p[i])
+			}
+		case '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			class := p[i+1 : i+end]
+			if class == "" || class[0] == '!' || class[0] == '^' {
+				b.WriteByte('x')
+			} else {
+				b.WriteByte(class[0])
+			}
+			i += end
+		case '{':
+			end := strings.IndexByte(p[i:], '}')
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			alt, _, _ := strings.Cut(p[i+1:i+end], ",")
+			b.WriteString(alt)
+			i += end
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Mounts checks each local watch against the filesystem holding it.
+// Watches whose filesystem cannot be inspected are skipped.
+func Mounts(cfg config.Config) []Finding {
+	var out []Finding
+	for _, w := range cfg.Watches {
+		if w.IsRemote() {
+			continue
+		}
+		if m, err := diskusage.MountOf(w.Path); err == nil {
+			out = append(out, MountFindings(w, m)...)
+		}
+	}
+	return out
+}
+
+// MountFindings reports settings of w that do not suit its filesystem:
+// native notifications on network mounts, which miss changes made by
+// other hosts, and actions changing the watched files on read-only ones.
+func MountFindings(w config.Watch, m diskusage.Mount) []Finding {
+	var out []Finding
+	if m.Network && w.Strategy == config.StrategyNative {
+		out = append(out, Finding{Watch: w.Key(), Message: fmt.Sprintf("native notifications miss changes made by other hosts on %s; use strategy hybrid or poll", m.Type)})
+	}
+	if !m.ReadOnly {
+		return out
+	}
+	for _, a := range w.Actions {
+		switch {
+		case a.Type == config.ActionMove, a.Type == config.ActionRename, a.Type == config.ActionRenamePattern,
+			a.Type == config.ActionTransfer, a.Type == config.ActionTag, a.Type == config.ActionArchive && !a.KeepSource:
+			out = append(out, Finding{Watch: w.Key(), Action: a.Name, Message: fmt.Sprintf("%s is mounted read-only; %s actions cannot change the watched files", m.Type, a.Type)})
+		}
+	}
+	return out
+}
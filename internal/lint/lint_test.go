@@ -0,0 +1,63 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/diskusage"
+)
+
+func TestCheckFindsOverlapShadowAndCancel(t *testing.T) {
+	create := []config.EventType{config.EventCreate}
+	cfg := config.Config{Watches: []config.Watch{{
+		Path:             "/w",
+		StopOnFirstMatch: true,
+		Actions: []config.Action{
+			{Name: "all-images", Include: []string{"**/*.{jpg,png}"}, Events: create},
+			{Name: "jpgs", Include: []string{"**/*.jpg"}, Events: create},
+			{Name: "docs", Include: []string{"**/*.pdf"}, Events: create},
+			{Name: "never", Include: []string{"**/*.txt"}, Exclude: []string{"**/*"}, Events: create},
+		},
+	}}}
+	findings := Check(cfg)
+	var shadow, cancel bool
+	for _, f := range findings {
+		if f.Action == "jpgs" && strings.Contains(f.Message, "shadowed by all-images") {
+			shadow = true
+		}
+		if f.Action == "never" && strings.Contains(f.Message, "cancel") {
+			cancel = true
+		}
+		if f.Action == "docs" {
+			t.Fatalf("unexpected finding for docs: %s", f)
+		}
+	}
+	if !shadow || !cancel {
+		t.Fatalf("expected shadow and cancel findings, got %v", findings)
+	}
+}
+
+func TestMountFindings(t *testing.T) {
+	w := config.Watch{
+		Path:     "/mnt/share",
+		Strategy: config.StrategyNative,
+		Actions: []config.Action{
+			{Name: "archive", Type: config.ActionMove},
+			{Name: "notify", Type: config.ActionWebhook},
+		},
+	}
+	findings := MountFindings(w, diskusage.Mount{Type: "nfs", Network: true, ReadOnly: true})
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %v", findings)
+	}
+	if findings[0].Action != "" || !strings.Contains(findings[0].String(), "use strategy hybrid") {
+		t.Fatalf("unexpected strategy finding %s", findings[0])
+	}
+	if findings[1].Action != "archive" || !strings.Contains(findings[1].Message, "read-only") {
+		t.Fatalf("unexpected read-only finding %s", findings[1])
+	}
+	if got := MountFindings(w, diskusage.Mount{Type: "ext4"}); len(got) != 0 {
+		t.Fatalf("unexpected findings on a local disk: %v", got)
+	}
+}
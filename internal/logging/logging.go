@@ -1,12 +1,23 @@
 package logging
 
 import (
+	"io"
 	"log/slog"
 	"os"
+
+	"watcher-cli/internal/config"
 )
 
-// New creates a baseline structured logger.
-func New(level slog.Leveler) *slog.Logger {
-	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})
-	return slog.New(handler)
+// New creates a baseline structured logger writing format to stdout, text
+// unless it is config.LogJSON.
+func New(level slog.Leveler, format config.LogFormat) *slog.Logger {
+	return newLogger(os.Stdout, level, format)
+}
+
+func newLogger(w io.Writer, level slog.Leveler, format config.LogFormat) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == config.LogJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
 }
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestNewWritesJSON(t *testing.T) {
+	var buf bytes.Buffer
+	newLogger(&buf, slog.LevelInfo, config.LogJSON).Info("action ok", "watch", "inbox", "duration_ms", 12)
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("not a JSON line: %q (%v)", buf.String(), err)
+	}
+	if line["msg"] != "action ok" || line["level"] != "INFO" || line["watch"] != "inbox" || line["duration_ms"] != float64(12) {
+		t.Fatalf("unexpected fields %v", line)
+	}
+}
+
+func TestNewDefaultsToText(t *testing.T) {
+	for _, format := range []config.LogFormat{"", config.LogText} {
+		var buf bytes.Buffer
+		newLogger(&buf, slog.LevelInfo, format).Debug("hidden")
+		newLogger(&buf, slog.LevelInfo, format).Info("shown", "watch", "inbox")
+		if out := buf.String(); strings.Contains(out, "hidden") || !strings.Contains(out, "msg=shown watch=inbox") {
+			t.Fatalf("format %q: unexpected output %q", format, out)
+		}
+	}
+}
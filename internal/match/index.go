@@ -0,0 +1,115 @@
+package match
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"watcher-cli/internal/config"
+)
+
+// index narrows the actions of one watch to candidates for an event, by
+// event type and file extension. Candidate lists keep config order so
+// stop_on_first_match behaves as with a linear scan.
+type index struct {
+	size   int
+	byExt  map[config.EventType]map[string][]int
+	always map[config.EventType][]int // actions whose includes have no fixed extension
+}
+
+func buildIndex(actions []config.Action) *index {
+	idx := &index{
+		size:   len(actions),
+		byExt:  map[config.EventType]map[string][]int{},
+		always: map[config.EventType][]int{},
+	}
+	for i, a := range actions {
+		exts, ok := includeExts(a.Include)
+		for _, t := range a.Events {
+			if !ok {
+				idx.always[t] = appendOnce(idx.always[t], i)
+				continue
+			}
+			if idx.byExt[t] == nil {
+				idx.byExt[t] = map[string][]int{}
+			}
+			for _, ext := range exts {
+				idx.byExt[t][ext] = appendOnce(idx.byExt[t][ext], i)
+			}
+		}
+	}
+	return idx
+}
+
+// candidates returns action indexes that may match, in config order.
+func (idx *index) candidates(evType config.EventType, relPath string) []int {
+	byExt := idx.byExt[evType][filepath.Ext(relPath)]
+	always := idx.always[evType]
+	if len(byExt) == 0 {
+		return always
+	}
+	if len(always) == 0 {
+		return byExt
+	}
+	out := make([]int, 0, len(byExt)+len(always))
+	i, j := 0, 0
+	for i < len(byExt) || j < len(always) {
+		if j >= len(always) || (i < len(byExt) && byExt[i] < always[j]) {
+			out = append(out, byExt[i])
+			i++
+		} else {
+			out = append(out, always[j])
+			j++
+		}
+	}
+	return out
+}
+
+// includeExts returns the extension every path matching the patterns must
+// have, or ok=false when some pattern does not pin one down (or there are
+// no includes, which match everything).
+func includeExts(patterns []string) (exts []string, ok bool) {
+	if len(patterns) == 0 {
+		return nil, false
+	}
+	for _, p := range patterns {
+		ext, ok := literalExt(filepath.ToSlash(p))
+		if !ok {
+			return nil, false
+		}
+		exts = append(exts, ext)
+	}
+	return exts, true
+}
+
+// literalExt extracts the extension from the literal tail of a pattern's
+// last segment, e.g. ".jpg" from "**/*.jpg" or "img[0-9].jpg".
+func literalExt(pattern string) (string, bool) {
+	base := path.Base(pattern)
+	tail := base
+	if i := strings.LastIndexAny(base, `*?]}\`); i >= 0 {
+		tail = base[i+1:]
+	} else if strings.ContainsAny(base, `[{`) {
+		return "", false
+	}
+	if strings.ContainsAny(tail, `[{`) {
+		return "", false
+	}
+	dot := strings.LastIndex(tail, ".")
+	if dot < 0 {
+		// A fully literal name without a dot has no extension; a wildcard
+		// tail without a dot could end in anything.
+		if tail == base {
+			return "", true
+		}
+		return "", false
+	}
+	return tail[dot:], true
+}
+
+func appendOnce(list []int, i int) []int {
+	if n := len(list); n > 0 && list[n-1] == i {
+		return list
+	}
+	return append(list, i)
+}
@@ -0,0 +1,95 @@
+package match
+
+import (
+	"fmt"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+func TestLiteralExt(t *testing.T) {
+	cases := map[string]struct {
+		ext string
+		ok  bool
+	}{
+		"**/*.jpg":      {".jpg", true},
+		"*.tar.gz":      {".gz", true},
+		"img[0-9].png":  {".png", true},
+		"Makefile":      {"", true},
+		"*":             {"", false},
+		"docs/**":       {"", false},
+		"*.{jpg,png}":   {"", false},
+		"*jpg":          {"", false},
+		"a/b/report.md": {".md", true},
+	}
+	for p, want := range cases {
+		ext, ok := literalExt(p)
+		if ext != want.ext || ok != want.ok {
+			t.Fatalf("%q: got (%q, %v), want (%q, %v)", p, ext, ok, want.ext, want.ok)
+		}
+	}
+}
+
+func TestIndexedMatchEqualsLinear(t *testing.T) {
+	w := largeWatch(200)
+	w.Actions = append(w.Actions,
+		config.Action{Name: "all", Events: []config.EventType{config.EventCreate}},
+		config.Action{Name: "braces", Include: []string{"**/*.{jpg,png}"}, Events: []config.EventType{config.EventCreate, config.EventModify}},
+	)
+	indexed := New(w)
+	linear := New()
+	for _, rel := range []string{"a/b/f17.ext17", "f3.ext3", "x.png", "y.jpg", "Makefile", "z.ext199", "deep/nested/none.txt"} {
+		for _, typ := range []string{"create", "modify", "delete"} {
+			ev := scanner.Event{Path: "/w/" + rel, RelPath: rel, Type: typ}
+			got, want := names(indexed.Match(ev, w)), names(linear.Match(ev, w))
+			if got != want {
+				t.Fatalf("%s %s: indexed %s, linear %s", typ, rel, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkMatchLinear(b *testing.B) {
+	benchmarkMatch(b, New())
+}
+
+func BenchmarkMatchIndexed(b *testing.B) {
+	benchmarkMatch(b, nil)
+}
+
+func benchmarkMatch(b *testing.B, m *Matcher) {
+	w := largeWatch(500)
+	if m == nil {
+		m = New(w)
+	}
+	ev := scanner.Event{Path: "/w/a/b/f250.ext250", RelPath: "a/b/f250.ext250", Type: "create"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if len(m.Match(ev, w)) != 1 {
+			b.Fatal("expected one match")
+		}
+	}
+}
+
+func largeWatch(n int) config.Watch {
+	hidden := false
+	w := config.Watch{Path: "/w"}
+	for i := 0; i < n; i++ {
+		w.Actions = append(w.Actions, config.Action{
+			Name:      fmt.Sprintf("a%d", i),
+			Include:   []string{fmt.Sprintf("**/*.ext%d", i)},
+			Events:    []config.EventType{config.EventCreate},
+			Condition: config.Condition{IgnoreHidden: &hidden},
+		})
+	}
+	return w
+}
+
+func names(actions []config.Action) string {
+	out := ""
+	for _, a := range actions {
+		out += a.Name + ","
+	}
+	return out
+}
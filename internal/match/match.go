@@ -1,45 +1,84 @@
 package match
 
 import (
+	"path"
 	"path/filepath"
 	"strings"
 
 	"watcher-cli/internal/config"
 	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/xattr"
 )
 
 // Matcher applies action filters to events.
-type Matcher struct{}
+type Matcher struct {
+	// indexes is keyed by the first element of a watch's action slice,
+	// which copies of the same watch share. It is read-only after New.
+	indexes map[*config.Action]*index
+}
 
-// New returns a matcher.
-func New() *Matcher {
-	return &Matcher{}
+// New returns a matcher. Watches passed here get an index built up front;
+// others are matched by scanning all of their actions.
+func New(watches ...config.Watch) *Matcher {
+	m := &Matcher{indexes: map[*config.Action]*index{}}
+	for _, w := range watches {
+		if len(w.Actions) > 0 {
+			m.indexes[&w.Actions[0]] = buildIndex(w.Actions)
+		}
+	}
+	return m
 }
 
 // Match returns actions that should run for the event.
 func (m *Matcher) Match(ev scanner.Event, watch config.Watch) []config.Action {
 	var selected []config.Action
-	for _, a := range watch.Actions {
-		if !eventAllowed(ev, a) {
-			continue
-		}
-		if !a.MatchesInclude(ev.RelPath) {
-			continue
-		}
-		if a.MatchesExclude(ev.RelPath) {
-			continue
-		}
-		if !conditionsPass(ev, a.Condition) {
-			continue
+	consider := func(a config.Action) bool {
+		if !matches(ev, a) {
+			return false
 		}
 		selected = append(selected, a)
-		if watch.StopOnFirstMatch {
+		return watch.StopOnFirstMatch
+	}
+	if idx := m.index(watch); idx != nil {
+		for _, i := range idx.candidates(config.EventType(ev.Type), ev.RelPath) {
+			if consider(watch.Actions[i]) {
+				break
+			}
+		}
+		return selected
+	}
+	for _, a := range watch.Actions {
+		if consider(a) {
 			break
 		}
 	}
 	return selected
 }
 
+func (m *Matcher) index(watch config.Watch) *index {
+	if len(watch.Actions) == 0 {
+		return nil
+	}
+	idx, ok := m.indexes[&watch.Actions[0]]
+	if !ok || idx.size != len(watch.Actions) {
+		return nil
+	}
+	return idx
+}
+
+func matches(ev scanner.Event, a config.Action) bool {
+	if !eventAllowed(ev, a) {
+		return false
+	}
+	if !a.MatchesInclude(ev.RelPath) {
+		return false
+	}
+	if a.MatchesExclude(ev.RelPath) {
+		return false
+	}
+	return conditionsPass(ev, a.Condition)
+}
+
 func eventAllowed(ev scanner.Event, a config.Action) bool {
 	types := map[config.EventType]struct{}{}
 	for _, t := range a.Events {
@@ -73,6 +112,24 @@ func conditionsPass(ev scanner.Event, c config.Condition) bool {
 			return false
 		}
 	}
+	for name, pattern := range c.Fields {
+		v, ok := ev.Fields[name]
+		if !ok {
+			return false
+		}
+		if matched, _ := path.Match(pattern, v); !matched {
+			return false
+		}
+	}
+	if x := c.Xattr; x != nil {
+		v, ok := ev.Info.Xattrs[xattr.Key(x.Key)]
+		if x.Absent {
+			return !ok
+		}
+		if !ok || x.Value != "" && v != x.Value {
+			return false
+		}
+	}
 	return true
 }
 
@@ -107,3 +107,49 @@ func TestMatchConditions(t *testing.T) {
 		t.Fatalf("expected dir to be blocked by OnlyFiles, got %d", got)
 	}
 }
+
+func TestMatchXattrCondition(t *testing.T) {
+	w := config.Watch{
+		Path: "/tmp",
+		Actions: []config.Action{{
+			Name:      "tagged",
+			Type:      config.ActionExec,
+			Events:    []config.EventType{config.EventCreate},
+			Condition: config.Condition{Xattr: &config.XattrMatch{Key: "user.origin", Value: "scanner"}},
+		}},
+	}
+	m := New(w)
+	ev := scanner.Event{Path: "/tmp/a.pdf", RelPath: "a.pdf", Type: "create"}
+	if got := len(m.Match(ev, w)); got != 0 {
+		t.Fatalf("expected untagged file to be blocked, got %d", got)
+	}
+	ev.Info.Xattrs = map[string]string{"origin": "mail"}
+	if got := len(m.Match(ev, w)); got != 0 {
+		t.Fatalf("expected other value to be blocked, got %d", got)
+	}
+	ev.Info.Xattrs["origin"] = "scanner"
+	if got := len(m.Match(ev, w)); got != 1 {
+		t.Fatalf("expected tagged file to match, got %d", got)
+	}
+}
+
+func TestMatchXattrAbsent(t *testing.T) {
+	w := config.Watch{
+		Path: "/tmp",
+		Actions: []config.Action{{
+			Name:      "once",
+			Type:      config.ActionTag,
+			Events:    []config.EventType{config.EventCreate},
+			Condition: config.Condition{Xattr: &config.XattrMatch{Key: "processed", Absent: true}},
+		}},
+	}
+	m := New(w)
+	ev := scanner.Event{Path: "/tmp/a.pdf", RelPath: "a.pdf", Type: "create"}
+	if got := len(m.Match(ev, w)); got != 1 {
+		t.Fatalf("expected untagged file to match, got %d", got)
+	}
+	ev.Info.Xattrs = map[string]string{"processed": "2025-01-02"}
+	if got := len(m.Match(ev, w)); got != 0 {
+		t.Fatalf("expected tagged file to be skipped, got %d", got)
+	}
+}
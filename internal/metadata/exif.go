@@ -0,0 +1,139 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+)
+
+var exifTags = map[uint16]string{
+	0x010F: "Make",
+	0x0110: "Model",
+	0x0112: "Orientation",
+	0x0131: "Software",
+	0x0132: "DateTime",
+	0x013B: "Artist",
+	0x8298: "Copyright",
+	0x9003: "DateTimeOriginal",
+	0x9004: "DateTimeDigitized",
+	0xA002: "PixelXDimension",
+	0xA003: "PixelYDimension",
+}
+
+const exifIFDPointer = 0x8769
+
+// readEXIF parses ASCII and integer tags from IFD0 and the Exif sub-IFD of
+// a JPEG or TIFF file. Derived year/month/day fields come from the capture
+// date so templates can build dated folder layouts.
+func readEXIF(path string) map[string]string {
+	data, err := readHead(path, maxRead)
+	if err != nil {
+		return nil
+	}
+	tiff := findTIFF(data)
+	if tiff == nil {
+		return nil
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+	out := map[string]string{}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if sub := parseIFD(tiff, ifd, order, out); sub > 0 {
+		parseIFD(tiff, sub, order, out)
+	}
+	date := out["DateTimeOriginal"]
+	if date == "" {
+		date = out["DateTime"]
+	}
+	// EXIF dates look like "2023:05:01 12:34:56".
+	if len(date) >= 10 {
+		out["year"] = date[0:4]
+		out["month"] = date[5:7]
+		out["day"] = date[8:10]
+	}
+	return out
+}
+
+func findTIFF(data []byte) []byte {
+	if len(data) >= 8 && (bytes.HasPrefix(data, []byte("II*\x00")) || bytes.HasPrefix(data, []byte("MM\x00*"))) {
+		return data
+	}
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+	i := 2
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		size := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		// The length counts its own two bytes; anything shorter, or running
+		// past the end of the file, is a corrupt segment.
+		if size < 2 || i+2+size > len(data) {
+			return nil
+		}
+		if marker == 0xE1 {
+			seg := data[i+4 : i+2+size]
+			if bytes.HasPrefix(seg, []byte("Exif\x00\x00")) && len(seg) > 14 {
+				return seg[6:]
+			}
+		}
+		if marker == 0xDA { // start of scan, no more metadata
+			return nil
+		}
+		i += 2 + size
+	}
+	return nil
+}
+
+// parseIFD stores known tags in out and returns the Exif sub-IFD offset.
+func parseIFD(tiff []byte, off int, order binary.ByteOrder, out map[string]string) int {
+	if off <= 0 || off+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[off : off+2]))
+	sub := 0
+	for i := 0; i < count; i++ {
+		e := off + 2 + i*12
+		if e+12 > len(tiff) {
+			break
+		}
+		tag := order.Uint16(tiff[e : e+2])
+		typ := order.Uint16(tiff[e+2 : e+4])
+		n := int(order.Uint32(tiff[e+4 : e+8]))
+		val := tiff[e+8 : e+12]
+		if tag == exifIFDPointer {
+			sub = int(order.Uint32(val))
+			continue
+		}
+		name, ok := exifTags[tag]
+		if !ok {
+			continue
+		}
+		switch typ {
+		case 2: // ASCII
+			raw := val
+			if n > 4 {
+				p := int(order.Uint32(val))
+				if p < 0 || p+n > len(tiff) {
+					continue
+				}
+				raw = tiff[p : p+n]
+			} else {
+				raw = raw[:n]
+			}
+			out[name] = strings.TrimRight(string(raw), "\x00 ")
+		case 3: // SHORT
+			out[name] = strconv.Itoa(int(order.Uint16(val[:2])))
+		case 4: // LONG
+			out[name] = strconv.FormatUint(uint64(order.Uint32(val)), 10)
+		}
+	}
+	return sub
+}
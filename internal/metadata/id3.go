@@ -0,0 +1,113 @@
+package metadata
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf16"
+)
+
+var id3Frames = map[string]string{
+	"TIT2": "title",
+	"TPE1": "artist",
+	"TPE2": "albumartist",
+	"TALB": "album",
+	"TYER": "year",
+	"TDRC": "year",
+	"TCON": "genre",
+	"TRCK": "track",
+}
+
+// readID3 reads common text frames from an ID3v2 header, falling back to
+// the fixed-size ID3v1 trailer.
+func readID3(path string) map[string]string {
+	out := map[string]string{}
+	if head, err := readHead(path, maxRead); err == nil {
+		parseID3v2(head, out)
+	}
+	if len(out) > 0 {
+		return out
+	}
+	if tail, err := readTail(path, 128); err == nil && len(tail) == 128 && bytes.HasPrefix(tail, []byte("TAG")) {
+		set := func(key string, b []byte) {
+			if v := strings.TrimRight(string(b), "\x00 "); v != "" {
+				out[key] = v
+			}
+		}
+		set("title", tail[3:33])
+		set("artist", tail[33:63])
+		set("album", tail[63:93])
+		set("year", tail[93:97])
+	}
+	return out
+}
+
+func parseID3v2(data []byte, out map[string]string) {
+	if len(data) < 10 || !bytes.HasPrefix(data, []byte("ID3")) {
+		return
+	}
+	major := data[3]
+	if major != 3 && major != 4 {
+		return
+	}
+	size := syncsafe(data[6:10])
+	end := 10 + size
+	if end > len(data) {
+		end = len(data)
+	}
+	for i := 10; i+10 <= end; {
+		id := string(data[i : i+4])
+		if id[0] == 0 {
+			break
+		}
+		var n int
+		if major == 4 {
+			n = syncsafe(data[i+4 : i+8])
+		} else {
+			n = int(data[i+4])<<24 | int(data[i+5])<<16 | int(data[i+6])<<8 | int(data[i+7])
+		}
+		body := i + 10
+		if n <= 0 || body+n > end {
+			break
+		}
+		if key, ok := id3Frames[id]; ok {
+			if v := decodeText(data[body : body+n]); v != "" {
+				out[key] = v
+			}
+		}
+		i = body + n
+	}
+}
+
+func syncsafe(b []byte) int {
+	return int(b[0])<<21 | int(b[1])<<14 | int(b[2])<<7 | int(b[3])
+}
+
+// decodeText decodes an ID3 text frame body (encoding byte + text).
+func decodeText(b []byte) string {
+	if len(b) < 2 {
+		return ""
+	}
+	enc, text := b[0], b[1:]
+	var s string
+	switch enc {
+	case 1, 2: // UTF-16 with BOM / UTF-16BE
+		bigEndian := enc == 2
+		if len(text) >= 2 && text[0] == 0xFE && text[1] == 0xFF {
+			bigEndian, text = true, text[2:]
+		} else if len(text) >= 2 && text[0] == 0xFF && text[1] == 0xFE {
+			bigEndian, text = false, text[2:]
+		}
+		u := make([]uint16, 0, len(text)/2)
+		for i := 0; i+1 < len(text); i += 2 {
+			if bigEndian {
+				u = append(u, uint16(text[i])<<8|uint16(text[i+1]))
+			} else {
+				u = append(u, uint16(text[i+1])<<8|uint16(text[i]))
+			}
+		}
+		s = string(utf16.Decode(u))
+	default: // ISO-8859-1 / UTF-8
+		s = string(text)
+	}
+	return strings.TrimRight(s, "\x00 ")
+}
@@ -0,0 +1,93 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Kinds of metadata that can be extracted.
+const (
+	KindEXIF = "exif"
+	KindID3  = "id3"
+	KindPDF  = "pdf"
+	// KindXattr values come from the scanner rather than Extract.
+	KindXattr = "xattr"
+)
+
+// maxRead bounds how much of a file extractors inspect.
+const maxRead = 256 << 10
+
+// Extract reads the requested metadata kinds from path and returns values
+// keyed as "<kind>:<field>", e.g. "exif:DateTimeOriginal". Files that do not
+// carry a kind are skipped silently.
+func Extract(path string, kinds []string) map[string]string {
+	out := map[string]string{}
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, kind := range kinds {
+		var fields map[string]string
+		switch kind {
+		case KindEXIF:
+			if ext == ".jpg" || ext == ".jpeg" || ext == ".tif" || ext == ".tiff" {
+				fields = readEXIF(path)
+			}
+		case KindID3:
+			if ext == ".mp3" {
+				fields = readID3(path)
+			}
+		case KindPDF:
+			if ext == ".pdf" {
+				fields = readPDF(path)
+			}
+		}
+		for k, v := range fields {
+			out[kind+":"+k] = v
+		}
+	}
+	return out
+}
+
+// Valid reports whether kind is a supported metadata kind.
+func Valid(kind string) bool {
+	switch kind {
+	case KindEXIF, KindID3, KindPDF, KindXattr:
+		return true
+	}
+	return false
+}
+
+func readHead(path string, n int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buf := make([]byte, n)
+	read, err := f.Read(buf)
+	if read == 0 && err != nil {
+		return nil, err
+	}
+	return buf[:read], nil
+}
+
+func readTail(path string, n int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	off := info.Size() - n
+	if off < 0 {
+		off = 0
+	}
+	buf := make([]byte, info.Size()-off)
+	read, err := f.ReadAt(buf, off)
+	if read == 0 && err != nil {
+		return nil, err
+	}
+	return buf[:read], nil
+}
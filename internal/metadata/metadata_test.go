@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractEXIFDate(t *testing.T) {
+	date := "2023:05:01 12:34:56\x00"
+	// TIFF header + IFD0 with a single DateTimeOriginal entry.
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x9003)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 2)
+	tiff = binary.LittleEndian.AppendUint32(tiff, uint32(len(date)))
+	tiff = binary.LittleEndian.AppendUint32(tiff, 26)
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, date...)
+	seg := append([]byte("Exif\x00\x00"), tiff...)
+	jpg := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	jpg = binary.BigEndian.AppendUint16(jpg, uint16(len(seg)+2))
+	jpg = append(jpg, seg...)
+	jpg = append(jpg, 0xFF, 0xDA, 0, 2)
+
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	if err := os.WriteFile(path, jpg, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got := Extract(path, []string{KindEXIF})
+	if got["exif:DateTimeOriginal"] != "2023:05:01 12:34:56" {
+		t.Fatalf("unexpected DateTimeOriginal: %#v", got)
+	}
+	if got["exif:year"] != "2023" || got["exif:month"] != "05" {
+		t.Fatalf("expected derived year/month, got %#v", got)
+	}
+}
+
+func TestFindTIFFRejectsCorruptSegments(t *testing.T) {
+	for name, jpg := range map[string][]byte{
+		"zero length": {0xFF, 0xD8, 0xFF, 0xE1, 0, 0, 'E', 'x'},
+		"length one":  {0xFF, 0xD8, 0xFF, 0xE1, 0, 1, 'E', 'x'},
+		"truncated":   append([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 40}, "Exif\x00\x00II*\x00"...),
+	} {
+		if tiff := findTIFF(jpg); tiff != nil {
+			t.Fatalf("%s: expected no TIFF block, got %q", name, tiff)
+		}
+	}
+}
+
+func TestExtractID3AndPDF(t *testing.T) {
+	dir := t.TempDir()
+	frame := append([]byte{0}, "Some Artist"...)
+	tag := []byte("ID3\x03\x00\x00\x00\x00\x00\x00")
+	tag = append(tag, "TPE1"...)
+	tag = binary.BigEndian.AppendUint32(tag, uint32(len(frame)))
+	tag = append(tag, 0, 0)
+	tag = append(tag, frame...)
+	tag[9] = byte(len(tag) - 10)
+	mp3 := filepath.Join(dir, "song.mp3")
+	if err := os.WriteFile(mp3, tag, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := Extract(mp3, []string{KindID3}); got["id3:artist"] != "Some Artist" {
+		t.Fatalf("unexpected id3: %#v", got)
+	}
+
+	pdf := filepath.Join(dir, "doc.pdf")
+	body := "%PDF-1.4\n1 0 obj << /Title (Quarterly \\(Q3\\) Report) /Author (Jane) >> endobj\n%%EOF"
+	if err := os.WriteFile(pdf, []byte(body), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got := Extract(pdf, []string{KindPDF})
+	if got["pdf:title"] != "Quarterly (Q3) Report" || got["pdf:author"] != "Jane" {
+		t.Fatalf("unexpected pdf: %#v", got)
+	}
+}
@@ -0,0 +1,39 @@
+package metadata
+
+import (
+	"regexp"
+	"strings"
+)
+
+var pdfInfoKey = regexp.MustCompile(`/(Title|Author|Subject|Keywords|Creator|Producer|CreationDate)\s*\(((?:\\.|[^\\)])*)\)`)
+
+// readPDF reads literal-string entries of the document info dictionary.
+// The info dictionary usually sits near the end of the file, so both ends
+// are searched.
+func readPDF(path string) map[string]string {
+	out := map[string]string{}
+	for _, read := range []func() ([]byte, error){
+		func() ([]byte, error) { return readTail(path, maxRead) },
+		func() ([]byte, error) { return readHead(path, maxRead) },
+	} {
+		data, err := read()
+		if err != nil {
+			continue
+		}
+		for _, m := range pdfInfoKey.FindAllSubmatch(data, -1) {
+			key := strings.ToLower(string(m[1]))
+			if _, seen := out[key]; seen {
+				continue
+			}
+			if v := unescapePDF(string(m[2])); v != "" {
+				out[key] = v
+			}
+		}
+	}
+	return out
+}
+
+func unescapePDF(s string) string {
+	r := strings.NewReplacer(`\(`, "(", `\)`, ")", `\\`, `\`, `\n`, " ", `\r`, " ", `\t`, " ")
+	return strings.TrimSpace(r.Replace(s))
+}
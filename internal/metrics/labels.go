@@ -0,0 +1,187 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Labels a Recorder can attach to its series.
+const (
+	LabelWatch  = "watch"
+	LabelAction = "action"
+	LabelEvent  = "event"
+	LabelExt    = "ext"
+)
+
+// OtherValue replaces label values past a label's cap.
+const OtherValue = "other"
+
+// noExt is the ext label of files without an extension.
+const noExt = "none"
+
+// labelOrder is the order labels appear in a series, whatever their order
+// in the config.
+var labelOrder = []string{LabelWatch, LabelAction, LabelEvent, LabelExt}
+
+// Recorder counts events and action results under a configurable set of
+// labels. Each label keeps at most maxValues distinct values; later ones
+// are counted under OtherValue, so per-extension labels on a share full of
+// odd names cannot explode the number of series.
+type Recorder struct {
+	mu        sync.Mutex
+	labels    map[string]bool
+	maxValues int
+	values    map[string]map[string]bool // label -> values kept
+	dropped   map[string]int64           // label -> values folded into other
+	events    map[string]int64           // label values joined by \x00
+	actions   map[string]int64           // label values and result joined by \x00
+}
+
+// NewRecorder returns a recorder attaching labels to its series, keeping
+// up to maxValues values per label; 0 keeps any number.
+func NewRecorder(labels []string, maxValues int) *Recorder {
+	r := &Recorder{
+		labels:    map[string]bool{},
+		maxValues: maxValues,
+		values:    map[string]map[string]bool{},
+		dropped:   map[string]int64{},
+		events:    map[string]int64{},
+		actions:   map[string]int64{},
+	}
+	for _, l := range labels {
+		r.labels[l] = true
+	}
+	return r
+}
+
+// Event counts an event of type event on path in watch.
+func (r *Recorder) Event(watch, event, path string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[r.key(watch, "", event, path)]++
+}
+
+// Action counts the result of action run for an event of type event on
+// path in watch.
+func (r *Recorder) Action(watch, action, event, path string, ok bool) {
+	if r == nil {
+		return
+	}
+	result := "error"
+	if ok {
+		result = "ok"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actions[r.key(watch, action, event, path)+"\x00"+result]++
+}
+
+// key joins the values of the configured labels in labelOrder. action is
+// empty for event series, which have no action label.
+func (r *Recorder) key(watch, action, event, path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		ext = noExt
+	}
+	all := map[string]string{LabelWatch: watch, LabelAction: action, LabelEvent: event, LabelExt: ext}
+	var parts []string
+	for _, l := range labelOrder {
+		if !r.labels[l] || (l == LabelAction && action == "") {
+			continue
+		}
+		parts = append(parts, l+"="+r.cap(l, all[l], true))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// pairs renders the configured watch and action labels of a tracker
+// counter; action is empty for a watch's own counters.
+func (r *Recorder) pairs(watch, action string) string {
+	var pairs []string
+	for _, l := range labelOrder {
+		v := map[string]string{LabelWatch: watch, LabelAction: action}[l]
+		if !r.labels[l] || v == "" {
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", l, escapeLabel(r.cap(l, v, false))))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// cap returns v, or OtherValue once label holds maxValues other values.
+// count adds values folded into OtherValue to the overflow counter.
+func (r *Recorder) cap(label, v string, count bool) string {
+	seen := r.values[label]
+	if seen == nil {
+		seen = map[string]bool{}
+		r.values[label] = seen
+	}
+	if seen[v] {
+		return v
+	}
+	if r.maxValues > 0 && len(seen) >= r.maxValues {
+		if count {
+			r.dropped[label]++
+		}
+		return OtherValue
+	}
+	seen[v] = true
+	return v
+}
+
+// Render writes the recorder's series in the Prometheus text exposition
+// format, to follow the output of Render.
+func (r *Recorder) Render() []byte {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b bytes.Buffer
+	writeLabeled(&b, "watcher_file_events_total", "Events by the configured labels.", r.events, false)
+	writeLabeled(&b, "watcher_file_actions_total", "Action results by the configured labels.", r.actions, true)
+	const dropped = "watcher_metric_label_overflow_total"
+	fmt.Fprintf(&b, "# HELP %s Label values counted as %q after a label reached max_label_values.\n# TYPE %s counter\n", dropped, OtherValue, dropped)
+	for _, l := range labelOrder {
+		if n := r.dropped[l]; n > 0 {
+			fmt.Fprintf(&b, "%s{label=\"%s\"} %d\n", dropped, l, n)
+		}
+	}
+	return b.Bytes()
+}
+
+func writeLabeled(b *bytes.Buffer, name, help string, counts map[string]int64, result bool) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts := strings.Split(k, "\x00")
+		var pairs []string
+		for i, p := range parts {
+			if result && i == len(parts)-1 {
+				pairs = append(pairs, fmt.Sprintf("result=\"%s\"", p))
+				continue
+			}
+			if p == "" {
+				continue // no labels configured
+			}
+			l, v, _ := strings.Cut(p, "=")
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", l, escapeLabel(v)))
+		}
+		if len(pairs) == 0 {
+			fmt.Fprintf(b, "%s %d\n", name, counts[k])
+			continue
+		}
+		fmt.Fprintf(b, "%s{%s} %d\n", name, strings.Join(pairs, ","), counts[k])
+	}
+}
@@ -0,0 +1,152 @@
+// Package metrics renders tracker counters in the Prometheus text format
+// and pushes them to a Pushgateway.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"watcher-cli/internal/status"
+)
+
+const pushTimeout = 10 * time.Second
+
+// Render writes counters in the Prometheus text exposition format. Keys are
+// watch paths (event counts) or "<watch>.<action>" (action counts), and
+// label each series as name.
+func Render(snap map[string]status.Counter) []byte {
+	return render(snap, func(name string) string {
+		return fmt.Sprintf("name=\"%s\"", escapeLabel(name))
+	})
+}
+
+// RenderCounters is Render with the recorder's watch and action labels in
+// place of name. watches lists the watch keys, which tell a watch's own
+// counters from its actions'. Series left with the same labels are summed.
+func (r *Recorder) RenderCounters(snap map[string]status.Counter, watches []string) []byte {
+	if r == nil {
+		return Render(snap)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return render(snap, func(name string) string {
+		watch, action := splitName(name, watches)
+		return r.pairs(watch, action)
+	})
+}
+
+// splitName splits a counter key into its watch and action, matching the
+// longest watch key so dots in watch names are kept.
+func splitName(name string, watches []string) (watch, action string) {
+	watch = name
+	best := -1
+	for _, w := range watches {
+		switch {
+		case name == w:
+			return w, ""
+		case strings.HasPrefix(name, w+".") && len(w) > best:
+			best = len(w)
+			watch, action = w, name[len(w)+1:]
+		}
+	}
+	return watch, action
+}
+
+// render writes the counter series, labeling each key with labels and
+// summing keys that share them.
+func render(snap map[string]status.Counter, labels func(name string) string) []byte {
+	// Sorted, so capped label values keep the same names on every render.
+	names := make([]string, 0, len(snap))
+	for k := range snap {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b bytes.Buffer
+	series := []struct {
+		name, help string
+		value      func(status.Counter) int64
+	}{
+		{"watcher_events_seen_total", "Events seen per watch.", func(c status.Counter) int64 { return c.EventsSeen }},
+		{"watcher_actions_run_total", "Actions run.", func(c status.Counter) int64 { return c.ActionsRun }},
+		{"watcher_actions_ok_total", "Actions that succeeded.", func(c status.Counter) int64 { return c.ActionsOK }},
+		{"watcher_actions_error_total", "Actions that failed.", func(c status.Counter) int64 { return c.ActionsError }},
+		{"watcher_events_handled_total", "Events whose actions all succeeded.", func(c status.Counter) int64 { return c.EventsHandled }},
+		{"watcher_events_partially_failed_total", "Events with some failed actions.", func(c status.Counter) int64 { return c.EventsPartial }},
+		{"watcher_events_failed_total", "Events whose actions all failed.", func(c status.Counter) int64 { return c.EventsFailed }},
+		{"watcher_worker_restarts_total", "Watch workers restarted after a crash.", func(c status.Counter) int64 { return c.Restarts }},
+	}
+	for _, s := range series {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", s.name, s.help, s.name)
+		counts := map[string]int64{}
+		for _, n := range names {
+			if v := s.value(snap[n]); v != 0 {
+				counts[labels(n)] += v
+			}
+		}
+		writeCounts(&b, s.name, counts)
+	}
+	const byClass = "watcher_action_errors_by_class_total"
+	fmt.Fprintf(&b, "# HELP %s Actions that failed, by error class.\n# TYPE %s counter\n", byClass, byClass)
+	counts := map[string]int64{}
+	for _, n := range names {
+		for k, v := range snap[n].ErrorClasses {
+			counts[joinPairs(labels(n), fmt.Sprintf("class=\"%s\"", escapeLabel(k)))] += v
+		}
+	}
+	writeCounts(&b, byClass, counts)
+	return b.Bytes()
+}
+
+// writeCounts writes one line per label set, sorted.
+func writeCounts(b *bytes.Buffer, name string, counts map[string]int64) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if k == "" {
+			fmt.Fprintf(b, "%s %d\n", name, counts[k])
+			continue
+		}
+		fmt.Fprintf(b, "%s{%s} %d\n", name, k, counts[k])
+	}
+}
+
+func joinPairs(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "," + b
+}
+
+// Push replaces the metrics of job on the Pushgateway at gateway.
+func Push(ctx context.Context, gateway, job string, body []byte) error {
+	target := strings.TrimSuffix(gateway, "/") + "/metrics/job/" + url.PathEscape(job)
+	ctx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pushgateway returned %s", resp.Status)
+	}
+	return nil
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"watcher-cli/internal/status"
+)
+
+func TestRender(t *testing.T) {
+	out := string(Render(map[string]status.Counter{
+		"/in":        {EventsSeen: 3},
+		"/in.upload": {ActionsRun: 2, ActionsOK: 1, ActionsError: 1},
+	}))
+	for _, want := range []string{
+		`watcher_events_seen_total{name="/in"} 3`,
+		`watcher_actions_run_total{name="/in.upload"} 2`,
+		`watcher_actions_error_total{name="/in.upload"} 1`,
+		"# TYPE watcher_actions_ok_total counter",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+	}))
+	defer srv.Close()
+	if err := Push(context.Background(), srv.URL+"/", "nightly import", []byte("x 1\n")); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/nightly import" || body != "x 1\n" {
+		t.Fatalf("unexpected request %s %s %q", method, path, body)
+	}
+}
+
+func TestRecorderLabelsAndCap(t *testing.T) {
+	r := NewRecorder([]string{LabelExt, LabelWatch, LabelAction}, 2)
+	for _, p := range []string{"/in/a.JPG", "/in/b.jpg", "/in/c", "/in/d.png", "/in/e.png"} {
+		r.Event("/in", "create", p)
+	}
+	r.Action("/in", "upload", "create", "/in/a.jpg", true)
+	r.Action("/in", "upload", "create", "/in/c", false)
+	out := string(r.Render())
+	for _, want := range []string{
+		`watcher_file_events_total{watch="/in",ext=".jpg"} 2`,
+		`watcher_file_events_total{watch="/in",ext="none"} 1`,
+		`watcher_file_events_total{watch="/in",ext="other"} 2`,
+		`watcher_file_actions_total{watch="/in",action="upload",ext=".jpg",result="ok"} 1`,
+		`watcher_file_actions_total{watch="/in",action="upload",ext="none",result="error"} 1`,
+		`watcher_metric_label_overflow_total{label="ext"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "event=") {
+		t.Fatalf("event label not configured but rendered:\n%s", out)
+	}
+
+	r = NewRecorder(nil, 0)
+	r.Event("/in", "create", "/in/a")
+	r.Action("/in", "upload", "create", "/in/a", true)
+	out = string(r.Render())
+	if !strings.Contains(out, "watcher_file_events_total 1\n") || !strings.Contains(out, `watcher_file_actions_total{result="ok"} 1`) {
+		t.Fatalf("unlabeled series wrong:\n%s", out)
+	}
+}
+
+func TestRenderCountersUsesConfiguredLabels(t *testing.T) {
+	snap := map[string]status.Counter{
+		"/in.d":         {EventsSeen: 3},
+		"/in.d.upload":  {ActionsRun: 2, ActionsError: 1, ErrorClasses: map[string]int64{"network": 1}},
+		"/out":          {EventsSeen: 1},
+		"/out.upload":   {ActionsRun: 1},
+		"/out.v2.thumb": {ActionsRun: 4},
+	}
+	watches := []string{"/in.d", "/out", "/out.v2"}
+	out := string(NewRecorder([]string{LabelWatch, LabelAction}, 0).RenderCounters(snap, watches))
+	for _, want := range []string{
+		`watcher_events_seen_total{watch="/in.d"} 3`,
+		`watcher_actions_run_total{watch="/in.d",action="upload"} 2`,
+		`watcher_actions_run_total{watch="/out.v2",action="thumb"} 4`,
+		`watcher_action_errors_by_class_total{watch="/in.d",action="upload",class="network"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "name=") {
+		t.Fatalf("name label rendered with labels configured:\n%s", out)
+	}
+
+	out = string(NewRecorder([]string{LabelAction}, 0).RenderCounters(snap, watches))
+	for _, want := range []string{
+		"watcher_events_seen_total 4\n",
+		`watcher_actions_run_total{action="upload"} 3`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	r := NewRecorder([]string{LabelWatch}, 1)
+	out = string(r.RenderCounters(snap, watches))
+	if !strings.Contains(out, `watcher_events_seen_total{watch="/in.d"} 3`) || !strings.Contains(out, `watcher_events_seen_total{watch="other"} 1`) {
+		t.Fatalf("capped watch label wrong:\n%s", out)
+	}
+	if strings.Contains(string(r.Render()), "label_overflow_total{") {
+		t.Fatalf("rendering counted label overflow")
+	}
+}
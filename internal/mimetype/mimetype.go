@@ -0,0 +1,127 @@
+// Package mimetype sniffs a file's media type and sorts it into a coarse
+// kind for templates.
+package mimetype
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Kinds returned by Kind.
+const (
+	KindImage    = "image"
+	KindVideo    = "video"
+	KindAudio    = "audio"
+	KindDocument = "document"
+	KindArchive  = "archive"
+	KindOther    = "other"
+)
+
+// sniffLen is how much of a file content sniffing looks at.
+const sniffLen = 512
+
+// Detect returns the media type of path, without parameters. Content is
+// sniffed first; the extension decides when the content is unreadable or
+// too generic to tell, e.g. a .docx sniffs as a zip archive.
+func Detect(path string) string {
+	sniffed := ""
+	if f, err := os.Open(path); err == nil {
+		buf := make([]byte, sniffLen)
+		n, _ := io.ReadFull(f, buf)
+		f.Close()
+		if n > 0 {
+			sniffed = base(http.DetectContentType(buf[:n]))
+		}
+	}
+	byExt := byExtension(path)
+	switch {
+	case byExt != "" && (sniffed == "" || generic(sniffed)):
+		return byExt
+	case sniffed != "":
+		return sniffed
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// extensions covers common types the host's MIME tables may lack.
+var extensions = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".doc":  "application/msword",
+	".epub": "application/epub+zip",
+	".md":   "text/markdown",
+	".csv":  "text/csv",
+	".txt":  "text/plain",
+	".heic": "image/heic",
+	".mp4":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".mov":  "video/quicktime",
+	".mp3":  "audio/mpeg",
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+	".zip":  "application/zip",
+	".tar":  "application/x-tar",
+	".gz":   "application/gzip",
+	".tgz":  "application/gzip",
+	".7z":   "application/x-7z-compressed",
+	".rar":  "application/vnd.rar",
+	".xz":   "application/x-xz",
+	".zst":  "application/zstd",
+}
+
+func byExtension(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if t, ok := extensions[ext]; ok {
+		return t
+	}
+	return base(mime.TypeByExtension(ext))
+}
+
+// generic reports whether a sniffed type is a container or fallback the
+// extension can refine.
+func generic(t string) bool {
+	switch t {
+	case "application/octet-stream", "text/plain", "application/zip", "text/xml", "application/xml":
+		return true
+	}
+	return false
+}
+
+func base(t string) string {
+	t, _, _ = strings.Cut(t, ";")
+	return strings.TrimSpace(t)
+}
+
+// Kind sorts a media type into image, video, audio, document, archive or
+// other.
+func Kind(mediaType string) string {
+	major, sub, _ := strings.Cut(mediaType, "/")
+	switch major {
+	case "image", "video", "audio":
+		return major
+	case "text":
+		return KindDocument
+	}
+	switch {
+	case sub == "pdf", sub == "rtf", sub == "msword",
+		strings.HasPrefix(sub, "vnd.openxmlformats-officedocument"),
+		strings.HasPrefix(sub, "vnd.oasis.opendocument"),
+		strings.HasPrefix(sub, "vnd.ms-excel"), strings.HasPrefix(sub, "vnd.ms-powerpoint"),
+		sub == "epub+zip":
+		return KindDocument
+	case sub == "zip", sub == "gzip", sub == "x-gzip", sub == "x-tar", sub == "x-7z-compressed",
+		sub == "vnd.rar", sub == "x-rar-compressed", sub == "x-bzip2", sub == "x-xz", sub == "zstd":
+		return KindArchive
+	case sub == "ogg":
+		return KindAudio
+	}
+	return KindOther
+}
@@ -0,0 +1,39 @@
+package mimetype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectAndKind(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return p
+	}
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	zip := []byte("PK\x03\x04rest-of-archive")
+	cases := []struct {
+		path, mime, kind string
+	}{
+		// Content wins over a misleading extension.
+		{write("photo.txt", png), "image/png", KindImage},
+		// A zip container is refined by the extension.
+		{write("report.docx", zip), extensions[".docx"], KindDocument},
+		{write("bundle.zip", zip), "application/zip", KindArchive},
+		{write("notes", []byte("plain words")), "text/plain", KindDocument},
+		// Missing files fall back to the extension.
+		{filepath.Join(dir, "gone.mp4"), "video/mp4", KindVideo},
+		{filepath.Join(dir, "gone"), "application/octet-stream", KindOther},
+	}
+	for _, c := range cases {
+		mt := Detect(c.path)
+		if mt != c.mime || Kind(mt) != c.kind {
+			t.Fatalf("%s: got %s/%s, want %s/%s", filepath.Base(c.path), mt, Kind(mt), c.mime, c.kind)
+		}
+	}
+}
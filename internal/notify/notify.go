@@ -0,0 +1,193 @@
+// Package notify escalates repeated failures to humans via webhook, Slack
+// or email.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+const sendTimeout = 10 * time.Second
+
+// Message is a single escalation.
+type Message struct {
+	Subject string        `json:"subject"`
+	Text    string        `json:"text"`
+	Watch   string        `json:"watch"`
+	Action  string        `json:"action,omitempty"`
+	Count   int           `json:"count"`
+	Window  time.Duration `json:"-"`
+}
+
+// Notifier counts failures and notifies targets when a threshold is crossed.
+// A nil Notifier ignores all failures.
+type Notifier struct {
+	cfg    config.Notifications
+	client *http.Client
+	now    func() time.Time
+
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	firedAt  map[string]time.Time
+	wg       sync.WaitGroup
+}
+
+// New returns a notifier, or nil when no thresholds are configured.
+func New(cfg config.Notifications) *Notifier {
+	if len(cfg.Targets) == 0 || (cfg.ActionFailures == nil && cfg.ScanErrors == nil && cfg.SafetyHolds == nil) {
+		return nil
+	}
+	return &Notifier{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: sendTimeout},
+		now:      time.Now,
+		failures: map[string][]time.Time{},
+		firedAt:  map[string]time.Time{},
+	}
+}
+
+// ActionFailed records an action failure.
+func (n *Notifier) ActionFailed(watch, action string, err error) {
+	if n == nil || n.cfg.ActionFailures == nil {
+		return
+	}
+	n.record("action:"+watch+"."+action, n.cfg.ActionFailures, Message{
+		Subject: fmt.Sprintf("watcher: action %s on %s is failing", action, watch),
+		Text:    fmt.Sprintf("last error: %v", err),
+		Watch:   watch,
+		Action:  action,
+	})
+}
+
+// ScanFailed records a failed scan of a watch.
+func (n *Notifier) ScanFailed(watch string, err error) {
+	if n == nil || n.cfg.ScanErrors == nil {
+		return
+	}
+	n.record("scan:"+watch, n.cfg.ScanErrors, Message{
+		Subject: fmt.Sprintf("watcher: scanning %s is failing", watch),
+		Text:    fmt.Sprintf("last error: %v", err),
+		Watch:   watch,
+	})
+}
+
+// SafetyHold records that a watch held back events it distrusts.
+func (n *Notifier) SafetyHold(watch, reason string) {
+	if n == nil || n.cfg.SafetyHolds == nil {
+		return
+	}
+	n.record("hold:"+watch, n.cfg.SafetyHolds, Message{
+		Subject: fmt.Sprintf("watcher: %s is holding back events", watch),
+		Text:    reason,
+		Watch:   watch,
+	})
+}
+
+// Wait blocks until in-flight notifications have been sent.
+func (n *Notifier) Wait() {
+	if n != nil {
+		n.wg.Wait()
+	}
+}
+
+func (n *Notifier) record(key string, th *config.Threshold, msg Message) {
+	window := th.Window.Duration()
+	n.mu.Lock()
+	now := n.now()
+	times := n.failures[key][:0]
+	for _, t := range n.failures[key] {
+		if now.Sub(t) < window {
+			times = append(times, t)
+		}
+	}
+	times = append(times, now)
+	n.failures[key] = times
+	fire := len(times) >= th.Count
+	if last, ok := n.firedAt[key]; ok && now.Sub(last) < window {
+		fire = false
+	}
+	if fire {
+		n.firedAt[key] = now
+		delete(n.failures, key)
+	}
+	n.mu.Unlock()
+	if !fire {
+		return
+	}
+	msg.Count = len(times)
+	msg.Window = window
+	msg.Text = fmt.Sprintf("%d failure(s) within %s; %s", msg.Count, window, msg.Text)
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for _, t := range n.cfg.Targets {
+			if err := n.send(t, msg); err != nil {
+				slog.Error("notification failed", "target", t.Name, "type", t.Type, "err", err)
+			}
+		}
+	}()
+}
+
+func (n *Notifier) send(t config.NotifyTarget, msg Message) error {
+	switch t.Type {
+	case config.NotifyWebhook:
+		return n.post(t.URL, msg)
+	case config.NotifySlack:
+		return n.post(t.URL, map[string]string{"text": "*" + msg.Subject + "*\n" + msg.Text})
+	case config.NotifyEmail:
+		return sendMail(t, msg)
+	default:
+		return fmt.Errorf("unknown notification type %s", t.Type)
+	}
+}
+
+func (n *Notifier) post(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification returned %s", resp.Status)
+	}
+	return nil
+}
+
+func sendMail(t config.NotifyTarget, msg Message) error {
+	var auth smtp.Auth
+	if t.Username != "" {
+		host, _, err := net.SplitHostPort(t.SMTP)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", t.Username, t.Password, host)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", t.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(t.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n\r\n", msg.Subject)
+	b.WriteString(msg.Text + "\r\n")
+	return smtp.SendMail(t.SMTP, auth, t.From, t.To, []byte(b.String()))
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+func TestActionFailuresThreshold(t *testing.T) {
+	var mu sync.Mutex
+	var got []Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m Message
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		mu.Lock()
+		got = append(got, m)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	n := New(config.Notifications{
+		Targets:        []config.NotifyTarget{{Name: "ops", Type: config.NotifyWebhook, URL: srv.URL}},
+		ActionFailures: &config.Threshold{Count: 2, Window: config.MillisFromDuration(10 * time.Minute)},
+	})
+	now := time.Unix(1000, 0)
+	n.now = func() time.Time { return now }
+	boom := errors.New("boom")
+
+	n.ActionFailed("/in", "upload", boom)
+	n.Wait()
+	if len(got) != 0 {
+		t.Fatalf("notified below threshold: %+v", got)
+	}
+	n.ActionFailed("/in", "upload", boom)
+	n.Wait()
+	if len(got) != 1 || got[0].Action != "upload" || got[0].Count != 2 {
+		t.Fatalf("unexpected notifications: %+v", got)
+	}
+	// Further failures inside the window do not repeat the notification.
+	n.ActionFailed("/in", "upload", boom)
+	n.ActionFailed("/in", "upload", boom)
+	n.Wait()
+	if len(got) != 1 {
+		t.Fatalf("expected a single notification per window, got %d", len(got))
+	}
+	now = now.Add(11 * time.Minute)
+	n.ActionFailed("/in", "upload", boom)
+	n.ActionFailed("/in", "upload", boom)
+	n.Wait()
+	if len(got) != 2 {
+		t.Fatalf("expected a new notification after the window, got %d", len(got))
+	}
+}
+
+func TestNewWithoutThresholds(t *testing.T) {
+	n := New(config.Notifications{Targets: []config.NotifyTarget{{Type: config.NotifySlack, URL: "http://x"}}})
+	if n != nil {
+		t.Fatalf("expected nil notifier")
+	}
+	n.ScanFailed("/in", errors.New("boom"))
+}
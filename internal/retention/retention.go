@@ -0,0 +1,55 @@
+package retention
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+type entry struct {
+	path string
+	info scanner.FileInfo
+}
+
+// Plan returns the paths a rule would prune from snap, oldest first.
+func Plan(root string, snap scanner.Snapshot, rule config.Retention, now time.Time) []string {
+	var files []entry
+	for p, info := range snap {
+		if info.IsDir {
+			continue
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || !rule.Matches(rel) {
+			continue
+		}
+		files = append(files, entry{path: p, info: info})
+	}
+	// Newest first so limits keep the most recent files.
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].info.ModTime.Equal(files[j].info.ModTime) {
+			return files[i].path < files[j].path
+		}
+		return files[i].info.ModTime.After(files[j].info.ModTime)
+	})
+	maxAge := rule.MaxAge.Duration()
+	var total int64
+	var pruned []string
+	for i, f := range files {
+		total += f.info.Size
+		switch {
+		case rule.KeepLastN > 0 && i >= rule.KeepLastN:
+		case rule.MaxTotalSizeBytes > 0 && total > rule.MaxTotalSizeBytes:
+		case maxAge > 0 && now.Sub(f.info.ModTime) > maxAge:
+		default:
+			continue
+		}
+		pruned = append(pruned, f.path)
+	}
+	for i, j := 0, len(pruned)-1; i < j; i, j = i+1, j-1 {
+		pruned[i], pruned[j] = pruned[j], pruned[i]
+	}
+	return pruned
+}
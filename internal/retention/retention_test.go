@@ -0,0 +1,36 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+func TestPlanLimits(t *testing.T) {
+	now := time.Now()
+	snap := scanner.Snapshot{
+		"/w/a.log":    {Size: 10, ModTime: now.Add(-4 * time.Hour)},
+		"/w/b.log":    {Size: 10, ModTime: now.Add(-3 * time.Hour)},
+		"/w/c.log":    {Size: 10, ModTime: now.Add(-2 * time.Hour)},
+		"/w/d.log":    {Size: 10, ModTime: now.Add(-1 * time.Hour)},
+		"/w/keep.txt": {Size: 10, ModTime: now.Add(-9 * time.Hour)},
+		"/w/sub":      {IsDir: true, ModTime: now.Add(-9 * time.Hour)},
+	}
+
+	got := Plan("/w", snap, config.Retention{Include: []string{"*.log"}, KeepLastN: 2}, now)
+	if len(got) != 2 || got[0] != "/w/a.log" || got[1] != "/w/b.log" {
+		t.Fatalf("keep_last_n: expected a,b oldest first, got %v", got)
+	}
+
+	got = Plan("/w", snap, config.Retention{Include: []string{"*.log"}, MaxTotalSizeBytes: 30}, now)
+	if len(got) != 1 || got[0] != "/w/a.log" {
+		t.Fatalf("max_total_size_bytes: expected a, got %v", got)
+	}
+
+	got = Plan("/w", snap, config.Retention{Include: []string{"*.log"}, MaxAge: config.MillisFromDuration(150 * time.Minute)}, now)
+	if len(got) != 2 || got[0] != "/w/a.log" || got[1] != "/w/b.log" {
+		t.Fatalf("max_age_ms: expected a,b, got %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+// Package rotate moves append-only files aside once they grow, keeping a
+// bounded number of the old ones.
+package rotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File renames path with now's UTC timestamp before its extension, as in
+// events-20240101T120000.000000000.csv, and drops the oldest rotated files
+// beyond keep.
+func File(path string, now time.Time, keep int) error {
+	ext := filepath.Ext(path)
+	name := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(path, ext), now.UTC().Format("20060102T150405.000000000"), ext)
+	if err := os.Rename(path, name); err != nil {
+		return err
+	}
+	files, err := List(path)
+	if err != nil {
+		return err
+	}
+	for len(files) > keep {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// List returns the rotated files of path, oldest first.
+func List(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	files, err := filepath.Glob(strings.TrimSuffix(path, ext) + "-*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
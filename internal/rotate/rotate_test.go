@@ -0,0 +1,35 @@
+package rotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.csv")
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(path, []byte{byte('a' + i)}, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := File(path, start.Add(time.Duration(i)*time.Second), 2); err != nil {
+			t.Fatalf("rotate: %v", err)
+		}
+	}
+	files, err := List(path)
+	if err != nil || len(files) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v (%v)", files, err)
+	}
+	if filepath.Base(files[0]) != "events-20240101T120001.000000000.csv" {
+		t.Fatalf("oldest kept file is %s", files[0])
+	}
+	if data, _ := os.ReadFile(files[1]); string(data) != "c" {
+		t.Fatalf("newest rotated file holds %q", data)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("current file should be moved aside: %v", err)
+	}
+}
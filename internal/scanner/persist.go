@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"watcher-cli/internal/unorm"
+)
+
+// Saved is a snapshot on disk together with the settings it was taken
+// with; a scanner set up differently would misreport changes.
+type Saved struct {
+	Root      string     `json:"root"`
+	Recursive bool       `json:"recursive"`
+	Normalize unorm.Form `json:"normalize,omitempty"`
+	Xattrs    bool       `json:"xattrs,omitempty"`
+	Entries   Snapshot   `json:"entries"`
+}
+
+// Describe pairs snap with the scanner's settings.
+func (s *Scanner) Describe(snap Snapshot) Saved {
+	return Saved{
+		Root:      s.root,
+		Recursive: s.recursive,
+		Normalize: s.form,
+		Xattrs:    s.xattrs,
+		Entries:   snap,
+	}
+}
+
+// Save writes snap to path atomically.
+func (s *Scanner) Save(path string, snap Snapshot) error {
+	data, err := json.Marshal(s.Describe(snap))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads a snapshot saved by Save. It returns false when there is
+// none or it was taken with other scanner settings.
+func (s *Scanner) Load(path string) (Snapshot, bool, error) {
+	saved, err := ReadSaved(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if saved.Root != s.root || saved.Recursive != s.recursive || saved.Normalize != s.form || saved.Xattrs != s.xattrs {
+		return nil, false, nil
+	}
+	return saved.Entries, true, nil
+}
+
+// ReadSaved reads a snapshot written by Save, whatever its settings.
+func ReadSaved(path string) (Saved, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Saved{}, err
+	}
+	var saved Saved
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return Saved{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if saved.Entries == nil {
+		saved.Entries = Snapshot{}
+	}
+	return saved, nil
+}
@@ -6,8 +6,13 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"watcher-cli/internal/fsys"
+	"watcher-cli/internal/unorm"
+	"watcher-cli/internal/xattr"
 )
 
 // FileInfo captures file metadata relevant for diffing.
@@ -16,11 +21,41 @@ type FileInfo struct {
 	ModTime time.Time
 	IsDir   bool
 	Mode    fs.FileMode
+	// DiskPath is the name on disk when it differs from the normalized
+	// snapshot key.
+	DiskPath string
+	// Xattrs holds user extended attributes (without the "user." prefix)
+	// when the scanner captures them.
+	Xattrs map[string]string
+}
+
+// Special names the kind of a FIFO, socket or device node, or returns ""
+// for regular files, directories and symlinks.
+func (i FileInfo) Special() string {
+	switch {
+	case i.Mode&fs.ModeNamedPipe != 0:
+		return "fifo"
+	case i.Mode&fs.ModeSocket != 0:
+		return "socket"
+	case i.Mode&fs.ModeDevice != 0:
+		return "device"
+	case i.Mode&fs.ModeIrregular != 0:
+		return "irregular"
+	}
+	return ""
 }
 
 // Snapshot maps absolute paths to file info.
 type Snapshot map[string]FileInfo
 
+// DiskPath returns the on-disk name for a snapshot key.
+func (s Snapshot) DiskPath(key string) string {
+	if d := s[key].DiskPath; d != "" {
+		return d
+	}
+	return key
+}
+
 // Event represents a change detected between snapshots.
 type Event struct {
 	Path     string
@@ -29,23 +64,72 @@ type Event struct {
 	Type     string // create, modify, delete, move
 	Info     FileInfo
 	Age      time.Duration
+	// Fields holds values attached by the watch's enrichers.
+	Fields map[string]string
+}
+
+// DiskPath returns the on-disk name of the event's file.
+func (e Event) DiskPath() string {
+	if e.Info.DiskPath != "" {
+		return e.Info.DiskPath
+	}
+	return e.Path
 }
 
 // Scanner walks a root directory to produce a snapshot.
 type Scanner struct {
+	fs        fsys.FS
 	root      string
 	recursive bool
+	form      unorm.Form
+	xattrs    bool
+	lister    Lister
 }
 
-// New creates a scanner for a root.
+// Lister lists a root that is not a directory on a filesystem, such as a
+// bucket prefix. Entries are keyed by slash-separated path relative to
+// the root.
+type Lister interface {
+	List() (map[string]FileInfo, error)
+}
+
+// ErrRemote is returned for single-path lookups under a Lister root.
+var ErrRemote = errors.New("not available for remote sources")
+
+// New creates a scanner for a root on the local filesystem.
 func New(root string, recursive bool) *Scanner {
-	return &Scanner{root: root, recursive: recursive}
+	return NewFS(fsys.OS, root, recursive)
+}
+
+// NewFS creates a scanner for a root on fs.
+func NewFS(fs fsys.FS, root string, recursive bool) *Scanner {
+	return &Scanner{fs: fs, root: root, recursive: recursive}
+}
+
+// NewLister creates a scanner for a root listed by l. Snapshot keys are
+// the root and the entry's relative path joined by a slash.
+func NewLister(root string, l Lister, recursive bool) *Scanner {
+	return &Scanner{root: root, recursive: recursive, lister: l}
+}
+
+// SetNormalization keys snapshots by names normalized to form, so a file
+// written in composed and decomposed form is the same entry.
+func (s *Scanner) SetNormalization(form unorm.Form) {
+	s.form = form
+}
+
+// SetXattrs makes scans capture user extended attributes of local files.
+func (s *Scanner) SetXattrs(on bool) {
+	s.xattrs = on
 }
 
 // Scan walks the root and builds a snapshot.
 func (s *Scanner) Scan() (Snapshot, error) {
+	if s.lister != nil {
+		return s.scanLister()
+	}
 	out := make(Snapshot)
-	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+	err := fsys.WalkDir(s.fs, s.root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -66,12 +150,8 @@ func (s *Scanner) Scan() (Snapshot, error) {
 		if err != nil {
 			return err
 		}
-		out[path] = FileInfo{
-			Size:    info.Size(),
-			ModTime: info.ModTime(),
-			IsDir:   info.IsDir(),
-			Mode:    info.Mode(),
-		}
+		key, fi := s.entry(path, rel, info)
+		out[key] = fi
 		return nil
 	})
 	if err != nil {
@@ -80,16 +160,98 @@ func (s *Scanner) Scan() (Snapshot, error) {
 	return out, nil
 }
 
+// Entry stats a single path the way Scan records it and returns its
+// snapshot key, relative path and info. Paths outside the root keep their
+// name as key and relative path.
+func (s *Scanner) Entry(path string) (string, string, FileInfo, error) {
+	if s.lister != nil {
+		return "", "", FileInfo{}, ErrRemote
+	}
+	info, err := s.fs.Stat(path)
+	if err != nil {
+		return "", "", FileInfo{}, err
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return path, path, s.fileInfo(path, info), nil
+	}
+	key, fi := s.entry(path, rel, info)
+	return key, s.form.String(rel), fi, nil
+}
+
+// scanLister builds a snapshot from the lister's entries.
+func (s *Scanner) scanLister() (Snapshot, error) {
+	entries, err := s.lister.List()
+	if err != nil {
+		return nil, err
+	}
+	out := make(Snapshot, len(entries))
+	for rel, fi := range entries {
+		if !s.recursive && strings.Contains(rel, "/") {
+			continue
+		}
+		key := s.root + "/" + rel
+		if norm := s.form.String(rel); norm != rel {
+			fi.DiskPath = key
+			key = s.root + "/" + norm
+		}
+		out[key] = fi
+	}
+	return out, nil
+}
+
+// entry builds the snapshot key and info of path, rel being its path
+// relative to the root.
+func (s *Scanner) entry(path, rel string, info fs.FileInfo) (string, FileInfo) {
+	fi := s.fileInfo(path, info)
+	key := path
+	if norm := s.form.String(rel); norm != rel {
+		key = filepath.Join(s.root, norm)
+		fi.DiskPath = path
+	}
+	return key, fi
+}
+
+func (s *Scanner) fileInfo(path string, info fs.FileInfo) FileInfo {
+	fi := FileInfo{
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+		Mode:    info.Mode(),
+	}
+	if s.xattrs && fsys.IsOS(s.fs) {
+		fi.Xattrs = xattr.List(path)
+	}
+	return fi
+}
+
 // Diff compares previous and current snapshots.
 func Diff(root string, prev, curr Snapshot) []Event {
+	return diff(root, prev, curr, false)
+}
+
+// DiffCaseInsensitive compares snapshots of a case-insensitive filesystem:
+// a path whose name changed only in case is reported as a move, whether
+// or not its content changed too.
+func DiffCaseInsensitive(root string, prev, curr Snapshot) []Event {
+	return diff(root, prev, curr, true)
+}
+
+func diff(root string, prev, curr Snapshot, foldCase bool) []Event {
 	events := []Event{}
 	prevBySignature := map[string]string{}
 	deletes := map[string]Event{}
 	modifies := []Event{}
 	creates := []Event{}
-	moves := []Event{}
+	moves, movedFrom, movedTo := dirMoves(root, prev, curr)
+	if foldCase {
+		moves = append(moves, caseRenames(root, prev, curr, movedFrom, movedTo)...)
+	}
 
 	for p, info := range prev {
+		if _, ok := movedFrom[p]; ok {
+			continue
+		}
 		sig := signature(info)
 		prevBySignature[sig] = p
 		if _, exists := curr[p]; !exists {
@@ -102,6 +264,9 @@ func Diff(root string, prev, curr Snapshot) []Event {
 		}
 	}
 	for p, info := range curr {
+		if _, ok := movedTo[p]; ok {
+			continue
+		}
 		if prevInfo, exists := prev[p]; exists {
 			if hasChanged(prevInfo, info) {
 				modifies = append(modifies, Event{
@@ -135,19 +300,171 @@ func Diff(root string, prev, curr Snapshot) []Event {
 			})
 		}
 	}
-	for _, ev := range modifies {
-		events = append(events, ev)
+	events = append(events, byPath(modifies)...)
+	events = append(events, byPath(moves)...)
+	events = append(events, byPath(creates)...)
+	dels := make([]Event, 0, len(deletes))
+	for _, ev := range deletes {
+		dels = append(dels, ev)
 	}
-	for _, ev := range moves {
-		events = append(events, ev)
+	return append(events, byPath(dels)...)
+}
+
+// byPath sorts events by path, so each type comes out of diff in the
+// same order on every run.
+func byPath(events []Event) []Event {
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+// caseRenames pairs paths that vanished from prev with new paths in curr
+// equal to them but for case, adding both to movedFrom and movedTo. A
+// folded name shared by several vanished paths is left to the signature
+// match.
+func caseRenames(root string, prev, curr Snapshot, movedFrom, movedTo map[string]struct{}) []Event {
+	gone := map[string]string{}
+	for p := range prev {
+		if _, ok := movedFrom[p]; ok {
+			continue
+		}
+		if _, ok := curr[p]; ok {
+			continue
+		}
+		key := unorm.FoldCase(p)
+		if _, dup := gone[key]; dup {
+			gone[key] = ""
+			continue
+		}
+		gone[key] = p
 	}
-	for _, ev := range creates {
-		events = append(events, ev)
+	if len(gone) == 0 {
+		return nil
 	}
-	for _, ev := range deletes {
-		events = append(events, ev)
+	var moves []Event
+	for p, info := range curr {
+		if _, ok := movedTo[p]; ok {
+			continue
+		}
+		if _, ok := prev[p]; ok {
+			continue
+		}
+		old := gone[unorm.FoldCase(p)]
+		if old == "" {
+			continue
+		}
+		// Claim it, so of two new paths only one takes it.
+		gone[unorm.FoldCase(p)] = ""
+		moves = append(moves, Event{
+			Path:     p,
+			PrevPath: old,
+			RelPath:  rel(root, p),
+			Type:     "move",
+			Info:     info,
+			Age:      age(info),
+		})
+		movedFrom[old] = struct{}{}
+		movedTo[p] = struct{}{}
 	}
-	return events
+	sort.Slice(moves, func(i, j int) bool { return moves[i].Path < moves[j].Path })
+	return moves
+}
+
+// dirMoves pairs directories that vanished from prev with new directories
+// in curr holding the same entries (names and signatures), i.e. renamed or
+// moved folders. Each pair yields a move for the directory and for every
+// entry below it, with PrevPath set, instead of unrelated deletes and
+// creates. movedFrom and movedTo hold the paths covered.
+func dirMoves(root string, prev, curr Snapshot) (moves []Event, movedFrom, movedTo map[string]struct{}) {
+	movedFrom, movedTo = map[string]struct{}{}, map[string]struct{}{}
+	gone := topDirs(prev, curr)
+	added := topDirs(curr, prev)
+	if len(gone) == 0 || len(added) == 0 {
+		return nil, movedFrom, movedTo
+	}
+	byContent := map[string][]string{}
+	for _, d := range gone {
+		if fp := fingerprint(prev, d); fp != "" {
+			byContent[fp] = append(byContent[fp], d)
+		}
+	}
+	sort.Strings(added)
+	for _, d := range added {
+		fp := fingerprint(curr, d)
+		candidates := byContent[fp]
+		if fp == "" || len(candidates) == 0 {
+			continue
+		}
+		from := candidates[0]
+		byContent[fp] = candidates[1:]
+		for _, sub := range subtree(curr, d) {
+			to := d
+			old := from
+			if sub != "" {
+				to = filepath.Join(d, sub)
+				old = filepath.Join(from, sub)
+			}
+			info := curr[to]
+			moves = append(moves, Event{
+				Path:     to,
+				PrevPath: old,
+				RelPath:  rel(root, to),
+				Type:     "move",
+				Info:     info,
+				Age:      age(info),
+			})
+			movedFrom[old] = struct{}{}
+			movedTo[to] = struct{}{}
+		}
+	}
+	return moves, movedFrom, movedTo
+}
+
+// topDirs returns directories in a that are missing from b and whose parent
+// is not itself missing.
+func topDirs(a, b Snapshot) []string {
+	var out []string
+	for p, info := range a {
+		if !info.IsDir {
+			continue
+		}
+		if _, ok := b[p]; ok {
+			continue
+		}
+		parent := filepath.Dir(p)
+		if pi, ok := a[parent]; ok && pi.IsDir {
+			if _, inB := b[parent]; !inB {
+				continue
+			}
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// subtree lists dir ("") and the paths below it relative to dir, sorted.
+func subtree(s Snapshot, dir string) []string {
+	prefix := dir + string(filepath.Separator)
+	out := []string{""}
+	for p := range s {
+		if strings.HasPrefix(p, prefix) {
+			out = append(out, p[len(prefix):])
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// fingerprint describes the entries below dir; empty directories have
+// none and are never paired.
+func fingerprint(s Snapshot, dir string) string {
+	var b strings.Builder
+	for _, sub := range subtree(s, dir)[1:] {
+		b.WriteString(sub)
+		b.WriteByte(0)
+		b.WriteString(signature(s[filepath.Join(dir, sub)]))
+		b.WriteByte(0)
+	}
+	return b.String()
 }
 
 func hasChanged(prev, curr FileInfo) bool {
@@ -5,6 +5,9 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"watcher-cli/internal/fsys"
+	"watcher-cli/internal/unorm"
 )
 
 func TestDiffCreateModifyMoveDelete(t *testing.T) {
@@ -53,3 +56,141 @@ func TestDiffCreateModifyMoveDelete(t *testing.T) {
 		t.Fatalf("expected delete event, got %#v", evs)
 	}
 }
+
+func TestScanMemFS(t *testing.T) {
+	mem := fsys.NewMem()
+	root := filepath.FromSlash("/watch")
+	if err := mem.WriteFile(filepath.Join(root, "a.txt"), []byte("a")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := mem.WriteFile(filepath.Join(root, "sub", "b.txt"), []byte("bb")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	snap, err := NewFS(mem, root, false).Scan()
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(snap) != 2 || snap[filepath.Join(root, "a.txt")].Size != 1 || !snap[filepath.Join(root, "sub")].IsDir {
+		t.Fatalf("unexpected non-recursive snapshot %#v", snap)
+	}
+	prev, _ := NewFS(mem, root, true).Scan()
+	if len(prev) != 3 {
+		t.Fatalf("expected 3 entries, got %#v", prev)
+	}
+	if err := mem.Rename(filepath.Join(root, "sub"), filepath.Join(root, "moved")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	curr, _ := NewFS(mem, root, true).Scan()
+	if _, ok := curr[filepath.Join(root, "moved", "b.txt")]; !ok {
+		t.Fatalf("expected renamed child, got %#v", curr)
+	}
+}
+
+func TestDiffDirectoryRenamePairsEntries(t *testing.T) {
+	dir := t.TempDir()
+	mtime := time.Unix(1700000000, 0)
+	for _, name := range []string{"a.txt", "b.txt", "sub/c.txt"} {
+		p := filepath.Join(dir, "old", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		// Same size and mtime, so signatures alone cannot pair them.
+		if err := os.WriteFile(p, []byte("same"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	scn := New(dir, true)
+	prev, _ := scn.Scan()
+	if err := os.Rename(filepath.Join(dir, "old"), filepath.Join(dir, "new")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	curr, _ := scn.Scan()
+	evs := Diff(dir, prev, curr)
+	if len(evs) != 5 {
+		t.Fatalf("expected 5 move events, got %#v", evs)
+	}
+	for _, ev := range evs {
+		if ev.Type != "move" {
+			t.Fatalf("expected only moves, got %#v", ev)
+		}
+		rel, _ := filepath.Rel(filepath.Join(dir, "new"), ev.Path)
+		want := filepath.Join(dir, "old", rel)
+		if rel == "." {
+			want = filepath.Join(dir, "old")
+		}
+		if ev.PrevPath != want {
+			t.Fatalf("%s: prev path %s, want %s", ev.Path, ev.PrevPath, want)
+		}
+	}
+}
+
+func TestScanNormalizesNames(t *testing.T) {
+	mem := fsys.NewMem()
+	root := filepath.FromSlash("/watch")
+	decomposed := filepath.Join(root, "Cafe\u0301.txt")
+	if err := mem.WriteFile(decomposed, []byte("a")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	scn := NewFS(mem, root, true)
+	scn.SetNormalization(unorm.NFC)
+	prev, _ := scn.Scan()
+	key := filepath.Join(root, "Caf\u00e9.txt")
+	if prev.DiskPath(key) != decomposed {
+		t.Fatalf("expected composed key for %q, got %#v", decomposed, prev)
+	}
+	// Rewriting the name in composed form is not a change.
+	if err := mem.Rename(decomposed, key); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	curr, _ := scn.Scan()
+	if events := Diff(root, prev, curr); len(events) != 0 {
+		t.Fatalf("expected no events, got %#v", events)
+	}
+}
+
+func TestSavedSnapshotRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hi"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	scn := New(dir, true)
+	snap, _ := scn.Scan()
+	state := filepath.Join(t.TempDir(), "snap.json")
+	if err := scn.Save(state, snap); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, ok, err := scn.Load(state)
+	if err != nil || !ok {
+		t.Fatalf("load: ok=%v err=%v", ok, err)
+	}
+	if events := Diff(dir, loaded, snap); len(events) != 0 {
+		t.Fatalf("restored snapshot differs: %#v", events)
+	}
+	// A scanner with other settings must not reuse it.
+	if _, ok, _ := New(dir, false).Load(state); ok {
+		t.Fatalf("snapshot of a recursive scan loaded by a flat one")
+	}
+}
+
+func TestDiffCaseInsensitivePairsCaseOnlyRenames(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := Snapshot{
+		"/in/Report.pdf": {Size: 10, ModTime: t0},
+		"/in/a.txt":      {Size: 1, ModTime: t0},
+	}
+	// Renamed and rewritten: no signature to pair it by.
+	curr := Snapshot{
+		"/in/report.pdf": {Size: 12, ModTime: t0.Add(time.Second)},
+		"/in/a.txt":      {Size: 1, ModTime: t0},
+	}
+	if evs := Diff("/in", prev, curr); len(evs) != 2 {
+		t.Fatalf("expected create and delete on a case-sensitive diff, got %#v", evs)
+	}
+	evs := DiffCaseInsensitive("/in", prev, curr)
+	if len(evs) != 1 || evs[0].Type != "move" || evs[0].PrevPath != "/in/Report.pdf" || evs[0].Path != "/in/report.pdf" || evs[0].RelPath != "report.pdf" {
+		t.Fatalf("expected case-only move, got %#v", evs)
+	}
+}
@@ -0,0 +1,101 @@
+// Package service registers the watcher as a system service: a systemd
+// unit on Linux and a service of the Windows service control manager.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultName is the service name used when none is given.
+const DefaultName = "watcher"
+
+// ErrUnsupported is returned by Install and Uninstall on platforms
+// without a supported service manager.
+var ErrUnsupported = errors.New("services are supported with systemd on Linux and on Windows")
+
+// Options describe the service to install.
+type Options struct {
+	Name string
+	// Executable and Config are absolute paths of the watcher binary and
+	// the config it runs.
+	Executable string
+	Config     string
+	// RunAs is the account the systemd service runs as; empty is root.
+	RunAs string
+	// UserUnit installs a systemd --user unit instead of a system unit.
+	UserUnit bool
+	// Watchdog is the systemd WatchdogSec; 0 disables the watchdog.
+	Watchdog time.Duration
+	// Force replaces an existing unit file.
+	Force bool
+}
+
+// Args returns the arguments the service starts the watcher with.
+func (o Options) Args() []string {
+	return []string{"service", "run", "--config", o.Config, "--name", o.Name}
+}
+
+// Validate checks the options before anything is installed.
+func (o Options) Validate() error {
+	if o.Name == "" || strings.ContainsAny(o.Name, `/\ `) {
+		return fmt.Errorf("invalid service name %q", o.Name)
+	}
+	if o.Executable == "" || o.Config == "" {
+		return errors.New("service needs the watcher executable and config paths")
+	}
+	if o.Watchdog < 0 {
+		return errors.New("watchdog must be >= 0")
+	}
+	return nil
+}
+
+// Unit returns the systemd unit for o. The watcher reports readiness and
+// pings the watchdog itself, so the unit is Type=notify.
+func Unit(o Options) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=watcher (%s)\n", o.Config)
+	b.WriteString("After=network-online.target\nWants=network-online.target\n\n")
+	b.WriteString("[Service]\nType=notify\nNotifyAccess=main\n")
+	args := append([]string{o.Executable}, o.Args()...)
+	for i, a := range args {
+		args[i] = systemdQuote(a)
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+	b.WriteString("Restart=on-failure\nRestartSec=5s\n")
+	if o.Watchdog > 0 {
+		fmt.Fprintf(&b, "WatchdogSec=%ds\n", int64((o.Watchdog+time.Second-1)/time.Second))
+	}
+	if o.RunAs != "" && !o.UserUnit {
+		fmt.Fprintf(&b, "User=%s\n", o.RunAs)
+	}
+	target := "multi-user.target"
+	if o.UserUnit {
+		target = "default.target"
+	}
+	fmt.Fprintf(&b, "\n[Install]\nWantedBy=%s\n", target)
+	return b.String()
+}
+
+// systemdQuote quotes an ExecStart word, escaping the specifiers and
+// variables systemd would otherwise expand.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\;") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// runInForeground runs until SIGINT or SIGTERM, as `watcher run` does.
+func runInForeground(run func(ctx context.Context) error) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return run(ctx)
+}
@@ -0,0 +1,85 @@
+//go:build linux
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Install writes the unit for o, then enables and starts it.
+func Install(o Options) (string, error) {
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	path, err := unitPath(o)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil && !o.Force {
+		return "", fmt.Errorf("%s exists; uninstall it first or use --force", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(Unit(o)), 0o644); err != nil {
+		return "", err
+	}
+	if err := systemctl(o, "daemon-reload"); err != nil {
+		return path, err
+	}
+	return path, systemctl(o, "enable", "--now", o.Name+".service")
+}
+
+// Uninstall stops and disables the unit and removes its file.
+func Uninstall(o Options) (string, error) {
+	path, err := unitPath(o)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("no unit at %s", path)
+	}
+	if err := systemctl(o, "disable", "--now", o.Name+".service"); err != nil {
+		return path, err
+	}
+	if err := os.Remove(path); err != nil {
+		return path, err
+	}
+	return path, systemctl(o, "daemon-reload")
+}
+
+// Run runs the watcher under systemd, which stops it with SIGTERM.
+func Run(name string, run func(ctx context.Context) error) error {
+	return runInForeground(run)
+}
+
+func unitPath(o Options) (string, error) {
+	if !o.UserUnit {
+		return filepath.Join("/etc/systemd/system", o.Name+".service"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "systemd", "user", o.Name+".service"), nil
+}
+
+func systemctl(o Options, args ...string) error {
+	if o.UserUnit {
+		args = append([]string{"--user"}, args...)
+	}
+	out, err := exec.Command("systemctl", args...).CombinedOutput()
+	if errors.Is(err, exec.ErrNotFound) {
+		return errors.New("systemctl not found; is this host running systemd?")
+	}
+	if err != nil {
+		return fmt.Errorf("systemctl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
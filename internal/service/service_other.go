@@ -0,0 +1,14 @@
+//go:build !linux && !windows
+
+package service
+
+import "context"
+
+func Install(o Options) (string, error) { return "", ErrUnsupported }
+
+func Uninstall(o Options) (string, error) { return "", ErrUnsupported }
+
+// Run runs the watcher in the foreground, e.g. under launchd.
+func Run(name string, run func(ctx context.Context) error) error {
+	return runInForeground(run)
+}
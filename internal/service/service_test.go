@@ -0,0 +1,39 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnit(t *testing.T) {
+	unit := Unit(Options{
+		Name:       "watcher",
+		Executable: "/usr/local/bin/watcher",
+		Config:     "/etc/watcher/my 100%.yaml",
+		RunAs:      "svc",
+		Watchdog:   1500 * time.Millisecond,
+	})
+	for _, want := range []string{
+		"Type=notify\n",
+		`ExecStart=/usr/local/bin/watcher service run --config "/etc/watcher/my 100%%.yaml" --name watcher` + "\n",
+		"WatchdogSec=2s\n",
+		"User=svc\n",
+		"WantedBy=multi-user.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Fatalf("unit lacks %q:\n%s", want, unit)
+		}
+	}
+	user := Unit(Options{Name: "w", Executable: "/bin/watcher", Config: "/c.yaml", RunAs: "svc", UserUnit: true})
+	if strings.Contains(user, "User=") || strings.Contains(user, "WatchdogSec") || !strings.Contains(user, "WantedBy=default.target") {
+		t.Fatalf("unexpected user unit:\n%s", user)
+	}
+}
+
+func TestValidateRejectsBadName(t *testing.T) {
+	o := Options{Name: "a/b", Executable: "/bin/watcher", Config: "/c.yaml"}
+	if err := o.Validate(); err == nil {
+		t.Fatalf("expected error for name %q", o.Name)
+	}
+}
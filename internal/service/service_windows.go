@@ -0,0 +1,182 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Install registers o with the service control manager, set to start
+// automatically and to restart after a crash, and starts it.
+func Install(o Options) (string, error) {
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	if o.RunAs != "" || o.UserUnit {
+		return "", errors.New("--run-as and --user are systemd options; pick the account in services.msc")
+	}
+	words := append([]string{o.Executable}, o.Args()...)
+	for i, w := range words {
+		words[i] = syscall.EscapeArg(w)
+	}
+	if o.Force {
+		sc("stop", o.Name)
+		sc("delete", o.Name)
+	}
+	if err := sc("create", o.Name, "binPath=", strings.Join(words, " "), "start=", "auto", "DisplayName=", "watcher ("+o.Name+")"); err != nil {
+		return "", err
+	}
+	if err := sc("description", o.Name, "watcher running "+o.Config); err != nil {
+		return o.Name, err
+	}
+	if err := sc("failure", o.Name, "reset=", "86400", "actions=", "restart/5000/restart/5000/restart/60000"); err != nil {
+		return o.Name, err
+	}
+	return o.Name, sc("start", o.Name)
+}
+
+// Uninstall stops the service and removes it.
+func Uninstall(o Options) (string, error) {
+	sc("stop", o.Name)
+	return o.Name, sc("delete", o.Name)
+}
+
+func sc(args ...string) error {
+	out, err := exec.Command("sc.exe", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("sc %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+var (
+	advapi32                         = syscall.NewLazyDLL("advapi32.dll")
+	procStartServiceCtrlDispatcher   = advapi32.NewProc("StartServiceCtrlDispatcherW")
+	procRegisterServiceCtrlHandlerEx = advapi32.NewProc("RegisterServiceCtrlHandlerExW")
+	procSetServiceStatus             = advapi32.NewProc("SetServiceStatus")
+)
+
+const (
+	serviceWin32OwnProcess = 0x10
+
+	stateStopped     = 1
+	stateStopPending = 3
+	stateRunning     = 4
+
+	acceptStop     = 0x1
+	acceptShutdown = 0x4
+
+	controlStop        = 1
+	controlInterrogate = 4
+	controlShutdown    = 5
+
+	errServiceSpecific = 1066
+	// errNotAService is returned by the dispatcher when the process was
+	// not started by the service control manager.
+	errNotAService = syscall.Errno(1063)
+)
+
+type serviceStatus struct {
+	ServiceType             uint32
+	CurrentState            uint32
+	ControlsAccepted        uint32
+	Win32ExitCode           uint32
+	ServiceSpecificExitCode uint32
+	CheckPoint              uint32
+	WaitHint                uint32
+}
+
+type serviceTableEntry struct {
+	name *uint16
+	proc uintptr
+}
+
+// scm is the state of the one service a process runs; the dispatcher
+// calls serviceMain and serviceHandler on threads of its own.
+var scm struct {
+	mu     sync.Mutex
+	name   *uint16
+	run    func(ctx context.Context) error
+	cancel context.CancelFunc
+	handle uintptr
+	state  uint32
+	err    error
+}
+
+// Run hands the process to the service control manager, which starts
+// run and cancels its context on stop or shutdown. Started from a
+// console instead, it runs until Ctrl-C.
+func Run(name string, run func(ctx context.Context) error) error {
+	p, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	scm.name, scm.run = p, run
+	table := []serviceTableEntry{{name: p, proc: syscall.NewCallback(serviceMain)}, {}}
+	r, _, callErr := procStartServiceCtrlDispatcher.Call(uintptr(unsafe.Pointer(&table[0])))
+	if r == 0 {
+		if callErr == errNotAService {
+			return runInForeground(run)
+		}
+		return fmt.Errorf("start service dispatcher: %w", callErr)
+	}
+	return scm.err
+}
+
+func serviceMain(argc, argv uintptr) uintptr {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scm.mu.Lock()
+	scm.cancel = cancel
+	scm.mu.Unlock()
+	h, _, err := procRegisterServiceCtrlHandlerEx.Call(uintptr(unsafe.Pointer(scm.name)), syscall.NewCallback(serviceHandler), 0)
+	if h == 0 {
+		scm.err = fmt.Errorf("register service handler: %w", err)
+		return 0
+	}
+	scm.handle = h
+	setState(stateRunning, nil)
+	scm.err = scm.run(ctx)
+	setState(stateStopped, scm.err)
+	return 0
+}
+
+func serviceHandler(control, eventType, eventData, handlerContext uintptr) uintptr {
+	switch control {
+	case controlStop, controlShutdown:
+		setState(stateStopPending, nil)
+		scm.mu.Lock()
+		scm.cancel()
+		scm.mu.Unlock()
+	case controlInterrogate:
+		scm.mu.Lock()
+		state := scm.state
+		scm.mu.Unlock()
+		setState(state, nil)
+	}
+	return 0
+}
+
+func setState(state uint32, exitErr error) {
+	scm.mu.Lock()
+	defer scm.mu.Unlock()
+	scm.state = state
+	s := serviceStatus{ServiceType: serviceWin32OwnProcess, CurrentState: state}
+	switch state {
+	case stateRunning:
+		s.ControlsAccepted = acceptStop | acceptShutdown
+	case stateStopPending:
+		s.WaitHint = 30000
+	}
+	if exitErr != nil {
+		s.Win32ExitCode, s.ServiceSpecificExitCode = errServiceSpecific, 1
+	}
+	procSetServiceStatus.Call(scm.handle, uintptr(unsafe.Pointer(&s)))
+}
@@ -0,0 +1,516 @@
+// Package sftp lists and reads remote directories over SFTP (protocol
+// version 3), speaking to the sftp subsystem through the system ssh
+// client. Only the read-only requests a watch needs are implemented.
+package sftp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Packet types.
+const (
+	fxpInit      = 1
+	fxpVersion   = 2
+	fxpOpen      = 3
+	fxpClose     = 4
+	fxpRead      = 5
+	fxpOpendir   = 11
+	fxpReaddir   = 12
+	fxpStatus    = 101
+	fxpHandle    = 102
+	fxpData      = 103
+	fxpName      = 104
+	fxpAttrs     = 105
+	fxfRead      = 1
+	fxOK         = 0
+	fxEOF        = 1
+	fxNoSuchFile = 2
+)
+
+// Attribute flags.
+const (
+	attrSize        = 0x1
+	attrUIDGID      = 0x2
+	attrPermissions = 0x4
+	attrACModTime   = 0x8
+	attrExtended    = 0x80000000
+)
+
+// maxPacket bounds incoming packets; servers send at most 256 KiB.
+const maxPacket = 1 << 20
+
+// readChunk is the size of each read request.
+const readChunk = 32 << 10
+
+// StatusError is a failed request.
+type StatusError struct {
+	Code uint32
+	Msg  string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("sftp: %s (code %d)", e.Msg, e.Code)
+}
+
+// Is makes a missing file match fs.ErrNotExist.
+func (e *StatusError) Is(target error) bool {
+	return target == fs.ErrNotExist && e.Code == fxNoSuchFile
+}
+
+// Entry is a directory entry.
+type Entry struct {
+	Name    string
+	Size    int64
+	Mode    fs.FileMode
+	ModTime time.Time
+}
+
+// IsDir reports whether the entry is a directory.
+func (e Entry) IsDir() bool { return e.Mode.IsDir() }
+
+// DefaultTimeout bounds each request when Client.Timeout is unset.
+const DefaultTimeout = 30 * time.Second
+
+// Client is a session with the sftp subsystem. Requests are serialized.
+type Client struct {
+	// Timeout bounds each request; a request without a reply in time
+	// closes the session.
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	w      io.WriteCloser
+	r      io.Reader
+	nextID uint32
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Start runs cmd, which must connect its stdin and stdout to an sftp
+// subsystem (e.g. ssh -s host sftp), and negotiates the protocol.
+func Start(cmd *exec.Cmd) (*Client, error) {
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	c, err := NewClient(r, w)
+	if err != nil {
+		w.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, err
+	}
+	c.cmd = cmd
+	return c, nil
+}
+
+// NewClient negotiates a session over a connected reader and writer.
+func NewClient(r io.Reader, w io.WriteCloser) (*Client, error) {
+	c := &Client{r: r, w: w}
+	var init packet
+	init.byte(fxpInit)
+	init.uint32(3)
+	if err := c.send(init); err != nil {
+		return nil, err
+	}
+	typ, _, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	if typ != fxpVersion {
+		return nil, fmt.Errorf("sftp: unexpected packet %d during init", typ)
+	}
+	return c, nil
+}
+
+// Close ends the session. It does not wait for a request in flight, which
+// fails.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() { c.closeErr = c.shutdown() })
+	return c.closeErr
+}
+
+func (c *Client) shutdown() error {
+	err := c.w.Close()
+	if r, ok := c.r.(io.Closer); ok {
+		r.Close()
+	}
+	if c.cmd != nil {
+		// The session only reads, so there is nothing to flush before
+		// stopping the client.
+		_ = c.cmd.Process.Kill()
+		_ = c.cmd.Wait()
+	}
+	return err
+}
+
+// ReadDir lists a directory, without "." and "..".
+func (c *Client) ReadDir(path string) ([]Entry, error) {
+	handle, err := c.openHandle(fxpOpendir, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeHandle(handle)
+	var out []Entry
+	for {
+		entries, err := c.readdir(handle)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Name != "." && e.Name != ".." {
+				out = append(out, e)
+			}
+		}
+	}
+}
+
+// Open opens a file for sequential reading.
+func (c *Client) Open(path string) (io.ReadCloser, error) {
+	handle, err := c.openHandle(fxpOpen, path, fxfRead)
+	if err != nil {
+		return nil, err
+	}
+	return &file{c: c, handle: handle}, nil
+}
+
+type file struct {
+	c      *Client
+	handle string
+	offset uint64
+	eof    bool
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	if f.eof {
+		return 0, io.EOF
+	}
+	if len(p) > readChunk {
+		p = p[:readChunk]
+	}
+	n, err := f.c.read(f.handle, f.offset, p)
+	f.offset += uint64(n)
+	if errors.Is(err, io.EOF) {
+		f.eof = true
+		if n > 0 {
+			err = nil
+		}
+	}
+	return n, err
+}
+
+func (f *file) Close() error {
+	return f.c.closeHandle(f.handle)
+}
+
+func (c *Client) openHandle(typ byte, path string, pflags uint32) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var p packet
+	p.byte(typ)
+	p.uint32(c.id())
+	p.string(path)
+	if typ == fxpOpen {
+		p.uint32(pflags)
+		p.uint32(0) // no attributes
+	}
+	rtyp, body, err := c.roundTrip(p)
+	if err != nil {
+		return "", err
+	}
+	if rtyp != fxpHandle {
+		return "", unexpected(rtyp)
+	}
+	return body.string()
+}
+
+func (c *Client) closeHandle(handle string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var p packet
+	p.byte(fxpClose)
+	p.uint32(c.id())
+	p.string(handle)
+	_, _, err := c.roundTrip(p)
+	return err
+}
+
+func (c *Client) readdir(handle string) ([]Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var p packet
+	p.byte(fxpReaddir)
+	p.uint32(c.id())
+	p.string(handle)
+	rtyp, body, err := c.roundTrip(p)
+	if err != nil {
+		return nil, err
+	}
+	if rtyp != fxpName {
+		return nil, unexpected(rtyp)
+	}
+	count, err := body.uint32()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Entry, 0, count)
+	for i := uint32(0); i < count; i++ {
+		name, err := body.string()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := body.string(); err != nil { // long name
+			return nil, err
+		}
+		e, err := body.attrs()
+		if err != nil {
+			return nil, err
+		}
+		e.Name = name
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (c *Client) read(handle string, offset uint64, buf []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var p packet
+	p.byte(fxpRead)
+	p.uint32(c.id())
+	p.string(handle)
+	p.uint64(offset)
+	p.uint32(uint32(len(buf)))
+	rtyp, body, err := c.roundTrip(p)
+	if err != nil {
+		return 0, err
+	}
+	if rtyp != fxpData {
+		return 0, unexpected(rtyp)
+	}
+	data, err := body.string()
+	if err != nil {
+		return 0, err
+	}
+	return copy(buf, data), nil
+}
+
+func (c *Client) id() uint32 {
+	c.nextID++
+	return c.nextID
+}
+
+// roundTrip sends a request and returns its reply. A status reply is
+// returned as an error unless it is OK; EOF becomes io.EOF. A request
+// without a reply within the timeout closes the session.
+func (c *Client) roundTrip(p packet) (byte, *reader, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	timer := time.AfterFunc(timeout, func() { c.Close() })
+	typ, body, err := c.exchange(p)
+	if !timer.Stop() {
+		return 0, nil, fmt.Errorf("sftp: no reply within %s", timeout)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	id, err := body.uint32()
+	if err != nil {
+		return 0, nil, err
+	}
+	if id != c.nextID {
+		// Requests are serialized, so any other id means the stream is
+		// out of step and no later reply can be trusted either.
+		c.Close()
+		return 0, nil, fmt.Errorf("sftp: reply to request %d while waiting for %d", id, c.nextID)
+	}
+	if typ != fxpStatus {
+		return typ, body, nil
+	}
+	code, err := body.uint32()
+	if err != nil {
+		return 0, nil, err
+	}
+	switch code {
+	case fxOK:
+		return typ, body, nil
+	case fxEOF:
+		return 0, nil, io.EOF
+	}
+	msg, _ := body.string()
+	return 0, nil, &StatusError{Code: code, Msg: msg}
+}
+
+func (c *Client) exchange(p packet) (byte, *reader, error) {
+	if err := c.send(p); err != nil {
+		return 0, nil, err
+	}
+	return c.recv()
+}
+
+func (c *Client) send(p packet) error {
+	frame := make([]byte, 4, 4+len(p))
+	binary.BigEndian.PutUint32(frame, uint32(len(p)))
+	_, err := c.w.Write(append(frame, p...))
+	return err
+}
+
+func (c *Client) recv() (byte, *reader, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(c.r, size[:]); err != nil {
+		return 0, nil, fmt.Errorf("sftp: connection lost: %w", err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n == 0 || n > maxPacket {
+		return 0, nil, fmt.Errorf("sftp: bad packet length %d", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(c.r, buf); err != nil {
+		return 0, nil, fmt.Errorf("sftp: connection lost: %w", err)
+	}
+	return buf[0], &reader{b: buf[1:]}, nil
+}
+
+func unexpected(typ byte) error {
+	return fmt.Errorf("sftp: unexpected reply %d", typ)
+}
+
+// packet builds a request.
+type packet []byte
+
+func (p *packet) byte(b byte) { *p = append(*p, b) }
+
+func (p *packet) uint32(v uint32) { *p = binary.BigEndian.AppendUint32(*p, v) }
+
+func (p *packet) uint64(v uint64) { *p = binary.BigEndian.AppendUint64(*p, v) }
+
+func (p *packet) string(s string) {
+	p.uint32(uint32(len(s)))
+	*p = append(*p, s...)
+}
+
+// reader decodes a reply.
+type reader struct{ b []byte }
+
+var errShort = errors.New("sftp: short packet")
+
+func (r *reader) uint32() (uint32, error) {
+	if len(r.b) < 4 {
+		return 0, errShort
+	}
+	v := binary.BigEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v, nil
+}
+
+func (r *reader) uint64() (uint64, error) {
+	if len(r.b) < 8 {
+		return 0, errShort
+	}
+	v := binary.BigEndian.Uint64(r.b)
+	r.b = r.b[8:]
+	return v, nil
+}
+
+func (r *reader) string() (string, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return "", err
+	}
+	if uint32(len(r.b)) < n {
+		return "", errShort
+	}
+	s := string(r.b[:n])
+	r.b = r.b[n:]
+	return s, nil
+}
+
+// attrs decodes a file attribute block.
+func (r *reader) attrs() (Entry, error) {
+	var e Entry
+	flags, err := r.uint32()
+	if err != nil {
+		return e, err
+	}
+	if flags&attrSize != 0 {
+		size, err := r.uint64()
+		if err != nil {
+			return e, err
+		}
+		e.Size = int64(size)
+	}
+	if flags&attrUIDGID != 0 {
+		if _, err := r.uint64(); err != nil {
+			return e, err
+		}
+	}
+	if flags&attrPermissions != 0 {
+		perm, err := r.uint32()
+		if err != nil {
+			return e, err
+		}
+		e.Mode = fileMode(perm)
+	}
+	if flags&attrACModTime != 0 {
+		if _, err := r.uint32(); err != nil { // atime
+			return e, err
+		}
+		mtime, err := r.uint32()
+		if err != nil {
+			return e, err
+		}
+		e.ModTime = time.Unix(int64(mtime), 0)
+	}
+	if flags&attrExtended != 0 {
+		count, err := r.uint32()
+		if err != nil {
+			return e, err
+		}
+		for i := uint32(0); i < 2*count; i++ {
+			if _, err := r.string(); err != nil {
+				return e, err
+			}
+		}
+	}
+	return e, nil
+}
+
+// fileMode converts POSIX st_mode bits.
+func fileMode(perm uint32) fs.FileMode {
+	mode := fs.FileMode(perm & 0o777)
+	switch perm & 0o170000 {
+	case 0o040000:
+		mode |= fs.ModeDir
+	case 0o120000:
+		mode |= fs.ModeSymlink
+	case 0o010000:
+		mode |= fs.ModeNamedPipe
+	case 0o140000:
+		mode |= fs.ModeSocket
+	case 0o020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0o060000:
+		mode |= fs.ModeDevice
+	}
+	return mode
+}
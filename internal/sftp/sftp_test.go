@@ -0,0 +1,242 @@
+package sftp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/sshcmd"
+)
+
+// fakeRootEnv makes the test binary serve SFTP for a directory on its
+// stdin and stdout, standing in for ssh -s host sftp.
+const fakeRootEnv = "WATCHER_FAKE_SFTP_ROOT"
+
+func TestMain(m *testing.M) {
+	if root := os.Getenv(fakeRootEnv); root != "" {
+		serve(root, os.Stdin, os.Stdout)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// serve answers the requests the client sends, mapping remote paths below
+// root.
+func serve(root string, in io.Reader, out io.Writer) {
+	c := &Client{r: in}
+	listed := map[string]bool{}
+	reply := func(p packet) {
+		frame := binary.BigEndian.AppendUint32(nil, uint32(len(p)))
+		out.Write(append(frame, p...))
+	}
+	status := func(id, code uint32) {
+		var p packet
+		p.byte(fxpStatus)
+		p.uint32(id)
+		p.uint32(code)
+		p.string("status")
+		p.string("")
+		reply(p)
+	}
+	for {
+		typ, body, err := c.recv()
+		if err != nil {
+			return
+		}
+		if typ == fxpInit {
+			reply(packet{fxpVersion, 0, 0, 0, 3})
+			continue
+		}
+		id, _ := body.uint32()
+		arg, _ := body.string()
+		local := filepath.Join(root, strings.TrimPrefix(arg, "/"))
+		var p packet
+		switch typ {
+		case fxpOpendir, fxpOpen:
+			if _, err := os.Stat(local); err != nil {
+				status(id, fxNoSuchFile)
+				continue
+			}
+			p.byte(fxpHandle)
+			p.uint32(id)
+			p.string(arg)
+		case fxpReaddir:
+			if listed[arg] {
+				status(id, fxEOF)
+				continue
+			}
+			listed[arg] = true
+			entries, _ := os.ReadDir(local)
+			p.byte(fxpName)
+			p.uint32(id)
+			p.uint32(uint32(len(entries)))
+			for _, e := range entries {
+				info, _ := e.Info()
+				perm := uint32(0o100644)
+				if info.IsDir() {
+					perm = 0o040755
+				}
+				p.string(e.Name())
+				p.string(e.Name())
+				p.uint32(attrSize | attrPermissions | attrACModTime)
+				p.uint64(uint64(info.Size()))
+				p.uint32(perm)
+				p.uint32(uint32(info.ModTime().Unix()))
+				p.uint32(uint32(info.ModTime().Unix()))
+			}
+		case fxpRead:
+			offset, _ := body.uint64()
+			n, _ := body.uint32()
+			data, _ := os.ReadFile(local)
+			if offset >= uint64(len(data)) {
+				status(id, fxEOF)
+				continue
+			}
+			end := min(offset+uint64(n), uint64(len(data)))
+			p.byte(fxpData)
+			p.uint32(id)
+			p.string(string(data[offset:end]))
+		case fxpClose:
+			delete(listed, arg)
+			status(id, fxOK)
+			continue
+		default:
+			status(id, 8) // unsupported
+			continue
+		}
+		reply(p)
+	}
+}
+
+func TestSourceListsAndReadsRemoteDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "in", "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	big := strings.Repeat("x", 100<<10)
+	os.WriteFile(filepath.Join(root, "in", "a.csv"), []byte(big), 0o644)
+	os.WriteFile(filepath.Join(root, "in", "sub", "b.csv"), []byte("b"), 0o644)
+	t.Setenv(fakeRootEnv, root)
+	old := sshcmd.Binary
+	sshcmd.Binary = os.Args[0]
+	defer func() { sshcmd.Binary = old }()
+
+	src := NewSource(&config.SSHTarget{Host: "box"}, "/in/", true)
+	defer src.Close()
+	entries, err := src.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 3 || entries["a.csv"].Size != int64(len(big)) || !entries["sub"].IsDir || entries["sub/b.csv"].Size != 1 {
+		t.Fatalf("unexpected listing %+v", entries)
+	}
+	if src.Root() != "sftp://box/in" {
+		t.Fatalf("unexpected root %q", src.Root())
+	}
+
+	f, err := src.Open("sftp://box/in/a.csv")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || string(data) != big {
+		t.Fatalf("read %d bytes, err %v", len(data), err)
+	}
+	if _, err := src.Open("sftp://box/in/missing.csv"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	// The session survives a failed request.
+	if _, err := src.List(); err != nil {
+		t.Fatalf("list after failed open: %v", err)
+	}
+}
+
+func TestSourceListsOnlyTopLevelUnlessRecursive(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "in", "sub"), 0o755)
+	os.WriteFile(filepath.Join(root, "in", "a.csv"), []byte("a"), 0o644)
+	os.WriteFile(filepath.Join(root, "in", "sub", "b.csv"), []byte("b"), 0o644)
+	t.Setenv(fakeRootEnv, root)
+	old := sshcmd.Binary
+	sshcmd.Binary = os.Args[0]
+	defer func() { sshcmd.Binary = old }()
+
+	src := NewSource(&config.SSHTarget{Host: "box"}, "/in", false)
+	defer src.Close()
+	entries, err := src.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 2 || !entries["sub"].IsDir {
+		t.Fatalf("expected only the top level, got %+v", entries)
+	}
+}
+
+// stubServer answers the version handshake, then hands each request to
+// answer, which returns the reply to send or nil to stay silent.
+func stubServer(t *testing.T, answer func(id uint32) packet) *Client {
+	t.Helper()
+	reqR, reqW := io.Pipe()
+	repR, repW := io.Pipe()
+	go func() {
+		server := &Client{r: reqR}
+		send := func(p packet) {
+			frame := binary.BigEndian.AppendUint32(nil, uint32(len(p)))
+			repW.Write(append(frame, p...))
+		}
+		for {
+			typ, body, err := server.recv()
+			if err != nil {
+				repW.Close()
+				return
+			}
+			if typ == fxpInit {
+				send(packet{fxpVersion, 0, 0, 0, 3})
+				continue
+			}
+			id, _ := body.uint32()
+			if p := answer(id); p != nil {
+				send(p)
+			}
+		}
+	}()
+	c, err := NewClient(repR, reqW)
+	if err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestClientRejectsReplyToAnotherRequest(t *testing.T) {
+	c := stubServer(t, func(id uint32) packet {
+		var p packet
+		p.byte(fxpHandle)
+		p.uint32(id + 1)
+		p.string("h")
+		return p
+	})
+	if _, err := c.ReadDir("/in"); err == nil || !strings.Contains(err.Error(), "while waiting for") {
+		t.Fatalf("expected a mismatched reply to fail, got %v", err)
+	}
+}
+
+func TestClientTimesOutSilentServer(t *testing.T) {
+	c := stubServer(t, func(uint32) packet { return nil })
+	c.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	if _, err := c.ReadDir("/in"); err == nil || !strings.Contains(err.Error(), "no reply within") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("request was not cut off by its timeout")
+	}
+}
@@ -0,0 +1,143 @@
+package sftp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/sshcmd"
+)
+
+// Source lists a remote directory for a scanner and opens its files. The
+// session is kept across scans and re-established after an error.
+type Source struct {
+	target    *config.SSHTarget
+	dir       string
+	recursive bool
+	// root is the prefix of snapshot keys, sftp://host/dir.
+	root string
+	mux  sshcmd.Mux
+
+	mu     sync.Mutex
+	client *Client
+}
+
+// NewSource returns a source for dir on t's host, listing its
+// subdirectories too when recursive.
+func NewSource(t *config.SSHTarget, dir string, recursive bool) *Source {
+	dir = path.Clean("/" + dir)
+	return &Source{target: t, dir: dir, recursive: recursive, root: Root(t, dir)}
+}
+
+// Root names a remote directory for snapshot keys and templates.
+func Root(t *config.SSHTarget, dir string) string {
+	return "sftp://" + t.Host + strings.TrimRight(path.Clean("/"+dir), "/")
+}
+
+// Root returns the prefix of the source's snapshot keys.
+func (s *Source) Root() string { return s.root }
+
+func (s *Source) session() (*Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+	cmd := sshcmd.Command(s.target, s.mux.ControlPath(), "-s")
+	cmd.Args = append(cmd.Args, "sftp")
+	c, err := Start(cmd)
+	if err != nil {
+		return nil, err
+	}
+	c.Timeout = sshcmd.Timeout(s.target)
+	s.client = c
+	return c, nil
+}
+
+// dropUnlessStatus drops the session after err unless err is a failed
+// request, after which the session is still usable.
+func (s *Source) dropUnlessStatus(c *Client, err error) {
+	var status *StatusError
+	if !errors.As(err, &status) {
+		s.drop(c)
+	}
+}
+
+// drop closes a session that failed so the next request reconnects.
+func (s *Source) drop(c *Client) {
+	s.mu.Lock()
+	if s.client == c {
+		s.client = nil
+	}
+	s.mu.Unlock()
+	c.Close()
+}
+
+// Close ends the session and removes its control socket directory.
+func (s *Source) Close() error {
+	s.mu.Lock()
+	c := s.client
+	s.client = nil
+	s.mu.Unlock()
+	var err error
+	if c != nil {
+		err = c.Close()
+	}
+	return errors.Join(err, s.mux.Close())
+}
+
+// List walks the remote directory and returns its entries keyed by path
+// relative to it, descending only into subdirectories of a recursive
+// source. Symlinks are recorded but not followed.
+func (s *Source) List() (map[string]scanner.FileInfo, error) {
+	c, err := s.session()
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]scanner.FileInfo{}
+	if err := s.walk(c, "", out); err != nil {
+		s.dropUnlessStatus(c, err)
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Source) walk(c *Client, rel string, out map[string]scanner.FileInfo) error {
+	entries, err := c.ReadDir(path.Join(s.dir, rel))
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		child := path.Join(rel, e.Name)
+		out[child] = scanner.FileInfo{Size: e.Size, ModTime: e.ModTime, IsDir: e.IsDir(), Mode: e.Mode}
+		if e.IsDir() && s.recursive {
+			if err := s.walk(c, child, out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Open opens the file with the snapshot key name for reading.
+func (s *Source) Open(name string) (io.ReadCloser, error) {
+	rel, ok := strings.CutPrefix(name, s.root+"/")
+	if !ok {
+		return nil, fmt.Errorf("%s is not below %s", name, s.root)
+	}
+	c, err := s.session()
+	if err != nil {
+		return nil, err
+	}
+	f, err := c.Open(path.Join(s.dir, rel))
+	if err != nil {
+		s.dropUnlessStatus(c, err)
+		return nil, err
+	}
+	return f, nil
+}
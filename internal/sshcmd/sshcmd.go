@@ -0,0 +1,131 @@
+// Package sshcmd builds invocations of the system ssh client, shared by
+// ssh_exec actions and SFTP watches. Authentication, host keys and
+// ~/.ssh/config are left to the client.
+package sshcmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+// Binary is the ssh client; tests point it at a stub.
+var Binary = "ssh"
+
+// DefaultPersist is how long an idle shared connection stays open.
+const DefaultPersist = 60 * time.Second
+
+// DefaultTimeout bounds connecting to a host and how long a connection may
+// go without an answer from it.
+const DefaultTimeout = 30 * time.Second
+
+// Timeout returns t's timeout, or DefaultTimeout.
+func Timeout(t *config.SSHTarget) time.Duration {
+	if d := t.Timeout.Duration(); d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// Mux lets runs to the same host share one connection via an OpenSSH
+// control socket, so a burst of work does not pay for a handshake each.
+// The zero value is ready to use; Close removes its socket directory.
+type Mux struct {
+	mu     sync.Mutex
+	dir    string
+	closed bool
+}
+
+// ControlPath returns the control socket pattern, or "" where OpenSSH
+// does not support shared connections or m is closed.
+func (m *Mux) ControlPath() string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.dir == "" && !m.closed {
+		// A private directory: whoever can reach the socket can use the
+		// connection.
+		dir, err := os.MkdirTemp("", "watcher-ssh-")
+		if err != nil {
+			return ""
+		}
+		m.dir = dir
+	}
+	if m.dir == "" {
+		return ""
+	}
+	return filepath.Join(m.dir, "%C")
+}
+
+// Close removes the control socket directory. Connections still open keep
+// running until they end, but no later run joins them; runs after Close
+// connect without sharing.
+func (m *Mux) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	if m.dir == "" {
+		return nil
+	}
+	dir := m.dir
+	m.dir = ""
+	return os.RemoveAll(dir)
+}
+
+// Command returns the ssh client invocation for t with extra client
+// options, ending in the host; callers append the remote command.
+func Command(t *config.SSHTarget, controlPath string, extra ...string) *exec.Cmd {
+	return exec.Command(Binary, Args(t, controlPath, extra...)...)
+}
+
+// Args returns the ssh client arguments for t with extra client options,
+// ending in "--" and the host.
+func Args(t *config.SSHTarget, controlPath string, extra ...string) []string {
+	args := []string{"-T", "-o", "BatchMode=yes"}
+	// Keepalives end a session whose host stopped answering: three missed
+	// probes spread over the timeout.
+	timeout := Timeout(t)
+	args = append(args,
+		"-o", "ConnectTimeout="+seconds(timeout),
+		"-o", "ServerAliveInterval="+seconds(timeout/3),
+		"-o", "ServerAliveCountMax=3",
+	)
+	if controlPath != "" {
+		persist := t.Persist.Duration()
+		if persist == 0 {
+			persist = DefaultPersist
+		}
+		args = append(args,
+			"-o", "ControlMaster=auto",
+			"-o", "ControlPath="+controlPath,
+			"-o", "ControlPersist="+seconds(persist)+"s",
+		)
+	}
+	if t.User != "" {
+		args = append(args, "-l", t.User)
+	}
+	if t.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(t.Port))
+	}
+	if t.Key != "" {
+		args = append(args, "-i", t.Key, "-o", "IdentitiesOnly=yes")
+	}
+	if t.KnownHosts != "" {
+		args = append(args, "-o", "UserKnownHostsFile="+t.KnownHosts)
+	}
+	args = append(args, extra...)
+	return append(args, "--", t.Host)
+}
+
+// seconds formats d in whole seconds, at least one.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(max(int(d.Seconds()), 1))
+}
@@ -0,0 +1,29 @@
+package sshcmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestMuxCloseRemovesControlDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no control sockets")
+	}
+	var m Mux
+	path := m.ControlPath()
+	if path == "" {
+		t.Fatalf("no control path")
+	}
+	dir := filepath.Dir(path)
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("control dir %s left behind: %v", dir, err)
+	}
+	if p := m.ControlPath(); p != "" {
+		t.Fatalf("closed mux handed out %s", p)
+	}
+}
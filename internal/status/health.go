@@ -0,0 +1,136 @@
+package status
+
+import (
+	"fmt"
+	"time"
+)
+
+// recentOutcomes is how many of the latest action results feed the error
+// rate.
+const recentOutcomes = 50
+
+// HealthState is the health of a watch.
+type HealthState string
+
+const (
+	HealthOK       HealthState = "ok"
+	HealthDegraded HealthState = "degraded"
+)
+
+// Thresholds flip a watch to degraded when crossed. Zero values disable a
+// check.
+type Thresholds struct {
+	// MaxErrorRate is the highest tolerated share (0..1) of failed actions
+	// among the latest results.
+	MaxErrorRate float64
+	// MaxQueueDepth is the highest tolerated number of pending events.
+	MaxQueueDepth int
+	// MaxIdle is the longest tolerated time without events, for watches
+	// that should always see traffic.
+	MaxIdle time.Duration
+}
+
+// Health is the evaluated health of a watch.
+type Health struct {
+	State      HealthState
+	Reasons    []string
+	QueueDepth int
+	LastEvent  time.Time
+}
+
+type watchHealth struct {
+	thresholds Thresholds
+	since      time.Time
+	lastEvent  time.Time
+	queueDepth int
+	outcomes   []bool
+	next       int
+}
+
+// SetThresholds enables health evaluation for a watch.
+func (t *Tracker) SetThresholds(watch string, th Thresholds) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.ensureHealth(watch)
+	h.thresholds = th
+}
+
+// SetQueueDepth records the number of events waiting to be handled.
+func (t *Tracker) SetQueueDepth(watch string, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ensureHealth(watch).queueDepth = n
+}
+
+// ObserveAction feeds an action result into the watch's error rate.
+func (t *Tracker) ObserveAction(watch string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.ensureHealth(watch)
+	if len(h.outcomes) < recentOutcomes {
+		h.outcomes = append(h.outcomes, ok)
+		return
+	}
+	h.outcomes[h.next] = ok
+	h.next = (h.next + 1) % recentOutcomes
+}
+
+// Health evaluates the thresholds of every watch that has them.
+func (t *Tracker) Health() map[string]Health {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	out := make(map[string]Health, len(t.health))
+	for name, h := range t.health {
+		out[name] = h.evaluate(now)
+	}
+	return out
+}
+
+// WatchHealth evaluates a single watch.
+func (t *Tracker) WatchHealth(watch string) Health {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.ensureHealth(watch).evaluate(time.Now())
+}
+
+func (h *watchHealth) evaluate(now time.Time) Health {
+	res := Health{State: HealthOK, QueueDepth: h.queueDepth, LastEvent: h.lastEvent}
+	th := h.thresholds
+	if th.MaxErrorRate > 0 && len(h.outcomes) > 0 {
+		failed := 0
+		for _, ok := range h.outcomes {
+			if !ok {
+				failed++
+			}
+		}
+		if rate := float64(failed) / float64(len(h.outcomes)); rate > th.MaxErrorRate {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("error rate %.0f%% above %.0f%%", rate*100, th.MaxErrorRate*100))
+		}
+	}
+	if th.MaxQueueDepth > 0 && h.queueDepth > th.MaxQueueDepth {
+		res.Reasons = append(res.Reasons, fmt.Sprintf("queue depth %d above %d", h.queueDepth, th.MaxQueueDepth))
+	}
+	if th.MaxIdle > 0 {
+		last := h.lastEvent
+		if last.IsZero() {
+			last = h.since
+		}
+		if idle := now.Sub(last); idle > th.MaxIdle {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("no events for %s", idle.Round(time.Second)))
+		}
+	}
+	if len(res.Reasons) > 0 {
+		res.State = HealthDegraded
+	}
+	return res
+}
+
+func (t *Tracker) ensureHealth(watch string) *watchHealth {
+	if h, ok := t.health[watch]; ok {
+		return h
+	}
+	h := &watchHealth{since: time.Now()}
+	t.health[watch] = h
+	return h
+}
@@ -0,0 +1,41 @@
+package status
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthThresholds(t *testing.T) {
+	tr := NewTracker()
+	tr.SetThresholds("/in", Thresholds{MaxErrorRate: 0.5, MaxQueueDepth: 10})
+	if h := tr.WatchHealth("/in"); h.State != HealthOK {
+		t.Fatalf("expected ok, got %+v", h)
+	}
+	tr.ObserveAction("/in", true)
+	tr.ObserveAction("/in", false)
+	tr.ObserveAction("/in", false)
+	if h := tr.WatchHealth("/in"); h.State != HealthDegraded || len(h.Reasons) != 1 {
+		t.Fatalf("expected degraded by error rate, got %+v", h)
+	}
+	for i := 0; i < recentOutcomes; i++ {
+		tr.ObserveAction("/in", true)
+	}
+	tr.SetQueueDepth("/in", 11)
+	h := tr.WatchHealth("/in")
+	if h.State != HealthDegraded || len(h.Reasons) != 1 || h.QueueDepth != 11 {
+		t.Fatalf("expected degraded by queue depth only, got %+v", h)
+	}
+}
+
+func TestHealthIdle(t *testing.T) {
+	tr := NewTracker()
+	tr.SetThresholds("/in", Thresholds{MaxIdle: time.Minute})
+	tr.health["/in"].since = time.Now().Add(-2 * time.Minute)
+	if h := tr.WatchHealth("/in"); h.State != HealthDegraded {
+		t.Fatalf("expected idle watch to be degraded, got %+v", h)
+	}
+	tr.IncEvent("/in")
+	if h := tr.WatchHealth("/in"); h.State != HealthOK {
+		t.Fatalf("expected ok after an event, got %+v", h)
+	}
+}
@@ -0,0 +1,59 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileName is the tracker's file inside the state dir.
+const FileName = "status.json"
+
+// Save writes the counters to path atomically.
+func (t *Tracker) Save(path string) error {
+	data, err := json.MarshalIndent(t.Snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Restore loads counters saved by Save. A missing file is not an error.
+func (t *Tracker) Restore(path string) error {
+	saved, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for name, c := range saved {
+		c := c
+		t.Watches[name] = &c
+	}
+	return nil
+}
+
+// ReadFile reads counters saved by Save. A missing file yields no counters.
+func ReadFile(path string) (map[string]Counter, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Counter{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved map[string]Counter
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return saved, nil
+}
@@ -0,0 +1,32 @@
+package status
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", FileName)
+	tr := NewTracker()
+	tr.IncEvent("/in")
+	tr.IncAction("/in.upload", false, "boom")
+	if err := tr.Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	restored := NewTracker()
+	if err := restored.Restore(path); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restored.IncEvent("/in")
+	snap := restored.Snapshot()
+	if snap["/in"].EventsSeen != 2 {
+		t.Fatalf("expected totals to continue, got %+v", snap["/in"])
+	}
+	if c := snap["/in.upload"]; c.ActionsError != 1 || c.LastError != "boom" {
+		t.Fatalf("unexpected action counters %+v", c)
+	}
+	if err := NewTracker().Restore(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Fatalf("missing file should not fail: %v", err)
+	}
+}
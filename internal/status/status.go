@@ -13,17 +13,34 @@ type Counter struct {
 	ActionsError int64
 	LastError    string
 	LastRun      time.Time
+	// EventsHandled, EventsPartial and EventsFailed count a watch's events
+	// by how their actions ended: all succeeded, some failed, all failed.
+	EventsHandled int64 `json:",omitempty"`
+	EventsPartial int64 `json:",omitempty"`
+	EventsFailed  int64 `json:",omitempty"`
+	// ErrorClasses counts failed actions by error class, e.g. "network";
+	// LastErrorClass is the class of LastError.
+	ErrorClasses   map[string]int64 `json:",omitempty"`
+	LastErrorClass string           `json:",omitempty"`
+	// Upstream counts events handed to this watch by then_watch, keyed by
+	// the "<watch>.<action>" that handed them off.
+	Upstream map[string]int64 `json:",omitempty"`
+	// Restarts counts how often the watch's worker crashed and was
+	// restarted; LastCrash is the latest reason.
+	Restarts  int64  `json:",omitempty"`
+	LastCrash string `json:",omitempty"`
 }
 
 // Tracker keeps stats per watch/action.
 type Tracker struct {
 	mu      sync.Mutex
 	Watches map[string]*Counter
+	health  map[string]*watchHealth
 }
 
 // NewTracker builds a tracker.
 func NewTracker() *Tracker {
-	return &Tracker{Watches: map[string]*Counter{}}
+	return &Tracker{Watches: map[string]*Counter{}, health: map[string]*watchHealth{}}
 }
 
 // IncEvent increments event count.
@@ -33,6 +50,7 @@ func (t *Tracker) IncEvent(name string) {
 	c := t.ensure(name)
 	c.EventsSeen++
 	c.LastRun = time.Now()
+	t.ensureHealth(name).lastEvent = c.LastRun
 }
 
 // IncAction increments action counts.
@@ -50,13 +68,74 @@ func (t *Tracker) IncAction(name string, ok bool, errStr string) {
 	c.LastRun = time.Now()
 }
 
+// IncOutcome counts an event of watch name whose actions are done, by
+// outcome: "handled", "partially_failed" or "failed".
+func (t *Tracker) IncOutcome(name, outcome string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.ensure(name)
+	switch outcome {
+	case "handled":
+		c.EventsHandled++
+	case "partially_failed":
+		c.EventsPartial++
+	case "failed":
+		c.EventsFailed++
+	}
+}
+
+// IncErrorClass counts a failed action of name by its error class.
+func (t *Tracker) IncErrorClass(name, class string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.ensure(name)
+	if c.ErrorClasses == nil {
+		c.ErrorClasses = map[string]int64{}
+	}
+	c.ErrorClasses[class]++
+	c.LastErrorClass = class
+}
+
+// IncHandoff counts an event handed to watch name by from.
+func (t *Tracker) IncHandoff(name, from string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.ensure(name)
+	if c.Upstream == nil {
+		c.Upstream = map[string]int64{}
+	}
+	c.Upstream[from]++
+}
+
+// IncRestart counts a crash and restart of watch name's worker.
+func (t *Tracker) IncRestart(name, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.ensure(name)
+	c.Restarts++
+	c.LastCrash = reason
+}
+
 // Snapshot returns a copy of stats.
 func (t *Tracker) Snapshot() map[string]Counter {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	out := make(map[string]Counter, len(t.Watches))
 	for k, v := range t.Watches {
-		out[k] = *v
+		c := *v
+		if v.Upstream != nil {
+			c.Upstream = make(map[string]int64, len(v.Upstream))
+			for from, n := range v.Upstream {
+				c.Upstream[from] = n
+			}
+		}
+		if v.ErrorClasses != nil {
+			c.ErrorClasses = make(map[string]int64, len(v.ErrorClasses))
+			for class, n := range v.ErrorClasses {
+				c.ErrorClasses[class] = n
+			}
+		}
+		out[k] = c
 	}
 	return out
 }
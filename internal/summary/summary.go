@@ -0,0 +1,179 @@
+// Package summary collects an end-of-run report.
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// slowestKept is how many of the slowest action runs a report lists.
+const slowestKept = 5
+
+// ActionStats counts runs of one action.
+type ActionStats struct {
+	OK     int64 `json:"ok"`
+	Failed int64 `json:"failed"`
+}
+
+// Run is a single timed action execution.
+type Run struct {
+	Watch    string        `json:"watch"`
+	Action   string        `json:"action"`
+	Path     string        `json:"path"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report is the end-of-run summary.
+type Report struct {
+	Started     time.Time                         `json:"started"`
+	Finished    time.Time                         `json:"finished"`
+	Events      map[string]map[string]int64       `json:"events"`  // watch -> event type -> count
+	Actions     map[string]map[string]ActionStats `json:"actions"` // watch -> action -> stats
+	BytesCopied int64                             `json:"bytes_copied"`
+	BytesMoved  int64                             `json:"bytes_moved"`
+	Slowest     []Run                             `json:"slowest"`
+}
+
+// Recorder accumulates a Report. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	report Report
+}
+
+// New starts a recorder.
+func New() *Recorder {
+	return &Recorder{report: Report{
+		Started: time.Now(),
+		Events:  map[string]map[string]int64{},
+		Actions: map[string]map[string]ActionStats{},
+	}}
+}
+
+// Event counts a detected event.
+func (r *Recorder) Event(watch, event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.report.Events[watch]
+	if m == nil {
+		m = map[string]int64{}
+		r.report.Events[watch] = m
+	}
+	m[event]++
+}
+
+// Action records a finished action. copied and moved are the bytes it
+// transferred.
+func (r *Recorder) Action(run Run, ok bool, copied, moved int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.report.Actions[run.Watch]
+	if m == nil {
+		m = map[string]ActionStats{}
+		r.report.Actions[run.Watch] = m
+	}
+	st := m[run.Action]
+	if ok {
+		st.OK++
+		r.report.BytesCopied += copied
+		r.report.BytesMoved += moved
+	} else {
+		st.Failed++
+	}
+	m[run.Action] = st
+	slow := append(r.report.Slowest, run)
+	sort.SliceStable(slow, func(i, j int) bool { return slow[i].Duration > slow[j].Duration })
+	if len(slow) > slowestKept {
+		slow = slow[:slowestKept]
+	}
+	r.report.Slowest = slow
+}
+
+// Report returns a copy of the summary, stamped with the finish time.
+func (r *Recorder) Report() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.report
+	out.Finished = time.Now()
+	out.Events = make(map[string]map[string]int64, len(r.report.Events))
+	for w, m := range r.report.Events {
+		cp := make(map[string]int64, len(m))
+		for k, v := range m {
+			cp[k] = v
+		}
+		out.Events[w] = cp
+	}
+	out.Actions = make(map[string]map[string]ActionStats, len(r.report.Actions))
+	for w, m := range r.report.Actions {
+		cp := make(map[string]ActionStats, len(m))
+		for k, v := range m {
+			cp[k] = v
+		}
+		out.Actions[w] = cp
+	}
+	out.Slowest = append([]Run(nil), r.report.Slowest...)
+	return out
+}
+
+// WriteJSON writes the report as indented JSON.
+func (rep Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// WriteText writes a human-readable report, e.g. for cron mail.
+func (rep Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "watcher run summary (%s)\n", rep.Finished.Sub(rep.Started).Round(time.Second))
+	for _, watch := range sortedKeys(rep.Events, rep.Actions) {
+		fmt.Fprintf(w, "%s\n", watch)
+		events := rep.Events[watch]
+		for _, ev := range sortedStrings(events) {
+			fmt.Fprintf(w, "  event %s: %d\n", ev, events[ev])
+		}
+		acts := rep.Actions[watch]
+		names := make([]string, 0, len(acts))
+		for name := range acts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "  action %s: ok=%d failed=%d\n", name, acts[name].OK, acts[name].Failed)
+		}
+	}
+	fmt.Fprintf(w, "bytes copied: %d\nbytes moved: %d\n", rep.BytesCopied, rep.BytesMoved)
+	if len(rep.Slowest) > 0 {
+		fmt.Fprintln(w, "slowest actions:")
+		for _, run := range rep.Slowest {
+			fmt.Fprintf(w, "  %s %s %s (%s)\n", run.Watch, run.Action, run.Path, run.Duration.Round(time.Millisecond))
+		}
+	}
+}
+
+func sortedStrings(m map[string]int64) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func sortedKeys(events map[string]map[string]int64, actions map[string]map[string]ActionStats) []string {
+	seen := map[string]struct{}{}
+	for k := range events {
+		seen[k] = struct{}{}
+	}
+	for k := range actions {
+		seen[k] = struct{}{}
+	}
+	out := make([]string, 0, len(seen))
+	for k := range seen {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,42 @@
+package summary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	r := New()
+	r.Event("/in", "create")
+	r.Event("/in", "create")
+	r.Event("/in", "delete")
+	for i := 1; i <= 7; i++ {
+		r.Action(Run{Watch: "/in", Action: "archive", Path: "f", Duration: time.Duration(i) * time.Second}, true, 0, 10)
+	}
+	r.Action(Run{Watch: "/in", Action: "upload", Duration: time.Millisecond}, false, 0, 0)
+
+	rep := r.Report()
+	if rep.Events["/in"]["create"] != 2 || rep.Events["/in"]["delete"] != 1 {
+		t.Fatalf("unexpected events %+v", rep.Events)
+	}
+	if st := rep.Actions["/in"]["archive"]; st.OK != 7 || st.Failed != 0 {
+		t.Fatalf("unexpected archive stats %+v", st)
+	}
+	if st := rep.Actions["/in"]["upload"]; st.Failed != 1 {
+		t.Fatalf("unexpected upload stats %+v", st)
+	}
+	if rep.BytesMoved != 70 {
+		t.Fatalf("expected 70 bytes moved, got %d", rep.BytesMoved)
+	}
+	if len(rep.Slowest) != slowestKept || rep.Slowest[0].Duration != 7*time.Second {
+		t.Fatalf("unexpected slowest %+v", rep.Slowest)
+	}
+
+	var buf bytes.Buffer
+	rep.WriteText(&buf)
+	if !strings.Contains(buf.String(), "action upload: ok=0 failed=1") {
+		t.Fatalf("unexpected text report:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,73 @@
+package template
+
+import (
+	"errors"
+	"strings"
+)
+
+// Fields splits a command line template into arguments before it is
+// expanded, so each token's value stays within its argument however many
+// spaces it holds. Whitespace separates arguments except inside quotes
+// and {...} tokens. Single quotes keep their text as written; double
+// quotes do too, except that \" and \\ stand for " and \. Outside quotes
+// a backslash escapes a following space, quote or backslash and is
+// otherwise kept, so Windows paths need no doubling.
+func Fields(line string) ([]string, error) {
+	var out []string
+	var cur strings.Builder
+	inArg := false
+	var quote byte // ' or " while inside quotes
+	depth := 0     // open braces outside quotes
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+				continue
+			}
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+				continue
+			}
+			if c == '\\' && i+1 < len(line) && (line[i+1] == '"' || line[i+1] == '\\') {
+				i++
+				c = line[i]
+			}
+		case depth > 0:
+			if c == '{' {
+				depth++
+			} else if c == '}' {
+				depth--
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				out = append(out, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+			continue
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+			continue
+		case c == '\\' && i+1 < len(line) && strings.IndexByte(" \t'\"\\", line[i+1]) >= 0:
+			i++
+			c = line[i]
+		case c == '{':
+			depth++
+		}
+		cur.WriteByte(c)
+		inArg = true
+	}
+	switch {
+	case quote != 0:
+		return nil, errors.New("unterminated " + string(quote) + " quote")
+	case depth > 0:
+		return nil, errors.New("unterminated { token")
+	}
+	if inArg {
+		out = append(out, cur.String())
+	}
+	return out, nil
+}
@@ -0,0 +1,169 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// value is a token's value on its way through filters. Time tokens keep
+// their time until a filter needs the string, so format can use it.
+type value struct {
+	s string
+	t time.Time
+}
+
+// filter transforms a value; arg is the text after the filter's first ':'.
+type filter func(v value, arg string) (value, error)
+
+var filters = map[string]filter{
+	"lower":    stringFilter(strings.ToLower),
+	"upper":    stringFilter(strings.ToUpper),
+	"trim":     stringFilter(strings.TrimSpace),
+	"dirname":  stringFilter(filepath.Dir),
+	"basename": stringFilter(filepath.Base),
+	"stem": stringFilter(func(s string) string {
+		name := filepath.Base(s)
+		if dot := strings.LastIndex(name, "."); dot > 0 {
+			return name[:dot]
+		}
+		return name
+	}),
+	"ext": stringFilter(func(s string) string {
+		name := filepath.Base(s)
+		if dot := strings.LastIndex(name, "."); dot > 0 {
+			return name[dot:]
+		}
+		return ""
+	}),
+	"slug": stringFilter(slug),
+	// json quotes the value as a JSON string, for webhook body templates.
+	"json": stringFilter(func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}),
+	"sha256": func(v value, arg string) (value, error) {
+		sum := sha256.Sum256([]byte(v.s))
+		out := hex.EncodeToString(sum[:])
+		if arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				return v, fmt.Errorf("sha256: length %q is not a positive number", arg)
+			}
+			if n < len(out) {
+				out = out[:n]
+			}
+		}
+		return value{s: out}, nil
+	},
+	"format": func(v value, arg string) (value, error) {
+		if arg == "" {
+			return v, fmt.Errorf("format: missing layout")
+		}
+		t := v.t
+		if t.IsZero() {
+			t = parseTime(v.s)
+		}
+		if t.IsZero() {
+			return value{}, nil
+		}
+		return value{s: t.Format(arg)}, nil
+	},
+	"default": func(v value, arg string) (value, error) {
+		if v.s == "" {
+			return value{s: arg}, nil
+		}
+		return v, nil
+	},
+	"truncate": func(v value, arg string) (value, error) {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("truncate: length %q is not a number", arg)
+		}
+		if r := []rune(v.s); len(r) > n {
+			return value{s: string(r[:n])}, nil
+		}
+		return value{s: v.s}, nil
+	},
+	"replace": func(v value, arg string) (value, error) {
+		old, repl, ok := strings.Cut(arg, ":")
+		if !ok || old == "" {
+			return v, fmt.Errorf("replace: want replace:OLD:NEW")
+		}
+		return value{s: strings.ReplaceAll(v.s, old, repl)}, nil
+	},
+}
+
+func stringFilter(fn func(string) string) filter {
+	return func(v value, _ string) (value, error) {
+		return value{s: fn(v.s)}, nil
+	}
+}
+
+// timeLayouts are the layouts format accepts for string values: RFC 3339
+// and EXIF's DateTimeOriginal.
+var timeLayouts = []string{time.RFC3339Nano, "2006:01:02 15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// slug lowercases s and joins its runs of letters and digits with '-'.
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// applyFilters runs v through the '|'-separated filters in chain.
+func applyFilters(v value, chain string) (value, error) {
+	for _, f := range strings.Split(strings.TrimPrefix(chain, "|"), "|") {
+		name, arg, _ := strings.Cut(f, ":")
+		fn, ok := filters[strings.TrimSpace(name)]
+		if !ok {
+			return v, fmt.Errorf("unknown filter %q", name)
+		}
+		var err error
+		if v, err = fn(v, arg); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+// Check reports the first filter in s that is unknown or has a bad
+// argument, so configs fail at load rather than leave raw tokens in paths.
+func Check(s string) error {
+	for _, m := range token.FindAllStringSubmatchIndex(s, -1) {
+		if m[6] == m[7] || m[0] > 0 && s[m[0]-1] == '$' {
+			continue
+		}
+		if _, err := applyFilters(value{}, s[m[6]:m[7]]); err != nil {
+			return fmt.Errorf("%s: %w", s[m[0]:m[1]], err)
+		}
+	}
+	return nil
+}
@@ -2,23 +2,135 @@ package template
 
 import (
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"watcher-cli/internal/mimetype"
 )
 
 // Context provides values for token substitution.
 type Context struct {
+	ID      string
 	Path    string
 	RelPath string
-	Event   string
-	Size    int64
-	ModTime time.Time
-	Age     time.Duration
+	// PrevPath and PrevRelPath locate the file before a move; empty otherwise.
+	PrevPath    string
+	PrevRelPath string
+	Event       string
+	Size        int64
+	ModTime     time.Time
+	Age         time.Duration
+	Duplicates  []string
+	// DeletedAt is when a delete was detected; zero for other events.
+	DeletedAt time.Time
+	// Now is the expansion time; zero means the current time.
+	Now time.Time
+	// Location renders {now} and {mtime} tokens; nil means the host's zone.
+	Location *time.Location
+	// Meta holds namespaced values such as "exif:DateTimeOriginal".
+	Meta map[string]string
+	// OutputDir is an exec action's expanded output_dir, if any.
+	OutputDir string
+	// LastOutput is the stdout of the event's latest exec action.
+	LastOutput string
 }
 
-// Expand replaces known tokens in the input string.
+// token matches one token: a name, an optional ':'-argument such as a
+// time layout or a metadata key, and optional filters, as in {name},
+// {now:2006-01-02}, {exif:Model} or {mtime|format:2006|upper}.
+var token = regexp.MustCompile(`\{([A-Za-z0-9_]+)(?::([^{}|]+))?((?:\|[^{}|]+)*)\}`)
+
+// metaKinds are the namespaces of {kind:field} metadata tokens.
+var metaKinds = map[string]bool{"exif": true, "id3": true, "pdf": true, "clamav": true, "out": true, "xattr": true, "field": true}
+
+// Expand replaces known tokens in the input string. A conditional
+// {token?then} or {token?then:else} picks a branch by whether token is
+// non-empty; branches may hold tokens and further conditionals, and the
+// first ':' outside braces starts the else branch. A token may be piped
+// through filters, as in {name|lower} or {mtime|format:2006-01-02}.
 func Expand(in string, ctx Context) string {
+	return ExpandQuoted(in, ctx, nil)
+}
+
+// ExpandQuoted is Expand with every substituted value passed through
+// quote, so values land in a shell line as single words whatever they
+// contain. Text outside tokens is kept as written; a nil quote leaves
+// values as they are.
+func ExpandQuoted(in string, ctx Context, quote func(string) string) string {
+	if strings.Contains(in, "?") {
+		in = expandConditionals(in, ctx)
+	}
+	return expand(in, ctx, quote)
+}
+
+// expandConditionals replaces conditionals by their chosen branch, leaving
+// the branch's tokens for expand.
+func expandConditionals(in string, ctx Context) string {
+	var b strings.Builder
+	for i := 0; i < len(in); i++ {
+		if in[i] != '{' || i > 0 && in[i-1] == '$' {
+			b.WriteByte(in[i])
+			continue
+		}
+		name, then, els, n, ok := parseConditional(in[i:])
+		if !ok {
+			b.WriteByte(in[i])
+			continue
+		}
+		tok := "{" + name + "}"
+		if v := expand(tok, ctx, nil); v != "" && v != tok {
+			b.WriteString(expandConditionals(then, ctx))
+		} else {
+			b.WriteString(expandConditionals(els, ctx))
+		}
+		i += n - 1
+	}
+	return b.String()
+}
+
+// parseConditional parses a conditional at the start of s and returns its
+// token name, branches and length.
+func parseConditional(s string) (name, then, els string, n int, ok bool) {
+	j := 1
+	for j < len(s) && isTokenChar(s[j]) {
+		j++
+	}
+	if j == 1 || j >= len(s) || s[j] != '?' {
+		return "", "", "", 0, false
+	}
+	name = s[1:j]
+	start, sep, depth := j+1, -1, 0
+	for k := start; k < len(s); k++ {
+		switch s[k] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				if sep < 0 {
+					return name, s[start:k], "", k + 1, true
+				}
+				return name, s[start:sep], s[sep+1 : k], k + 1, true
+			}
+			depth--
+		case ':':
+			if depth == 0 && sep < 0 {
+				sep = k
+			}
+		}
+	}
+	return "", "", "", 0, false
+}
+
+func isTokenChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-' || c == ':'
+}
+
+func expand(in string, ctx Context, quote func(string) string) string {
+	if quote == nil {
+		quote = func(s string) string { return s }
+	}
 	// Precompute common fields.
 	dir := filepath.Dir(ctx.Path)
 	name := filepath.Base(ctx.Path)
@@ -28,24 +140,167 @@ func Expand(in string, ctx Context) string {
 		stem = name[:dot]
 		ext = name[dot:]
 	}
+	prevDir, prevName := "", ""
+	if ctx.PrevPath != "" {
+		prevDir, prevName = filepath.Dir(ctx.PrevPath), filepath.Base(ctx.PrevPath)
+	}
+	loc := ctx.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := ctx.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now, mtime := now.In(loc), ctx.ModTime.In(loc)
+	deletedAt := func(layout string) string {
+		if ctx.DeletedAt.IsZero() {
+			return ""
+		}
+		return ctx.DeletedAt.In(loc).Format(layout)
+	}
 	repl := map[string]string{
-		"{path}":     ctx.Path,
-		"{relpath}":  ctx.RelPath,
-		"{event}":    ctx.Event,
-		"{size}":     intToString(ctx.Size),
-		"{mtime}":    ctx.ModTime.Format(time.RFC3339),
-		"{age_ms}":   intToString(ctx.Age.Milliseconds()),
-		"{age_days}": intToString(int64(ctx.Age.Hours() / 24)),
-		"{dir}":      dir,
-		"{name}":     name,
-		"{stem}":     stem,
-		"{ext}":      ext,
-	}
-	out := in
-	for k, v := range repl {
-		out = strings.ReplaceAll(out, k, v)
-	}
-	return out
+		"event_id":     ctx.ID,
+		"path":         ctx.Path,
+		"relpath":      ctx.RelPath,
+		"event":        ctx.Event,
+		"size":         intToString(ctx.Size),
+		"age_ms":       intToString(ctx.Age.Milliseconds()),
+		"age_days":     intToString(int64(ctx.Age.Hours() / 24)),
+		"age_human":    humanAge(ctx.Age),
+		"size_human":   humanSize(ctx.Size),
+		"mtime_unix":   mtimeUnix(ctx.ModTime),
+		"depth":        intToString(depth(ctx.RelPath)),
+		"dir":          dir,
+		"name":         name,
+		"stem":         stem,
+		"ext":          ext,
+		"prev_path":    ctx.PrevPath,
+		"prev_relpath": ctx.PrevRelPath,
+		"prev_dir":     prevDir,
+		"prev_name":    prevName,
+		"duplicates":   strings.Join(ctx.Duplicates, " "),
+		"output_dir":   ctx.OutputDir,
+		"last_output":  ctx.LastOutput,
+	}
+	// One pass over the input, so substituted values are never scanned
+	// for tokens again. Unknown tokens and filters are left as written.
+	return replaceTokens(in, func(tok string) string {
+		m := token.FindStringSubmatch(tok)
+		kind, arg, chain := m[1], m[2], m[3]
+		hasArg := arg != ""
+		var v value
+		switch {
+		case kind == "now":
+			v = value{s: now.Format(time.RFC3339), t: now}
+		case kind == "mtime":
+			v = value{s: mtime.Format(time.RFC3339), t: mtime}
+		case kind == "deleted_at":
+			v = value{s: deletedAt(time.RFC3339)}
+			if !ctx.DeletedAt.IsZero() {
+				v.t = ctx.DeletedAt.In(loc)
+			}
+		case metaKinds[kind] && hasArg:
+			// Unknown metadata expands to empty so paths never contain
+			// raw tokens.
+			v = value{s: ctx.Meta[kind+":"+arg]}
+		case (kind == "mime" || kind == "kind") && !hasArg:
+			// Sniffing reads the file, so only do it when asked for.
+			mt := mimetype.Detect(ctx.Path)
+			if v.s = mt; kind == "kind" {
+				v.s = mimetype.Kind(mt)
+			}
+		default:
+			s, ok := repl[kind]
+			if !ok || hasArg {
+				return tok
+			}
+			v = value{s: s}
+		}
+		if hasArg && (kind == "now" || kind == "mtime" || kind == "deleted_at") {
+			if v.t.IsZero() {
+				v = value{}
+			} else {
+				v = value{s: v.t.Format(arg)}
+			}
+		}
+		if chain != "" {
+			var err error
+			if v, err = applyFilters(v, chain); err != nil {
+				return tok
+			}
+		}
+		return quote(v.s)
+	})
+}
+
+// replaceTokens replaces each token in s by sub's result. A brace after
+// '$' is a shell parameter expansion such as ${HOME:-/tmp}, not a token.
+func replaceTokens(s string, sub func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range token.FindAllStringIndex(s, -1) {
+		if m[0] > 0 && s[m[0]-1] == '$' {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(sub(s[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// humanSize formats bytes with decimal units, e.g. "1.4 MB".
+func humanSize(n int64) string {
+	if n < 1000 {
+		return intToString(n) + " B"
+	}
+	v := float64(n)
+	units := []string{"KB", "MB", "GB", "TB", "PB"}
+	unit := ""
+	for _, unit = range units {
+		v /= 1000
+		if v < 999.95 {
+			break
+		}
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64) + " " + unit
+}
+
+// humanAge formats a duration by its two largest units, e.g. "2h13m",
+// "3d4h" or "42s".
+func humanAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	s := int64(d / time.Second)
+	days, hours, mins, secs := s/86400, s%86400/3600, s%3600/60, s%60
+	switch {
+	case days > 0:
+		return intToString(days) + "d" + intToString(hours) + "h"
+	case hours > 0:
+		return intToString(hours) + "h" + intToString(mins) + "m"
+	case mins > 0:
+		return intToString(mins) + "m" + intToString(secs) + "s"
+	}
+	return intToString(secs) + "s"
+}
+
+func mtimeUnix(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return intToString(t.Unix())
+}
+
+// depth counts the directories between the watch root and the file.
+func depth(relPath string) int64 {
+	rel := strings.Trim(filepath.ToSlash(relPath), "/")
+	if rel == "" {
+		return 0
+	}
+	return int64(strings.Count(rel, "/"))
 }
 
 func intToString(v int64) string {
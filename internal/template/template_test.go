@@ -36,3 +36,206 @@ func TestExpand(t *testing.T) {
 		t.Fatalf("expected size replacement")
 	}
 }
+
+func TestExpandMetaTokens(t *testing.T) {
+	ctx := Context{Path: "/in/b.mp3", Meta: map[string]string{"id3:artist": "Nina", "exif:Model": "M"}}
+	if got := Expand("{id3:artist}/{exif:Model}/{name}", ctx); got != "Nina/M/b.mp3" {
+		t.Fatalf("Expand = %q, want Nina/M/b.mp3", got)
+	}
+}
+
+func TestExpandTimeTokensUseLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	ctx := Context{
+		Path:     "/in/a.txt",
+		ModTime:  time.Date(2024, 12, 31, 20, 0, 0, 0, time.UTC),
+		Now:      time.Date(2025, 6, 30, 23, 0, 0, 0, time.UTC),
+		Location: loc,
+	}
+	out := Expand("/archive/{mtime:2006/01/02}/{now:2006-01}/{name}", ctx)
+	if out != "/archive/2025/01/01/2025-07/a.txt" {
+		t.Fatalf("unexpected expansion %s", out)
+	}
+	if got := Expand("{mtime}", ctx); got != "2025-01-01T06:00:00+10:00" {
+		t.Fatalf("unexpected mtime %s", got)
+	}
+}
+
+func TestExpandPrevPathTokens(t *testing.T) {
+	ctx := Context{Path: "/w/new/b.txt", RelPath: "new/b.txt", PrevPath: "/w/old/a.txt", PrevRelPath: "old/a.txt"}
+	got := Expand("{prev_path} {prev_dir} {prev_name} {prev_relpath} {path}", ctx)
+	if want := "/w/old/a.txt /w/old a.txt old/a.txt /w/new/b.txt"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := Expand("[{prev_dir}{prev_name}]", Context{Path: "/w/c.txt"}); got != "[]" {
+		t.Fatalf("prev tokens should be empty without a move, got %q", got)
+	}
+}
+
+func TestExpandDeletedAt(t *testing.T) {
+	deleted := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := Context{Path: "/w/a.txt", DeletedAt: deleted, Location: time.UTC}
+	if got := Expand("{deleted_at} {deleted_at:2006-01-02}", ctx); got != "2024-03-01T12:00:00Z 2024-03-01" {
+		t.Fatalf("got %q", got)
+	}
+	if got := Expand("[{deleted_at}{deleted_at:2006}]", Context{Path: "/w/a.txt"}); got != "[]" {
+		t.Fatalf("deleted_at should be empty for other events, got %q", got)
+	}
+}
+
+func TestExpandConditionals(t *testing.T) {
+	moved := Context{Path: "/w/b.txt", PrevPath: "/w/a.txt", Meta: map[string]string{"exif:Model": "X100", "id3:artist": "Nina"}}
+	plain := Context{Path: "/w/README"}
+	cases := []struct {
+		in   string
+		ctx  Context
+		want string
+	}{
+		{"{name}{ext?:.bin}", plain, "README.bin"},
+		{"{stem}{ext?{ext}:.bin}", moved, "b.txt"},
+		{"created{prev_path? (moved from {prev_path})}", moved, "created (moved from /w/a.txt)"},
+		{"created{prev_path? (moved from {prev_path})}", plain, "created"},
+		{"{exif:Model?cam={exif:Model}:no camera}", moved, "cam=X100"},
+		{"{exif:Model?cam={exif:Model}:no camera}", plain, "no camera"},
+		{"{id3:artist?{id3:artist}:Unknown}", moved, "Nina"},
+		{"{id3:artist?{id3:artist}:Unknown}", plain, "Unknown"},
+		{"{prev_path?{ext?a:b}:c}", moved, "a"},
+		{"{unknown?x:y}", plain, "y"},
+		{"{now:2006}?", plain, time.Now().Format("2006") + "?"},
+		{"{ext?unclosed", plain, "{ext?unclosed"},
+	}
+	for _, c := range cases {
+		if got := Expand(c.in, c.ctx); got != c.want {
+			t.Fatalf("Expand(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestExpandDerivedTokens(t *testing.T) {
+	ctx := Context{
+		Path:    "/w/a/b/c.txt",
+		RelPath: "a/b/c.txt",
+		Size:    1_400_000,
+		Age:     2*time.Hour + 13*time.Minute + 7*time.Second,
+		ModTime: time.Unix(1700000000, 0),
+	}
+	got := Expand("{size_human}|{age_human}|{mtime_unix}|{depth}", ctx)
+	if want := "1.4 MB|2h13m|1700000000|2"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for size, want := range map[int64]string{0: "0 B", 999: "999 B", 1000: "1.0 KB", 999_960: "1.0 MB", 3_200_000_000: "3.2 GB"} {
+		if got := humanSize(size); got != want {
+			t.Fatalf("humanSize(%d) = %q, want %q", size, got, want)
+		}
+	}
+	for d, want := range map[time.Duration]string{42 * time.Second: "42s", 13*time.Minute + 5*time.Second: "13m5s", 76 * time.Hour: "3d4h"} {
+		if got := humanAge(d); got != want {
+			t.Fatalf("humanAge(%s) = %q, want %q", d, got, want)
+		}
+	}
+	if got := Expand("{depth}{mtime_unix}", Context{Path: "/w/x", RelPath: "x"}); got != "0" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestExpandFilters(t *testing.T) {
+	ctx := Context{
+		Path:     "/w/Photos 2024/IMG 0042.JPG",
+		RelPath:  "Photos 2024/IMG 0042.JPG",
+		ModTime:  time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+		Meta:     map[string]string{"exif:DateTimeOriginal": "2023:12:24 18:30:00"},
+	}
+	for in, want := range map[string]string{
+		"{name|lower}":                           "img 0042.jpg",
+		"{ext|lower}":                            ".jpg",
+		"{mtime|format:2006-01-02}":              "2024-03-09",
+		"{relpath|dirname}":                      "Photos 2024",
+		"{stem|slug}":                            "img-0042",
+		"{path|sha256:12}":                       "4b04494c56c1",
+		"{exif:DateTimeOriginal|format:2006/01}": "2023/12",
+		"{exif:Model|default:unknown|upper}":     "UNKNOWN",
+		"{stem|replace: :_|truncate:5}":          "IMG_0",
+		"{name|nope}":                            "{name|nope}",
+		"{bogus|lower}":                          "{bogus|lower}",
+		"{ext?{ext|upper}:none}":                 ".JPG",
+	} {
+		if got := Expand(in, ctx); got != want {
+			t.Fatalf("Expand(%q) = %q, want %q", in, got, want)
+		}
+	}
+	// Filter output is final: tokens it happens to form are not expanded.
+	tricky := Context{Path: "/w/{Size}-{EXIF:Model}-{Now:2006}.JPG", Size: 9, Meta: map[string]string{"exif:model": "X"}}
+	if got := Expand("{name|lower}", tricky); got != "{size}-{exif:model}-{now:2006}.jpg" {
+		t.Fatalf("filtered value was expanded again: %q", got)
+	}
+	if err := Check("{name|lower}/{mtime|format:2006}"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, bad := range []string{"{name|nope}", "{name|truncate:x}", "{mtime|format}"} {
+		if Check(bad) == nil {
+			t.Fatalf("Check(%q) accepted a bad filter", bad)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	for line, want := range map[string][]string{
+		`convert {path} {dir}/out/{stem}.webp`:    {"convert", "{path}", "{dir}/out/{stem}.webp"},
+		`echo "a b" 'c "d"' e\ f ''`:              {"echo", "a b", `c "d"`, "e f", ""},
+		`echo "say \"hi\""`:                       {"echo", `say "hi"`},
+		`C:\tools\x.exe \\server\share`:           {`C:\tools\x.exe`, `\server\share`},
+		`mv {path} {prev_path? from {prev_path}}`: {"mv", "{path}", "{prev_path? from {prev_path}}"},
+		`find . -exec rm {} ;`:                    {"find", ".", "-exec", "rm", "{}", ";"},
+	} {
+		got, err := Fields(line)
+		if err != nil || strings.Join(got, "|") != strings.Join(want, "|") || len(got) != len(want) {
+			t.Fatalf("Fields(%q) = %q, %v; want %q", line, got, err, want)
+		}
+	}
+	for _, bad := range []string{`echo "open`, `echo 'open`, `echo {path`} {
+		if _, err := Fields(bad); err == nil {
+			t.Fatalf("Fields(%q) accepted an unterminated quote or token", bad)
+		}
+	}
+}
+
+func TestExpandQuoted(t *testing.T) {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'" }
+	ctx := Context{Path: "/in/it's a file.txt", Meta: map[string]string{"out:n": "$(x)"}}
+	got := ExpandQuoted("cat {path} {name|upper} {out:n} > /dev/null{ext?; echo {ext}}", ctx, quote)
+	want := `cat '/in/it'\''s a file.txt' 'IT'\''S A FILE.TXT' '$(x)' > /dev/null; echo '.txt'`
+	if got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestExpandLeavesShellParameters(t *testing.T) {
+	quote := func(s string) string { return "'" + s + "'" }
+	ctx := Context{Path: "/in/a.txt"}
+	got := ExpandQuoted(`cp {path} "${HOME:-/tmp}/${name}" && echo ${out:?unset} {name}`, ctx, quote)
+	want := `cp '/in/a.txt' "${HOME:-/tmp}/${name}" && echo ${out:?unset} 'a.txt'`
+	if got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+	if err := Check("${x|nosuchfilter}"); err != nil {
+		t.Fatalf("shell parameter checked as a token: %v", err)
+	}
+}
+
+func TestExpandDoesNotReexpandValues(t *testing.T) {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'" }
+	ctx := Context{
+		Path: "/in/{exif:Artist}{size}.jpg",
+		Size: 7,
+		Meta: map[string]string{"exif:Artist": "x; touch /tmp/pwned; echo x", "out:v": "{path}"},
+	}
+	got := ExpandQuoted("echo {path} {out:v}", ctx, quote)
+	want := `echo '/in/{exif:Artist}{size}.jpg' '{path}'`
+	if got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+	if got := Expand("{size}-{out:v}", ctx); got != "7-{path}" {
+		t.Fatalf("values should be substituted as they are, got %s", got)
+	}
+}
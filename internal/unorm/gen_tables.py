@@ -0,0 +1,46 @@
+#!/usr/bin/env python3
+"""Generates tables.go from the Unicode database bundled with Python."""
+
+import unicodedata as u
+
+
+def chunks(items, n):
+    for i in range(0, len(items), n):
+        yield items[i:i + n]
+
+
+def lit(s):
+    return '"' + ''.join('\\U%08X' % ord(c) for c in s) + '"'
+
+
+decomp, ccc, comp = [], [], []
+for cp in range(0x110000):
+    if 0xAC00 <= cp <= 0xD7A3:  # Hangul syllables are handled algorithmically
+        continue
+    ch = chr(cp)
+    if u.combining(ch):
+        ccc.append((cp, u.combining(ch)))
+    d = u.decomposition(ch)
+    if not d or d.startswith('<'):
+        continue
+    decomp.append((cp, u.normalize('NFD', ch)))
+    parts = [int(x, 16) for x in d.split()]
+    # Primary composites: pairs that NFC recombines (no exclusions).
+    if len(parts) == 2 and u.normalize('NFC', ch) == ch:
+        comp.append((parts[0], parts[1], cp))
+
+out = ['// Code generated by gen_tables.py from Unicode %s; DO NOT EDIT.' % u.unidata_version,
+       '', 'package unorm', '',
+       '// decomp maps runes to their full canonical decomposition.',
+       'var decomp = map[rune]string{']
+for c in chunks(decomp, 4):
+    out.append('\t' + ' '.join('0x%X: %s,' % (cp, lit(s)) for cp, s in c))
+out += ['}', '', '// ccc holds non-zero canonical combining classes.', 'var ccc = map[rune]uint8{']
+for c in chunks(ccc, 8):
+    out.append('\t' + ' '.join('0x%X: %d,' % e for e in c))
+out += ['}', '', '// compose maps starter and combining rune pairs to their primary composite.',
+        'var compose = map[[2]rune]rune{']
+for c in chunks(comp, 4):
+    out.append('\t' + ' '.join('{0x%X, 0x%X}: 0x%X,' % e for e in c))
+out.append('}')
+open('tables.go', 'w').write('\n'.join(out) + '\n')
@@ -0,0 +1,159 @@
+// Package unorm implements Unicode canonical normalization (NFC and NFD)
+// for file names, so the same name written by macOS (decomposed) and
+// Linux (composed) compares equal.
+package unorm
+
+//go:generate python3 gen_tables.py
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Form selects a normalization form.
+type Form string
+
+const (
+	// Off leaves strings untouched.
+	Off Form = "off"
+	// NFC composes characters, as Linux and Windows tools usually write.
+	NFC Form = "nfc"
+	// NFD decomposes characters, as HFS+ stores names.
+	NFD Form = "nfd"
+)
+
+// ParseForm validates a form name; empty means Off.
+func ParseForm(s string) (Form, error) {
+	switch Form(s) {
+	case "", Off:
+		return Off, nil
+	case NFC, NFD:
+		return Form(s), nil
+	}
+	return "", fmt.Errorf("unknown unicode normalization %q", s)
+}
+
+// String normalizes s to form f.
+func (f Form) String(s string) string {
+	if f != NFC && f != NFD || isASCII(s) {
+		return s
+	}
+	d := decompose(s)
+	if f == NFC {
+		d = composeRunes(d)
+	}
+	return string(d)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	hangulBase  = 0xAC00
+	hangulCount = 11172
+	jamoL       = 0x1100
+	jamoV       = 0x1161
+	jamoT       = 0x11A7
+	jamoVCount  = 21
+	jamoTCount  = 28
+	jamoNCount  = jamoVCount * jamoTCount
+)
+
+// decompose returns the canonical decomposition of s in canonical order.
+func decompose(s string) []rune {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= hangulBase && r < hangulBase+hangulCount:
+			i := r - hangulBase
+			out = append(out, jamoL+i/jamoNCount, jamoV+(i%jamoNCount)/jamoTCount)
+			if t := i % jamoTCount; t != 0 {
+				out = append(out, jamoT+t)
+			}
+		case decomp[r] != "":
+			out = append(out, []rune(decomp[r])...)
+		default:
+			out = append(out, r)
+		}
+	}
+	// Reorder each run of combining marks by combining class.
+	for i := 0; i < len(out); {
+		if ccc[out[i]] == 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < len(out) && ccc[out[j]] != 0 {
+			j++
+		}
+		run := out[i:j]
+		sort.SliceStable(run, func(a, b int) bool { return ccc[run[a]] < ccc[run[b]] })
+		i = j
+	}
+	return out
+}
+
+// composeRunes applies canonical composition to decomposed runes.
+func composeRunes(rs []rune) []rune {
+	if len(rs) == 0 {
+		return rs
+	}
+	out := rs[:0:0]
+	starter := -1
+	var lastClass uint8
+	for _, r := range rs {
+		class := ccc[r]
+		if starter >= 0 && (lastClass < class || lastClass == 0 && len(out)-1 == starter) {
+			if c, ok := composePair(out[starter], r); ok {
+				out[starter] = c
+				continue
+			}
+		}
+		if class == 0 {
+			starter = len(out)
+		}
+		lastClass = class
+		out = append(out, r)
+	}
+	return out
+}
+
+func composePair(a, b rune) (rune, bool) {
+	switch {
+	case a >= jamoL && a < jamoL+19 && b >= jamoV && b < jamoV+jamoVCount:
+		return hangulBase + ((a-jamoL)*jamoVCount+(b-jamoV))*jamoTCount, true
+	case a >= hangulBase && a < hangulBase+hangulCount && (a-hangulBase)%jamoTCount == 0 &&
+		b > jamoT && b < jamoT+jamoTCount:
+		return a + (b - jamoT), true
+	}
+	c, ok := compose[[2]rune{a, b}]
+	return c, ok
+}
+
+// FoldCase maps s to a form shared by every name a case-insensitive
+// filesystem treats as the same. It uses Unicode simple case folding,
+// not the rules of a locale, so the result does not depend on where the
+// watcher runs.
+func FoldCase(s string) string {
+	return strings.Map(foldRune, s)
+}
+
+// foldRune returns the smallest rune in r's case folding orbit.
+func foldRune(r rune) rune {
+	least := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < least {
+			least = f
+		}
+	}
+	return least
+}
@@ -0,0 +1,56 @@
+package unorm
+
+import "testing"
+
+func TestForms(t *testing.T) {
+	cases := []struct {
+		in, nfc, nfd string
+	}{
+		{"plain.txt", "plain.txt", "plain.txt"},
+		{"Caf\u00e9.txt", "Caf\u00e9.txt", "Cafe\u0301.txt"},
+		{"Cafe\u0301.txt", "Caf\u00e9.txt", "Cafe\u0301.txt"},
+		// Marks are reordered by combining class before composing.
+		{"a\u0302\u0323", "\u1ead", "a\u0323\u0302"},
+		// Hangul composes algorithmically.
+		{"\u1100\u1161\u11a8", "\uac01", "\u1100\u1161\u11a8"},
+	}
+	for _, c := range cases {
+		if got := NFC.String(c.in); got != c.nfc {
+			t.Fatalf("NFC(%q) = %q, want %q", c.in, got, c.nfc)
+		}
+		if got := NFD.String(c.in); got != c.nfd {
+			t.Fatalf("NFD(%q) = %q, want %q", c.in, got, c.nfd)
+		}
+		if got := Off.String(c.in); got != c.in {
+			t.Fatalf("Off changed %q", c.in)
+		}
+	}
+}
+
+func TestParseForm(t *testing.T) {
+	if f, err := ParseForm(""); err != nil || f != Off {
+		t.Fatalf("empty form = %q, %v", f, err)
+	}
+	if _, err := ParseForm("nfkc"); err == nil {
+		t.Fatalf("expected error for unsupported form")
+	}
+}
+
+func TestFoldCase(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"Report.PDF", "report.pdf"},
+		{"ÄRGER.txt", "ärger.txt"},
+		{"ΣΊΣΥΦΟΣ", "σίσυφος"},
+		{"K", "k"}, // Kelvin sign
+	} {
+		if FoldCase(pair[0]) != FoldCase(pair[1]) {
+			t.Fatalf("FoldCase(%q) = %q, FoldCase(%q) = %q", pair[0], FoldCase(pair[0]), pair[1], FoldCase(pair[1]))
+		}
+	}
+	// No locale's rules: dotted capital I is not a case of plain i.
+	for _, pair := range [][2]string{{"a.txt", "b.txt"}, {"İstanbul", "istanbul"}} {
+		if FoldCase(pair[0]) == FoldCase(pair[1]) {
+			t.Fatalf("%q and %q fold together", pair[0], pair[1])
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+)
+
+// pendingBatch collects the events of an action with a batch block until
+// it is full or its max_wait_ms has passed.
+type pendingBatch struct {
+	action config.Action
+	events []actions.Context
+	due    time.Time
+}
+
+// batchEvent adds evCtx to its action's pending batch and runs the batch
+// once it is full. It runs on the worker goroutine.
+func (w *Worker) batchEvent(ctx context.Context, evCtx actions.Context, action config.Action) {
+	evCtx.Location = w.location
+	evCtx.Normalize = w.cfg.NormalizeUnicode
+	// The batch may outlive the event's other actions; read from disk.
+	evCtx.Cache = nil
+	b := w.batches[action.Name]
+	if b == nil {
+		if w.batches == nil {
+			w.batches = map[string]*pendingBatch{}
+			w.batching = map[string]int{}
+		}
+		b = &pendingBatch{action: action, due: w.clock.Now().Add(action.Batch.MaxWait.Duration())}
+		w.batches[action.Name] = b
+	}
+	b.events = append(b.events, evCtx)
+	w.batching[evCtx.Path]++
+	w.logger.Debug("event batched", "event_id", evCtx.ID, "watch", w.cfg.Key(), "action", action.Name, "path", evCtx.Path, "items", len(b.events))
+	if len(b.events) >= action.Batch.MaxItems {
+		w.flushBatch(ctx, action.Name)
+	}
+}
+
+// flushBatches runs the pending batches whose max_wait_ms has passed, or
+// all of them when force is set.
+func (w *Worker) flushBatches(ctx context.Context, force bool) {
+	if len(w.batches) == 0 {
+		return
+	}
+	names := make([]string, 0, len(w.batches))
+	for name := range w.batches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	now := w.clock.Now()
+	for _, name := range names {
+		if force || !now.Before(w.batches[name].due) {
+			w.flushBatch(ctx, name)
+		}
+	}
+}
+
+// flushBatch runs the named action's pending batch, in the pool when
+// there is one. Its events are handled once the batch is done.
+func (w *Worker) flushBatch(ctx context.Context, name string) {
+	b := w.batches[name]
+	delete(w.batches, name)
+	// Batches of one action run in order, apart from the events' chains.
+	w.pool.submit("\x00batch:"+name, func() {
+		started := time.Now()
+		var err error
+		w.inSlot(ctx, b.events[0], b.action, func(ctx context.Context) { err = w.executor.ExecuteBatch(ctx, b.events, b.action) })
+		took := time.Since(started)
+		w.pool.post(func() { w.finishBatch(b, took, err) })
+	})
+}
+
+// finishBatch records a batch's result for each of its events.
+func (w *Worker) finishBatch(b *pendingBatch, took time.Duration, err error) {
+	action := b.action
+	log := w.logger.With("watch", w.cfg.Key(), "watch_path", w.cfg.Path, "action", action.Name, "action_type", string(action.Type), "duration_ms", took.Milliseconds(), "items", len(b.events))
+	if errors.Is(err, actions.ErrSkip) {
+		err = nil
+	}
+	for _, evCtx := range b.events {
+		w.metrics.Action(w.cfg.Key(), action.Name, evCtx.Event, evCtx.Path, err == nil)
+		w.recordSummary(evCtx, action, took, err == nil)
+		w.recordHistory(evCtx, action, took, err == nil, err)
+		w.exportAction(evCtx, action, took, err)
+		w.reportAction(evCtx, action, err)
+		w.deadLetter(evCtx, action, 0, err)
+	}
+	if err != nil {
+		class := string(actions.Classify(err))
+		log.Error("batch error", "error_class", class, "err", err)
+		w.tracker.IncAction(w.cfg.Key()+"."+action.Name, false, err.Error())
+		w.tracker.IncErrorClass(w.cfg.Key()+"."+action.Name, class)
+		w.tracker.ObserveAction(w.cfg.Key(), false)
+		w.notifier.ActionFailed(w.cfg.Key(), action.Name, err)
+	} else {
+		log.Info("batch ok")
+		w.tracker.IncAction(w.cfg.Key()+"."+action.Name, true, "")
+		w.tracker.ObserveAction(w.cfg.Key(), true)
+	}
+	for _, evCtx := range b.events {
+		w.noteResult(evCtx, action, took, err)
+		w.endHandling(evCtx.ID)
+		if w.batching[evCtx.Path]--; w.batching[evCtx.Path] <= 0 {
+			delete(w.batching, evCtx.Path)
+		}
+		w.finish(evCtx.Path)
+	}
+}
+
+// nextBatch returns when the earliest pending batch is due.
+func (w *Worker) nextBatch() (time.Time, bool) {
+	var next time.Time
+	for _, b := range w.batches {
+		if next.IsZero() || b.due.Before(next) {
+			next = b.due
+		}
+	}
+	return next, !next.IsZero()
+}
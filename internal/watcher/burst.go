@@ -0,0 +1,30 @@
+package watcher
+
+import "fmt"
+
+// holdBurst reports whether a scan yielding n events must be held back
+// because it exceeds max_events_per_scan. A held scan keeps the previous
+// snapshot as baseline, so the burst is dispatched in full once confirmed
+// by burst_confirm_scans consecutive scans, or vanishes if the tree comes
+// back.
+func (w *Worker) holdBurst(n int) bool {
+	limit := w.cfg.MaxEventsPerScan
+	if limit <= 0 || n <= limit {
+		if w.burstScans > 0 {
+			w.logger.Info("event burst subsided", "watch", w.cfg.Key(), "events", n)
+		}
+		w.burstScans = 0
+		return false
+	}
+	w.burstScans++
+	if confirm := w.cfg.BurstConfirmScans; confirm > 0 && w.burstScans >= confirm {
+		w.logger.Warn("event burst confirmed, dispatching", "watch", w.cfg.Key(), "events", n, "scans", w.burstScans)
+		w.burstScans = 0
+		return false
+	}
+	if w.burstScans == 1 {
+		w.logger.Error("event burst held", "watch", w.cfg.Key(), "events", n, "max_events_per_scan", limit)
+		w.notifier.SafetyHold(w.cfg.Key(), fmt.Sprintf("scan yielded %d events, more than max_events_per_scan %d", n, limit))
+	}
+	return true
+}
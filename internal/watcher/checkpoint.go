@@ -0,0 +1,87 @@
+package watcher
+
+import (
+	"maps"
+
+	"watcher-cli/internal/scanner"
+)
+
+// unfinishedEvent remembers the snapshot entries an event changed, as
+// they were before it, until its action chain completes.
+type unfinishedEvent struct {
+	key         string
+	info        scanner.FileInfo
+	existed     bool
+	prevKey     string
+	prevInfo    scanner.FileInfo
+	prevExisted bool
+}
+
+// track records the events of a scan as unfinished. Only saved snapshots
+// need them, so without persist_snapshots nothing is tracked. An event on
+// a path whose earlier event is still unfinished keeps the older entry.
+func (w *Worker) track(old scanner.Snapshot, events []scanner.Event) {
+	if w.snapshotPath == "" || old == nil {
+		return
+	}
+	if w.unfinished == nil {
+		w.unfinished = map[string]unfinishedEvent{}
+	}
+	for _, ev := range events {
+		path := ev.DiskPath()
+		if _, ok := w.unfinished[path]; ok {
+			continue
+		}
+		u := unfinishedEvent{key: ev.Path}
+		u.info, u.existed = old[ev.Path]
+		if ev.PrevPath != "" {
+			u.prevKey = ev.PrevPath
+			u.prevInfo, u.prevExisted = old[ev.PrevPath]
+		}
+		w.unfinished[path] = u
+	}
+}
+
+// finish marks the event on path as handled unless a retry of it is
+// still pending.
+func (w *Worker) finish(path string) {
+	if _, ok := w.unfinished[path]; !ok || w.pool.busy(path) || w.batching[path] > 0 {
+		return
+	}
+	for _, r := range w.retries {
+		if r.ev.Path == path {
+			return
+		}
+	}
+	for _, h := range w.held {
+		if h.ev.DiskPath() == path {
+			return
+		}
+	}
+	delete(w.unfinished, path)
+	w.snapshotDirty = true
+}
+
+// checkpoint returns the baseline to save: the current one with the
+// entries of unfinished events rolled back, so it only reflects events
+// that were fully handled and the next start reports the rest again.
+func (w *Worker) checkpoint() scanner.Snapshot {
+	if len(w.unfinished) == 0 {
+		return w.prev.data
+	}
+	snap := maps.Clone(w.prev.data)
+	restore := func(key string, info scanner.FileInfo, existed bool) {
+		if existed {
+			snap[key] = info
+		} else {
+			delete(snap, key)
+		}
+	}
+	for _, u := range w.unfinished {
+		restore(u.key, u.info, u.existed)
+		if u.prevKey != "" {
+			restore(u.prevKey, u.prevInfo, u.prevExisted)
+		}
+	}
+	return snap
+}
@@ -0,0 +1,51 @@
+package watcher
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"time"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/deadletter"
+)
+
+// deadLetter appends an event whose action failed for good to the
+// action's dead_letter file.
+func (w *Worker) deadLetter(evCtx actions.Context, action config.Action, attempts int, err error) {
+	deadLetter(w.logger, w.cfg.Key(), w.clock.Now(), evCtx, action, attempts, err)
+}
+
+func deadLetter(logger *slog.Logger, watch string, now time.Time, evCtx actions.Context, action config.Action, attempts int, err error) {
+	if action.DeadLetter == "" || err == nil || errors.Is(err, actions.ErrSkip) {
+		return
+	}
+	e := deadletter.Entry{
+		Time:        now,
+		Watch:       watch,
+		Action:      action.Name,
+		EventID:     evCtx.ID,
+		Event:       evCtx.Event,
+		Path:        evCtx.Path,
+		RelPath:     evCtx.RelPath,
+		PrevPath:    evCtx.PrevPath,
+		PrevRelPath: evCtx.PrevRelPath,
+		Size:        evCtx.Size,
+		ModTime:     evCtx.ModTime,
+		IsDir:       evCtx.IsDir,
+		Meta:        evCtx.Meta,
+		Outputs:     maps.Clone(evCtx.Outputs),
+		Attempts:    attempts,
+		Error:       err.Error(),
+		ErrorClass:  string(actions.Classify(err)),
+	}
+	if !evCtx.DeletedAt.IsZero() {
+		e.DeletedAt = &evCtx.DeletedAt
+	}
+	if err := deadletter.Append(action.DeadLetter, e); err != nil {
+		logger.Error("dead-letter append", "event_id", evCtx.ID, "watch", watch, "action", action.Name, "file", action.DeadLetter, "err", err)
+		return
+	}
+	logger.Info("event dead-lettered", "event_id", evCtx.ID, "watch", watch, "action", action.Name, "file", action.DeadLetter)
+}
@@ -0,0 +1,70 @@
+package watcher
+
+import "time"
+
+// maxDebounceEntries bounds the debounce state of one watch.
+const maxDebounceEntries = 100000
+
+type debounceKey struct {
+	path string
+	typ  string
+}
+
+// debouncer suppresses repeats of the same event type on the same path
+// within a window. Different event types never suppress each other, so a
+// create followed by a meaningful modify still yields both.
+type debouncer struct {
+	window time.Duration
+	seen   map[debounceKey]time.Time
+}
+
+func newDebouncer(window time.Duration) *debouncer {
+	return &debouncer{window: window, seen: map[debounceKey]time.Time{}}
+}
+
+// allow reports whether an event should be handled and records it.
+func (d *debouncer) allow(path, typ string, now time.Time) bool {
+	if d.window <= 0 {
+		return true
+	}
+	key := debounceKey{path: path, typ: typ}
+	if last, ok := d.seen[key]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	if len(d.seen) >= maxDebounceEntries {
+		d.expire(now)
+		if len(d.seen) >= maxDebounceEntries {
+			d.evictOldest()
+		}
+	}
+	d.seen[key] = now
+	return true
+}
+
+// forget drops state for a deleted path so a re-created file is not
+// suppressed by its predecessor.
+func (d *debouncer) forget(path string) {
+	for _, typ := range []string{"create", "modify", "move"} {
+		delete(d.seen, debounceKey{path: path, typ: typ})
+	}
+}
+
+// expire drops entries whose window has passed.
+func (d *debouncer) expire(now time.Time) {
+	for k, t := range d.seen {
+		if now.Sub(t) >= d.window {
+			delete(d.seen, k)
+		}
+	}
+}
+
+func (d *debouncer) evictOldest() {
+	var oldest debounceKey
+	var oldestAt time.Time
+	for k, t := range d.seen {
+		if oldestAt.IsZero() || t.Before(oldestAt) {
+			oldest, oldestAt = k, t
+		}
+	}
+	delete(d.seen, oldest)
+}
@@ -0,0 +1,31 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebouncerKeysByPathAndType(t *testing.T) {
+	d := newDebouncer(time.Second)
+	now := time.Unix(1000, 0)
+	if !d.allow("/a", "create", now) {
+		t.Fatalf("first create suppressed")
+	}
+	if !d.allow("/a", "modify", now.Add(100*time.Millisecond)) {
+		t.Fatalf("modify after create suppressed")
+	}
+	if d.allow("/a", "modify", now.Add(200*time.Millisecond)) {
+		t.Fatalf("repeated modify inside window allowed")
+	}
+	if !d.allow("/a", "modify", now.Add(2*time.Second)) {
+		t.Fatalf("modify after window suppressed")
+	}
+	d.forget("/a")
+	if !d.allow("/a", "create", now.Add(2100*time.Millisecond)) {
+		t.Fatalf("create after delete suppressed")
+	}
+	d.expire(now.Add(10 * time.Second))
+	if len(d.seen) != 0 {
+		t.Fatalf("expected expired entries to be dropped, got %d", len(d.seen))
+	}
+}
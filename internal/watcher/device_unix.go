@@ -0,0 +1,17 @@
+//go:build !windows
+
+package watcher
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// deviceOf returns the device a file lives on, if the platform reports it.
+func deviceOf(info fs.FileInfo) (uint64, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true
+}
@@ -0,0 +1,8 @@
+package watcher
+
+import "io/fs"
+
+// deviceOf returns false: device IDs are not compared on Windows.
+func deviceOf(fs.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,118 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/history"
+	"watcher-cli/internal/match"
+	"watcher-cli/internal/scanner"
+)
+
+// OpenHistory opens the history in g's state dir, or returns nil when
+// history is off.
+func OpenHistory(g config.Global) (*history.Store, error) {
+	if !g.History {
+		return nil, nil
+	}
+	return history.Open(filepath.Join(g.StateDir, history.FileName), history.Rotation{
+		MaxSizeBytes: g.HistoryMaxSizeBytes,
+		MaxFiles:     g.HistoryMaxFiles,
+	})
+}
+
+// Dispatcher runs a watch's actions for single events outside a worker,
+// for commands such as backfill and simulate. Events are enriched,
+// matched and given metadata as a worker does; actions whose
+// not_previously_run action already succeeded are skipped, and results go
+// to the history and dead-letter files.
+type Dispatcher struct {
+	cfg     config.Watch
+	exec    *actions.Executor
+	matcher *match.Matcher
+	history *history.Store
+	logger  *slog.Logger
+}
+
+// NewDispatcher returns a Dispatcher for wcfg. A nil history skips
+// nothing and records nothing.
+func NewDispatcher(wcfg config.Watch, exec *actions.Executor, hist *history.Store, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{cfg: wcfg, exec: exec, matcher: match.New(wcfg), history: hist, logger: logger}
+}
+
+// Select enriches ev and returns it with the actions it matches, less
+// those already run for its file.
+func (d *Dispatcher) Select(ctx context.Context, ev scanner.Event) (scanner.Event, []config.Action) {
+	ev = enrichEvent(ctx, d.logger, d.cfg, ev)
+	return ev, skipPreviouslyRun(d.logger, d.history, d.cfg, ev.DiskPath(), d.matcher.Match(ev, d.cfg))
+}
+
+// Context builds the action context of ev.
+func (d *Dispatcher) Context(ev scanner.Event, id string) actions.Context {
+	return EventContext(d.cfg, ev, id)
+}
+
+// EventContext builds the action context of an event of a local watch as
+// a worker does, with the metadata its actions expand.
+func EventContext(wcfg config.Watch, ev scanner.Event, id string) actions.Context {
+	evCtx := eventContext(wcfg, wcfg.Path, ev, id, time.Now())
+	evCtx.Location = wcfg.Location()
+	evCtx.Normalize = wcfg.NormalizeUnicode
+	return evCtx
+}
+
+// Run executes action for evCtx, with its retries, and records the result.
+// Dry runs record nothing.
+func (d *Dispatcher) Run(ctx context.Context, evCtx actions.Context, action config.Action) error {
+	started := time.Now()
+	err := d.exec.Execute(ctx, evCtx, action)
+	if d.exec.DryRun || ctx.Err() != nil {
+		return err
+	}
+	ok := err == nil || errors.Is(err, actions.ErrSkip)
+	recordHistory(d.logger, d.history, d.cfg.Key(), time.Now(), evCtx, action, time.Since(started), ok, err)
+	deadLetter(d.logger, d.cfg.Key(), time.Now(), evCtx, action, 0, err)
+	return err
+}
+
+// RunChain runs chain in order for evCtx as a worker does, calling report
+// with each action's result. ErrSkip ends the chain, and an infected file
+// goes to the action's quarantine instead of the remaining actions.
+func (d *Dispatcher) RunChain(ctx context.Context, evCtx actions.Context, chain []config.Action, report func(config.Action, error)) {
+	for _, action := range chain {
+		err := d.Run(ctx, evCtx, action)
+		if ctx.Err() != nil {
+			return
+		}
+		report(action, err)
+		var infected *actions.InfectedError
+		if errors.As(err, &infected) {
+			if q, ok := d.action(action.Quarantine); ok {
+				evCtx.Meta = withMeta(evCtx.Meta, "clamav:signature", infected.Signature)
+				report(q, d.Run(ctx, evCtx, q))
+			}
+			return
+		}
+		if errors.Is(err, actions.ErrSkip) {
+			return
+		}
+	}
+}
+
+// action looks up a watch action by name.
+func (d *Dispatcher) action(name string) (config.Action, bool) {
+	if name == "" {
+		return config.Action{}, false
+	}
+	for _, a := range d.cfg.Actions {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return config.Action{}, false
+}
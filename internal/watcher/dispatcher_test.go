@@ -0,0 +1,101 @@
+package watcher
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/history"
+	"watcher-cli/internal/scanner"
+)
+
+func TestDispatcherSkipsPreviouslyRunAndRecordsHistory(t *testing.T) {
+	dir, state := t.TempDir(), t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(path, []byte("a"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfgPath := filepath.Join(t.TempDir(), "watcher.yaml")
+	yaml := `
+global:
+  state_dir: ` + state + `
+  history: true
+watches:
+  - path: ` + dir + `
+    actions:
+      - name: once
+        type: exec
+        include: ["*.txt"]
+        cmd: "true"
+        condition:
+          not_previously_run: once
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	hist, err := OpenHistory(cfg.Global)
+	if err != nil {
+		t.Fatalf("open history: %v", err)
+	}
+	d := NewDispatcher(cfg.Watches[0], &actions.Executor{Registry: actions.NewRegistry()}, hist, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ev := scanner.Event{Path: path, RelPath: "a.txt", Type: "create", Info: scanner.FileInfo{Size: 1, ModTime: time.Now()}}
+
+	ev, selected := d.Select(context.Background(), ev)
+	if len(selected) != 1 {
+		t.Fatalf("expected the action to match, got %v", selected)
+	}
+	if err := d.Run(context.Background(), d.Context(ev, "ev1"), selected[0]); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, selected = d.Select(context.Background(), ev); len(selected) != 0 {
+		t.Fatalf("expected the action to be skipped once run, got %v", selected)
+	}
+	entries, err := history.ReadAll(filepath.Join(state, history.FileName))
+	if err != nil || len(entries) != 1 || entries[0].Action != "once" || entries[0].EventID != "ev1" || !entries[0].OK {
+		t.Fatalf("unexpected history %+v (%v)", entries, err)
+	}
+}
+
+type recordingRunner struct {
+	ran []string
+	err error
+}
+
+func (r *recordingRunner) Run(ctx context.Context, ev actions.Context, cfg config.Action) error {
+	r.ran = append(r.ran, cfg.Name+":"+ev.Meta["clamav:signature"])
+	return r.err
+}
+
+func TestDispatcherQuarantinesInfectedFiles(t *testing.T) {
+	scan := &recordingRunner{err: &actions.InfectedError{Path: "/in/a.exe", Signature: "Eicar"}}
+	rest := &recordingRunner{}
+	reg := actions.NewRegistry()
+	reg.Register(config.ActionClamScan, scan)
+	reg.Register(config.ActionExec, rest)
+	wcfg := config.Watch{Path: "/in", Actions: []config.Action{
+		{Name: "scan", Type: config.ActionClamScan, Quarantine: "isolate"},
+		{Name: "publish", Type: config.ActionExec},
+		{Name: "isolate", Type: config.ActionExec},
+	}}
+	d := NewDispatcher(wcfg, &actions.Executor{Registry: reg}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var reported []string
+	d.RunChain(context.Background(), actions.Context{Path: "/in/a.exe"}, wcfg.Actions[:2], func(a config.Action, err error) {
+		reported = append(reported, a.Name)
+	})
+	if len(rest.ran) != 1 || rest.ran[0] != "isolate:Eicar" {
+		t.Fatalf("expected only the quarantine to run after detection, ran %v", rest.ran)
+	}
+	if len(reported) != 2 || reported[0] != "scan" || reported[1] != "isolate" {
+		t.Fatalf("unexpected reports %v", reported)
+	}
+}
@@ -0,0 +1,178 @@
+package watcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/history"
+)
+
+// eventHandling collects the results of every action one event triggered,
+// so the event is reported once, as a whole, when the last is done: its
+// chain, retries included, held stable_for_ms actions and batches.
+type eventHandling struct {
+	id, event, path string
+	started         time.Time
+	pending         int
+	results         []handledAction
+}
+
+// handledAction is one action's final result in a handled event.
+type handledAction struct {
+	Action     string `json:"action"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	ErrorClass string `json:"error_class,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// handledReport is the body of a handled_webhook post.
+type handledReport struct {
+	ID         string          `json:"id"`
+	Watch      string          `json:"watch"`
+	Event      string          `json:"event"`
+	Path       string          `json:"path"`
+	Outcome    string          `json:"outcome"`
+	DurationMS int64           `json:"duration_ms"`
+	Actions    []handledAction `json:"actions"`
+}
+
+// handlings tracks the events with actions still to finish. Results come
+// from pool goroutines, hence the lock.
+type handlings struct {
+	mu     sync.Mutex
+	events map[string]*eventHandling
+	// posts tracks handled_webhook posts in flight; the worker waits for
+	// them when it stops.
+	posts sync.WaitGroup
+}
+
+// beginHandling opens a part of the event's handling that a later
+// endHandling closes.
+func (w *Worker) beginHandling(id, event, path string) {
+	w.handling.mu.Lock()
+	defer w.handling.mu.Unlock()
+	h := w.handling.events[id]
+	if h == nil {
+		if w.handling.events == nil {
+			w.handling.events = map[string]*eventHandling{}
+		}
+		h = &eventHandling{id: id, event: event, path: path, started: w.clock.Now()}
+		w.handling.events[id] = h
+	}
+	h.pending++
+}
+
+// noteResult adds an action's final result to its event's handling.
+func (w *Worker) noteResult(evCtx actions.Context, action config.Action, took time.Duration, err error) {
+	r := handledAction{Action: action.Name, OK: err == nil || errors.Is(err, actions.ErrSkip), DurationMS: took.Milliseconds()}
+	if !r.OK {
+		r.Error = err.Error()
+		r.ErrorClass = string(actions.Classify(err))
+	}
+	w.handling.mu.Lock()
+	defer w.handling.mu.Unlock()
+	if h := w.handling.events[evCtx.ID]; h != nil {
+		h.results = append(h.results, r)
+	}
+}
+
+// endHandling closes a part of the event's handling and reports the event
+// once no part is left.
+func (w *Worker) endHandling(id string) {
+	w.handling.mu.Lock()
+	h := w.handling.events[id]
+	if h == nil {
+		w.handling.mu.Unlock()
+		return
+	}
+	h.pending--
+	done := h.pending <= 0
+	if done {
+		delete(w.handling.events, id)
+	}
+	w.handling.mu.Unlock()
+	if done && len(h.results) > 0 {
+		w.reportHandled(h)
+	}
+}
+
+// reportHandled logs, counts, exports and posts the outcome of an event.
+func (w *Worker) reportHandled(h *eventHandling) {
+	var ok int
+	var failed []string
+	for _, r := range h.results {
+		if r.OK {
+			ok++
+		} else {
+			failed = append(failed, r.Action)
+		}
+	}
+	outcome := config.HandlingHandled
+	switch {
+	case ok == 0:
+		outcome = config.HandlingFailed
+	case len(failed) > 0:
+		outcome = config.HandlingPartial
+	}
+	took := w.clock.Now().Sub(h.started)
+	log := w.logger.With("event_id", h.id, "watch", w.cfg.Key(), "event", h.event, "path", h.path, "outcome", outcome, "actions", len(h.results), "duration_ms", took.Milliseconds())
+	if len(failed) > 0 {
+		log.Warn("event handled", "failed", failed)
+	} else {
+		log.Info("event handled")
+	}
+	w.tracker.IncOutcome(w.cfg.Key(), string(outcome))
+	w.export.Handled(w.cfg.Key(), h.id, h.event, h.path, string(outcome), took, failed)
+	e := history.Entry{Path: h.path, Time: w.clock.Now(), Watch: w.cfg.Key(), EventID: h.id, Event: h.event, OK: outcome == config.HandlingHandled, Outcome: string(outcome), Failed: failed, DurationMS: took.Milliseconds()}
+	if err := w.history.Record(e); err != nil {
+		w.logger.Error("record history", "watch", w.cfg.Key(), "path", h.path, "err", err)
+	}
+	hook := w.cfg.HandledWebhook
+	if hook == nil || (len(hook.Outcomes) > 0 && !slices.Contains(hook.Outcomes, outcome)) {
+		return
+	}
+	report := handledReport{ID: h.id, Watch: w.cfg.Key(), Event: h.event, Path: h.path, Outcome: string(outcome), DurationMS: took.Milliseconds(), Actions: h.results}
+	w.handling.posts.Add(1)
+	go func() {
+		defer w.handling.posts.Done()
+		if err := postHandled(*hook, report); err != nil {
+			w.logger.Error("handled webhook failed", "event_id", h.id, "watch", w.cfg.Key(), "url", hook.URL, "err", err)
+		}
+	}()
+}
+
+func postHandled(hook config.HandledWebhook, report handledReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	timeout := hook.Timeout.Duration()
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Watcher-Event-Id", report.ID)
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("handled webhook returned %s", resp.Status)
+	}
+	return nil
+}
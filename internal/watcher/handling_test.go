@@ -0,0 +1,46 @@
+package watcher
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/status"
+)
+
+func TestHandledWebhookTimesOutAndIsAwaited(t *testing.T) {
+	release := make(chan struct{})
+	var answered atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		answered.Store(true)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	hook := &config.HandledWebhook{URL: srv.URL, Timeout: config.MillisFromDuration(50 * time.Millisecond)}
+	w := &Worker{cfg: config.Watch{Path: "/in", HandledWebhook: hook}, logger: slog.Default(), tracker: status.NewTracker(), clock: realClock{}}
+	start := time.Now()
+	w.reportHandled(&eventHandling{id: "ev", event: "create", path: "/in/a", results: []handledAction{{Action: "ok", OK: true}}})
+
+	waited := make(chan struct{})
+	go func() {
+		w.handling.posts.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		if took := time.Since(start); took < 40*time.Millisecond {
+			t.Fatalf("stopping did not wait for the post in flight (%s)", took)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handled webhook post not bounded by its timeout")
+	}
+	if answered.Load() {
+		t.Fatalf("post finished before the server answered")
+	}
+}
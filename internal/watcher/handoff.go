@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"context"
+	"sync"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+// handoff is a file passed to a watch by another watch's then_watch action.
+type handoff struct {
+	path    string
+	from    string // "<watch>.<action>"
+	eventID string
+}
+
+// inbox queues handoffs for a worker without ever blocking the sender.
+type inbox struct {
+	mu    sync.Mutex
+	items []handoff
+	wake  chan struct{}
+}
+
+func newInbox() *inbox {
+	return &inbox{wake: make(chan struct{}, 1)}
+}
+
+func (b *inbox) push(h handoff) {
+	b.mu.Lock()
+	b.items = append(b.items, h)
+	b.mu.Unlock()
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *inbox) take() []handoff {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items := b.items
+	b.items = nil
+	return items
+}
+
+// handOff passes an action's output, its destination or else the event's
+// file, to the watch named by then_watch.
+func (w *Worker) handOff(evCtx actions.Context, action config.Action, dest string) {
+	path := dest
+	if path == "" {
+		path = evCtx.Path
+	}
+	if w.executor.DryRun {
+		w.logger.Info("dry-run handoff", "event_id", evCtx.ID, "watch", w.cfg.Key(), "action", action.Name, "to", action.ThenWatch, "path", path)
+		return
+	}
+	if w.route != nil {
+		w.route(action.ThenWatch, handoff{path: path, from: w.cfg.Key() + "." + action.Name, eventID: evCtx.ID})
+	}
+}
+
+// receiveHandoffs runs the watch's actions on every queued handoff as a
+// create event and returns how many there were.
+func (w *Worker) receiveHandoffs(ctx context.Context) int {
+	w.applyReload()
+	items := w.inbox.take()
+	for _, h := range items {
+		w.receive(ctx, h)
+	}
+	return len(items)
+}
+
+func (w *Worker) receive(ctx context.Context, h handoff) {
+	key, rel, info, err := w.scn.Entry(h.path)
+	if err != nil {
+		w.logger.Error("handoff file unavailable", "watch", w.cfg.Key(), "from", h.from, "from_event_id", h.eventID, "err", err)
+		return
+	}
+	if rel != h.path && w.prev.data != nil {
+		// Inside the root: record the file so the next scan does not
+		// report it again, unless a scan already dispatched it.
+		if prev, ok := w.prev.data[key]; ok && prev.Size == info.Size && prev.ModTime.Equal(info.ModTime) {
+			w.logger.Debug("handoff already seen by scan", "watch", w.cfg.Key(), "from", h.from, "path", h.path)
+			return
+		}
+		w.prev.data[key] = info
+		w.snapshotDirty = true
+	}
+	w.tracker.IncHandoff(w.cfg.Key(), h.from)
+	w.logger.Info("handoff received", "watch", w.cfg.Key(), "from", h.from, "from_event_id", h.eventID, "path", h.path)
+	ev := scanner.Event{Path: key, RelPath: rel, Type: "create", Info: info}
+	if !info.ModTime.IsZero() {
+		ev.Age = w.clock.Now().Sub(info.ModTime)
+	}
+	w.handleEvent(ctx, ev)
+}
@@ -0,0 +1,51 @@
+package watcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+)
+
+func TestActionLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	w := &Worker{
+		cfg:    config.Watch{Name: "inbox", Path: "/in"},
+		logger: slog.New(slog.NewJSONHandler(&buf, nil)),
+	}
+	action := config.Action{Name: "thumb", Type: config.ActionExec}
+	evCtx := actions.Context{ID: "ev1", Path: "/in/a.jpg"}
+
+	w.actionLogger(evCtx, action, 2, 1500*time.Millisecond).Info("action ok")
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("not a JSON line: %q (%v)", buf.String(), err)
+	}
+	want := map[string]any{
+		"event_id":    "ev1",
+		"watch":       "inbox",
+		"watch_path":  "/in",
+		"action":      "thumb",
+		"action_type": "exec",
+		"attempt":     float64(2),
+		"duration_ms": float64(1500),
+	}
+	for k, v := range want {
+		if line[k] != v {
+			t.Fatalf("%s = %v, want %v (line %v)", k, line[k], v, line)
+		}
+	}
+
+	// Runs that retry in place leave the attempt out.
+	buf.Reset()
+	w.actionLogger(evCtx, action, 0, 0).Info("action ok")
+	line = nil
+	json.Unmarshal(buf.Bytes(), &line)
+	if _, ok := line["attempt"]; ok {
+		t.Fatalf("attempt 0 should be left out: %v", line)
+	}
+}
@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"log/slog"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/diskusage"
+)
+
+func stubDiskUsage(t *testing.T, usage *diskusage.Usage) {
+	t.Helper()
+	orig := diskUsage
+	diskUsage = func(string) (diskusage.Usage, error) { return *usage, nil }
+	t.Cleanup(func() { diskUsage = orig })
+}
+
+func TestLowSpaceThreshold(t *testing.T) {
+	var usage diskusage.Usage
+	stubDiskUsage(t, &usage)
+	cases := []struct {
+		name string
+		ls   config.LowSpace
+		free uint64
+		fire bool
+	}{
+		{"bytes below", config.LowSpace{MinFreeBytes: 100}, 99, true},
+		{"bytes at", config.LowSpace{MinFreeBytes: 100}, 100, false},
+		{"bytes above", config.LowSpace{MinFreeBytes: 100}, 101, false},
+		{"percent below", config.LowSpace{MinFreePercent: 10}, 99, true},
+		{"percent at", config.LowSpace{MinFreePercent: 10}, 100, false},
+		{"percent above", config.LowSpace{MinFreePercent: 10}, 101, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			usage = diskusage.Usage{Total: 1000, Free: c.free}
+			w := &Worker{cfg: config.Watch{Path: "/in", LowSpace: &c.ls}, logger: slog.Default()}
+			if _, fire := w.crossedLowSpace(); fire != c.fire {
+				t.Fatalf("%d of 1000 bytes free: fired %v, want %v", c.free, fire, c.fire)
+			}
+		})
+	}
+}
+
+func TestLowSpaceFiresOncePerDrop(t *testing.T) {
+	var usage diskusage.Usage
+	stubDiskUsage(t, &usage)
+	w := &Worker{cfg: config.Watch{Path: "/in", LowSpace: &config.LowSpace{MinFreeBytes: 100}}, logger: slog.Default()}
+	for i, step := range []struct {
+		free uint64
+		fire bool
+	}{{50, true}, {40, false}, {100, false}, {60, true}} {
+		usage = diskusage.Usage{Total: 1000, Free: step.free}
+		if _, fire := w.crossedLowSpace(); fire != step.fire {
+			t.Fatalf("step %d with %d bytes free: fired %v, want %v", i, step.free, fire, step.fire)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"watcher-cli/internal/scanner"
+)
+
+// holdMassDelete reports whether a scan's deletes must be held back. When
+// at least mass_delete_threshold of the known entries vanished at once,
+// the root is checked first: an unmounted or unreadable root looks empty
+// without anything having been deleted. While it is unhealthy the
+// previous snapshot stays the baseline.
+func (w *Worker) holdMassDelete(events []scanner.Event) bool {
+	known := len(w.prev.data)
+	deletes := 0
+	for _, ev := range events {
+		if ev.Type == "delete" {
+			deletes++
+		}
+	}
+	if deletes == 0 || float64(deletes) < w.cfg.MassDeleteThreshold*float64(known) {
+		w.massDeleteHeld = false
+		return false
+	}
+	err := w.checkRoot()
+	if err == nil {
+		w.massDeleteHeld = false
+		return false
+	}
+	if !w.massDeleteHeld {
+		w.massDeleteHeld = true
+		w.logger.Error("mass delete held, root looks unhealthy", "watch", w.cfg.Key(), "deletes", deletes, "known", known, "err", err)
+		w.notifier.SafetyHold(w.cfg.Key(), fmt.Sprintf("%d of %d entries vanished and the root looks unhealthy: %v", deletes, known, err))
+	}
+	return true
+}
+
+// rememberRoot records the device the root lives on at start.
+func (w *Worker) rememberRoot() {
+	if w.cfg.IsRemote() {
+		return
+	}
+	if info, err := os.Stat(w.cfg.Path); err == nil {
+		w.rootDev, w.rootDevOK = deviceOf(info)
+	}
+}
+
+// checkRoot verifies the root is still a readable directory on the device
+// it was on at start. A bucket listing that succeeded is authoritative.
+func (w *Worker) checkRoot() error {
+	if w.cfg.IsRemote() {
+		return nil
+	}
+	info, err := os.Stat(w.cfg.Path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New("root is not a directory")
+	}
+	if dev, ok := deviceOf(info); ok && w.rootDevOK && dev != w.rootDev {
+		return errors.New("root moved to another device; unmounted?")
+	}
+	f, err := os.Open(w.cfg.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Readdirnames(1); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
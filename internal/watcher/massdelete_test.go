@@ -0,0 +1,34 @@
+package watcher
+
+import (
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+func TestMassDeleteHeldWhileRootUnhealthy(t *testing.T) {
+	dir := t.TempDir()
+	w := &Worker{cfg: config.Watch{Path: dir, MassDeleteThreshold: 1}, logger: slog.Default()}
+	w.rememberRoot()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	w.prev.data = scanner.Snapshot{a: {}, b: {}}
+	events := []scanner.Event{{Path: a, Type: "delete"}, {Path: b, Type: "delete"}}
+
+	if w.holdMassDelete(events) {
+		t.Fatalf("deletes under a healthy root should pass")
+	}
+	if w.holdMassDelete(events[:1]) {
+		t.Fatalf("a partial delete should not be checked")
+	}
+	if !w.rootDevOK {
+		t.Skip("device IDs not available")
+	}
+	// Simulate the root now being a bare mount point on another device.
+	w.rootDev++
+	if !w.holdMassDelete(events) {
+		t.Fatalf("deletes should be held when the root changed device")
+	}
+}
@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"slices"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/diskusage"
+	"watcher-cli/internal/fsys"
+	"watcher-cli/internal/lint"
+)
+
+// probeMount records the filesystem holding the watch for filesystem
+// conditions and warns about settings that do not suit it.
+func (w *Worker) probeMount() {
+	w.mount, w.mountOK = diskusage.Mount{}, false
+	if w.cfg.IsRemote() {
+		return
+	}
+	m, err := diskusage.MountOf(w.cfg.Path)
+	if err != nil {
+		if w.usesFilesystem() {
+			w.logger.Warn("filesystem unknown, filesystem conditions will not match", "watch", w.cfg.Key(), "err", err)
+		}
+		return
+	}
+	w.mount, w.mountOK = m, true
+	w.logger.Debug("filesystem", "watch", w.cfg.Key(), "type", m.Type, "read_only", m.ReadOnly, "network", m.Network)
+	for _, f := range lint.MountFindings(w.cfg, m) {
+		w.logger.Warn(f.Message, "watch", w.cfg.Key(), "action", f.Action)
+	}
+}
+
+// probeCase decides whether names that differ only in case are the same
+// file, for pairing case-only renames.
+func (w *Worker) probeCase() {
+	switch w.cfg.CaseSensitivity {
+	case config.CaseInsensitive:
+		w.foldCase = true
+	case config.CaseSensitive:
+		w.foldCase = false
+	default:
+		w.foldCase = !w.cfg.IsRemote() && fsys.CaseInsensitive(w.cfg.Path)
+	}
+	w.logger.Debug("case sensitivity", "watch", w.cfg.Key(), "insensitive", w.foldCase)
+}
+
+func (w *Worker) usesFilesystem() bool {
+	for _, a := range w.cfg.Actions {
+		if a.Condition.Filesystem != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFilesystem drops actions whose filesystem condition the watch's
+// filesystem does not meet.
+func (w *Worker) filterFilesystem(selected []config.Action) []config.Action {
+	var out []config.Action
+	for _, a := range selected {
+		if fs := a.Condition.Filesystem; fs != nil && !w.filesystemMatches(fs) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func (w *Worker) filesystemMatches(fs *config.FilesystemMatch) bool {
+	if !w.mountOK {
+		return false
+	}
+	if len(fs.Types) > 0 && !slices.Contains(fs.Types, w.mount.Type) {
+		return false
+	}
+	if fs.Network != nil && *fs.Network != w.mount.Network {
+		return false
+	}
+	return fs.ReadOnly == nil || *fs.ReadOnly == w.mount.ReadOnly
+}
@@ -0,0 +1,38 @@
+package watcher
+
+import (
+	"sort"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+// orderEvents sorts one scan's events for the watch's event_order. Diff
+// already yields them by type, each type by path.
+func orderEvents(events []scanner.Event, order config.EventOrder) {
+	var less func(a, b scanner.Event) bool
+	switch order {
+	case config.OrderPath:
+		less = func(a, b scanner.Event) bool { return false }
+	case config.OrderOldest:
+		less = func(a, b scanner.Event) bool { return a.Info.ModTime.Before(b.Info.ModTime) }
+	case config.OrderNewest:
+		less = func(a, b scanner.Event) bool { return a.Info.ModTime.After(b.Info.ModTime) }
+	case config.OrderSmallest:
+		less = func(a, b scanner.Event) bool { return a.Info.Size < b.Info.Size }
+	case config.OrderLargest:
+		less = func(a, b scanner.Event) bool { return a.Info.Size > b.Info.Size }
+	default:
+		return
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.Path < b.Path
+	})
+}
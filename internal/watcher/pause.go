@@ -0,0 +1,69 @@
+package watcher
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WatchState describes a running watch for the admin API.
+type WatchState struct {
+	Key     string   `json:"key"`
+	Path    string   `json:"path"`
+	Paused  bool     `json:"paused"`
+	Actions []string `json:"actions"`
+}
+
+// Watches lists the configured watches, sorted by key.
+func (s *Supervisor) Watches() []WatchState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []WatchState
+	for _, wcfg := range s.cfg.Watches {
+		st := WatchState{Key: wcfg.Key(), Path: wcfg.Path, Paused: s.paused[wcfg.Key()], Actions: []string{}}
+		for _, a := range wcfg.Actions {
+			st.Actions = append(st.Actions, a.Name)
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// Pause stops the watch with the given key from scanning until Resume.
+// Its baseline is kept, so changes made while paused fire on the first
+// scan after it resumes. Actions already running, retries and handoffs
+// carry on.
+func (s *Supervisor) Pause(watch string) error {
+	return s.setPaused(watch, true)
+}
+
+// Resume undoes Pause.
+func (s *Supervisor) Resume(watch string) error {
+	return s.setPaused(watch, false)
+}
+
+func (s *Supervisor) setPaused(watch string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensureWorkers()
+	w, ok := s.byKey[watch]
+	if !ok {
+		return fmt.Errorf("unknown watch %q", watch)
+	}
+	if s.paused[watch] == paused {
+		return nil
+	}
+	// Kept by key, so a watch restarted by a reload stays paused.
+	if paused {
+		s.paused[watch] = true
+	} else {
+		delete(s.paused, watch)
+	}
+	w.paused.Store(paused)
+	if paused {
+		s.logger.Info("watch paused", "watch", watch)
+	} else {
+		s.logger.Info("watch resumed", "watch", watch)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package watcher
+
+import (
+	"context"
+	"sync"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+)
+
+// actionPool runs a worker's event chains off the worker goroutine when
+// global.max_concurrent_actions allows more than one action at a time, so
+// a slow action on one file does not hold up the rest of the watch. Chains
+// for the same file run one after another, in order. State owned by the
+// worker is only changed through post, whose functions the worker runs
+// between its own steps. A nil pool runs everything in place.
+type actionPool struct {
+	// slots caps action runs across all watches.
+	slots chan struct{}
+	// limits caps runs per action with concurrency set.
+	limits map[string]chan struct{}
+
+	mu     sync.Mutex
+	queued map[string][]func() // per running file, the chains behind it
+	posted []func()
+	wake   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newActionSlots returns the slots shared by all pools, or nil when
+// actions run one at a time.
+func newActionSlots(g config.Global) chan struct{} {
+	if g.MaxConcurrentActions <= 1 {
+		return nil
+	}
+	return make(chan struct{}, g.MaxConcurrentActions)
+}
+
+// newActionPool returns a pool drawing on slots, or nil when slots is.
+func newActionPool(slots chan struct{}, acts []config.Action) *actionPool {
+	if slots == nil {
+		return nil
+	}
+	p := &actionPool{slots: slots, queued: map[string][]func(){}, wake: make(chan struct{}, 1)}
+	p.setActions(acts)
+	return p
+}
+
+// setActions sizes the per-action limits; the pool must be idle.
+func (p *actionPool) setActions(acts []config.Action) {
+	if p == nil {
+		return
+	}
+	p.limits = map[string]chan struct{}{}
+	for _, a := range acts {
+		if a.Concurrency > 0 {
+			p.limits[a.Name] = make(chan struct{}, a.Concurrency)
+		}
+	}
+}
+
+// submit runs fn for path on its own goroutine, after any chain for path
+// already submitted.
+func (p *actionPool) submit(path string, fn func()) {
+	if p == nil {
+		fn()
+		return
+	}
+	p.mu.Lock()
+	if q, running := p.queued[path]; running {
+		p.queued[path] = append(q, fn)
+		p.mu.Unlock()
+		return
+	}
+	p.queued[path] = nil
+	p.wg.Add(1)
+	p.mu.Unlock()
+	go p.run(path, fn)
+}
+
+func (p *actionPool) run(path string, fn func()) {
+	defer p.wg.Done()
+	for {
+		p.call(fn)
+		p.mu.Lock()
+		q := p.queued[path]
+		if len(q) == 0 {
+			delete(p.queued, path)
+			p.mu.Unlock()
+			return
+		}
+		fn, p.queued[path] = q[0], q[1:]
+		p.mu.Unlock()
+	}
+}
+
+// call runs a chain. A panic is passed on to the worker goroutine, where
+// it crashes only this watch like a panic in the worker itself.
+func (p *actionPool) call(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.post(func() { panic(r) })
+		}
+	}()
+	fn()
+}
+
+// busy reports whether a chain for path is running or queued.
+func (p *actionPool) busy(path string) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.queued[path]
+	return ok
+}
+
+// acquire waits for a slot to run the named action and returns its
+// release.
+func (p *actionPool) acquire(name string) func() {
+	if p == nil {
+		return func() {}
+	}
+	limit := p.limits[name]
+	if limit != nil {
+		limit <- struct{}{}
+	}
+	p.slots <- struct{}{}
+	return func() {
+		<-p.slots
+		if limit != nil {
+			<-limit
+		}
+	}
+}
+
+// post hands fn to the worker goroutine.
+func (p *actionPool) post(fn func()) {
+	if p == nil {
+		fn()
+		return
+	}
+	p.mu.Lock()
+	p.posted = append(p.posted, fn)
+	p.mu.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// woken fires when functions were posted.
+func (p *actionPool) woken() <-chan struct{} {
+	if p == nil {
+		return nil
+	}
+	return p.wake
+}
+
+// drain runs the posted functions; only the worker goroutine calls it.
+func (p *actionPool) drain() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	fns := p.posted
+	p.posted = nil
+	p.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// settle waits for every submitted chain and then drains.
+func (p *actionPool) settle() {
+	if p == nil {
+		return
+	}
+	p.wg.Wait()
+	p.drain()
+}
+
+// startChain runs an event's chain, in the pool when there is one, and
+// marks the event handled once the chain is done. A chain's first start
+// opens a part of the event's handling that its end closes.
+func (w *Worker) startChain(ctx context.Context, evCtx actions.Context, chain []config.Action, attempt int) {
+	if attempt == 0 {
+		w.beginHandling(evCtx.ID, evCtx.Event, evCtx.Path)
+	}
+	w.pool.submit(evCtx.Path, func() {
+		if !w.runChain(ctx, evCtx, chain, attempt) {
+			w.endHandling(evCtx.ID)
+		}
+		w.pool.post(func() { w.finish(evCtx.Path) })
+	})
+}
+
+// inSlot runs fn, a run of action, once the action's rate limit allows
+// it and the pool has a slot for it. The rate limit is waited out first,
+// so a throttled action does not hold a slot other actions could use.
+func (w *Worker) inSlot(ctx context.Context, evCtx actions.Context, action config.Action, fn func(context.Context)) {
+	ctx = w.executor.Throttle(ctx, evCtx, action)
+	release := w.pool.acquire(action.Name)
+	defer release()
+	fn(ctx)
+}
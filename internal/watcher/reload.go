@@ -0,0 +1,128 @@
+package watcher
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/match"
+)
+
+// reloadedWatch is a watch's new definition, waiting to be picked up by
+// its worker between events.
+type reloadedWatch struct {
+	cfg     config.Watch
+	matcher *match.Matcher
+}
+
+// Reload applies a changed config without a restart. Watches no longer
+// configured stop and new ones start. A watch whose only change is its
+// actions swaps them in before its next scan, so no event sees a mix of
+// old and new definitions; any other change restarts the watch with a
+// fresh baseline. Global and notification settings are not reloaded.
+func (s *Supervisor) Reload(cfg config.Config) {
+	if !sameYAML(s.cfg.Global, cfg.Global) || !sameYAML(s.cfg.Notifications, cfg.Notifications) {
+		s.logger.Warn("global or notification settings changed; restart the watcher to apply them")
+	}
+	matcher := match.New(cfg.Watches...)
+	s.mu.Lock()
+	s.ensureWorkers()
+	oldCfg := map[string]config.Watch{}
+	for _, wcfg := range s.cfg.Watches {
+		oldCfg[wcfg.Key()] = wcfg
+	}
+	old := s.byKey
+	var halt, start []*Worker
+	var added, restarted, updated int
+	s.workers = nil
+	s.byKey = map[string]*Worker{}
+	for _, wcfg := range cfg.Watches {
+		key := wcfg.Key()
+		w, ok := old[key]
+		delete(old, key)
+		switch {
+		case !ok:
+			w = s.newWorker(wcfg, matcher)
+			start = append(start, w)
+			added++
+			s.logger.Info("watch added", "watch", key)
+		case !oldCfg[key].SameSettings(wcfg):
+			// Queued handoffs move on to the replacement.
+			prev := w
+			w = s.newWorker(wcfg, matcher)
+			w.inbox = prev.inbox
+			halt = append(halt, prev)
+			start = append(start, w)
+			restarted++
+			s.logger.Info("watch restarted", "watch", key)
+		default:
+			if !sameYAML(oldCfg[key].Actions, wcfg.Actions) {
+				updated++
+				s.logger.Info("watch actions updated", "watch", key)
+			}
+			w.swap(wcfg, matcher)
+		}
+		s.workers = append(s.workers, w)
+		s.byKey[key] = w
+	}
+	for key, w := range old {
+		halt = append(halt, w)
+		s.logger.Info("watch removed", "watch", key)
+	}
+	removed := len(old)
+	s.cfg.Watches = cfg.Watches
+	s.matcher = matcher
+	s.mu.Unlock()
+
+	// Workers finish their current event before stopping; the lock is
+	// released meanwhile so their handoffs can still be routed.
+	for _, w := range halt {
+		w.halt()
+	}
+	s.mu.Lock()
+	if s.running != nil {
+		for _, w := range start {
+			s.launch(w)
+		}
+	}
+	s.mu.Unlock()
+	s.logger.Info("config reloaded", "added", added, "removed", removed, "restarted", restarted, "updated", updated)
+}
+
+func sameYAML(a, b any) bool {
+	x, errX := yaml.Marshal(a)
+	y, errY := yaml.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+// swap hands the worker a new definition of its watch.
+func (w *Worker) swap(cfg config.Watch, matcher *match.Matcher) {
+	w.reloadMu.Lock()
+	w.reloaded = &reloadedWatch{cfg: cfg, matcher: matcher}
+	w.reloadMu.Unlock()
+}
+
+// applyReload switches to a definition handed over by swap, if any.
+func (w *Worker) applyReload() {
+	w.reloadMu.Lock()
+	r := w.reloaded
+	w.reloaded = nil
+	w.reloadMu.Unlock()
+	if r != nil {
+		// Running chains read the definition; let them finish first.
+		w.pool.settle()
+		w.cfg = r.cfg
+		w.matcher = r.matcher
+		w.pool.setActions(r.cfg.Actions)
+	}
+}
+
+// halt stops a worker started by launch and waits for it to exit.
+func (w *Worker) halt() {
+	if w.stop == nil {
+		return
+	}
+	w.stop()
+	<-w.done
+}
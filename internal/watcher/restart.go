@@ -0,0 +1,59 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	// restartBaseDelay is the wait before restarting a crashed worker; it
+	// doubles with every further crash up to restartMaxDelay.
+	restartBaseDelay = time.Second
+	restartMaxDelay  = time.Minute
+	// restartStableAfter resets the delay once a worker ran this long
+	// without crashing.
+	restartStableAfter = 5 * time.Minute
+)
+
+// supervise runs w until ctx is done. A panic outside an action's runner
+// crashes only this watch: it is counted in status and the worker restarts
+// with a fresh baseline after a backoff.
+func (s *Supervisor) supervise(ctx context.Context, w *Worker) {
+	delay := restartBaseDelay
+	for {
+		began := time.Now()
+		reason, crashed := w.runGuarded(ctx)
+		if !crashed || ctx.Err() != nil {
+			return
+		}
+		if time.Since(began) > restartStableAfter {
+			delay = restartBaseDelay
+		}
+		s.tracker.IncRestart(w.cfg.Key(), reason)
+		s.logger.Error("watch crashed, restarting", "watch", w.cfg.Key(), "panic", reason, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > restartMaxDelay {
+			delay = restartMaxDelay
+		}
+	}
+}
+
+// runGuarded runs w and reports a panic instead of propagating it.
+func (w *Worker) runGuarded(ctx context.Context) (reason string, crashed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			reason, crashed = fmt.Sprint(r), true
+			w.logger.Error("watch panicked", "watch", w.cfg.Key(), "panic", r, "stack", string(debug.Stack()))
+			w.saveSnapshot()
+		}
+	}()
+	w.Run(ctx)
+	return "", false
+}
@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/config"
+)
+
+func TestCrashedWatchRestarts(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(t.TempDir(), "watcher.yaml")
+	yaml := `
+version: 1
+global:
+  control_socket: none
+watches:
+  - path: ` + dir + `
+    strategy: poll
+    scan_interval_ms: 20ms
+    actions:
+      - name: log
+        type: exec
+        include: ["*"]
+        cmd: "true"
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	s := NewSupervisor(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), true)
+	var calls atomic.Int32
+	s.OnAction(func(ActionResult) {
+		if calls.Add(1) == 1 {
+			panic("hook failed")
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Status()[dir].Restarts == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("crash not recorded: %+v", s.Status())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if c := s.Status()[dir]; c.LastCrash != "hook failed" {
+		t.Fatalf("LastCrash = %q", c.LastCrash)
+	}
+	// The restarted worker takes a new baseline and handles new files.
+	time.Sleep(restartBaseDelay + 100*time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("bb"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for calls.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("restarted watch did not run actions")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWatchdogSeesStalledWorkers(t *testing.T) {
+	now := time.Now()
+	idle, running, stuck := &Worker{}, &Worker{}, &Worker{cfg: config.Watch{Path: "/stuck"}}
+	running.beat.Store(now.Add(-time.Second).UnixNano())
+	s := &Supervisor{workers: []*Worker{idle, running}}
+	if w := s.stalled(now, 10*time.Second); w != nil {
+		t.Fatalf("healthy and stopped workers reported stalled")
+	}
+	stuck.beat.Store(now.Add(-time.Minute).UnixNano())
+	s.workers = append(s.workers, stuck)
+	if w := s.stalled(now, 10*time.Second); w != stuck {
+		t.Fatalf("stalled worker not found, got %v", w)
+	}
+}
@@ -0,0 +1,63 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/config"
+)
+
+// pendingRetry is the unfinished rest of an event's action chain, waiting
+// for its first action to be attempted again.
+type pendingRetry struct {
+	ev      actions.Context
+	chain   []config.Action
+	attempt int
+	due     time.Time
+}
+
+// requeue schedules the chain for another attempt of its first action
+// after the action's backoff, instead of retrying in place, so the worker
+// can handle other events meanwhile.
+func (w *Worker) requeue(ev actions.Context, chain []config.Action, attempt int) {
+	delay := actions.RetryDelay(chain[0], attempt)
+	// The file may change before the retry; read it afresh.
+	ev.Cache = nil
+	r := pendingRetry{
+		ev:      ev,
+		chain:   chain,
+		attempt: attempt,
+		due:     w.clock.Now().Add(delay),
+	}
+	w.pool.post(func() { w.retries = append(w.retries, r) })
+}
+
+// runRetries resumes every chain whose retry is due, oldest first.
+func (w *Worker) runRetries(ctx context.Context) {
+	now := w.clock.Now()
+	var due []pendingRetry
+	waiting := w.retries[:0]
+	for _, r := range w.retries {
+		if now.Before(r.due) {
+			waiting = append(waiting, r)
+		} else {
+			due = append(due, r)
+		}
+	}
+	w.retries = waiting
+	for _, r := range due {
+		w.startChain(ctx, r.ev, r.chain, r.attempt)
+	}
+}
+
+// nextRetry returns when the earliest pending retry is due.
+func (w *Worker) nextRetry() (time.Time, bool) {
+	var next time.Time
+	for _, r := range w.retries {
+		if next.IsZero() || r.due.Before(next) {
+			next = r.due
+		}
+	}
+	return next, !next.IsZero()
+}
@@ -0,0 +1,110 @@
+package watcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"time"
+
+	"watcher-cli/internal/scanner"
+)
+
+const (
+	// snapshotDir holds the saved snapshots inside state_dir.
+	snapshotDir = "snapshots"
+	// snapshotSaveInterval is the most often a changing snapshot is saved.
+	snapshotSaveInterval = 10 * time.Second
+)
+
+// SnapshotFile names the saved snapshot of the watch with the given key.
+func SnapshotFile(stateDir, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(stateDir, snapshotDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// restoreSnapshot makes the saved snapshot the baseline and reports
+// whether there was a usable one.
+func (w *Worker) restoreSnapshot() bool {
+	if w.snapshotPath == "" {
+		return false
+	}
+	snap, ok, err := w.scn.Load(w.snapshotPath)
+	if err != nil {
+		w.logger.Error("restore snapshot", "watch", w.cfg.Key(), "path", w.snapshotPath, "err", err)
+	}
+	if !ok {
+		return false
+	}
+	w.prev.data = snap
+	return true
+}
+
+// saveSnapshotIfDue saves a changed snapshot unless it was saved recently.
+func (w *Worker) saveSnapshotIfDue() {
+	if w.snapshotDirty && w.clock.Now().Sub(w.snapshotSavedAt) >= snapshotSaveInterval {
+		w.saveSnapshot()
+	}
+}
+
+// saveSnapshot saves the baseline if it changed since the last save,
+// leaving out events that were not fully handled yet.
+func (w *Worker) saveSnapshot() {
+	if w.snapshotPath == "" || !w.snapshotDirty || w.prev.data == nil {
+		return
+	}
+	if err := w.scn.Save(w.snapshotPath, w.checkpoint()); err != nil {
+		w.logger.Error("save snapshot", "watch", w.cfg.Key(), "path", w.snapshotPath, "err", err)
+		return
+	}
+	w.snapshotDirty = false
+	w.snapshotSavedAt = w.clock.Now()
+}
+
+// Snapshot returns the baseline of the watch with the given key as its
+// running worker sees it. The copy is made on the worker's goroutine, so
+// it waits for a scan in progress to end.
+func (s *Supervisor) Snapshot(ctx context.Context, watch string) (scanner.Saved, error) {
+	s.mu.Lock()
+	w, ok := s.byKey[watch]
+	s.mu.Unlock()
+	if !ok {
+		return scanner.Saved{}, fmt.Errorf("unknown watch %q", watch)
+	}
+	reply := make(chan scanner.Saved, 1)
+	select {
+	case w.snapshotReq <- reply:
+	case <-ctx.Done():
+		return scanner.Saved{}, ctx.Err()
+	}
+	select {
+	case saved := <-reply:
+		return saved, nil
+	case <-ctx.Done():
+		return scanner.Saved{}, ctx.Err()
+	}
+}
+
+// answerSnapshot replies to a Snapshot request on the worker goroutine.
+func (w *Worker) answerSnapshot(reply chan<- scanner.Saved) {
+	snap := maps.Clone(w.prev.data)
+	if snap == nil {
+		snap = scanner.Snapshot{}
+	}
+	reply <- w.scn.Describe(snap)
+}
+
+// Checkpoint saves the snapshots of all started watches. Run saves them
+// itself on exit; Checkpoint is for supervisors driven by Step.
+func (s *Supervisor) Checkpoint() {
+	s.mu.Lock()
+	workers := append([]*Worker(nil), s.workers...)
+	s.mu.Unlock()
+	for _, w := range workers {
+		if w.started {
+			w.saveSnapshot()
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"strings"
+
+	"watcher-cli/internal/actions"
+	"watcher-cli/internal/bucket"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/sftp"
+)
+
+// remoteSource is a listed source whose files actions can open.
+type remoteSource interface {
+	scanner.Lister
+	actions.Remote
+	Close() error
+}
+
+// newScanner sets up the scanner for the watch's root: a local directory,
+// a bucket prefix listed over the bucket's API, or an SFTP directory. It
+// returns the root snapshot keys are under.
+func (w *Worker) newScanner() (*scanner.Scanner, string) {
+	switch {
+	case w.cfg.Source == config.SourceSFTP:
+		src := sftp.NewSource(w.cfg.SSH, w.cfg.Path, w.cfg.Recursive)
+		w.remote = src
+		return scanner.NewLister(src.Root(), src, w.cfg.Recursive), src.Root()
+	case w.cfg.IsBucket():
+		var opts bucket.Options
+		if b := w.cfg.Bucket; b != nil {
+			opts = bucket.Options{Endpoint: b.Endpoint, Region: b.Region, AccessKeyEnv: b.AccessKeyEnv, SecretKeyEnv: b.SecretKeyEnv}
+		}
+		root := strings.TrimRight(w.cfg.Path, "/")
+		l, err := bucket.NewLister(root, opts)
+		if err != nil {
+			// Load validates bucket URLs; report the error on every scan.
+			return scanner.NewLister(root, failedLister{err}, w.cfg.Recursive), root
+		}
+		return scanner.NewLister(root, l, w.cfg.Recursive), root
+	}
+	return scanner.New(w.cfg.Path, w.cfg.Recursive), w.cfg.Path
+}
+
+// closeSource ends the session of a remote source.
+func (w *Worker) closeSource() {
+	if w.remote == nil {
+		return
+	}
+	if err := w.remote.Close(); err != nil {
+		w.logger.Debug("close source", "watch", w.cfg.Key(), "err", err)
+	}
+}
+
+// failedLister is a lister that could not be set up.
+type failedLister struct{ err error }
+
+func (l failedLister) List() (map[string]scanner.FileInfo, error) {
+	return nil, l.err
+}
@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"watcher-cli/internal/config"
+)
+
+func TestBucketWatchReportsObjectChanges(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]string{"in/old.csv": "2024-01-01T00:00:00.000Z"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprint(w, "<ListBucketResult><IsTruncated>false</IsTruncated>")
+		for key, mtime := range objects {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>%s</LastModified></Contents>", key, len(key), mtime)
+		}
+		fmt.Fprint(w, "</ListBucketResult>")
+	}))
+	defer srv.Close()
+
+	cfgPath := filepath.Join(t.TempDir(), "watcher.yaml")
+	yaml := `
+version: 1
+watches:
+  - path: s3://drop/in
+    bucket:
+      endpoint: ` + srv.URL + `
+      access_key_env: WATCHER_TEST_UNSET
+      secret_key_env: WATCHER_TEST_UNSET
+    actions:
+      - name: ingest
+        type: exec
+        include: ["*.csv"]
+        events: [create, modify, delete]
+        cmd: "true"
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	s := NewSupervisor(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), true)
+	var got []string
+	s.OnAction(func(r ActionResult) { got = append(got, r.Event.Event+" "+r.Event.Path) })
+	ctx := context.Background()
+	s.Step(ctx)
+
+	mu.Lock()
+	objects["in/new.csv"] = "2024-01-02T00:00:00.000Z"
+	objects["in/old.csv"] = "2024-01-03T00:00:00.000Z"
+	mu.Unlock()
+	s.Step(ctx)
+	mu.Lock()
+	delete(objects, "in/new.csv")
+	mu.Unlock()
+	s.Step(ctx)
+
+	want := map[string]bool{"create s3://drop/in/new.csv": true, "modify s3://drop/in/old.csv": true, "delete s3://drop/in/new.csv": true}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected events %v", got)
+	}
+	for _, g := range got {
+		if !want[g] {
+			t.Fatalf("unexpected events %v", got)
+		}
+	}
+}
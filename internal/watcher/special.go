@@ -0,0 +1,34 @@
+package watcher
+
+import (
+	"fmt"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+// filterSpecial removes events for FIFOs, sockets and device nodes, which
+// actions cannot handle (copying a FIFO blocks forever), reporting them as
+// the watch's special_files policy asks.
+func (w *Worker) filterSpecial(events []scanner.Event) []scanner.Event {
+	out := events[:0]
+	for _, ev := range events {
+		kind := ev.Info.Special()
+		if kind == "" {
+			out = append(out, ev)
+			continue
+		}
+		if ev.Type == "delete" {
+			continue
+		}
+		switch w.cfg.SpecialFiles {
+		case config.SpecialReport:
+			w.logger.Warn("special file ignored", "watch", w.cfg.Key(), "path", ev.Path, "kind", kind)
+		case config.SpecialError:
+			err := fmt.Errorf("special file %s (%s)", ev.Path, kind)
+			w.logger.Error("scan error", "watch", w.cfg.Key(), "err", err)
+			w.notifier.ScanFailed(w.cfg.Key(), err)
+		}
+	}
+	return out
+}
@@ -0,0 +1,24 @@
+package watcher
+
+import (
+	"io/fs"
+	"log/slog"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+func TestFilterSpecialDropsFIFOsAndDevices(t *testing.T) {
+	w := &Worker{cfg: config.Watch{Path: "/w", SpecialFiles: config.SpecialReport}, logger: slog.Default()}
+	events := []scanner.Event{
+		{Path: "/w/pipe", Type: "create", Info: scanner.FileInfo{Mode: fs.ModeNamedPipe}},
+		{Path: "/w/a.txt", Type: "create"},
+		{Path: "/w/tty", Type: "create", Info: scanner.FileInfo{Mode: fs.ModeDevice | fs.ModeCharDevice}},
+		{Path: "/w/pipe", Type: "delete", Info: scanner.FileInfo{Mode: fs.ModeNamedPipe}},
+	}
+	out := w.filterSpecial(events)
+	if len(out) != 1 || out[0].Path != "/w/a.txt" {
+		t.Fatalf("expected only the regular file, got %+v", out)
+	}
+}
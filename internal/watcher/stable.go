@@ -0,0 +1,146 @@
+package watcher
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+// heldEvent is a create or modify event whose actions with
+// condition.stable_for_ms wait for the file to stop changing.
+type heldEvent struct {
+	ev      scanner.Event
+	id      string
+	since   time.Time // when a scan last saw the size or mtime change
+	actions []config.Action
+}
+
+// holdUntilStable holds the selected actions that wait for the event's
+// file to settle and returns the ones to run now. A file already held
+// keeps its first event, so a file still being written runs each action
+// once.
+func (w *Worker) holdUntilStable(ev scanner.Event, id string, selected []config.Action) []config.Action {
+	if ev.Info.IsDir || (ev.Type != "create" && ev.Type != "modify") {
+		return selected
+	}
+	var now, later []config.Action
+	for _, a := range selected {
+		if a.Condition.StableFor.Duration() > 0 {
+			later = append(later, a)
+		} else {
+			now = append(now, a)
+		}
+	}
+	if len(later) == 0 {
+		return selected
+	}
+	h := w.held[ev.Path]
+	if h == nil {
+		if w.held == nil {
+			w.held = map[string]*heldEvent{}
+		}
+		h = &heldEvent{ev: ev, id: id, since: w.clock.Now()}
+		w.held[ev.Path] = h
+		w.beginHandling(id, ev.Type, ev.DiskPath())
+	}
+	for _, a := range later {
+		if !heldAction(h.actions, a.Name) {
+			h.actions = append(h.actions, a)
+		}
+	}
+	w.logger.Debug("event held until stable", "event_id", h.id, "watch", w.cfg.Key(), "path", ev.Path, "actions", len(h.actions))
+	return now
+}
+
+func heldAction(list []config.Action, name string) bool {
+	for _, a := range list {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseStable checks held files against the scan curr: a changed file
+// restarts its quiet period, a vanished one drops its actions, and
+// actions whose quiet period has passed run.
+func (w *Worker) releaseStable(ctx context.Context, curr scanner.Snapshot) {
+	if len(w.held) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(w.held))
+	for path := range w.held {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	now := w.clock.Now()
+	for _, path := range paths {
+		h := w.held[path]
+		info, ok := curr[path]
+		if !ok || info.IsDir {
+			delete(w.held, path)
+			w.logger.Debug("held event dropped, file gone", "event_id", h.id, "watch", w.cfg.Key(), "path", path)
+			w.endHandling(h.id)
+			w.finish(h.ev.DiskPath())
+			continue
+		}
+		if info.Size != h.ev.Info.Size || !info.ModTime.Equal(h.ev.Info.ModTime) {
+			h.ev.Info = info
+			h.since = now
+			continue
+		}
+		var ready, waiting []config.Action
+		for _, a := range h.actions {
+			if now.Sub(h.since) >= a.Condition.StableFor.Duration() {
+				ready = append(ready, a)
+			} else {
+				waiting = append(waiting, a)
+			}
+		}
+		if len(ready) == 0 {
+			continue
+		}
+		h.actions = waiting
+		if len(waiting) == 0 {
+			delete(w.held, path)
+		}
+		ev := h.ev
+		ev.Age = now.Sub(ev.Info.ModTime)
+		w.logger.Debug("held event released", "event_id", h.id, "watch", w.cfg.Key(), "path", path, "stable_for_ms", now.Sub(h.since).Milliseconds())
+		w.dispatch(ctx, ev, h.id, ready)
+		if len(waiting) == 0 {
+			w.endHandling(h.id)
+		}
+		w.finish(ev.DiskPath())
+	}
+}
+
+// nextStable returns when the earliest held action may be released.
+func (w *Worker) nextStable() (time.Time, bool) {
+	var next time.Time
+	for _, h := range w.held {
+		for _, a := range h.actions {
+			due := h.since.Add(a.Condition.StableFor.Duration())
+			if next.IsZero() || due.Before(next) {
+				next = due
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// nextWake returns when the worker next needs to run without a scan or
+// handoff: for a retry, to release held events or to run a batch.
+func (w *Worker) nextWake() (time.Time, bool) {
+	next, ok := w.nextRetry()
+	if due, held := w.nextStable(); held && (!ok || due.Before(next)) {
+		next, ok = due, true
+	}
+	if due, batched := w.nextBatch(); batched && (!ok || due.Before(next)) {
+		next, ok = due, true
+	}
+	return next, ok
+}
@@ -0,0 +1,115 @@
+package watcher
+
+import (
+	"os"
+	"sort"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+// transientFilter suppresses files that appear and disappear again within
+// a scan or two, such as editor temp files and partial downloads.
+type transientFilter struct {
+	cfg config.Transient
+	// dropped holds paths whose create was dropped, so their delete is
+	// dropped too.
+	dropped map[string]struct{}
+	// pending holds delayed creates and the scans left before dispatch.
+	pending map[string]*pendingCreate
+}
+
+type pendingCreate struct {
+	ev   scanner.Event
+	left int
+}
+
+func newTransientFilter(cfg *config.Transient) *transientFilter {
+	if cfg == nil {
+		return nil
+	}
+	return &transientFilter{cfg: *cfg, dropped: map[string]struct{}{}, pending: map[string]*pendingCreate{}}
+}
+
+// filter returns the events to dispatch for one scan and the number of
+// events suppressed as transient.
+func (f *transientFilter) filter(events []scanner.Event, curr scanner.Snapshot) ([]scanner.Event, int) {
+	if f == nil {
+		return events, 0
+	}
+	if f.cfg.Mode == config.TransientDrop {
+		return f.drop(events)
+	}
+	return f.delay(events, curr)
+}
+
+// drop discards events for paths that are gone by dispatch time, along
+// with the delete that follows.
+func (f *transientFilter) drop(events []scanner.Event) ([]scanner.Event, int) {
+	out := events[:0]
+	suppressed := 0
+	for _, ev := range events {
+		if ev.Type == "delete" {
+			if _, ok := f.dropped[ev.Path]; ok {
+				delete(f.dropped, ev.Path)
+				suppressed++
+				continue
+			}
+			out = append(out, ev)
+			continue
+		}
+		if _, err := os.Lstat(ev.DiskPath()); os.IsNotExist(err) {
+			f.dropped[ev.Path] = struct{}{}
+			suppressed++
+			continue
+		}
+		out = append(out, ev)
+	}
+	return out, suppressed
+}
+
+// delay holds creates for HoldScans scans and drops them if the file is
+// deleted meanwhile.
+func (f *transientFilter) delay(events []scanner.Event, curr scanner.Snapshot) ([]scanner.Event, int) {
+	out := make([]scanner.Event, 0, len(events))
+	suppressed := 0
+	for _, ev := range events {
+		switch {
+		case ev.Type == "create":
+			f.pending[ev.Path] = &pendingCreate{ev: ev, left: f.cfg.HoldScans}
+			continue
+		case ev.Type == "delete" && f.pending[ev.Path] != nil:
+			delete(f.pending, ev.Path)
+			suppressed++
+			continue
+		case ev.Type == "modify" && f.pending[ev.Path] != nil:
+			// Still settling; the create goes out with the latest info.
+			f.pending[ev.Path].ev.Info = ev.Info
+			f.pending[ev.Path].ev.Age = ev.Age
+			continue
+		case ev.Type == "move" && f.pending[ev.PrevPath] != nil:
+			p := f.pending[ev.PrevPath]
+			delete(f.pending, ev.PrevPath)
+			created := ev
+			created.Type = "create"
+			created.PrevPath = ""
+			f.pending[ev.Path] = &pendingCreate{ev: created, left: p.left}
+			continue
+		}
+		out = append(out, ev)
+	}
+	var ready []scanner.Event
+	for path, p := range f.pending {
+		if _, ok := curr[path]; !ok {
+			delete(f.pending, path)
+			suppressed++
+			continue
+		}
+		if p.left--; p.left < 0 {
+			ready = append(ready, p.ev)
+			delete(f.pending, path)
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Path < ready[j].Path })
+	return append(out, ready...), suppressed
+}
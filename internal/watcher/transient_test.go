@@ -0,0 +1,48 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/scanner"
+)
+
+func TestTransientDelayDropsShortLivedFiles(t *testing.T) {
+	f := newTransientFilter(&config.Transient{Mode: config.TransientDelay, HoldScans: 1})
+	tmp := scanner.Event{Path: "/w/a.tmp", Type: "create"}
+	keep := scanner.Event{Path: "/w/b.txt", Type: "create"}
+
+	out, _ := f.filter([]scanner.Event{tmp, keep}, scanner.Snapshot{tmp.Path: {}, keep.Path: {}})
+	if len(out) != 0 {
+		t.Fatalf("creates should be held, got %+v", out)
+	}
+	del := scanner.Event{Path: tmp.Path, Type: "delete"}
+	out, suppressed := f.filter([]scanner.Event{del}, scanner.Snapshot{keep.Path: {}})
+	if len(out) != 1 || out[0].Path != keep.Path || out[0].Type != "create" {
+		t.Fatalf("expected only the settled create, got %+v", out)
+	}
+	if suppressed != 1 {
+		t.Fatalf("expected the transient delete to be suppressed, got %d", suppressed)
+	}
+}
+
+func TestTransientDropSkipsVanishedFiles(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present.txt")
+	if err := os.WriteFile(present, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	gone := filepath.Join(dir, "gone.part")
+	f := newTransientFilter(&config.Transient{Mode: config.TransientDrop})
+
+	out, _ := f.filter([]scanner.Event{{Path: gone, Type: "create"}, {Path: present, Type: "create"}}, nil)
+	if len(out) != 1 || out[0].Path != present {
+		t.Fatalf("expected only the present file, got %+v", out)
+	}
+	out, _ = f.filter([]scanner.Event{{Path: gone, Type: "delete"}}, nil)
+	if len(out) != 0 {
+		t.Fatalf("delete of a dropped file should be dropped, got %+v", out)
+	}
+}
@@ -2,145 +2,1145 @@ package watcher
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"watcher-cli/internal/actions"
+	"watcher-cli/internal/chaos"
 	"watcher-cli/internal/config"
+	"watcher-cli/internal/control"
+	"watcher-cli/internal/dedup"
+	"watcher-cli/internal/diskusage"
+	"watcher-cli/internal/enrich"
+	"watcher-cli/internal/export"
+	"watcher-cli/internal/fswatch"
+	"watcher-cli/internal/history"
 	"watcher-cli/internal/match"
+	"watcher-cli/internal/metadata"
+	"watcher-cli/internal/metrics"
+	"watcher-cli/internal/notify"
+	"watcher-cli/internal/retention"
 	"watcher-cli/internal/scanner"
+	"watcher-cli/internal/sdnotify"
 	"watcher-cli/internal/status"
+	"watcher-cli/internal/summary"
 )
 
+// Clock supplies the current time; tests substitute a fake one.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// ActionResult describes a finished action and is passed to OnAction hooks.
+type ActionResult struct {
+	Watch  string
+	Action config.Action
+	Event  actions.Context
+	Err    error
+}
+
 // Supervisor manages watch workers.
 type Supervisor struct {
 	cfg      config.Config
 	logger   *slog.Logger
 	tracker  *status.Tracker
+	metrics  *metrics.Recorder
 	executor *actions.Executor
 	matcher  *match.Matcher
+	notifier *notify.Notifier
+	summary  *summary.Recorder
+	export   *export.Sink
+	history  *history.Store
+	faults   *chaos.Injector
+	clock    Clock
+	onAction func(ActionResult)
+	// slots caps action runs across watches; nil runs them one at a time.
+	slots chan struct{}
+	// paused holds the keys of watches paused through Pause.
+	paused map[string]bool
+
+	// mu guards the worker set, which Reload changes while running.
+	mu      sync.Mutex
+	workers []*Worker
+	byKey   map[string]*Worker
+	running context.Context // set by Run; nil when driven by Step
+	stopped bool
+	wg      sync.WaitGroup
 }
 
 // NewSupervisor constructs a supervisor.
 func NewSupervisor(cfg config.Config, logger *slog.Logger, dryRun bool) *Supervisor {
-	reg := actions.NewRegistry()
 	return &Supervisor{
 		cfg:      cfg,
 		logger:   logger,
 		tracker:  status.NewTracker(),
-		executor: &actions.Executor{Registry: reg, DryRun: dryRun},
-		matcher:  match.New(),
+		metrics:  metrics.NewRecorder(cfg.Global.Metrics.Labels, cfg.Global.Metrics.MaxLabelValues),
+		executor: actions.NewExecutor(cfg.Global, dryRun),
+		matcher:  match.New(cfg.Watches...),
+		notifier: notify.New(cfg.Notifications),
+		summary:  summary.New(),
+		export:   export.New(cfg.Global.Export),
+		clock:    realClock{},
+		slots:    newActionSlots(cfg.Global),
+		paused:   map[string]bool{},
 	}
 }
 
+// SetClock replaces the clock used for debouncing, ages and intervals.
+// It must be called before Run or Step.
+func (s *Supervisor) SetClock(c Clock) {
+	s.clock = c
+}
+
+// SetFaults injects faults into actions, copies and scans for resilience
+// testing. It must not be called while Run is active.
+func (s *Supervisor) SetFaults(f *chaos.Injector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executor.Faults = f
+	s.faults = f
+	for _, w := range s.workers {
+		w.executor.Faults = f
+		w.faults = f
+	}
+}
+
+// OnAction registers a hook called after every action, including dry-run
+// actions. It must be called before Run or Step.
+func (s *Supervisor) OnAction(fn func(ActionResult)) {
+	s.onAction = fn
+}
+
+// readCacheMax is the largest file buffered in memory for an event whose
+// actions all read it.
+const readCacheMax = 8 << 20
+
+// statusSaveInterval is how often persisted status counters are written.
+const statusSaveInterval = 30 * time.Second
+
+// heartbeatInterval is how often an idle worker loop turns to show the
+// watchdog it is alive.
+const heartbeatInterval = time.Second
+
 // Run starts all workers and blocks until ctx done.
 func (s *Supervisor) Run(ctx context.Context) error {
-	var wg sync.WaitGroup
+	if s.cfg.Global.PersistStatus {
+		path := filepath.Join(s.cfg.Global.StateDir, status.FileName)
+		if err := s.tracker.Restore(path); err != nil {
+			s.logger.Error("restore status", "path", path, "err", err)
+		}
+		defer s.saveStatus(path)
+		go func() {
+			ticker := time.NewTicker(statusSaveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.saveStatus(path)
+				}
+			}
+		}()
+	}
+	if path, ok := control.Resolve(s.cfg.Global.ControlSocket); ok {
+		s.serveControl(ctx, path)
+	}
+	s.mu.Lock()
+	s.running = ctx
+	for _, w := range s.ensureWorkers() {
+		s.launch(w)
+	}
+	s.mu.Unlock()
+	s.notifySystemd(sdnotify.Ready)
+	if every := sdnotify.WatchdogInterval(); every > 0 {
+		go s.watchdog(ctx, every)
+	}
+	<-ctx.Done()
+	s.notifySystemd(sdnotify.Stopping)
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.wg.Wait()
+	s.notifier.Wait()
+	if err := s.export.Close(); err != nil {
+		s.logger.Error("close export", "err", err)
+	}
+	return nil
+}
+
+// Step runs one scan of every watch synchronously and dispatches the
+// resulting events. The first call only records the baseline snapshot.
+// It lets tests drive the supervisor deterministically instead of Run.
+func (s *Supervisor) Step(ctx context.Context) {
+	s.mu.Lock()
+	workers := append([]*Worker(nil), s.ensureWorkers()...)
+	s.mu.Unlock()
+	for _, w := range workers {
+		if !w.started {
+			w.start(ctx)
+			continue
+		}
+		w.scan(ctx)
+	}
+	// Wait for the actions and follow handoffs through every stage of the
+	// pipeline.
+	for pending := true; pending; {
+		pending = false
+		for _, w := range workers {
+			w.pool.settle()
+		}
+		for _, w := range workers {
+			if w.started && w.receiveHandoffs(ctx) > 0 {
+				pending = true
+			}
+		}
+	}
+	s.notifier.Wait()
+}
+
+// ensureWorkers creates the workers on first use; callers hold s.mu.
+func (s *Supervisor) ensureWorkers() []*Worker {
+	if s.byKey != nil {
+		return s.workers
+	}
+	store, err := OpenHistory(s.cfg.Global)
+	if err != nil {
+		s.logger.Error("open history", "path", filepath.Join(s.cfg.Global.StateDir, history.FileName), "err", err)
+	}
+	s.history = store
+	s.byKey = map[string]*Worker{}
 	for _, wcfg := range s.cfg.Watches {
-		wg.Add(1)
-		go func(w config.Watch) {
-			defer wg.Done()
-			worker := &Worker{
-				cfg:      w,
-				logger:   s.logger,
-				tracker:  s.tracker,
-				executor: s.executor,
-				matcher:  s.matcher,
+		w := s.newWorker(wcfg, s.matcher)
+		s.workers = append(s.workers, w)
+		s.byKey[wcfg.Key()] = w
+	}
+	return s.workers
+}
+
+func (s *Supervisor) newWorker(wcfg config.Watch, matcher *match.Matcher) *Worker {
+	if h := wcfg.Health; h != nil {
+		s.tracker.SetThresholds(wcfg.Key(), status.Thresholds{
+			MaxErrorRate:  h.MaxErrorRate,
+			MaxQueueDepth: h.MaxQueueDepth,
+			MaxIdle:       h.MaxIdle.Duration(),
+		})
+	}
+	w := &Worker{
+		cfg:      wcfg,
+		logger:   s.logger,
+		tracker:  s.tracker,
+		metrics:  s.metrics,
+		executor: s.executor.ForWatch(),
+		matcher:  matcher,
+		notifier: s.notifier,
+		summary:  s.summary,
+		export:   s.export,
+		history:  s.history,
+		faults:   s.faults,
+		clock:    s.clock,
+		onAction: s.onAction,
+		inbox:    newInbox(),
+		route:    s.route,
+		pool:     newActionPool(s.slots, wcfg.Actions),
+		// Unbuffered, so only a running worker takes a request.
+		snapshotReq: make(chan chan<- scanner.Saved),
+	}
+	if s.cfg.Global.PersistSnapshots {
+		w.snapshotPath = SnapshotFile(s.cfg.Global.StateDir, wcfg.Key())
+	}
+	w.paused.Store(s.paused[wcfg.Key()])
+	return w
+}
+
+// route delivers a handoff to the watch with the given key.
+func (s *Supervisor) route(watch string, h handoff) {
+	s.mu.Lock()
+	target, ok := s.byKey[watch]
+	s.mu.Unlock()
+	if ok {
+		target.inbox.push(h)
+	}
+}
+
+// launch runs w until the supervisor's context is done or w is halted;
+// callers hold s.mu.
+func (s *Supervisor) launch(w *Worker) {
+	if s.stopped {
+		return
+	}
+	ctx, cancel := context.WithCancel(s.running)
+	w.stop = cancel
+	w.done = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(w.done)
+		defer w.executor.Close()
+		s.supervise(ctx, w)
+	}()
+}
+
+// serveControl answers `watcher status` on the control socket until ctx is
+// done. The watcher keeps running without it if the socket is unusable.
+func (s *Supervisor) serveControl(ctx context.Context, path string) {
+	ln, err := control.Listen(path)
+	if err != nil {
+		s.logger.Warn("control socket unavailable", "path", path, "err", err)
+		return
+	}
+	s.logger.Info("control socket listening", "path", path)
+	go func() {
+		if err := control.Serve(ctx, ln, s); err != nil {
+			s.logger.Error("control socket", "path", path, "err", err)
+		}
+	}()
+}
+
+// notifySystemd reports state to systemd when run as a Type=notify unit.
+func (s *Supervisor) notifySystemd(state string) {
+	if _, err := sdnotify.Notify(state); err != nil {
+		s.logger.Warn("systemd notify", "state", state, "err", err)
+	}
+}
+
+// watchdog pings the systemd watchdog until ctx is done, twice per
+// interval. A ping needs the worker set's lock and every running worker's
+// loop to have turned within the interval, so a wedged supervisor or watch
+// stops pinging and systemd restarts the service.
+func (s *Supervisor) watchdog(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			stopped := s.stopped
+			stalled := s.stalled(time.Now(), interval)
+			s.mu.Unlock()
+			switch {
+			case stopped:
+			case stalled != nil:
+				s.logger.Error("watch stalled, withholding watchdog ping", "watch", stalled.cfg.Key(), "since", time.Unix(0, stalled.beat.Load()))
+			default:
+				s.notifySystemd(sdnotify.Watchdog)
 			}
-			worker.Run(ctx)
-		}(wcfg)
+		}
+	}
+}
+
+// stalled returns a running worker whose loop last turned longer than
+// limit before now, or nil; callers hold s.mu.
+func (s *Supervisor) stalled(now time.Time, limit time.Duration) *Worker {
+	for _, w := range s.workers {
+		if beat := w.beat.Load(); beat != 0 && now.Sub(time.Unix(0, beat)) > limit {
+			return w
+		}
 	}
-	wg.Wait()
 	return nil
 }
 
+func (s *Supervisor) saveStatus(path string) {
+	if err := s.tracker.Save(path); err != nil {
+		s.logger.Error("save status", "path", path, "err", err)
+	}
+}
+
 // Status returns snapshot.
 func (s *Supervisor) Status() map[string]status.Counter {
 	return s.tracker.Snapshot()
 }
 
+// RenderMetrics writes the status counters and the labeled event and
+// action counts in the Prometheus text format, all under the configured
+// metrics labels.
+func (s *Supervisor) RenderMetrics() []byte {
+	s.mu.Lock()
+	watches := make([]string, 0, len(s.cfg.Watches))
+	for _, w := range s.cfg.Watches {
+		watches = append(watches, w.Key())
+	}
+	s.mu.Unlock()
+	return append(s.metrics.RenderCounters(s.Status(), watches), s.metrics.Render()...)
+}
+
+// Summary returns the run summary so far.
+func (s *Supervisor) Summary() summary.Report {
+	return s.summary.Report()
+}
+
+// Health returns the health of every watch.
+func (s *Supervisor) Health() map[string]status.Health {
+	return s.tracker.Health()
+}
+
 // Worker watches a single directory.
 type Worker struct {
 	cfg      config.Watch
 	logger   *slog.Logger
 	tracker  *status.Tracker
+	metrics  *metrics.Recorder
 	executor *actions.Executor
 	matcher  *match.Matcher
+	notifier *notify.Notifier
+	summary  *summary.Recorder
+	export   *export.Sink
+	history  *history.Store
+	faults   *chaos.Injector
+	clock    Clock
+	onAction func(ActionResult)
+
+	scn        *scanner.Scanner
+	started    bool
+	prev       snapshotState
+	debounce   *debouncer
+	transient  *transientFilter
+	location   *time.Location
+	lowSpace   bool
+	health     status.HealthState
+	retainedAt []time.Time
+	dedupAt    time.Time
+	retries    []pendingRetry
+	burstScans int
+	inbox      *inbox
+	route      func(watch string, h handoff)
+	pool       *actionPool
+	// snapshotReq carries Snapshot requests to the worker goroutine.
+	snapshotReq chan chan<- scanner.Saved
+
+	rootDev        uint64
+	rootDevOK      bool
+	mount          diskusage.Mount
+	mountOK        bool
+	foldCase       bool
+	massDeleteHeld bool
+
+	root   string
+	remote remoteSource
 
-	prev        snapshotState
-	debounceMap map[string]time.Time
+	snapshotPath    string
+	snapshotDirty   bool
+	snapshotSavedAt time.Time
+	unfinished      map[string]unfinishedEvent
+	held            map[string]*heldEvent
+	batches         map[string]*pendingBatch // by action name
+	batching        map[string]int           // events per path in batches
+	handling        handlings
+
+	stop     context.CancelFunc
+	done     chan struct{}
+	reloadMu sync.Mutex
+	reloaded *reloadedWatch
+	// paused skips scans while set; see Supervisor.Pause.
+	paused atomic.Bool
+	// beat is when the loop last turned, in Unix nanoseconds, or 0 while
+	// it is not running; see Supervisor.watchdog.
+	beat atomic.Int64
 }
 
 type snapshotState struct {
 	data scanner.Snapshot
 }
 
-// Run starts the polling loop.
+// Run starts the scan loop. Depending on the watch strategy, scans follow
+// the scan interval, native change notifications, or both.
 func (w *Worker) Run(ctx context.Context) {
-	scn := scanner.New(w.cfg.Path, w.cfg.Recursive)
-	ticker := time.NewTicker(w.cfg.ScanInterval.Duration())
-	defer ticker.Stop()
+	w.beat.Store(time.Now().UnixNano())
+	defer w.beat.Store(0)
+	w.start(ctx)
+	poll, notes := w.triggers()
+	if notes != nil {
+		defer notes.Close()
+	}
+	var tick, pending <-chan time.Time
+	var wake <-chan struct{}
+	if poll {
+		ticker := time.NewTicker(w.cfg.ScanInterval.Duration())
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	if notes != nil {
+		wake = notes.C
+	}
+	retry := time.NewTimer(time.Hour)
+	retry.Stop()
+	defer retry.Stop()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		w.beat.Store(time.Now().UnixNano())
+		if due, ok := w.nextWake(); ok {
+			retry.Reset(due.Sub(w.clock.Now()))
+		}
+		select {
+		case <-ctx.Done():
+			// Chains still running may add to batches; run those last,
+			// each still bounded by its action's timeout.
+			w.pool.settle()
+			w.flushBatches(context.WithoutCancel(ctx), true)
+			w.pool.settle()
+			w.handling.posts.Wait()
+			w.saveSnapshot()
+			w.closeSource()
+			if len(w.retries) > 0 && w.snapshotPath != "" {
+				w.logger.Info("pending retries left for next start", "watch", w.cfg.Key(), "count", len(w.retries))
+			} else if len(w.retries) > 0 {
+				w.logger.Warn("dropping pending retries", "watch", w.cfg.Key(), "count", len(w.retries))
+			}
+			return
+		case <-retry.C:
+			w.runRetries(ctx)
+			w.flushBatches(ctx, false)
+			if due, ok := w.nextStable(); ok && !due.After(w.clock.Now()) {
+				// Held files are only released by a scan that sees them
+				// unchanged.
+				w.scan(ctx)
+			}
+		case <-w.inbox.wake:
+			w.receiveHandoffs(ctx)
+		case reply := <-w.snapshotReq:
+			w.answerSnapshot(reply)
+		case <-w.pool.woken():
+			w.pool.drain()
+		case <-heartbeat.C:
+			// The turn itself is the heartbeat.
+		case <-tick:
+			w.scan(ctx)
+		case <-wake:
+			// Wait out the coalescing window so a burst of writes costs
+			// one scan.
+			if pending == nil {
+				pending = time.After(w.cfg.Coalesce.Duration())
+			}
+		case <-pending:
+			pending = nil
+			w.scan(ctx)
+		}
+	}
+}
 
-	// initial scan
-	w.prev.data, _ = scn.Scan()
-	w.debounceMap = make(map[string]time.Time)
+// triggers resolves the watch strategy into whether to poll and, for
+// native and hybrid watching, a change notifier. Without native support
+// the watch falls back to polling.
+func (w *Worker) triggers() (bool, *fswatch.Notifier) {
+	strategy := w.cfg.Strategy
+	if strategy == config.StrategyPoll || w.cfg.IsRemote() {
+		return true, nil
+	}
+	notes, err := fswatch.New(w.cfg.Path, w.cfg.Recursive)
+	if err != nil {
+		if strategy != config.StrategyAuto && strategy != "" {
+			w.logger.Warn("native notifications unavailable, polling instead", "watch", w.cfg.Key(), "strategy", strategy, "err", err)
+		}
+		return true, nil
+	}
+	return strategy != config.StrategyNative, notes
+}
 
-	for {
+// start records the initial snapshot.
+func (w *Worker) start(ctx context.Context) {
+	if w.clock == nil {
+		w.clock = realClock{}
+	}
+	w.closeSource()
+	w.remote = nil
+	w.scn, w.root = w.newScanner()
+	if src := w.remote; src != nil {
+		// Closing the session fails a listing stuck on an unresponsive
+		// host, so it cannot hold up shutdown.
+		context.AfterFunc(ctx, func() { src.Close() })
+	}
+	w.scn.SetNormalization(w.cfg.NormalizeUnicode)
+	w.scn.SetXattrs(w.cfg.NeedsXattrs())
+	restored := w.restoreSnapshot()
+	if !restored {
+		var err error
+		if w.prev.data, err = w.scn.Scan(); err != nil {
+			w.logger.Error("baseline scan error", "watch", w.cfg.Key(), "err", err)
+		}
+		w.snapshotDirty = true
+		w.saveSnapshot()
+	}
+	w.rememberRoot()
+	w.probeMount()
+	w.probeCase()
+	w.debounce = newDebouncer(w.cfg.Debounce.Duration())
+	w.transient = newTransientFilter(w.cfg.Transient)
+	w.location = w.cfg.Location()
+	w.started = true
+	w.checkLowSpace(ctx)
+	if restored {
+		// Dispatch what changed while the watcher was stopped.
+		w.logger.Info("snapshot restored, catching up", "watch", w.cfg.Key(), "entries", len(w.prev.data))
+		w.scan(ctx)
+	}
+}
+
+// scan diffs the watch against the previous snapshot and handles the
+// resulting events.
+func (w *Worker) scan(ctx context.Context) {
+	if d := w.faults.ScanDelay(); d > 0 {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			curr, err := scn.Scan()
-			if err != nil {
-				w.logger.Error("scan error", "path", w.cfg.Path, "err", err)
+		case <-time.After(d):
+		}
+	}
+	w.applyReload()
+	if w.paused.Load() {
+		return
+	}
+	curr, err := w.scn.Scan()
+	if err != nil {
+		w.logger.Error("scan error", "watch", w.cfg.Key(), "err", err)
+		w.notifier.ScanFailed(w.cfg.Key(), err)
+		return
+	}
+	if w.prev.data == nil && w.cfg.IsRemote() {
+		// The baseline listing failed at start; take it now rather than
+		// reporting the whole source as created.
+		w.prev.data = curr
+		w.snapshotDirty = true
+		return
+	}
+	// Retries of earlier events and handoffs run before newer events; a
+	// received handoff also keeps this scan from reporting its file again.
+	w.runRetries(ctx)
+	if w.inbox != nil {
+		w.receiveHandoffs(ctx)
+	}
+	diff := scanner.Diff
+	if w.foldCase {
+		diff = scanner.DiffCaseInsensitive
+	}
+	events := diff(w.root, w.prev.data, curr)
+	if w.holdMassDelete(events) || w.holdBurst(len(events)) {
+		return
+	}
+	old := w.prev.data
+	w.prev.data = curr
+	if len(events) > 0 {
+		w.snapshotDirty = true
+	}
+	events = w.filterSpecial(events)
+	events, suppressed := w.transient.filter(events, curr)
+	if suppressed > 0 {
+		w.logger.Debug("transient events suppressed", "watch", w.cfg.Key(), "count", suppressed)
+	}
+	orderEvents(events, w.cfg.EventOrder)
+	w.track(old, events)
+	w.releaseStable(ctx, curr)
+	w.tracker.SetQueueDepth(w.cfg.Key(), len(events))
+	w.checkHealth()
+	for i, ev := range events {
+		if ctx.Err() != nil {
+			// Shutting down: the rest stay unfinished for the next start.
+			w.logger.Info("events left for next start", "watch", w.cfg.Key(), "count", len(events)-i)
+			break
+		}
+		// Deletes carry the last-known mtime, so their age is as of deletion.
+		if !ev.Info.ModTime.IsZero() {
+			ev.Age = w.clock.Now().Sub(ev.Info.ModTime)
+		}
+		w.handleEvent(ctx, ev)
+		w.finish(ev.DiskPath())
+		w.tracker.SetQueueDepth(w.cfg.Key(), len(events)-i-1)
+	}
+	w.checkHealth()
+	w.flushBatches(ctx, false)
+	w.debounce.expire(w.clock.Now())
+	w.checkLowSpace(ctx)
+	w.applyRetention(ctx)
+	w.checkDuplicates(ctx)
+	w.saveSnapshotIfDue()
+}
+
+// checkHealth logs when the watch's health state changes.
+func (w *Worker) checkHealth() {
+	if w.cfg.Health == nil {
+		return
+	}
+	h := w.tracker.WatchHealth(w.cfg.Key())
+	if h.State == w.health {
+		return
+	}
+	if h.State == status.HealthDegraded {
+		w.logger.Warn("watch degraded", "watch", w.cfg.Key(), "reasons", h.Reasons)
+	} else if w.health != "" {
+		w.logger.Info("watch healthy", "watch", w.cfg.Key())
+	}
+	w.health = h.State
+}
+
+// checkDuplicates runs the dedup scan when its interval has elapsed and
+// reports each duplicate group.
+func (w *Worker) checkDuplicates(ctx context.Context) {
+	rule := w.cfg.Dedup
+	if rule == nil || w.clock.Now().Sub(w.dedupAt) < rule.Interval.Duration() {
+		return
+	}
+	w.dedupAt = w.clock.Now()
+	groups, err := dedup.Find(w.cfg.Path, w.prev.data, *rule)
+	if err != nil {
+		w.logger.Error("dedup error", "watch", w.cfg.Key(), "err", err)
+		return
+	}
+	for _, g := range groups {
+		w.logger.Info("duplicate files", "watch", w.cfg.Key(), "sha256", g.Hash, "size", g.Size, "paths", g.Paths)
+		action, ok := w.action(rule.Action)
+		if !ok {
+			continue
+		}
+		info := w.prev.data[g.Paths[0]]
+		w.runAction(ctx, actions.Context{
+			ID:         actions.NewEventID(),
+			Path:       w.prev.data.DiskPath(g.Paths[0]),
+			RelPath:    relPath(w.cfg.Path, g.Paths[0]),
+			Event:      string(config.EventDuplicate),
+			Size:       g.Size,
+			ModTime:    info.ModTime,
+			Age:        w.clock.Now().Sub(info.ModTime),
+			Duplicates: g.Paths[1:],
+		}, action)
+	}
+}
+
+// applyRetention prunes files covered by retention rules whose interval
+// has elapsed, using the latest snapshot.
+func (w *Worker) applyRetention(ctx context.Context) {
+	if len(w.retainedAt) != len(w.cfg.Retention) {
+		w.retainedAt = make([]time.Time, len(w.cfg.Retention))
+	}
+	now := w.clock.Now()
+	for i, rule := range w.cfg.Retention {
+		if now.Sub(w.retainedAt[i]) < rule.Interval.Duration() {
+			continue
+		}
+		w.retainedAt[i] = now
+		name := fmt.Sprintf("%s.retention[%d]", w.cfg.Key(), i)
+		for _, p := range retention.Plan(w.cfg.Path, w.prev.data, rule, now) {
+			if ctx.Err() != nil {
+				return
+			}
+			if w.executor.DryRun {
+				w.logger.Info("dry-run retention", "watch", w.cfg.Key(), "mode", rule.Mode, "path", p)
+				w.tracker.IncAction(name, true, "")
 				continue
 			}
-			events := scanner.Diff(w.cfg.Path, w.prev.data, curr)
-			w.prev.data = curr
-			for _, ev := range events {
-				w.handleEvent(ctx, ev)
+			err := w.prune(ctx, rule, p)
+			if err != nil {
+				class := string(actions.Classify(err))
+				w.logger.Error("retention error", "watch", w.cfg.Key(), "path", p, "error_class", class, "err", err)
+				w.tracker.IncAction(name, false, err.Error())
+				w.tracker.IncErrorClass(name, class)
+				continue
 			}
+			w.logger.Info("retention pruned", "watch", w.cfg.Key(), "mode", rule.Mode, "path", p)
+			w.tracker.IncAction(name, true, "")
 		}
 	}
 }
 
-func (w *Worker) handleEvent(ctx context.Context, ev scanner.Event) {
-	if w.cfg.Debounce.Duration() > 0 {
-		last, ok := w.debounceMap[ev.Path]
-		if ok && time.Since(last) < w.cfg.Debounce.Duration() {
-			return
+func (w *Worker) prune(ctx context.Context, rule config.Retention, path string) error {
+	if rule.Mode == config.RetentionDelete {
+		if err := w.executor.Safety.TakeDestructive("", "retention"); err != nil {
+			return err
 		}
-		w.debounceMap[ev.Path] = time.Now()
+		return os.Remove(w.prev.data.DiskPath(path))
+	}
+	info := w.prev.data[path]
+	overwrite := false
+	return w.executor.Execute(ctx, actions.Context{
+		ID:       actions.NewEventID(),
+		Path:     w.prev.data.DiskPath(path),
+		RelPath:  relPath(w.cfg.Path, path),
+		Event:    "retention",
+		Size:     info.Size,
+		ModTime:  info.ModTime,
+		Age:      w.clock.Now().Sub(info.ModTime),
+		Location: w.location,
+	}, config.Action{
+		Name:      "retention",
+		Type:      config.ActionMove,
+		Dest:      rule.Dest,
+		Overwrite: &overwrite,
+	})
+}
+
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// diskUsage probes free space; tests stub it.
+var diskUsage = diskusage.Of
+
+// checkLowSpace fires the low_space action once each time the watched
+// filesystem drops below its threshold, re-arming when space recovers.
+func (w *Worker) checkLowSpace(ctx context.Context) {
+	usage, fire := w.crossedLowSpace()
+	if !fire {
+		return
+	}
+	if action, ok := w.action(w.cfg.LowSpace.Action); ok {
+		w.runAction(ctx, actions.Context{
+			ID:    actions.NewEventID(),
+			Path:  w.cfg.Path,
+			Event: string(config.EventLowSpace),
+			Size:  int64(usage.Free),
+			IsDir: true,
+		}, action)
+	}
+}
+
+// crossedLowSpace probes the watched filesystem and reports whether it
+// just dropped below the low_space threshold.
+func (w *Worker) crossedLowSpace() (diskusage.Usage, bool) {
+	ls := w.cfg.LowSpace
+	if ls == nil {
+		return diskusage.Usage{}, false
+	}
+	usage, err := diskUsage(w.cfg.Path)
+	if err != nil {
+		w.logger.Error("free space check error", "watch", w.cfg.Key(), "err", err)
+		return usage, false
+	}
+	low := (ls.MinFreeBytes > 0 && usage.Free < uint64(ls.MinFreeBytes)) ||
+		(ls.MinFreePercent > 0 && usage.FreePercent() < ls.MinFreePercent)
+	if low == w.lowSpace {
+		return usage, false
+	}
+	w.lowSpace = low
+	if !low {
+		w.logger.Info("free space recovered", "watch", w.cfg.Key(), "free_bytes", usage.Free)
+		return usage, false
+	}
+	w.logger.Warn("low free space", "watch", w.cfg.Key(), "free_bytes", usage.Free, "free_percent", usage.FreePercent())
+	return usage, true
+}
+
+func (w *Worker) handleEvent(ctx context.Context, ev scanner.Event) {
+	if !w.debounce.allow(ev.Path, ev.Type, w.clock.Now()) {
+		return
 	}
 	if ev.Type == "delete" {
-		delete(w.debounceMap, ev.Path)
-	}
-	w.tracker.IncEvent(w.cfg.Path)
-	selected := w.matcher.Match(ev, w.cfg)
-	for _, action := range selected {
-		evCtx := actions.Context{
-			Path:     ev.Path,
-			RelPath:  ev.RelPath,
-			PrevPath: ev.PrevPath,
-			Event:    ev.Type,
-			Size:     ev.Info.Size,
-			ModTime:  ev.Info.ModTime,
-			Age:      ev.Age,
-			IsDir:    ev.Info.IsDir,
-		}
+		w.debounce.forget(ev.Path)
+	}
+	w.tracker.IncEvent(w.cfg.Key())
+	w.metrics.Event(w.cfg.Key(), ev.Type, ev.Path)
+	w.summary.Event(w.cfg.Key(), ev.Type)
+	id := actions.NewEventID()
+	w.export.Event(w.cfg.Key(), id, ev.Type, ev.DiskPath(), ev.Info.Size)
+	ev = enrichEvent(ctx, w.logger.With("event_id", id), w.cfg, ev)
+	selected := w.filterFilesystem(skipPreviouslyRun(w.logger, w.history, w.cfg, ev.DiskPath(), w.matcher.Match(ev, w.cfg)))
+	w.logger.Debug("event", "event_id", id, "watch", w.cfg.Key(), "event", ev.Type, "path", ev.Path, "matched", len(selected))
+	selected = w.holdUntilStable(ev, id, selected)
+	w.dispatch(ctx, ev, id, selected)
+}
+
+// dispatch runs the selected actions for an event.
+func (w *Worker) dispatch(ctx context.Context, ev scanner.Event, id string, selected []config.Action) {
+	if len(selected) == 0 {
+		return
+	}
+	evCtx := eventContext(w.cfg, w.root, ev, id, w.clock.Now())
+	if len(selected) > 1 && ev.Type != "delete" && !ev.Info.IsDir {
+		evCtx.Cache = actions.NewReadCache(readCacheMax)
+	}
+	evCtx.Remote = w.remote
+	w.startChain(ctx, evCtx, selected, 0)
+}
+
+// eventContext builds the action context of ev under root: its paths and
+// file info, and the metadata, extended attributes and enrichment fields
+// that actions expand.
+func eventContext(wcfg config.Watch, root string, ev scanner.Event, id string, now time.Time) actions.Context {
+	var meta map[string]string
+	if len(wcfg.Metadata) > 0 && ev.Type != "delete" && !ev.Info.IsDir {
+		meta = metadata.Extract(ev.DiskPath(), wcfg.Metadata)
+	}
+	for k, v := range ev.Info.Xattrs {
+		meta = withMeta(meta, "xattr:"+k, v)
+	}
+	for k, v := range ev.Fields {
+		meta = withMeta(meta, "field:"+k, v)
+	}
+	prevRel := ""
+	if ev.PrevPath != "" {
+		prevRel = relPath(root, ev.PrevPath)
+	}
+	var deletedAt time.Time
+	if ev.Type == "delete" {
+		deletedAt = now
+	}
+	return actions.Context{
+		ID:          id,
+		Path:        ev.DiskPath(),
+		RelPath:     ev.RelPath,
+		PrevPath:    ev.PrevPath,
+		PrevRelPath: prevRel,
+		Event:       ev.Type,
+		Size:        ev.Info.Size,
+		ModTime:     ev.Info.ModTime,
+		Age:         ev.Age,
+		IsDir:       ev.Info.IsDir,
+		DeletedAt:   deletedAt,
+		Meta:        meta,
+		Outputs:     map[string]string{},
+	}
+}
+
+// enrichEvent sets the enrichment fields of ev, which conditions may match
+// on. A failed hook is logged and leaves the fields it could not get out.
+func enrichEvent(ctx context.Context, logger *slog.Logger, wcfg config.Watch, ev scanner.Event) scanner.Event {
+	if len(wcfg.Enrich) == 0 || ev.Type == "delete" || ev.Info.IsDir {
+		return ev
+	}
+	fields, err := enrich.Fields(ctx, enrich.Event{
+		Path:    ev.DiskPath(),
+		RelPath: ev.RelPath,
+		Type:    ev.Type,
+		Size:    ev.Info.Size,
+		ModTime: ev.Info.ModTime,
+	}, wcfg.Enrich)
+	if err != nil {
+		logger.Warn("enrich error", "watch", wcfg.Key(), "path", ev.Path, "err", err)
+	}
+	ev.Fields = fields
+	return ev
+}
+
+// runChain runs an event's actions in order, the first of them on the
+// given attempt. A failure that will be retried requeues the rest of the
+// chain rather than blocking the worker until the retry, and reports
+// requeued.
+func (w *Worker) runChain(ctx context.Context, evCtx actions.Context, chain []config.Action, attempt int) (requeued bool) {
+	for i, action := range chain {
 		if w.executor.DryRun {
-			w.logger.Info("dry-run action", "watch", w.cfg.Path, "action", action.Name, "event", ev.Type, "path", ev.Path)
-			w.tracker.IncAction(w.cfg.Path+"."+action.Name, true, "")
+			w.inSlot(ctx, evCtx, action, func(ctx context.Context) { w.runAction(ctx, evCtx, action) })
+			if action.ThenWatch != "" {
+				w.handOff(evCtx, action, "")
+			}
+			continue
+		}
+		if action.Batch != nil {
+			w.beginHandling(evCtx.ID, evCtx.Event, evCtx.Path)
+			w.pool.post(func() { w.batchEvent(ctx, evCtx, action) })
 			continue
 		}
-		err := w.executor.Execute(ctx, evCtx, action)
-		if err != nil {
-			w.logger.Error("action error", "watch", w.cfg.Path, "action", action.Name, "err", err)
-			w.tracker.IncAction(w.cfg.Path+"."+action.Name, false, err.Error())
-		} else {
-			w.logger.Info("action ok", "watch", w.cfg.Path, "action", action.Name, "event", ev.Type, "path", ev.Path)
-			w.tracker.IncAction(w.cfg.Path+"."+action.Name, true, "")
+		var retry bool
+		var err error
+		w.inSlot(ctx, evCtx, action, func(ctx context.Context) { retry, err = w.attemptAction(ctx, evCtx, action, attempt) })
+		dest := actions.TakeDest(evCtx)
+		if retry {
+			w.requeue(evCtx, chain[i:], attempt+1)
+			return true
+		}
+		attempt = 0
+		if err == nil && action.ThenWatch != "" {
+			w.handOff(evCtx, action, dest)
+		}
+		var infected *actions.InfectedError
+		if errors.As(err, &infected) {
+			// Infected files never reach the remaining actions.
+			if q, ok := w.action(action.Quarantine); ok {
+				evCtx.Meta = withMeta(evCtx.Meta, "clamav:signature", infected.Signature)
+				w.inSlot(ctx, evCtx, q, func(ctx context.Context) { w.runAction(ctx, evCtx, q) })
+			}
+			return false
+		}
+		if errors.Is(err, actions.ErrSkip) {
+			return false
 		}
 	}
+	return false
+}
+
+// attemptAction makes one attempt at an action of an event chain. It
+// reports retry instead of recording the failure when the action has
+// attempts left and the retry budget allows another.
+func (w *Worker) attemptAction(ctx context.Context, evCtx actions.Context, action config.Action, attempt int) (retry bool, err error) {
+	evCtx.Location = w.location
+	evCtx.Normalize = w.cfg.NormalizeUnicode
+	started := time.Now()
+	err = w.executor.Attempt(ctx, evCtx, action)
+	if actions.Retryable(err) && attempt < action.RetriesFor(actions.Classify(err)) && w.executor.AllowRetry(evCtx, action) {
+		w.actionLogger(evCtx, action, attempt+1, time.Since(started)).Warn("action attempt failed, requeued", "error_class", actions.Classify(err), "err", err)
+		return true, err
+	}
+	return false, w.finishAction(evCtx, action, attempt+1, time.Since(started), err)
+}
+
+func (w *Worker) runAction(ctx context.Context, evCtx actions.Context, action config.Action) error {
+	log := w.logger.With("event_id", evCtx.ID)
+	evCtx.Location = w.location
+	evCtx.Normalize = w.cfg.NormalizeUnicode
+	if w.executor.DryRun {
+		log.Info("dry-run action", "watch", w.cfg.Key(), "action", action.Name, "event", evCtx.Event, "path", evCtx.Path)
+		w.tracker.IncAction(w.cfg.Key()+"."+action.Name, true, "")
+		w.metrics.Action(w.cfg.Key(), action.Name, evCtx.Event, evCtx.Path, true)
+		w.export.Action(w.cfg.Key(), evCtx.ID, evCtx.Event, evCtx.Path, action.Name, export.StatusDryRun, 0, nil, "")
+		w.reportAction(evCtx, action, nil)
+		w.noteResult(evCtx, action, 0, nil)
+		return nil
+	}
+	started := time.Now()
+	err := w.executor.Execute(ctx, evCtx, action)
+	return w.finishAction(evCtx, action, 0, time.Since(started), err)
+}
+
+// actionLogger returns the logger for one run of an action, carrying the
+// fields that identify it. attempt is 1-based; 0 leaves it out, for runs
+// that retry in place.
+func (w *Worker) actionLogger(evCtx actions.Context, action config.Action, attempt int, took time.Duration) *slog.Logger {
+	log := w.logger.With("event_id", evCtx.ID, "watch", w.cfg.Key(), "watch_path", w.cfg.Path, "action", action.Name, "action_type", string(action.Type))
+	if attempt > 0 {
+		log = log.With("attempt", attempt)
+	}
+	return log.With("duration_ms", took.Milliseconds())
+}
+
+// finishAction records the final result of an action.
+func (w *Worker) finishAction(evCtx actions.Context, action config.Action, attempt int, took time.Duration, err error) error {
+	log := w.actionLogger(evCtx, action, attempt, took)
+	ok := err == nil || errors.Is(err, actions.ErrSkip)
+	w.metrics.Action(w.cfg.Key(), action.Name, evCtx.Event, evCtx.Path, ok)
+	w.recordSummary(evCtx, action, took, ok)
+	w.recordHistory(evCtx, action, took, ok, err)
+	w.exportAction(evCtx, action, took, err)
+	w.reportAction(evCtx, action, err)
+	w.noteResult(evCtx, action, took, err)
+	w.deadLetter(evCtx, action, attempt, err)
+	if errors.Is(err, actions.ErrSkip) {
+		log.Info("action ok, skipping remaining actions", "event", evCtx.Event, "path", evCtx.Path)
+		w.tracker.IncAction(w.cfg.Key()+"."+action.Name, true, "")
+		return err
+	}
+	if err != nil {
+		class := string(actions.Classify(err))
+		log.Error("action error", "event", evCtx.Event, "path", evCtx.Path, "error_class", class, "err", err)
+		w.tracker.IncAction(w.cfg.Key()+"."+action.Name, false, err.Error())
+		w.tracker.IncErrorClass(w.cfg.Key()+"."+action.Name, class)
+		w.tracker.ObserveAction(w.cfg.Key(), false)
+		w.notifier.ActionFailed(w.cfg.Key(), action.Name, err)
+	} else {
+		log.Info("action ok", "event", evCtx.Event, "path", evCtx.Path)
+		w.tracker.IncAction(w.cfg.Key()+"."+action.Name, true, "")
+		w.tracker.ObserveAction(w.cfg.Key(), true)
+	}
+	return err
+}
+
+func (w *Worker) reportAction(evCtx actions.Context, action config.Action, err error) {
+	if w.onAction != nil {
+		w.onAction(ActionResult{Watch: w.cfg.Key(), Action: action, Event: evCtx, Err: err})
+	}
+}
+
+// exportAction writes an action result to the export sink.
+func (w *Worker) exportAction(evCtx actions.Context, action config.Action, took time.Duration, err error) {
+	st := export.StatusOK
+	switch {
+	case errors.Is(err, actions.ErrSkip):
+		st, err = export.StatusSkip, nil
+	case err != nil:
+		st = export.StatusError
+	}
+	w.export.Action(w.cfg.Key(), evCtx.ID, evCtx.Event, evCtx.Path, action.Name, st, took, err, actions.Classify(err))
+}
+
+// recordHistory adds a finished action to the file's history.
+func (w *Worker) recordHistory(evCtx actions.Context, action config.Action, took time.Duration, ok bool, err error) {
+	recordHistory(w.logger, w.history, w.cfg.Key(), w.clock.Now(), evCtx, action, took, ok, err)
+}
+
+func recordHistory(logger *slog.Logger, store *history.Store, watch string, now time.Time, evCtx actions.Context, action config.Action, took time.Duration, ok bool, err error) {
+	e := history.Entry{
+		Path:       evCtx.Path,
+		Time:       now,
+		Watch:      watch,
+		Action:     action.Name,
+		EventID:    evCtx.ID,
+		Event:      evCtx.Event,
+		OK:         ok,
+		DurationMS: took.Milliseconds(),
+	}
+	if !ok {
+		e.Error = err.Error()
+		e.ErrorClass = string(actions.Classify(err))
+	}
+	if err := store.Record(e); err != nil {
+		logger.Error("record history", "watch", watch, "path", evCtx.Path, "err", err)
+	}
+}
+
+// skipPreviouslyRun drops actions whose not_previously_run action already
+// completed for path.
+func skipPreviouslyRun(logger *slog.Logger, store *history.Store, wcfg config.Watch, path string, selected []config.Action) []config.Action {
+	var out []config.Action
+	for _, a := range selected {
+		if prev := a.Condition.NotPreviouslyRun; prev != "" && store.Succeeded(path, prev) {
+			logger.Debug("action skipped, previously run", "watch", wcfg.Key(), "action", a.Name, "path", path, "previous", prev)
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// recordSummary adds a finished action to the run summary.
+func (w *Worker) recordSummary(evCtx actions.Context, action config.Action, took time.Duration, ok bool) {
+	var copied, moved int64
+	if !evCtx.IsDir {
+		switch action.Type {
+		case config.ActionCopy:
+			copied = evCtx.Size
+		case config.ActionMove, config.ActionTransfer:
+			moved = evCtx.Size
+		case config.ActionArchive:
+			if action.KeepSource {
+				copied = evCtx.Size
+			} else {
+				moved = evCtx.Size
+			}
+		}
+	}
+	w.summary.Action(summary.Run{Watch: w.cfg.Key(), Action: action.Name, Path: evCtx.Path, Duration: took}, ok, copied, moved)
+}
+
+// action looks up a watch action by name.
+func (w *Worker) action(name string) (config.Action, bool) {
+	if name == "" {
+		return config.Action{}, false
+	}
+	for _, a := range w.cfg.Actions {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return config.Action{}, false
+}
+
+func withMeta(meta map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(meta)+1)
+	for k, v := range meta {
+		out[k] = v
+	}
+	out[key] = value
+	return out
 }
@@ -0,0 +1,31 @@
+// Package xattr reads user extended attributes, which some producers use
+// to tag files.
+package xattr
+
+import (
+	"errors"
+	"strings"
+)
+
+// Prefix is the namespace read; keys are returned without it.
+const Prefix = "user."
+
+// Key strips Prefix from a configured key so both "user.origin" and
+// "origin" name the same attribute.
+func Key(k string) string {
+	return strings.TrimPrefix(k, Prefix)
+}
+
+// ErrUnsupported is returned by Set where xattrs cannot be written.
+var ErrUnsupported = errors.New("extended attributes are not supported here")
+
+// Set writes the user attribute key (with or without Prefix) on path.
+func Set(path, key, value string) error {
+	return set(path, Prefix+Key(key), value)
+}
+
+// List returns the user.* attributes of path keyed without the prefix, or
+// nil when there are none or the platform or filesystem lacks support.
+func List(path string) map[string]string {
+	return list(path)
+}
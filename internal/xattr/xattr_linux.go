@@ -0,0 +1,66 @@
+//go:build linux
+
+package xattr
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+)
+
+func list(path string) map[string]string {
+	names := make([]byte, 1024)
+	n, err := syscall.Listxattr(path, names)
+	if err == syscall.ERANGE {
+		if n, err = syscall.Listxattr(path, nil); err == nil {
+			names = make([]byte, n)
+			n, err = syscall.Listxattr(path, names)
+		}
+	}
+	if err != nil || n == 0 {
+		return nil
+	}
+	var out map[string]string
+	for _, name := range bytes.Split(names[:n], []byte{0}) {
+		key := string(name)
+		if !strings.HasPrefix(key, Prefix) {
+			continue
+		}
+		value, ok := get(path, key)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[Key(key)] = value
+	}
+	return out
+}
+
+func set(path, key, value string) error {
+	err := syscall.Setxattr(path, key, []byte(value), 0)
+	if err == syscall.ENOTSUP {
+		return &os.PathError{Op: "setxattr", Path: path, Err: ErrUnsupported}
+	}
+	if err != nil {
+		return &os.PathError{Op: "setxattr", Path: path, Err: err}
+	}
+	return nil
+}
+
+func get(path, key string) (string, bool) {
+	buf := make([]byte, 256)
+	n, err := syscall.Getxattr(path, key, buf)
+	if err == syscall.ERANGE {
+		if n, err = syscall.Getxattr(path, key, nil); err == nil {
+			buf = make([]byte, n)
+			n, err = syscall.Getxattr(path, key, buf)
+		}
+	}
+	if err != nil {
+		return "", false
+	}
+	return string(buf[:n]), true
+}
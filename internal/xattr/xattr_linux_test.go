@@ -0,0 +1,27 @@
+//go:build linux
+
+package xattr
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestListReadsUserAttributes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Setxattr(path, "user.origin", []byte("scanner-3"), 0); err != nil {
+		t.Skipf("filesystem lacks user xattrs: %v", err)
+	}
+	got := List(path)
+	if got["origin"] != "scanner-3" || len(got) != 1 {
+		t.Fatalf("unexpected attributes %#v", got)
+	}
+	if Key("user.origin") != "origin" || Key("origin") != "origin" {
+		t.Fatalf("Key should strip the user. prefix")
+	}
+}
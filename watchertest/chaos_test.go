@@ -0,0 +1,73 @@
+package watchertest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/chaos"
+)
+
+// TestRecoversFromInjectedFaults drives copies, resumable copies and
+// transfers through random action failures and truncated copies, and
+// checks every file still arrives intact once retries run their course.
+func TestRecoversFromInjectedFaults(t *testing.T) {
+	out := t.TempDir()
+	h := New(t, strings.ReplaceAll(`
+version: 1
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: copy
+        type: copy
+        include: ["*.dat"]
+        dest: "OUT/copy/{name}"
+        retries: 20
+      - name: resumable
+        type: copy
+        include: ["*.dat"]
+        dest: "OUT/resumable/{name}"
+        resumable: true
+        retries: 20
+      - name: transfer
+        type: transfer
+        include: ["*.dat"]
+        dest: "OUT/transfer/{name}"
+        retries: 20
+`, "OUT", out))
+	h.InjectFaults(chaos.Faults{FailRate: 0.3, TruncateRate: 0.5, Seed: 7})
+
+	want := map[string]string{}
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("f%d.dat", i)
+		want[name] = strings.Repeat(fmt.Sprintf("%d-payload-", i), 2000+i*100)
+		h.WriteFile(name, want[name])
+	}
+	h.Step()
+	for i := 0; i < 30; i++ {
+		h.Advance(time.Minute)
+		h.Step()
+	}
+	for _, r := range h.Results() {
+		if r.Err != nil {
+			t.Fatalf("%s on %s did not recover: %v", r.Action, r.Path, r.Err)
+		}
+	}
+	for _, sub := range []string{"copy", "resumable"} {
+		for name, content := range want {
+			got, err := os.ReadFile(filepath.Join(out, sub, name))
+			if err != nil || string(got) != content {
+				t.Fatalf("%s/%s: corrupt or missing after faults (%d bytes, %v)", sub, name, len(got), err)
+			}
+		}
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(out, "transfer", name))
+		if err != nil || string(got) != content {
+			t.Fatalf("transfer/%s: corrupt or missing after faults (%d bytes, %v)", name, len(got), err)
+		}
+	}
+}
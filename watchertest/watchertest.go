@@ -0,0 +1,250 @@
+// Package watchertest runs a watcher Supervisor against a temporary
+// directory with a fake clock, so integration tests can inject filesystem
+// changes and assert on the resulting actions deterministically.
+//
+// A typical test:
+//
+//	h := watchertest.New(t, `
+//	watches:
+//	  - path: $WATCHERTEST_DIR
+//	    actions:
+//	      - name: images
+//	        type: exec
+//	        include: ["*.jpg"]
+//	        cmd: "true"
+//	`)
+//	h.WriteFile("a.jpg", "data")
+//	h.Step()
+//	h.ExpectActions("images")
+package watchertest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/chaos"
+	"watcher-cli/internal/config"
+	"watcher-cli/internal/watcher"
+)
+
+// DirVar is replaced with the watched temp dir in configs given to New.
+const DirVar = "$WATCHERTEST_DIR"
+
+// Clock is a manually advanced clock.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock set to now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current fake time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Result is an action the supervisor ran.
+type Result struct {
+	Watch  string
+	Action string
+	Type   string
+	Event  string
+	Path   string
+	Err    error
+}
+
+// Harness drives a Supervisor over a temp dir.
+type Harness struct {
+	t     testing.TB
+	Dir   string
+	Clock *Clock
+
+	sup     *watcher.Supervisor
+	cfg     config.Config
+	mu      sync.Mutex
+	results []Result
+}
+
+// New loads configYAML, with DirVar replaced by a fresh temp dir, and takes
+// the baseline snapshot. The clock starts at the current time truncated to
+// the second.
+func New(t testing.TB, configYAML string) *Harness {
+	t.Helper()
+	h := &Harness{
+		t:     t,
+		Dir:   t.TempDir(),
+		Clock: NewClock(time.Now().Truncate(time.Second)),
+	}
+	h.cfg = h.load(configYAML)
+	h.newSupervisor()
+	h.Step()
+	return h
+}
+
+func (h *Harness) newSupervisor() {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h.sup = watcher.NewSupervisor(h.cfg, logger, h.cfg.Global.DryRun)
+	h.sup.SetClock(h.Clock)
+	h.sup.OnAction(h.record)
+}
+
+// Restart simulates stopping the watcher and starting it again: state is
+// checkpointed and the supervisor replaced. Changes made before the next
+// Step happen while the watcher is down.
+func (h *Harness) Restart() {
+	h.sup.Checkpoint()
+	h.newSupervisor()
+}
+
+// Reload applies configYAML to the running supervisor as a config reload
+// would. Added watches take their baseline on the next Step.
+func (h *Harness) Reload(configYAML string) {
+	h.t.Helper()
+	h.cfg = h.load(configYAML)
+	h.sup.Reload(h.cfg)
+}
+
+func (h *Harness) load(configYAML string) config.Config {
+	h.t.Helper()
+	cfgPath := filepath.Join(h.t.TempDir(), "watcher.yaml")
+	if err := os.WriteFile(cfgPath, []byte(strings.ReplaceAll(configYAML, DirVar, h.Dir)), 0o644); err != nil {
+		h.t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		h.t.Fatalf("load config: %v", err)
+	}
+	if err := cfg.ResolvePaths(); err != nil {
+		h.t.Fatalf("resolve paths: %v", err)
+	}
+	return cfg
+}
+
+// InjectFaults makes the supervisor fail actions, cut copies short and
+// delay scans, to check that a config recovers from them.
+func (h *Harness) InjectFaults(f chaos.Faults) {
+	h.sup.SetFaults(chaos.New(f))
+}
+
+// Pause pauses the watch with the given key, as the admin API does.
+func (h *Harness) Pause(watch string) {
+	h.t.Helper()
+	if err := h.sup.Pause(watch); err != nil {
+		h.t.Fatalf("pause: %v", err)
+	}
+}
+
+// Resume resumes a watch paused by Pause.
+func (h *Harness) Resume(watch string) {
+	h.t.Helper()
+	if err := h.sup.Resume(watch); err != nil {
+		h.t.Fatalf("resume: %v", err)
+	}
+}
+
+// Path returns the absolute path of rel inside the watched dir.
+func (h *Harness) Path(rel string) string {
+	return filepath.Join(h.Dir, filepath.FromSlash(rel))
+}
+
+// WriteFile creates or replaces rel with content, stamping it with the fake
+// clock's time. Advance the clock between writes of equal size so the
+// change is detected as a modification.
+func (h *Harness) WriteFile(rel, content string) {
+	h.t.Helper()
+	p := h.Path(rel)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		h.t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		h.t.Fatalf("write %s: %v", rel, err)
+	}
+	now := h.Clock.Now()
+	if err := os.Chtimes(p, now, now); err != nil {
+		h.t.Fatalf("chtimes %s: %v", rel, err)
+	}
+}
+
+// Remove deletes rel.
+func (h *Harness) Remove(rel string) {
+	h.t.Helper()
+	if err := os.RemoveAll(h.Path(rel)); err != nil {
+		h.t.Fatalf("remove %s: %v", rel, err)
+	}
+}
+
+// Rename moves from to to inside the watched dir.
+func (h *Harness) Rename(from, to string) {
+	h.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(h.Path(to)), 0o755); err != nil {
+		h.t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Rename(h.Path(from), h.Path(to)); err != nil {
+		h.t.Fatalf("rename %s: %v", from, err)
+	}
+}
+
+// Advance moves the fake clock forward.
+func (h *Harness) Advance(d time.Duration) {
+	h.Clock.Advance(d)
+}
+
+// Step runs one scan of every watch and waits for its actions.
+func (h *Harness) Step() {
+	h.sup.Step(context.Background())
+}
+
+// Results returns and clears the actions run since the last call.
+func (h *Harness) Results() []Result {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := h.results
+	h.results = nil
+	return out
+}
+
+// ExpectActions fails the test unless exactly the named actions ran, in
+// order, since the last check.
+func (h *Harness) ExpectActions(names ...string) {
+	h.t.Helper()
+	results := h.Results()
+	got := make([]string, len(results))
+	for i, r := range results {
+		got[i] = r.Action
+	}
+	if strings.Join(got, ",") != strings.Join(names, ",") {
+		h.t.Fatalf("expected actions %v, got %v", names, got)
+	}
+}
+
+func (h *Harness) record(r watcher.ActionResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.results = append(h.results, Result{
+		Watch:  r.Watch,
+		Action: r.Action.Name,
+		Type:   string(r.Action.Type),
+		Event:  r.Event.Event,
+		Path:   r.Event.Path,
+		Err:    r.Err,
+	})
+}
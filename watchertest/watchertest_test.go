@@ -0,0 +1,613 @@
+package watchertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"watcher-cli/internal/deadletter"
+	"watcher-cli/internal/diskusage"
+)
+
+const cfg = `
+version: 1
+global:
+  dry_run: true
+  debounce_ms: 1s
+watches:
+  - path: $WATCHERTEST_DIR
+    recursive: true
+    actions:
+      - name: images
+        type: exec
+        include: ["**/*.jpg", "*.jpg"]
+        events: [create, modify]
+        cmd: "true"
+      - name: cleanup
+        type: exec
+        include: ["**/*", "*"]
+        events: [delete]
+        cmd: "true"
+`
+
+func TestHarnessDispatchesActions(t *testing.T) {
+	h := New(t, cfg)
+	h.WriteFile("a.jpg", "one")
+	h.WriteFile("notes.txt", "x")
+	h.Step()
+	h.ExpectActions("images")
+
+	// A modify right after the create is a different event type and runs.
+	h.Advance(500 * time.Millisecond)
+	h.WriteFile("a.jpg", "two!")
+	h.Step()
+	h.ExpectActions("images")
+
+	// A second modify inside the debounce window is ignored...
+	h.Advance(500 * time.Millisecond)
+	h.WriteFile("a.jpg", "three")
+	h.Step()
+	h.ExpectActions()
+
+	// ...and picked up once the window has passed.
+	h.Advance(2 * time.Second)
+	h.WriteFile("a.jpg", "four!!")
+	h.Step()
+	results := h.Results()
+	if len(results) != 1 || results[0].Event != "modify" || results[0].Path != h.Path("a.jpg") {
+		t.Fatalf("unexpected results %+v", results)
+	}
+
+	h.Remove("notes.txt")
+	h.Step()
+	h.ExpectActions("cleanup")
+}
+
+func TestFailedActionIsRequeued(t *testing.T) {
+	h := New(t, `
+version: 1
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: flaky
+        type: exec
+        include: ["*.bad"]
+        retries: 1
+        cmd: "false"
+      - name: after
+        type: exec
+        include: ["*"]
+        cmd: "true"
+`)
+	h.WriteFile("a.bad", "x")
+	h.Step()
+	// The failed attempt is requeued; the rest of its chain waits for it.
+	h.ExpectActions()
+
+	// Other events are handled while the retry is pending.
+	h.WriteFile("b.txt", "yy")
+	h.Step()
+	h.ExpectActions("after")
+
+	h.Advance(time.Second)
+	h.WriteFile("c.txt", "zzz")
+	h.Step()
+	results := h.Results()
+	if len(results) != 3 || results[0].Action != "flaky" || results[0].Err == nil || results[1].Action != "after" || results[1].Path != h.Path("a.bad") {
+		t.Fatalf("expected the retry to fail and resume its chain before new events, got %+v", results)
+	}
+}
+
+func TestEventBurstIsHeld(t *testing.T) {
+	h := New(t, `
+version: 1
+global:
+  dry_run: true
+watches:
+  - path: $WATCHERTEST_DIR
+    max_events_per_scan: 2
+    burst_confirm_scans: 2
+    actions:
+      - name: all
+        type: exec
+        include: ["*"]
+        cmd: "true"
+`)
+	h.WriteFile("a", "1")
+	h.WriteFile("b", "22")
+	h.WriteFile("c", "333")
+	h.Step()
+	h.ExpectActions()
+	h.Step()
+	h.ExpectActions("all", "all", "all")
+
+	// A burst that reverts before it is confirmed never fires.
+	h.Remove("a")
+	h.Remove("b")
+	h.Remove("c")
+	h.Step()
+	h.WriteFile("a", "1")
+	h.WriteFile("b", "22")
+	h.WriteFile("c", "333")
+	h.Step()
+	h.ExpectActions()
+}
+
+func TestThenWatchHandsOffOutput(t *testing.T) {
+	h := New(t, `
+version: 1
+watches:
+  - name: incoming
+    path: $WATCHERTEST_DIR
+    actions:
+      - name: convert
+        type: move
+        include: ["*.raw"]
+        dest: "$WATCHERTEST_DIR/{stem}.jpg"
+        then_watch: published
+  - name: published
+    path: $WATCHERTEST_DIR
+    actions:
+      - name: publish
+        type: exec
+        include: ["*.jpg"]
+        cmd: "true"
+`)
+	h.WriteFile("x.raw", "data")
+	h.Step()
+	results := h.Results()
+	if len(results) != 2 || results[0].Action != "convert" || results[1].Action != "publish" || results[1].Path != h.Path("x.jpg") {
+		t.Fatalf("expected convert then publish of the output, got %+v", results)
+	}
+	// The handed-off file is not reported again by the target's scans.
+	h.Step()
+	h.ExpectActions()
+	if up := h.sup.Status()["published"].Upstream; up["incoming.convert"] != 1 {
+		t.Fatalf("expected a back-reference to incoming.convert, got %v", up)
+	}
+}
+
+func TestReloadSwapsActionsAndAddsWatches(t *testing.T) {
+	h := New(t, cfg)
+	h.WriteFile("sub/keep.txt", "x")
+	h.WriteFile("a.jpg", "one")
+	h.Step()
+	h.ExpectActions("images")
+
+	h.Reload(`
+version: 1
+global:
+  dry_run: true
+  debounce_ms: 1s
+watches:
+  - path: $WATCHERTEST_DIR
+    recursive: true
+    actions:
+      - name: photos
+        type: exec
+        include: ["**/*.jpg", "*.jpg"]
+        events: [create, modify]
+        cmd: "true"
+  - name: sub
+    path: $WATCHERTEST_DIR/sub
+    actions:
+      - name: texts
+        type: exec
+        include: ["*.txt"]
+        cmd: "true"
+`)
+	// The new watch takes its baseline, so existing files do not fire.
+	h.Step()
+	h.ExpectActions()
+
+	h.WriteFile("b.jpg", "two!")
+	h.WriteFile("sub/new.txt", "yy")
+	h.Step()
+	results := h.Results()
+	got := map[string]string{}
+	for _, r := range results {
+		got[r.Action] = r.Path
+	}
+	if len(results) != 2 || got["photos"] != h.Path("b.jpg") || got["texts"] != h.Path("sub/new.txt") {
+		t.Fatalf("expected photos and texts after reload, got %+v", results)
+	}
+
+	// Removing the watch stops its actions; the other keeps its snapshot.
+	h.Reload(cfg)
+	h.Remove("sub/new.txt")
+	h.Step()
+	h.ExpectActions("cleanup")
+}
+
+func TestRestartCatchesUpOnOfflineChanges(t *testing.T) {
+	h := New(t, strings.Replace(cfg, "global:\n", "global:\n  state_dir: "+t.TempDir()+"\n  persist_snapshots: true\n", 1))
+	h.WriteFile("a.jpg", "one")
+	h.WriteFile("notes.txt", "x")
+	h.Step()
+	h.ExpectActions("images")
+
+	h.Restart()
+	h.WriteFile("b.jpg", "two!")
+	h.Remove("notes.txt")
+	h.Step()
+	results := h.Results()
+	got := map[string]string{}
+	for _, r := range results {
+		got[r.Action] = r.Path
+	}
+	if len(results) != 2 || got["images"] != h.Path("b.jpg") || got["cleanup"] != h.Path("notes.txt") {
+		t.Fatalf("expected offline changes to fire at startup, got %+v", results)
+	}
+	// They are not reported twice.
+	h.Step()
+	h.ExpectActions()
+}
+
+func TestRestartResumesPendingRetries(t *testing.T) {
+	h := New(t, `
+version: 1
+global:
+  state_dir: `+t.TempDir()+`
+  persist_snapshots: true
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: flaky
+        type: exec
+        include: ["*.bad"]
+        retries: 1
+        cmd: "false"
+`)
+	h.WriteFile("a.bad", "x")
+	h.WriteFile("b.txt", "yy")
+	h.Step()
+	h.ExpectActions()
+
+	// The retry is still pending at shutdown, so the event is not part of
+	// the checkpoint and is reported again after the restart.
+	h.Restart()
+	h.Step()
+	h.ExpectActions()
+	h.Advance(time.Second)
+	h.Step()
+	results := h.Results()
+	if len(results) != 1 || results[0].Action != "flaky" || results[0].Path != h.Path("a.bad") {
+		t.Fatalf("expected the unfinished event to run after the restart, got %+v", results)
+	}
+	h.Restart()
+	h.Step()
+	h.ExpectActions()
+}
+
+func TestStableForWaitsForWriterToFinish(t *testing.T) {
+	h := New(t, `
+version: 1
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: notify
+        type: exec
+        include: ["*"]
+        events: [create]
+        cmd: "true"
+      - name: ingest
+        type: exec
+        include: ["*"]
+        condition:
+          stable_for_ms: 2000
+        cmd: "true"
+`)
+	h.WriteFile("a.bin", "x")
+	h.Step()
+	h.ExpectActions("notify")
+
+	// Still growing: the quiet period starts over.
+	h.Advance(time.Second)
+	h.WriteFile("a.bin", "xx")
+	h.Step()
+	h.ExpectActions()
+	h.Advance(1500 * time.Millisecond)
+	h.Step()
+	h.ExpectActions()
+
+	h.Advance(time.Second)
+	h.Step()
+	results := h.Results()
+	if len(results) != 1 || results[0].Action != "ingest" || results[0].Event != "create" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	h.Advance(5 * time.Second)
+	h.Step()
+	h.ExpectActions()
+
+	// A file removed before it settles never reaches the held action.
+	h.WriteFile("b.bin", "x")
+	h.Step()
+	h.ExpectActions("notify")
+	h.Remove("b.bin")
+	h.Advance(5 * time.Second)
+	h.Step()
+	h.ExpectActions()
+}
+
+func TestSlowActionDoesNotBlockOtherFiles(t *testing.T) {
+	gate := filepath.Join(t.TempDir(), "gate")
+	h := New(t, `
+version: 1
+global:
+  max_concurrent_actions: 2
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: slow
+        type: exec
+        include: ["*.slow"]
+        timeout_ms: 5000
+        cmd: ["sh", "-c", "while [ ! -e `+gate+` ]; do sleep 0.01; done"]
+      - name: fast
+        type: exec
+        include: ["*.fast"]
+        cmd: ["touch", "`+gate+`"]
+`)
+	// Run in turn, a.slow would wait for b.fast until it timed out.
+	h.WriteFile("a.slow", "x")
+	h.WriteFile("b.fast", "x")
+	h.Step()
+	// slow may notice the gate before fast's result is recorded, so the
+	// order of the two is not fixed.
+	results := h.Results()
+	if len(results) != 2 || results[0].Action == results[1].Action {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("%s failed: %v", r.Action, r.Err)
+		}
+	}
+}
+
+func TestEnrichedFieldsSelectActions(t *testing.T) {
+	h := New(t, `
+version: 1
+global:
+  dry_run: true
+watches:
+  - path: $WATCHERTEST_DIR
+    enrich:
+      - type: mime
+    actions:
+      - name: images
+        type: exec
+        include: ["*"]
+        condition:
+          fields:
+            mime: "image/*"
+        cmd: "true"
+      - name: all
+        type: exec
+        include: ["*"]
+        cmd: "true"
+`)
+	h.WriteFile("photo.dat", "\x89PNG\r\n\x1a\n")
+	h.Step()
+	h.ExpectActions("images", "all")
+
+	h.WriteFile("notes.dat", "plain text")
+	h.Step()
+	h.ExpectActions("all")
+}
+
+func TestFilesystemCondition(t *testing.T) {
+	h := New(t, `
+version: 1
+global:
+  dry_run: true
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: local
+        type: exec
+        include: ["*"]
+        condition:
+          filesystem:
+            network: false
+        cmd: "true"
+      - name: share
+        type: exec
+        include: ["*"]
+        condition:
+          filesystem:
+            types: [nfs, cifs]
+        cmd: "true"
+`)
+	m, err := diskusage.MountOf(h.Dir)
+	if err != nil {
+		t.Skipf("filesystem type unknown: %v", err)
+	}
+	if m.Network {
+		t.Skipf("test directory is on %s", m.Type)
+	}
+	h.WriteFile("a.txt", "x")
+	h.Step()
+	h.ExpectActions("local")
+}
+
+func TestBatchRunsOnceForManyFiles(t *testing.T) {
+	tmp := t.TempDir()
+	list := filepath.Join(tmp, "list")
+	// A script, as the config expands $ variables.
+	script := filepath.Join(tmp, "upload.sh")
+	body := "#!/bin/sh\necho \"$WATCHER_BATCH_SIZE $*\" >> " + list + "\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	h := New(t, `
+version: 1
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: upload
+        type: exec
+        include: ["*"]
+        events: [create]
+        batch:
+          max_items: 3
+          max_wait_ms: 1000
+        cmd: ["`+script+`", "{paths}"]
+`)
+	h.WriteFile("a", "1")
+	h.WriteFile("b", "22")
+	h.Step()
+	h.ExpectActions()
+
+	// Not full: the batch runs once max_wait_ms has passed.
+	h.Advance(time.Second)
+	h.Step()
+	h.ExpectActions("upload", "upload")
+
+	// Full: the batch runs without waiting.
+	h.WriteFile("c", "333")
+	h.WriteFile("d", "4444")
+	h.WriteFile("e", "55555")
+	h.Step()
+	h.ExpectActions("upload", "upload", "upload")
+
+	data, err := os.ReadFile(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := func(name string) string { return filepath.Join(h.Dir, name) }
+	want := "2 " + p("a") + " " + p("b") + "\n3 " + p("c") + " " + p("d") + " " + p("e") + "\n"
+	if string(data) != want {
+		t.Fatalf("batches ran as\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestHandledWebhookReportsPartialFailure(t *testing.T) {
+	reports := make(chan map[string]any, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("decode report: %v", err)
+		}
+		reports <- report
+	}))
+	defer srv.Close()
+	h := New(t, `
+version: 1
+watches:
+  - path: $WATCHERTEST_DIR
+    handled_webhook:
+      url: `+srv.URL+`
+    actions:
+      - name: ok
+        type: exec
+        cmd: ["true"]
+      - name: broken
+        type: exec
+        cmd: ["false"]
+`)
+	h.WriteFile("a.txt", "x")
+	h.Step()
+	h.ExpectActions("ok", "broken")
+	select {
+	case report := <-reports:
+		if report["outcome"] != "partially_failed" || report["event"] != "create" {
+			t.Fatalf("unexpected report %+v", report)
+		}
+		if list, _ := report["actions"].([]any); len(list) != 2 {
+			t.Fatalf("expected 2 action results, got %+v", report["actions"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no handled report")
+	}
+}
+
+func TestEventOrderOldestFirst(t *testing.T) {
+	h := New(t, `
+version: 1
+global:
+  dry_run: true
+watches:
+  - path: $WATCHERTEST_DIR
+    event_order: oldest
+    actions:
+      - name: log
+        type: exec
+        cmd: ["true"]
+`)
+	base := time.Now().Add(-time.Hour)
+	names := []string{"a.txt", "b.txt", "c.txt"}
+	for i, name := range names {
+		h.WriteFile(name, "x")
+		mtime := base.Add(time.Duration(len(names)-i) * time.Minute)
+		if err := os.Chtimes(h.Path(name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Step()
+	var got []string
+	for _, r := range h.Results() {
+		got = append(got, filepath.Base(r.Path))
+	}
+	if strings.Join(got, ",") != "c.txt,b.txt,a.txt" {
+		t.Fatalf("got order %v, want oldest first", got)
+	}
+}
+
+func TestFailedActionIsDeadLettered(t *testing.T) {
+	dlq := filepath.Join(t.TempDir(), "failed.jsonl")
+	h := New(t, `
+version: 1
+global:
+  defaults:
+    dead_letter: `+dlq+`
+watches:
+  - path: $WATCHERTEST_DIR
+    actions:
+      - name: ok
+        type: exec
+        cmd: ["true"]
+      - name: upload
+        type: exec
+        cmd: ["false"]
+`)
+	h.WriteFile("a.txt", "x")
+	h.Step()
+	h.ExpectActions("ok", "upload")
+	entries, err := deadletter.Read(dlq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Action != "upload" || entries[0].Event != "create" || filepath.Base(entries[0].Path) != "a.txt" {
+		t.Fatalf("unexpected dead letters %+v", entries)
+	}
+}
+
+func TestPausedWatchCatchesUpOnResume(t *testing.T) {
+	h := New(t, cfg)
+	h.Pause(h.Dir)
+	h.WriteFile("a.jpg", "one")
+	h.Step()
+	h.ExpectActions()
+	h.Resume(h.Dir)
+	h.Step()
+	h.ExpectActions("images")
+
+	// A reload that restarts the watch keeps it paused.
+	h.Pause(h.Dir)
+	h.Reload(strings.Replace(cfg, "recursive: true", "recursive: false", 1))
+	h.Step()
+	h.Advance(2 * time.Second)
+	h.WriteFile("b.jpg", "two")
+	h.Step()
+	h.ExpectActions()
+	h.Resume(h.Dir)
+	h.Step()
+	h.ExpectActions("images")
+}